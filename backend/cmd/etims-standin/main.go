@@ -0,0 +1,29 @@
+// Command etims-standin is a local stand-in for KRA's eTIMS OSCU API. It signs
+// the sales it is sent so tax invoices can be exercised end to end without the
+// sandbox. Point ETIMS_BASE_URL at it to use it.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/EmilioCliff/jonche-med/internal/etims"
+)
+
+var (
+	addr    = flag.String("addr", ":8091", "address to listen on")
+	downFor = flag.Duration("down-for", 0, "answer every request with 503 for this long after starting")
+)
+
+func main() {
+	flag.Parse()
+
+	standin := etims.NewStandin()
+	if *downFor > 0 {
+		standin.Down(*downFor)
+	}
+
+	log.Printf("etims stand-in listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, standin))
+}
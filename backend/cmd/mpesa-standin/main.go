@@ -0,0 +1,115 @@
+// Command mpesa-standin is a local stand-in for the Daraja API. It issues access
+// tokens, accepts STK push requests and posts a callback to the request's
+// callback url after a short delay, so payments can be exercised end to end
+// without the sandbox. Point MPESA_BASE_URL at it to use it.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	addr  = flag.String("addr", ":8090", "address to listen on")
+	delay = flag.Duration("delay", 3*time.Second, "time before the callback is sent")
+	fail  = flag.Bool("fail", false, "reply to every push with a cancelled callback")
+)
+
+var counter atomic.Int64
+
+type stkPushRequest struct {
+	Amount      int64  `json:"Amount"`
+	PhoneNumber string `json:"PhoneNumber"`
+	CallBackURL string `json:"CallBackURL"`
+}
+
+func main() {
+	flag.Parse()
+
+	http.HandleFunc("/oauth/v1/generate", func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); !ok {
+			http.Error(w, "missing credentials", http.StatusUnauthorized)
+			return
+		}
+
+		writeJSON(w, map[string]string{"access_token": "standin-token", "expires_in": "3599"})
+	})
+
+	http.HandleFunc("/mpesa/stkpush/v1/processrequest", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer standin-token" {
+			http.Error(w, "invalid access token", http.StatusUnauthorized)
+			return
+		}
+
+		var req stkPushRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		n := counter.Add(1)
+		merchantID := fmt.Sprintf("standin-merchant-%d", n)
+		checkoutID := fmt.Sprintf("ws_CO_standin_%d", n)
+
+		go sendCallback(req, merchantID, checkoutID, n)
+
+		writeJSON(w, map[string]string{
+			"MerchantRequestID":   merchantID,
+			"CheckoutRequestID":   checkoutID,
+			"ResponseCode":        "0",
+			"ResponseDescription": "Success. Request accepted for processing",
+			"CustomerMessage":     "Success. Request accepted for processing",
+		})
+	})
+
+	log.Printf("mpesa stand-in listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
+
+func sendCallback(req stkPushRequest, merchantID, checkoutID string, n int64) {
+	time.Sleep(*delay)
+
+	callback := map[string]any{
+		"MerchantRequestID": merchantID,
+		"CheckoutRequestID": checkoutID,
+		"ResultCode":        0,
+		"ResultDesc":        "The service request is processed successfully.",
+		"CallbackMetadata": map[string]any{
+			"Item": []map[string]any{
+				{"Name": "Amount", "Value": req.Amount},
+				{"Name": "MpesaReceiptNumber", "Value": fmt.Sprintf("STANDIN%04d", n)},
+				{"Name": "TransactionDate", "Value": time.Now().Format("20060102150405")},
+				{"Name": "PhoneNumber", "Value": req.PhoneNumber},
+			},
+		},
+	}
+	if *fail {
+		callback = map[string]any{
+			"MerchantRequestID": merchantID,
+			"CheckoutRequestID": checkoutID,
+			"ResultCode":        1032,
+			"ResultDesc":        "Request cancelled by user",
+		}
+	}
+
+	body, _ := json.Marshal(map[string]any{"Body": map[string]any{"stkCallback": callback}})
+	resp, err := http.Post(req.CallBackURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Printf("callback for %s failed: %v", checkoutID, err)
+		return
+	}
+	resp.Body.Close()
+
+	log.Printf("callback for %s sent: %s", checkoutID, resp.Status)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
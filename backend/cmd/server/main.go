@@ -9,9 +9,14 @@ import (
 	"time"
 
 	"github.com/EmilioCliff/jonche-med/internal/cache"
+	"github.com/EmilioCliff/jonche-med/internal/etims"
 	"github.com/EmilioCliff/jonche-med/internal/handlers"
+	"github.com/EmilioCliff/jonche-med/internal/insurance"
+	"github.com/EmilioCliff/jonche-med/internal/jobs"
+	"github.com/EmilioCliff/jonche-med/internal/mpesa"
 	"github.com/EmilioCliff/jonche-med/internal/postgres"
 	"github.com/EmilioCliff/jonche-med/internal/reports"
+	"github.com/EmilioCliff/jonche-med/internal/services"
 	"github.com/EmilioCliff/jonche-med/pkg"
 )
 
@@ -41,15 +46,59 @@ func main() {
 
 	// create services
 	cache := cache.NewCacheClient(config.REDIS_ADDRESS, config.REDIS_PASSWORD, 1)
-	report := reports.NewReportService(postgresRepo)
+	report := reports.NewReportService(config, postgresRepo)
+	mobileMoney := mpesa.NewMpesaClient(config)
+	insuranceClients := map[string]services.InsuranceClient{
+		insurance.STUB_CLIENT: insurance.NewStubClient(),
+	}
+	taxInvoices := etims.NewTaxInvoiceService(config, etims.NewEtimsClient(config), postgresRepo)
 
 	// start server
-	server := handlers.NewServer(config, tokenMaker, postgresRepo, cache, report)
+	server := handlers.NewServer(config, tokenMaker, postgresRepo, cache, report, mobileMoney, insuranceClients, taxInvoices)
 	log.Println("starting server at address: ", config.SERVER_ADDRESS)
 	if err := server.Start(); err != nil {
 		log.Fatalf("Error starting server: %v", err)
 	}
 
+	// start background jobs
+	scheduler := jobs.NewScheduler()
+	scheduler.Every("apply-price-changes", config.JOBS_INTERVAL, func(ctx context.Context) error {
+		applied, err := postgresRepo.ProductsRepository.ApplyDuePriceChanges(ctx)
+		if applied > 0 {
+			log.Printf("applied %d scheduled price changes", applied)
+		}
+		return err
+	})
+	scheduler.Every("expire-reservations", config.JOBS_INTERVAL, func(ctx context.Context) error {
+		expired, err := postgresRepo.ReservationRepository.ExpireDue(ctx)
+		if expired > 0 {
+			log.Printf("expired %d reservations", expired)
+		}
+		return err
+	})
+	scheduler.Every("expire-mpesa-payments", config.JOBS_INTERVAL, func(ctx context.Context) error {
+		expired, err := postgresRepo.PaymentRepository.ExpirePending(ctx)
+		if expired > 0 {
+			log.Printf("expired %d pending mpesa payments", expired)
+		}
+		return err
+	})
+	scheduler.Every("generate-purchase-orders", config.JOBS_INTERVAL, func(ctx context.Context) error {
+		orders, err := postgresRepo.PurchaseOrderRepository.GenerateDrafts(ctx)
+		if len(orders) > 0 {
+			log.Printf("drafted purchase orders for %d suppliers", len(orders))
+		}
+		return err
+	})
+	scheduler.Every("retry-tax-invoices", config.JOBS_INTERVAL, func(ctx context.Context) error {
+		signed, err := taxInvoices.RetryDue(ctx)
+		if signed > 0 {
+			log.Printf("signed %d queued tax invoices", signed)
+		}
+		return err
+	})
+	scheduler.Start()
+
 	<-quit
 
 	signal.Stop(quit)
@@ -62,6 +111,7 @@ func main() {
 		log.Fatalf("Error stopping server: %v", err)
 	}
 
+	scheduler.Stop()
 	store.CloseDB()
 
 	log.Println("Server shutdown ...")
@@ -0,0 +1,143 @@
+package classification
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	CLASS_A = "A"
+	CLASS_B = "B"
+	CLASS_C = "C"
+
+	CLASS_X = "X"
+	CLASS_Y = "Y"
+	CLASS_Z = "Z"
+
+	// A items make up the first 80% of consumption value and B items the next
+	// 15%; the remaining items are C items.
+	aValueShare = 0.80
+	bValueShare = 0.95
+
+	// X items' demand deviates from its mean by at most half the mean and Y
+	// items' by at most the mean; more erratic items are Z items.
+	xVariation = 0.5
+	yVariation = 1.0
+)
+
+// Item is the consumption of a single product over the classified period.
+type Item struct {
+	ID    int64
+	Value float64
+	// Demand holds the quantity demanded in each period of equal length, with
+	// periods without demand included as zeros.
+	Demand []float64
+}
+
+// Result is an item's classes along with the figures they were derived from.
+type Result struct {
+	ID    int64
+	Value float64
+	// Share is the item's part of the total consumption value and
+	// CumulativeShare the part of the items ranked up to and including it.
+	Share           float64
+	CumulativeShare float64
+	ABC             string
+
+	MeanDemand float64
+	// CoefficientOfVariation is the standard deviation of the demand divided by
+	// its mean. It and XYZ are nil when the item had no demand.
+	CoefficientOfVariation *float64
+	XYZ                    *string
+}
+
+func IsValidABC(class string) bool {
+	return class == CLASS_A || class == CLASS_B || class == CLASS_C
+}
+
+func IsValidXYZ(class string) bool {
+	return class == CLASS_X || class == CLASS_Y || class == CLASS_Z
+}
+
+// Classify ranks the items by consumption value, highest first, and classes them
+// by their share of the total value and the variability of their demand. An item
+// is an A or B item when the items ranked above it have not yet reached the
+// class's share, so the item crossing a boundary stays in the higher class.
+func Classify(items []Item) []Result {
+	ranked := make([]Item, len(items))
+	copy(ranked, items)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Value != ranked[j].Value {
+			return ranked[i].Value > ranked[j].Value
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+
+	var total float64
+	for _, item := range ranked {
+		total += max(item.Value, 0)
+	}
+
+	results := make([]Result, len(ranked))
+	var cumulative float64
+	for i, item := range ranked {
+		result := Result{
+			ID:    item.ID,
+			Value: item.Value,
+			ABC:   CLASS_C,
+		}
+
+		if total > 0 && item.Value > 0 {
+			previous := cumulative
+			result.Share = item.Value / total
+			cumulative += result.Share
+
+			switch {
+			case previous < aValueShare:
+				result.ABC = CLASS_A
+			case previous < bValueShare:
+				result.ABC = CLASS_B
+			}
+		}
+		result.CumulativeShare = cumulative
+
+		result.MeanDemand, result.CoefficientOfVariation = variation(item.Demand)
+		if result.CoefficientOfVariation != nil {
+			class := CLASS_Z
+			switch {
+			case *result.CoefficientOfVariation <= xVariation:
+				class = CLASS_X
+			case *result.CoefficientOfVariation <= yVariation:
+				class = CLASS_Y
+			}
+			result.XYZ = &class
+		}
+
+		results[i] = result
+	}
+
+	return results
+}
+
+func variation(demand []float64) (float64, *float64) {
+	if len(demand) == 0 {
+		return 0, nil
+	}
+
+	var sum float64
+	for _, d := range demand {
+		sum += d
+	}
+	mean := sum / float64(len(demand))
+	if mean <= 0 {
+		return mean, nil
+	}
+
+	var squares float64
+	for _, d := range demand {
+		squares += (d - mean) * (d - mean)
+	}
+	cv := math.Sqrt(squares/float64(len(demand))) / mean
+
+	return mean, &cv
+}
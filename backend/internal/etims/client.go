@@ -0,0 +1,261 @@
+package etims
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+var _ services.TaxDeviceClient = (*etimsClient)(nil)
+
+const (
+	saveSalesPath = "/trnsSales/saveSales"
+
+	// resultSuccess is the result code of an accepted request.
+	resultSuccess = "000"
+
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102150405"
+)
+
+// Tax type codes eTIMS reports amounts under.
+const (
+	taxTypeExempt    = "A"
+	taxTypeStandard  = "B"
+	taxTypeZeroRated = "C"
+	taxTypeReduced   = "E"
+)
+
+// etimsClient submits sales to KRA's eTIMS through the OSCU API. The base url is
+// configurable so the client can be pointed at the sandbox, production or a local
+// stand-in server.
+type etimsClient struct {
+	baseURL    string
+	pin        string
+	branchID   string
+	cmcKey     string
+	sdcID      string
+	receiptURL string
+	httpClient *http.Client
+}
+
+func NewEtimsClient(config pkg.Config) services.TaxDeviceClient {
+	return &etimsClient{
+		baseURL:    strings.TrimRight(config.ETIMS_BASE_URL, "/"),
+		pin:        config.ETIMS_PIN,
+		branchID:   config.ETIMS_BRANCH_ID,
+		cmcKey:     config.ETIMS_CMC_KEY,
+		sdcID:      config.ETIMS_SDC_ID,
+		receiptURL: config.ETIMS_RECEIPT_URL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type saveSalesItem struct {
+	ItemSeq  int     `json:"itemSeq"`
+	ItemCd   string  `json:"itemCd"`
+	ItemNm   string  `json:"itemNm"`
+	Qty      int64   `json:"qty"`
+	Prc      float64 `json:"prc"`
+	SplyAmt  float64 `json:"splyAmt"`
+	DcAmt    float64 `json:"dcAmt"`
+	TaxTyCd  string  `json:"taxTyCd"`
+	TaxblAmt float64 `json:"taxblAmt"`
+	TaxAmt   float64 `json:"taxAmt"`
+	TotAmt   float64 `json:"totAmt"`
+}
+
+type saveSalesBody struct {
+	Tin         string          `json:"tin"`
+	BhfID       string          `json:"bhfId"`
+	InvcNo      uint32          `json:"invcNo"`
+	OrgInvcNo   uint32          `json:"orgInvcNo"`
+	CustNm      string          `json:"custNm,omitempty"`
+	SalesTyCd   string          `json:"salesTyCd"`
+	RcptTyCd    string          `json:"rcptTyCd"`
+	PmtTyCd     string          `json:"pmtTyCd"`
+	SalesSttsCd string          `json:"salesSttsCd"`
+	CfmDt       string          `json:"cfmDt"`
+	SalesDt     string          `json:"salesDt"`
+	TotItemCnt  int             `json:"totItemCnt"`
+	TaxblAmtA   float64         `json:"taxblAmtA"`
+	TaxblAmtB   float64         `json:"taxblAmtB"`
+	TaxblAmtC   float64         `json:"taxblAmtC"`
+	TaxblAmtD   float64         `json:"taxblAmtD"`
+	TaxblAmtE   float64         `json:"taxblAmtE"`
+	TaxAmtA     float64         `json:"taxAmtA"`
+	TaxAmtB     float64         `json:"taxAmtB"`
+	TaxAmtC     float64         `json:"taxAmtC"`
+	TaxAmtD     float64         `json:"taxAmtD"`
+	TaxAmtE     float64         `json:"taxAmtE"`
+	TotTaxblAmt float64         `json:"totTaxblAmt"`
+	TotTaxAmt   float64         `json:"totTaxAmt"`
+	TotAmt      float64         `json:"totAmt"`
+	RegrNm      string          `json:"regrNm"`
+	ItemList    []saveSalesItem `json:"itemList"`
+}
+
+type saveSalesResult struct {
+	ResultCd  string `json:"resultCd"`
+	ResultMsg string `json:"resultMsg"`
+	ResultDt  string `json:"resultDt"`
+	Data      *struct {
+		CurRcptNo   int64  `json:"curRcptNo"`
+		TotRcptNo   int64  `json:"totRcptNo"`
+		IntrlData   string `json:"intrlData"`
+		RcptSign    string `json:"rcptSign"`
+		SdcDateTime string `json:"sdcDateTime"`
+	} `json:"data"`
+}
+
+func (c *etimsClient) SubmitInvoice(ctx context.Context, req *services.TaxInvoiceRequest) (*services.TaxInvoiceResponse, error) {
+	now := time.Now()
+	body := saveSalesBody{
+		Tin:         c.pin,
+		BhfID:       c.branchID,
+		InvcNo:      req.InvoiceNumber,
+		CustNm:      req.CustomerName,
+		SalesTyCd:   "N",
+		RcptTyCd:    "S",
+		PmtTyCd:     paymentTypeCode(req),
+		SalesSttsCd: "02",
+		CfmDt:       now.Format(dateTimeLayout),
+		SalesDt:     req.SaleDate.Format(dateLayout),
+		TotItemCnt:  len(req.Lines),
+		TotTaxblAmt: round(req.TotalTaxable),
+		TotTaxAmt:   round(req.TotalTax),
+		TotAmt:      round(req.TotalAmount),
+		RegrNm:      req.ReceiptNumber,
+		ItemList:    make([]saveSalesItem, len(req.Lines)),
+	}
+
+	for i, line := range req.Lines {
+		taxType := taxTypeCode(line.TaxType, line.TaxRate)
+		body.ItemList[i] = saveSalesItem{
+			ItemSeq:  line.Sequence,
+			ItemCd:   line.ItemCode,
+			ItemNm:   line.ItemName,
+			Qty:      line.Quantity,
+			Prc:      round(line.UnitPrice),
+			SplyAmt:  round(line.UnitPrice * float64(line.Quantity)),
+			DcAmt:    round(line.Discount),
+			TaxTyCd:  taxType,
+			TaxblAmt: round(line.TaxableAmount),
+			TaxAmt:   round(line.TaxAmount),
+			TotAmt:   round(line.TotalAmount),
+		}
+
+		// amounts are totalled per tax type as well as per line
+		switch taxType {
+		case taxTypeExempt:
+			body.TaxblAmtA += line.TaxableAmount
+			body.TaxAmtA += line.TaxAmount
+		case taxTypeStandard:
+			body.TaxblAmtB += line.TaxableAmount
+			body.TaxAmtB += line.TaxAmount
+		case taxTypeZeroRated:
+			body.TaxblAmtC += line.TaxableAmount
+			body.TaxAmtC += line.TaxAmount
+		case taxTypeReduced:
+			body.TaxblAmtE += line.TaxableAmount
+			body.TaxAmtE += line.TaxAmount
+		}
+	}
+	body.TaxblAmtA, body.TaxAmtA = round(body.TaxblAmtA), round(body.TaxAmtA)
+	body.TaxblAmtB, body.TaxAmtB = round(body.TaxblAmtB), round(body.TaxAmtB)
+	body.TaxblAmtC, body.TaxAmtC = round(body.TaxblAmtC), round(body.TaxAmtC)
+	body.TaxblAmtE, body.TaxAmtE = round(body.TaxblAmtE), round(body.TaxAmtE)
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to encode tax invoice: %s", err.Error())
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+saveSalesPath, bytes.NewReader(payload))
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to build tax invoice request: %s", err.Error())
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("tin", c.pin)
+	httpReq.Header.Set("bhfId", c.branchID)
+	httpReq.Header.Set("cmcKey", c.cmcKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to send tax invoice: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "tax authority unavailable (status %d)", resp.StatusCode)
+	}
+
+	var result saveSalesResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to decode tax invoice response: %s", err.Error())
+	}
+
+	if result.ResultCd != resultSuccess || result.Data == nil {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "tax invoice rejected (%s): %s", result.ResultCd, result.ResultMsg)
+	}
+
+	signedAt := now
+	if t, err := time.ParseInLocation(dateTimeLayout, result.Data.SdcDateTime, time.Local); err == nil {
+		signedAt = t
+	}
+
+	return &services.TaxInvoiceResponse{
+		ControlNumber:    fmt.Sprintf("%s/%d", c.sdcID, result.Data.TotRcptNo),
+		InternalData:     result.Data.IntrlData,
+		ReceiptSignature: result.Data.RcptSign,
+		QRCode:           c.receiptURL + "?Data=" + c.pin + c.branchID + result.Data.RcptSign,
+		SignedAt:         signedAt,
+	}, nil
+}
+
+// paymentTypeCode maps a sale's tenders to the eTIMS payment type.
+func paymentTypeCode(req *services.TaxInvoiceRequest) string {
+	if req.OnAccount {
+		return "02"
+	}
+
+	switch req.PaymentMethod {
+	case repository.PAYMENT_CASH:
+		return "01"
+	case repository.PAYMENT_CARD:
+		return "05"
+	case repository.PAYMENT_MPESA:
+		return "06"
+	default:
+		return "07"
+	}
+}
+
+// taxTypeCode maps a tax class to the eTIMS tax type. Standard rated classes
+// below the 16% rate are reported under the reduced rate.
+func taxTypeCode(taxType string, rate float64) string {
+	switch taxType {
+	case repository.TAX_EXEMPT:
+		return taxTypeExempt
+	case repository.TAX_ZERO_RATED:
+		return taxTypeZeroRated
+	default:
+		if rate > 0 && rate < 16 {
+			return taxTypeReduced
+		}
+		return taxTypeStandard
+	}
+}
+
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,171 @@
+package etims
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+func newTestStandin(t *testing.T) (*Standin, services.TaxDeviceClient) {
+	t.Helper()
+
+	standin := NewStandin()
+	server := httptest.NewServer(standin)
+	t.Cleanup(server.Close)
+
+	client := NewEtimsClient(pkg.Config{
+		ETIMS_BASE_URL:    server.URL + "/",
+		ETIMS_PIN:         "P051234567X",
+		ETIMS_BRANCH_ID:   "00",
+		ETIMS_CMC_KEY:     "cmc-key",
+		ETIMS_SDC_ID:      "KRACU0100000001",
+		ETIMS_RECEIPT_URL: "https://etims.example.com/receipt",
+	})
+
+	return standin, client
+}
+
+func testInvoiceRequest(invoiceNumber uint32) *services.TaxInvoiceRequest {
+	return &services.TaxInvoiceRequest{
+		InvoiceNumber: invoiceNumber,
+		ReceiptNumber: "RCP-000001",
+		SaleDate:      time.Now(),
+		PaymentMethod: repository.PAYMENT_CASH,
+		Lines: []*services.TaxInvoiceLine{
+			{
+				Sequence:      1,
+				ItemCode:      "1",
+				ItemName:      "Paracetamol 500mg",
+				Quantity:      2,
+				UnitPrice:     58,
+				TaxType:       repository.TAX_STANDARD,
+				TaxRate:       16,
+				TaxableAmount: 100,
+				TaxAmount:     16,
+				TotalAmount:   116,
+			},
+			{
+				Sequence:      2,
+				ItemCode:      "2",
+				ItemName:      "Amoxicillin 250mg",
+				Quantity:      1,
+				UnitPrice:     50,
+				TaxType:       repository.TAX_EXEMPT,
+				TaxableAmount: 50,
+				TotalAmount:   50,
+			},
+		},
+		TotalTaxable: 150,
+		TotalTax:     16,
+		TotalAmount:  166,
+	}
+}
+
+func TestSubmitInvoiceSigns(t *testing.T) {
+	standin, client := newTestStandin(t)
+
+	resp, err := client.SubmitInvoice(context.Background(), testInvoiceRequest(1))
+	if err != nil {
+		t.Fatalf("SubmitInvoice: %v", err)
+	}
+
+	if resp.ControlNumber != "KRACU0100000001/1" {
+		t.Errorf("control number %q, want KRACU0100000001/1", resp.ControlNumber)
+	}
+	if resp.InternalData == "" || resp.ReceiptSignature == "" {
+		t.Errorf("missing signature: %+v", resp)
+	}
+	if want := "https://etims.example.com/receipt?Data=P051234567X00" + resp.ReceiptSignature; resp.QRCode != want {
+		t.Errorf("qr code %q, want %q", resp.QRCode, want)
+	}
+	if resp.SignedAt.IsZero() {
+		t.Error("signed at is not set")
+	}
+	if n := standin.Signed(); n != 1 {
+		t.Errorf("stand-in signed %d invoices, want 1", n)
+	}
+}
+
+func TestSubmitInvoiceResubmissionIsIdempotent(t *testing.T) {
+	standin, client := newTestStandin(t)
+
+	first, err := client.SubmitInvoice(context.Background(), testInvoiceRequest(7))
+	if err != nil {
+		t.Fatalf("SubmitInvoice: %v", err)
+	}
+	again, err := client.SubmitInvoice(context.Background(), testInvoiceRequest(7))
+	if err != nil {
+		t.Fatalf("SubmitInvoice again: %v", err)
+	}
+
+	if again.ControlNumber != first.ControlNumber || again.ReceiptSignature != first.ReceiptSignature || again.InternalData != first.InternalData {
+		t.Errorf("resubmission signed differently: %+v, first %+v", again, first)
+	}
+	if n := standin.Signed(); n != 1 {
+		t.Errorf("stand-in signed %d invoices, want 1", n)
+	}
+
+	other, err := client.SubmitInvoice(context.Background(), testInvoiceRequest(8))
+	if err != nil {
+		t.Fatalf("SubmitInvoice other: %v", err)
+	}
+	if other.ControlNumber == first.ControlNumber {
+		t.Errorf("a different invoice got the same control number %q", other.ControlNumber)
+	}
+}
+
+func TestSubmitInvoiceUnavailableIsRetryable(t *testing.T) {
+	standin, client := newTestStandin(t)
+	standin.Down(time.Minute)
+
+	_, err := client.SubmitInvoice(context.Background(), testInvoiceRequest(1))
+	if err == nil {
+		t.Fatal("expected an error while the stand-in is down")
+	}
+	// only INVALID errors are treated as rejections, anything else is retried
+	if code := pkg.ErrorCode(err); code != pkg.INTERNAL_ERROR {
+		t.Errorf("error code %q, want %q", code, pkg.INTERNAL_ERROR)
+	}
+	if n := standin.Signed(); n != 0 {
+		t.Errorf("stand-in signed %d invoices while down", n)
+	}
+}
+
+func TestSubmitInvoiceRejected(t *testing.T) {
+	_, client := newTestStandin(t)
+
+	req := testInvoiceRequest(1)
+	req.TotalAmount = 200
+
+	_, err := client.SubmitInvoice(context.Background(), req)
+	if code := pkg.ErrorCode(err); code != pkg.INVALID_ERROR {
+		t.Fatalf("error code %q, want %q", code, pkg.INVALID_ERROR)
+	}
+	if !strings.Contains(pkg.ErrorMessage(err), "total amount") {
+		t.Errorf("unexpected rejection message: %s", pkg.ErrorMessage(err))
+	}
+}
+
+func TestTaxTypeCode(t *testing.T) {
+	tests := []struct {
+		taxType string
+		rate    float64
+		want    string
+	}{
+		{repository.TAX_STANDARD, 16, taxTypeStandard},
+		{repository.TAX_STANDARD, 8, taxTypeReduced},
+		{repository.TAX_ZERO_RATED, 0, taxTypeZeroRated},
+		{repository.TAX_EXEMPT, 0, taxTypeExempt},
+	}
+	for _, tt := range tests {
+		if got := taxTypeCode(tt.taxType, tt.rate); got != tt.want {
+			t.Errorf("taxTypeCode(%s, %v) = %s, want %s", tt.taxType, tt.rate, got, tt.want)
+		}
+	}
+}
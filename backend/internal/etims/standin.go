@@ -0,0 +1,136 @@
+package etims
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Standin is a local stand-in for the eTIMS OSCU API. It signs every valid sale
+// it is sent and returns the same signature when an invoice number is sent
+// again. It can be served from an httptest server in tests, or run on its own
+// through cmd/etims-standin, by pointing ETIMS_BASE_URL at it.
+type Standin struct {
+	mu        sync.Mutex
+	downUntil time.Time
+	receipts  map[string]*standinReceipt
+	next      int64
+}
+
+type standinReceipt struct {
+	RcptNo      int64  `json:"curRcptNo"`
+	TotRcptNo   int64  `json:"totRcptNo"`
+	IntrlData   string `json:"intrlData"`
+	RcptSign    string `json:"rcptSign"`
+	SdcDateTime string `json:"sdcDateTime"`
+}
+
+func NewStandin() *Standin {
+	return &Standin{
+		receipts: make(map[string]*standinReceipt),
+	}
+}
+
+// Down makes the stand-in answer every request with 503 Service Unavailable for d,
+// to exercise the retry queue.
+func (s *Standin) Down(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.downUntil = time.Now().Add(d)
+}
+
+// Signed returns how many distinct invoices the stand-in has signed.
+func (s *Standin) Signed() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.receipts)
+}
+
+func (s *Standin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != saveSalesPath {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Now().Before(s.downUntil) {
+		http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	var body saveSalesBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeResult(w, "900", "invalid request body: "+err.Error(), nil)
+		return
+	}
+
+	if reason := validateSale(r, &body); reason != "" {
+		writeResult(w, "910", reason, nil)
+		return
+	}
+
+	key := fmt.Sprintf("%s-%s-%d", body.Tin, body.BhfID, body.InvcNo)
+	receipt, ok := s.receipts[key]
+	if !ok {
+		s.next++
+		now := time.Now()
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%.2f|%d", key, s.next, body.TotAmt, now.UnixNano())))
+		sign := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+		receipt = &standinReceipt{
+			RcptNo:      s.next,
+			TotRcptNo:   s.next,
+			IntrlData:   sign[:26],
+			RcptSign:    sign[26:42],
+			SdcDateTime: now.Format(dateTimeLayout),
+		}
+		s.receipts[key] = receipt
+	}
+
+	writeResult(w, resultSuccess, "It is succeeded", receipt)
+}
+
+// validateSale applies the checks eTIMS rejects a sale for, returning the reason
+// or an empty string.
+func validateSale(r *http.Request, body *saveSalesBody) string {
+	switch {
+	case r.Header.Get("tin") == "" || r.Header.Get("bhfId") == "" || r.Header.Get("cmcKey") == "":
+		return "missing device credentials"
+	case body.Tin != r.Header.Get("tin"):
+		return "tin does not match the device"
+	case body.InvcNo == 0:
+		return "invoice number is required"
+	case len(body.ItemList) == 0 || body.TotItemCnt != len(body.ItemList):
+		return "item count does not match the item list"
+	}
+
+	var total float64
+	for _, item := range body.ItemList {
+		total += item.TotAmt
+	}
+	if math.Abs(total-body.TotAmt) > 0.01 {
+		return fmt.Sprintf("total amount %.2f does not match the items' total %.2f", body.TotAmt, total)
+	}
+
+	return ""
+}
+
+func writeResult(w http.ResponseWriter, code, message string, data *standinReceipt) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"resultCd":  code,
+		"resultMsg": message,
+		"resultDt":  time.Now().Format(dateTimeLayout),
+		"data":      data,
+	})
+}
@@ -0,0 +1,173 @@
+package etims
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+var _ services.TaxInvoiceService = (*transmitter)(nil)
+
+const (
+	// leaseDuration keeps an invoice from being sent twice at once. It is longer
+	// than the client's request timeout.
+	leaseDuration = 2 * time.Minute
+	// retryBatchSize is the most invoices retried in one run.
+	retryBatchSize = 20
+	maxRetryDelay  = time.Hour
+)
+
+// transmitter sends queued tax invoices through a TaxDeviceClient. Invoices that
+// fail while the endpoint is down are retried with a delay that doubles on each
+// attempt, starting from ETIMS_RETRY_INTERVAL.
+type transmitter struct {
+	retryInterval time.Duration
+	client        services.TaxDeviceClient
+	invoices      repository.TaxInvoiceRepository
+	sales         repository.SaleRepository
+	taxClasses    repository.TaxClassRepository
+}
+
+func NewTaxInvoiceService(config pkg.Config, client services.TaxDeviceClient, store *postgres.PostgresRepo) services.TaxInvoiceService {
+	return &transmitter{
+		retryInterval: config.ETIMS_RETRY_INTERVAL,
+		client:        client,
+		invoices:      store.TaxInvoiceRepository,
+		sales:         store.SalesRepository,
+		taxClasses:    store.TaxClassRepository,
+	}
+}
+
+func (t *transmitter) Transmit(ctx context.Context, saleID int64) (*repository.TaxInvoice, error) {
+	invoice, err := t.invoices.Lease(ctx, saleID, leaseDuration)
+	if err != nil {
+		return nil, err
+	}
+	// already signed, rejected, waiting for a retry or being sent by someone else
+	if invoice == nil {
+		return t.invoices.GetBySale(ctx, saleID)
+	}
+
+	return t.send(ctx, invoice)
+}
+
+func (t *transmitter) RetryDue(ctx context.Context) (int, error) {
+	invoices, err := t.invoices.LeaseDue(ctx, retryBatchSize, leaseDuration)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		signed  int
+		lastErr error
+	)
+	for _, invoice := range invoices {
+		sent, err := t.send(ctx, invoice)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if sent.Status == repository.TAX_INVOICE_SIGNED {
+			signed++
+		}
+	}
+
+	return signed, lastErr
+}
+
+// send submits a leased invoice and records the outcome.
+func (t *transmitter) send(ctx context.Context, invoice *repository.TaxInvoice) (*repository.TaxInvoice, error) {
+	req, err := t.invoiceRequest(ctx, int64(invoice.SaleID))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.SubmitInvoice(ctx, req)
+	if err != nil {
+		rejected := pkg.ErrorCode(err) == pkg.INVALID_ERROR
+		return t.invoices.Fail(ctx, int64(invoice.ID), pkg.ErrorMessage(err), rejected, time.Now().Add(t.retryDelay(invoice.Attempts)))
+	}
+
+	return t.invoices.Sign(ctx, int64(invoice.ID), &repository.TaxInvoiceSignature{
+		ControlNumber:    resp.ControlNumber,
+		InternalData:     resp.InternalData,
+		ReceiptSignature: resp.ReceiptSignature,
+		QRCode:           resp.QRCode,
+		SignedAt:         resp.SignedAt,
+	})
+}
+
+// retryDelay is the wait before the next attempt after the given number of
+// failed attempts.
+func (t *transmitter) retryDelay(attempts int32) time.Duration {
+	delay := t.retryInterval
+	for i := int32(1); i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxRetryDelay)
+}
+
+func (t *transmitter) invoiceRequest(ctx context.Context, saleID int64) (*services.TaxInvoiceRequest, error) {
+	sale, err := t.sales.GetByID(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	taxClasses, err := t.taxClasses.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taxTypes := make(map[string]string, len(taxClasses))
+	for _, taxClass := range taxClasses {
+		taxTypes[taxClass.Code] = taxClass.Type
+	}
+
+	req := &services.TaxInvoiceRequest{
+		InvoiceNumber: sale.ID,
+		ReceiptNumber: sale.ReceiptNumber,
+		SaleDate:      sale.CreatedAt,
+		OnAccount:     sale.OnAccount,
+		Lines:         make([]*services.TaxInvoiceLine, len(sale.Items)),
+		TotalTax:      sale.TotalTax,
+		TotalAmount:   sale.TotalAmount,
+		TotalTaxable:  sale.TotalAmount - sale.TotalTax,
+	}
+	if sale.CustomerName != nil {
+		req.CustomerName = *sale.CustomerName
+	}
+
+	for _, payment := range sale.Payments {
+		if payment.Status != repository.PAYMENT_COMPLETED {
+			continue
+		}
+		if req.PaymentMethod != "" && req.PaymentMethod != payment.Method {
+			req.PaymentMethod = ""
+			break
+		}
+		req.PaymentMethod = payment.Method
+	}
+
+	for i, item := range sale.Items {
+		req.Lines[i] = &services.TaxInvoiceLine{
+			Sequence:      i + 1,
+			ItemCode:      fmt.Sprintf("%d", item.ProductID),
+			ItemName:      item.ProductName,
+			Quantity:      item.Quantity,
+			UnitPrice:     item.UnitPrice,
+			Discount:      item.DiscountAmount,
+			TaxType:       taxTypes[item.TaxCode],
+			TaxRate:       item.TaxRate,
+			TaxableAmount: item.LineTotal - item.TaxAmount,
+			TaxAmount:     item.TaxAmount,
+			TotalAmount:   item.LineTotal,
+		}
+	}
+
+	return req, nil
+}
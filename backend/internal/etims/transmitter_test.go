@@ -0,0 +1,332 @@
+package etims
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+// memoryInvoices is an in-memory TaxInvoiceRepository with the leasing rules of
+// the postgres one: a lease counts an attempt and holds the invoice until the
+// lease ends.
+type memoryInvoices struct {
+	mu       sync.Mutex
+	invoices map[int64]*repository.TaxInvoice
+}
+
+var _ repository.TaxInvoiceRepository = (*memoryInvoices)(nil)
+
+func newMemoryInvoices(saleIDs ...int64) *memoryInvoices {
+	m := &memoryInvoices{invoices: make(map[int64]*repository.TaxInvoice)}
+	for _, saleID := range saleIDs {
+		m.invoices[saleID] = &repository.TaxInvoice{
+			ID:            uint32(saleID),
+			SaleID:        uint32(saleID),
+			Status:        repository.TAX_INVOICE_PENDING,
+			NextAttemptAt: time.Now(),
+			CreatedAt:     time.Now(),
+		}
+	}
+
+	return m
+}
+
+// get returns a copy of the sale's invoice.
+func (m *memoryInvoices) get(saleID int64) repository.TaxInvoice {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return *m.invoices[saleID]
+}
+
+// makeDue moves the sale's next attempt into the past, as if the retry delay
+// had passed.
+func (m *memoryInvoices) makeDue(saleID int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.invoices[saleID].NextAttemptAt = time.Now().Add(-time.Second)
+}
+
+func (m *memoryInvoices) GetBySale(_ context.Context, saleID int64) (*repository.TaxInvoice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	invoice, ok := m.invoices[saleID]
+	if !ok {
+		return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "tax invoice for sale %d not found", saleID)
+	}
+	copied := *invoice
+
+	return &copied, nil
+}
+
+func (m *memoryInvoices) List(context.Context, *repository.TaxInvoiceFilter) ([]*repository.TaxInvoice, *pkg.Pagination, error) {
+	return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "not implemented")
+}
+
+func (m *memoryInvoices) Lease(_ context.Context, saleID int64, leaseFor time.Duration) (*repository.TaxInvoice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	invoice, ok := m.invoices[saleID]
+	if !ok || !m.due(invoice) {
+		return nil, nil
+	}
+
+	return m.lease(invoice, leaseFor), nil
+}
+
+func (m *memoryInvoices) LeaseDue(_ context.Context, limit int32, leaseFor time.Duration) ([]*repository.TaxInvoice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	leased := []*repository.TaxInvoice{}
+	for _, invoice := range m.invoices {
+		if int32(len(leased)) == limit {
+			break
+		}
+		if m.due(invoice) {
+			leased = append(leased, m.lease(invoice, leaseFor))
+		}
+	}
+
+	return leased, nil
+}
+
+func (m *memoryInvoices) due(invoice *repository.TaxInvoice) bool {
+	return invoice.Status == repository.TAX_INVOICE_PENDING && !invoice.NextAttemptAt.After(time.Now())
+}
+
+func (m *memoryInvoices) lease(invoice *repository.TaxInvoice, leaseFor time.Duration) *repository.TaxInvoice {
+	invoice.Attempts++
+	invoice.NextAttemptAt = time.Now().Add(leaseFor)
+	copied := *invoice
+
+	return &copied
+}
+
+func (m *memoryInvoices) Sign(_ context.Context, id int64, signature *repository.TaxInvoiceSignature) (*repository.TaxInvoice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	invoice := m.invoices[id]
+	invoice.Status = repository.TAX_INVOICE_SIGNED
+	invoice.LastError = nil
+	invoice.ControlNumber = &signature.ControlNumber
+	invoice.InternalData = &signature.InternalData
+	invoice.ReceiptSignature = &signature.ReceiptSignature
+	invoice.QRCode = &signature.QRCode
+	invoice.SignedAt = &signature.SignedAt
+	copied := *invoice
+
+	return &copied, nil
+}
+
+func (m *memoryInvoices) Fail(_ context.Context, id int64, reason string, rejected bool, retryAt time.Time) (*repository.TaxInvoice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	invoice := m.invoices[id]
+	if rejected {
+		invoice.Status = repository.TAX_INVOICE_REJECTED
+	}
+	invoice.LastError = &reason
+	invoice.NextAttemptAt = retryAt
+	copied := *invoice
+
+	return &copied, nil
+}
+
+func (m *memoryInvoices) Requeue(_ context.Context, saleID int64) (*repository.TaxInvoice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	invoice, ok := m.invoices[saleID]
+	if !ok || invoice.Status == repository.TAX_INVOICE_SIGNED {
+		return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "no unsigned tax invoice for sale %d", saleID)
+	}
+	invoice.Status = repository.TAX_INVOICE_PENDING
+	invoice.NextAttemptAt = time.Now()
+	copied := *invoice
+
+	return &copied, nil
+}
+
+// testSales serves fixed sales; the methods the transmitter does not use are
+// left to the embedded nil interface.
+type testSales struct {
+	repository.SaleRepository
+}
+
+func (testSales) GetByID(_ context.Context, id int64) (*repository.Sale, error) {
+	return &repository.Sale{
+		ID:            uint32(id),
+		ReceiptNumber: "RCP-000001",
+		TotalAmount:   116,
+		TotalTax:      16,
+		CreatedAt:     time.Now(),
+		Items: []*repository.SaleItem{
+			{
+				ProductID:   1,
+				ProductName: "Paracetamol 500mg",
+				Quantity:    2,
+				UnitPrice:   58,
+				LineTotal:   116,
+				TaxCode:     "B",
+				TaxRate:     16,
+				TaxAmount:   16,
+			},
+		},
+		Payments: []*repository.Payment{
+			{Method: repository.PAYMENT_CASH, Amount: 116, Status: repository.PAYMENT_COMPLETED},
+		},
+	}, nil
+}
+
+type testTaxClasses struct {
+	repository.TaxClassRepository
+}
+
+func (testTaxClasses) List(context.Context) ([]*repository.TaxClass, error) {
+	return []*repository.TaxClass{{Code: "B", Type: repository.TAX_STANDARD, Rate: 16}}, nil
+}
+
+func newTestTransmitter(t *testing.T, saleIDs ...int64) (*transmitter, *Standin, *memoryInvoices) {
+	t.Helper()
+
+	standin, client := newTestStandin(t)
+	invoices := newMemoryInvoices(saleIDs...)
+
+	return &transmitter{
+		retryInterval: time.Minute,
+		client:        client,
+		invoices:      invoices,
+		sales:         testSales{},
+		taxClasses:    testTaxClasses{},
+	}, standin, invoices
+}
+
+func TestTransmitSigns(t *testing.T) {
+	tr, standin, _ := newTestTransmitter(t, 1)
+
+	invoice, err := tr.Transmit(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Transmit: %v", err)
+	}
+	if invoice.Status != repository.TAX_INVOICE_SIGNED {
+		t.Fatalf("status %s, want %s", invoice.Status, repository.TAX_INVOICE_SIGNED)
+	}
+	if invoice.ControlNumber == nil || *invoice.ControlNumber == "" || invoice.Attempts != 1 {
+		t.Errorf("unexpected signed invoice: %+v", invoice)
+	}
+
+	// a signed invoice is not sent again
+	again, err := tr.Transmit(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Transmit again: %v", err)
+	}
+	if again.Attempts != 1 || standin.Signed() != 1 {
+		t.Errorf("signed invoice was sent again: attempts %d, signed %d", again.Attempts, standin.Signed())
+	}
+}
+
+func TestTransmitQueuesRetryWhileDown(t *testing.T) {
+	tr, standin, invoices := newTestTransmitter(t, 1)
+	standin.Down(time.Hour)
+
+	invoice, err := tr.Transmit(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Transmit: %v", err)
+	}
+	if invoice.Status != repository.TAX_INVOICE_PENDING || invoice.LastError == nil {
+		t.Fatalf("failed invoice should stay pending with its error: %+v", invoice)
+	}
+	if wait := time.Until(invoice.NextAttemptAt); wait < 50*time.Second || wait > time.Minute {
+		t.Errorf("first retry in %s, want about a minute", wait)
+	}
+
+	// nothing is due before the retry delay has passed
+	signed, err := tr.RetryDue(context.Background())
+	if err != nil || signed != 0 {
+		t.Fatalf("RetryDue before the delay = %d, %v", signed, err)
+	}
+	if got := invoices.get(1); got.Attempts != 1 {
+		t.Errorf("invoice retried before it was due: %d attempts", got.Attempts)
+	}
+
+	// a second failure doubles the delay
+	invoices.makeDue(1)
+	if _, err := tr.RetryDue(context.Background()); err != nil {
+		t.Fatalf("RetryDue while down: %v", err)
+	}
+	got := invoices.get(1)
+	if wait := time.Until(got.NextAttemptAt); got.Attempts != 2 || wait < 110*time.Second || wait > 2*time.Minute {
+		t.Errorf("second retry after %d attempts in %s, want about two minutes", got.Attempts, wait)
+	}
+
+	standin.Down(0)
+	invoices.makeDue(1)
+	signed, err = tr.RetryDue(context.Background())
+	if err != nil {
+		t.Fatalf("RetryDue: %v", err)
+	}
+	if signed != 1 {
+		t.Fatalf("RetryDue signed %d invoices, want 1", signed)
+	}
+	if got := invoices.get(1); got.Status != repository.TAX_INVOICE_SIGNED || got.Attempts != 3 {
+		t.Errorf("unexpected invoice after retry: %+v", got)
+	}
+	if n := standin.Signed(); n != 1 {
+		t.Errorf("stand-in signed %d invoices, want 1", n)
+	}
+}
+
+func TestRetryResubmissionKeepsSignature(t *testing.T) {
+	tr, standin, invoices := newTestTransmitter(t, 1)
+
+	first, err := tr.Transmit(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Transmit: %v", err)
+	}
+
+	// the authority signed the invoice but the response was lost, so it is still
+	// pending and sent again
+	invoices.mu.Lock()
+	invoices.invoices[1].Status = repository.TAX_INVOICE_PENDING
+	invoices.invoices[1].NextAttemptAt = time.Now().Add(-time.Second)
+	invoices.mu.Unlock()
+
+	signed, err := tr.RetryDue(context.Background())
+	if err != nil || signed != 1 {
+		t.Fatalf("RetryDue = %d, %v", signed, err)
+	}
+
+	again := invoices.get(1)
+	if *again.ControlNumber != *first.ControlNumber || *again.ReceiptSignature != *first.ReceiptSignature {
+		t.Errorf("resubmission signed as %s/%s, first %s/%s", *again.ControlNumber, *again.ReceiptSignature, *first.ControlNumber, *first.ReceiptSignature)
+	}
+	if n := standin.Signed(); n != 1 {
+		t.Errorf("stand-in signed %d invoices, want 1", n)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tr := &transmitter{retryInterval: time.Minute}
+
+	tests := map[int32]time.Duration{
+		1:  time.Minute,
+		2:  2 * time.Minute,
+		3:  4 * time.Minute,
+		10: maxRetryDelay,
+	}
+	for attempts, want := range tests {
+		if got := tr.retryDelay(attempts); got != want {
+			t.Errorf("retryDelay(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
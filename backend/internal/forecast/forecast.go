@@ -0,0 +1,193 @@
+package forecast
+
+import (
+	"math"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+const (
+	METHOD_MOVING_AVERAGE        = "MOVING_AVERAGE"
+	METHOD_EXPONENTIAL_SMOOTHING = "EXPONENTIAL_SMOOTHING"
+
+	// movingAverageWindow is the number of most recent days averaged.
+	movingAverageWindow = 28
+
+	// seasonLength is the weekly cycle daily demand repeats on. Exponential
+	// smoothing needs two full seasons of history to estimate it.
+	seasonLength = 7
+
+	// alpha and gamma are the smoothing weights of the level and the day of
+	// week effects. Higher weights follow recent demand more closely.
+	alpha = 0.3
+	gamma = 0.2
+
+	// z95 is the normal quantile of the 95% confidence band.
+	z95 = 1.96
+)
+
+// Point is the forecast demand of a single day.
+type Point struct {
+	Date     time.Time
+	Quantity float64
+	Lower    float64
+	Upper    float64
+}
+
+// Result is the forecast of a single product. Bands are 95% confidence bands
+// derived from the one day ahead errors the method made on the history.
+type Result struct {
+	// Method is the method used, which differs from the one asked for when the
+	// history was too short for it.
+	Method     string
+	Points     []Point
+	Total      float64
+	TotalLower float64
+	TotalUpper float64
+	// ErrorStdDev is the standard deviation of the one day ahead errors.
+	ErrorStdDev float64
+}
+
+type Forecaster struct {
+	method string
+}
+
+func NewForecaster(method string) (*Forecaster, error) {
+	if !IsValidMethod(method) {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "unsupported forecast method: %s", method)
+	}
+
+	return &Forecaster{method: method}, nil
+}
+
+func IsValidMethod(method string) bool {
+	return method == METHOD_MOVING_AVERAGE || method == METHOD_EXPONENTIAL_SMOOTHING
+}
+
+func (f *Forecaster) Method() string {
+	return f.method
+}
+
+// Forecast projects demand for the horizon days after lastDay. history holds the
+// quantity demanded on each day, oldest first, ending on lastDay; days without
+// demand must be included as zeros.
+func (f *Forecaster) Forecast(history []float64, lastDay time.Time, horizon int) *Result {
+	if f.method == METHOD_EXPONENTIAL_SMOOTHING && len(history) >= 2*seasonLength {
+		return seasonalSmoothing(history, lastDay, horizon)
+	}
+
+	return movingAverage(history, lastDay, horizon)
+}
+
+func movingAverage(history []float64, lastDay time.Time, horizon int) *Result {
+	// each day is predicted from the window of days before it
+	var squaredErrors float64
+	var errorCount int
+	for t := 1; t < len(history); t++ {
+		e := history[t] - mean(history[max(0, t-movingAverageWindow):t])
+		squaredErrors += e * e
+		errorCount++
+	}
+
+	level := mean(history[max(0, len(history)-movingAverageWindow):])
+	sigma := stdDev(squaredErrors, errorCount)
+
+	result := &Result{Method: METHOD_MOVING_AVERAGE, ErrorStdDev: sigma}
+	var variance float64
+	for k := 1; k <= horizon; k++ {
+		result.Points = append(result.Points, point(lastDay.AddDate(0, 0, k), level, sigma))
+		variance += sigma * sigma
+	}
+	result.finish(variance)
+
+	return result
+}
+
+// seasonalSmoothing is additive exponential smoothing with a weekly season: a
+// smoothed level of demand plus a smoothed effect for each day of the week.
+func seasonalSmoothing(history []float64, lastDay time.Time, horizon int) *Result {
+	// the first week sets the starting level and day of week effects
+	level := mean(history[:seasonLength])
+	seasonal := make([]float64, seasonLength)
+	for i := range seasonLength {
+		seasonal[i] = history[i] - level
+	}
+
+	var squaredErrors float64
+	var errorCount int
+	for t := seasonLength; t < len(history); t++ {
+		i := t % seasonLength
+		e := history[t] - (level + seasonal[i])
+		squaredErrors += e * e
+		errorCount++
+
+		newLevel := alpha*(history[t]-seasonal[i]) + (1-alpha)*level
+		seasonal[i] = gamma*(history[t]-newLevel) + (1-gamma)*seasonal[i]
+		level = newLevel
+	}
+
+	sigma := stdDev(squaredErrors, errorCount)
+
+	result := &Result{Method: METHOD_EXPONENTIAL_SMOOTHING, ErrorStdDev: sigma}
+	var variance float64
+	for k := 1; k <= horizon; k++ {
+		i := (len(history) - 1 + k) % seasonLength
+		// uncertainty about the level grows the further ahead the day is
+		daySigma := sigma * math.Sqrt(1+float64(k-1)*alpha*alpha)
+		result.Points = append(result.Points, point(lastDay.AddDate(0, 0, k), level+seasonal[i], daySigma))
+		variance += daySigma * daySigma
+	}
+	result.finish(variance)
+
+	return result
+}
+
+func point(date time.Time, quantity, sigma float64) Point {
+	// demand cannot be negative
+	quantity = math.Max(quantity, 0)
+
+	return Point{
+		Date:     date,
+		Quantity: round(quantity),
+		Lower:    round(math.Max(quantity-z95*sigma, 0)),
+		Upper:    round(quantity + z95*sigma),
+	}
+}
+
+// finish totals the points. Daily errors are taken as independent, so the
+// variance of the total is the sum of the daily variances.
+func (r *Result) finish(variance float64) {
+	for _, p := range r.Points {
+		r.Total += p.Quantity
+	}
+	r.Total = round(r.Total)
+	r.TotalLower = round(math.Max(r.Total-z95*math.Sqrt(variance), 0))
+	r.TotalUpper = round(r.Total + z95*math.Sqrt(variance))
+	r.ErrorStdDev = round(r.ErrorStdDev)
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
+
+func stdDev(squaredErrors float64, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+
+	return math.Sqrt(squaredErrors / float64(count))
+}
+
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
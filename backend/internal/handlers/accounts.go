@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type accountPaymentRequest struct {
+	Method    string  `json:"method" binding:"required,oneof=CASH CARD INSURANCE"`
+	Amount    float64 `json:"amount" binding:"required,gt=0"`
+	Reference *string `json:"reference"`
+}
+
+func (s *Server) getCustomerAccountHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid customer ID: %s", err.Error())))
+		return
+	}
+
+	account, err := s.repo.CustomerAccountRepository.GetAccount(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": account})
+}
+
+func (s *Server) recordAccountPaymentHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid customer ID: %s", err.Error())))
+		return
+	}
+
+	var req accountPaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	payments, err := s.repo.CustomerAccountRepository.RecordPayment(ctx, &repository.AccountPayment{
+		CustomerID: uint32(id),
+		Method:     req.Method,
+		Amount:     req.Amount,
+		Reference:  req.Reference,
+		ReceivedBy: payload.UserID,
+	})
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	account, err := s.repo.CustomerAccountRepository.GetAccount(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": payments, "account": account})
+}
+
+func (s *Server) getCustomerStatementHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid customer ID: %s", err.Error())))
+		return
+	}
+
+	// defaults to the current month
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	endDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+
+	if fromStr := ctx.Query("from"); fromStr != "" {
+		from, err := pkg.StringToTime(fromStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid start date: %s", err.Error())))
+			return
+		}
+		startDate = from
+	}
+
+	if toStr := ctx.Query("to"); toStr != "" {
+		to, err := pkg.StringToTime(toStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid end date: %s", err.Error())))
+			return
+		}
+		endDate = to.Add(time.Hour * 24)
+	}
+
+	if !endDate.After(startDate) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "end date must not be before start date")))
+		return
+	}
+
+	format := ctx.DefaultQuery("format", services.REPORT_FORMAT_PDF)
+	if format != "json" && format != services.REPORT_FORMAT_PDF && format != services.REPORT_FORMAT_TEXT {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid format: %s", format)))
+		return
+	}
+
+	statement, err := s.repo.CustomerAccountRepository.GetStatement(ctx, id, startDate, endDate)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if format == "json" {
+		ctx.JSON(http.StatusOK, gin.H{"data": statement})
+		return
+	}
+
+	document, err := s.report.CustomerStatement(statement, format)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if format == services.REPORT_FORMAT_TEXT {
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", document)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=statement-%d-%s.pdf", id, startDate.Format("2006-01-02")))
+	ctx.Data(http.StatusOK, "application/pdf", document)
+}
+
+func (s *Server) getAgeingReportHandler(ctx *gin.Context) {
+	asOf := time.Now()
+	if asOfStr := ctx.Query("as_of"); asOfStr != "" {
+		date, err := pkg.StringToTime(asOfStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid as_of date: %s", err.Error())))
+			return
+		}
+		// include the whole of the given day
+		asOf = date.Add(time.Hour * 24)
+	}
+
+	var customerID *uint32
+	if customerIDStr := ctx.Query("customer_id"); customerIDStr != "" {
+		id, err := pkg.StringToInt64(customerIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid customer ID: %s", err.Error())))
+			return
+		}
+		cid := uint32(id)
+		customerID = &cid
+	}
+
+	format := ctx.DefaultQuery("format", "json")
+	if format != "json" && format != services.REPORT_FORMAT_PDF && format != services.REPORT_FORMAT_TEXT {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid format: %s", format)))
+		return
+	}
+
+	report, err := s.repo.CustomerAccountRepository.GetAgeingReport(ctx, asOf, customerID)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if format == "json" {
+		ctx.JSON(http.StatusOK, gin.H{"data": report})
+		return
+	}
+
+	document, err := s.report.AgeingReport(report, format)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if format == services.REPORT_FORMAT_TEXT {
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", document)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=ageing-%s.pdf", asOf.Format("2006-01-02")))
+	ctx.Data(http.StatusOK, "application/pdf", document)
+}
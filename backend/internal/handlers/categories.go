@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type createCategoryRequest struct {
+	Name     string  `json:"name" binding:"required"`
+	ParentID *uint32 `json:"parent_id"`
+}
+
+func (s *Server) createCategoryHandler(ctx *gin.Context) {
+	var req createCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	category, err := s.repo.CategoryRepository.Create(ctx, &repository.Category{
+		Name:     req.Name,
+		ParentID: req.ParentID,
+	})
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": category})
+}
+
+func (s *Server) getCategoryHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid category ID: %s", err.Error())))
+		return
+	}
+
+	category, err := s.repo.CategoryRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": category})
+}
+
+func (s *Server) updateCategoryHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid category ID: %s", err.Error())))
+		return
+	}
+
+	var req repository.CategoryUpdate
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	if req.Name != nil && *req.Name == "" {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "name cannot be empty")))
+		return
+	}
+
+	category, err := s.repo.CategoryRepository.Update(ctx, id, &req)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": category})
+}
+
+func (s *Server) deleteCategoryHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid category ID: %s", err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	if payload.Role != repository.ADMIN_ROLE {
+		ctx.JSON(http.StatusForbidden, errorResponse(pkg.Errorf(pkg.FORBIDDEN_ERROR, "only admin users can delete categories")))
+		return
+	}
+
+	var moveTo *uint32
+	if moveToStr := ctx.Query("move_to"); moveToStr != "" {
+		moveToID, err := pkg.StringToInt64(moveToStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid move_to category ID: %s", err.Error())))
+			return
+		}
+		v := uint32(moveToID)
+		moveTo = &v
+	}
+
+	if err := s.repo.CategoryRepository.Delete(ctx, id, moveTo); err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": "category deleted successfully"})
+}
+
+func (s *Server) listCategoriesHandler(ctx *gin.Context) {
+	categories, err := s.repo.CategoryRepository.List(ctx)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": categories})
+}
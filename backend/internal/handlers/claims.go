@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type createClaimBatchRequest struct {
+	InsurerID uint32 `json:"insurer_id" binding:"required"`
+	From      string `json:"from" binding:"required"`
+	To        string `json:"to" binding:"required"`
+}
+
+func (s *Server) createClaimBatchHandler(ctx *gin.Context) {
+	var req createClaimBatchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	from, err := pkg.StringToTime(req.From)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid from date: %s", err.Error())))
+		return
+	}
+	to, err := pkg.StringToTime(req.To)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid to date: %s", err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	batch, err := s.repo.ClaimRepository.CreateBatch(ctx, &repository.ClaimBatch{
+		InsurerID:   req.InsurerID,
+		PeriodStart: from,
+		PeriodEnd:   to.Add(time.Hour * 24),
+		CreatedBy:   payload.UserID,
+	})
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": batch})
+}
+
+func (s *Server) getClaimBatchHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid claim batch ID: %s", err.Error())))
+		return
+	}
+
+	batch, err := s.repo.ClaimRepository.GetBatch(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": batch})
+}
+
+func (s *Server) listClaimBatchesHandler(ctx *gin.Context) {
+	pageNoStr := ctx.DefaultQuery("page", "1")
+	pageNo, err := pkg.StringToInt64(pageNoStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	pageSizeStr := ctx.DefaultQuery("limit", "10")
+	pageSize, err := pkg.StringToInt64(pageSizeStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	filter := &repository.ClaimBatchFilter{
+		Pagination: &pkg.Pagination{
+			Page:     uint32(pageNo),
+			PageSize: uint32(pageSize),
+		},
+		InsurerID: nil,
+		Status:    nil,
+	}
+
+	if insurerIDStr := ctx.Query("insurer_id"); insurerIDStr != "" {
+		insurerID, err := pkg.StringToInt64(insurerIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid insurer ID: %s", err.Error())))
+
+			return
+		}
+		iid := uint32(insurerID)
+		filter.InsurerID = &iid
+	}
+
+	if status := ctx.Query("status"); status != "" {
+		status = strings.ToUpper(status)
+		filter.Status = &status
+	}
+
+	batches, pagination, err := s.repo.ClaimRepository.ListBatches(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       batches,
+		"pagination": pagination,
+	})
+}
+
+func (s *Server) submitClaimBatchHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid claim batch ID: %s", err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	batch, err := s.repo.ClaimRepository.GetBatch(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if batch.Status != repository.CLAIM_BATCH_DRAFT {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "claim batch %s has already been submitted", batch.BatchNumber)))
+		return
+	}
+
+	client, err := s.insuranceClient(batch.InsurerClient)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	submission := &services.ClaimSubmission{
+		InsurerCode: batch.InsurerCode,
+		BatchNumber: batch.BatchNumber,
+		PeriodStart: batch.PeriodStart,
+		PeriodEnd:   batch.PeriodEnd,
+		Claims:      make([]*services.ClaimLine, len(batch.Claims)),
+	}
+	for i, claim := range batch.Claims {
+		submission.Claims[i] = &services.ClaimLine{
+			ClaimID:       claim.ID,
+			ReceiptNumber: claim.ReceiptNumber,
+			SaleDate:      claim.SaleDate,
+			SchemeName:    claim.SchemeName,
+			MemberNumber:  claim.MemberNumber,
+			Amount:        claim.Amount,
+		}
+		if claim.CustomerName != nil {
+			submission.Claims[i].PatientName = *claim.CustomerName
+		}
+	}
+
+	result, err := client.SubmitClaims(ctx, submission)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	submittedBatch, err := s.repo.ClaimRepository.MarkSubmitted(ctx, id, &result.Reference, payload.UserID)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": submittedBatch})
+}
+
+// refreshClaimBatchHandler asks the insurer for its decisions on a submitted batch.
+func (s *Server) refreshClaimBatchHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid claim batch ID: %s", err.Error())))
+		return
+	}
+
+	batch, err := s.repo.ClaimRepository.GetBatch(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if batch.Status != repository.CLAIM_BATCH_SUBMITTED || batch.ExternalReference == nil {
+		ctx.JSON(http.StatusOK, gin.H{"data": batch})
+		return
+	}
+
+	client, err := s.insuranceClient(batch.InsurerClient)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	statuses, err := client.GetClaimStatuses(ctx, batch.InsurerCode, *batch.ExternalReference)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	decisions := make([]*repository.ClaimDecision, len(statuses))
+	for i, status := range statuses {
+		decisions[i] = &repository.ClaimDecision{
+			ClaimID:        status.ClaimID,
+			Status:         status.Status,
+			ApprovedAmount: status.ApprovedAmount,
+		}
+		if status.RejectionReason != "" {
+			decisions[i].RejectionReason = &status.RejectionReason
+		}
+		if status.Reference != "" {
+			decisions[i].ExternalReference = &status.Reference
+		}
+	}
+
+	updatedBatch, err := s.repo.ClaimRepository.Decide(ctx, id, decisions)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": updatedBatch})
+}
+
+type claimDecisionRequest struct {
+	Status          string  `json:"status" binding:"required,oneof=APPROVED REJECTED"`
+	ApprovedAmount  float64 `json:"approved_amount" binding:"gte=0"`
+	RejectionReason *string `json:"rejection_reason"`
+	Reference       *string `json:"reference"`
+}
+
+// decideClaimHandler records a decision the insurer gave outside its client, e.g.
+// on a remittance advice.
+func (s *Server) decideClaimHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid claim batch ID: %s", err.Error())))
+		return
+	}
+
+	claimID, err := pkg.StringToInt64(ctx.Param("claimId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid claim ID: %s", err.Error())))
+		return
+	}
+
+	var req claimDecisionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	if payload.Role != repository.ADMIN_ROLE {
+		ctx.JSON(http.StatusForbidden, errorResponse(pkg.Errorf(pkg.FORBIDDEN_ERROR, "only admin users can record claim decisions")))
+		return
+	}
+
+	batch, err := s.repo.ClaimRepository.Decide(ctx, id, []*repository.ClaimDecision{{
+		ClaimID:           uint32(claimID),
+		Status:            req.Status,
+		ApprovedAmount:    req.ApprovedAmount,
+		RejectionReason:   req.RejectionReason,
+		ExternalReference: req.Reference,
+	}})
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": batch})
+}
+
+// insuranceClient returns the client an insurer is configured to submit claims through.
+func (s *Server) insuranceClient(name string) (services.InsuranceClient, error) {
+	client, ok := s.insuranceClients[name]
+	if !ok {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "insurance client %s is not configured", name)
+	}
+
+	return client, nil
+}
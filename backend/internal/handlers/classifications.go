@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+// classifyProductsHandler runs the ABC/XYZ classification over the period given
+// by the from and to dates and stores the classes on the products.
+func (s *Server) classifyProductsHandler(ctx *gin.Context) {
+	filter := &repository.ClassificationFilter{}
+
+	if fromStr := ctx.Query("from"); fromStr != "" {
+		startDate, err := pkg.StringToTime(fromStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid start date: %s", err.Error())))
+			return
+		}
+		filter.StartDate = &startDate
+	}
+
+	if toStr := ctx.Query("to"); toStr != "" {
+		endDate, err := pkg.StringToTime(toStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid end date: %s", err.Error())))
+			return
+		}
+		endDate = endDate.Add(time.Hour * 24)
+		filter.EndDate = &endDate
+	}
+
+	report, err := s.repo.ClassificationRepository.Classify(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": report})
+}
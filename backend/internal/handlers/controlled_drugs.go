@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+// witnessRequest carries the credentials of the second user confirming a movement
+// of a controlled product.
+type witnessRequest struct {
+	WitnessEmail    *string `json:"witness_email"`
+	WitnessPassword string  `json:"witness_password"`
+}
+
+// verifyWitness checks the witness credentials and role and returns the witness
+// user ID, or nil when no witness was given. Only pharmacists and admins can
+// witness.
+func (s *Server) verifyWitness(ctx *gin.Context, performedBy uint32, req witnessRequest) (*uint32, error) {
+	if req.WitnessEmail == nil || *req.WitnessEmail == "" {
+		return nil, nil
+	}
+
+	witness, hashPass, _, err := s.repo.UserRepository.GetUserInternalByEmail(ctx, *req.WitnessEmail)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.AUTHENTICATION_ERROR, "invalid witness email or password")
+	}
+
+	if err := pkg.ComparePasswordAndHash(hashPass, req.WitnessPassword); err != nil {
+		return nil, pkg.Errorf(pkg.AUTHENTICATION_ERROR, "invalid witness email or password")
+	}
+
+	if witness.Role != repository.PHARMACIST_ROLE && witness.Role != repository.ADMIN_ROLE {
+		return nil, pkg.Errorf(pkg.FORBIDDEN_ERROR, "the witness must be a pharmacist or an admin")
+	}
+
+	if witness.ID == performedBy {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "the witness must be a different user")
+	}
+
+	return &witness.ID, nil
+}
+
+func (s *Server) getControlledDrugRegisterHandler(ctx *gin.Context) {
+	filter := &repository.ControlledDrugFilter{}
+
+	if productIDStr := ctx.Query("product_id"); productIDStr != "" {
+		productID, err := pkg.StringToInt64(productIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid product ID: %s", err.Error())))
+			return
+		}
+		pid := uint32(productID)
+		filter.ProductID = &pid
+	}
+
+	if fromStr := ctx.Query("from"); fromStr != "" {
+		startDate, err := pkg.StringToTime(fromStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid start date: %s", err.Error())))
+			return
+		}
+		filter.StartDate = &startDate
+	}
+
+	if toStr := ctx.Query("to"); toStr != "" {
+		endDate, err := pkg.StringToTime(toStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid end date: %s", err.Error())))
+			return
+		}
+		endDate = endDate.Add(time.Hour * 24)
+		filter.EndDate = &endDate
+	}
+
+	format := ctx.DefaultQuery("format", "json")
+	if format != "json" && format != services.REPORT_FORMAT_PDF && format != services.REPORT_FORMAT_TEXT {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid format: %s", format)))
+		return
+	}
+
+	report, err := s.repo.ProductsRepository.GetControlledDrugRegister(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if format == "json" {
+		ctx.JSON(http.StatusOK, gin.H{"data": report})
+		return
+	}
+
+	document, err := s.report.ControlledDrugRegister(report, format)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if format == services.REPORT_FORMAT_TEXT {
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", document)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=controlled-drugs-register-%s.pdf", report.EndDate.Format("2006-01-02")))
+	ctx.Data(http.StatusOK, "application/pdf", document)
+}
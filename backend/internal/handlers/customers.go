@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type customerRequest struct {
+	Name        *string `json:"name"`
+	PhoneNumber *string `json:"phone_number"`
+	DateOfBirth *string `json:"date_of_birth"` // mm/dd/yyyy
+	Allergies   *string `json:"allergies"`
+	Notes       *string `json:"notes"`
+
+	// only admins can extend credit
+	CreditLimit      *float64 `json:"credit_limit" binding:"omitempty,gte=0"`
+	PaymentTermsDays *int32   `json:"payment_terms_days" binding:"omitempty,gt=0"`
+}
+
+// authorizeCreditChange only lets admin users set a customer's credit terms.
+func (s *Server) authorizeCreditChange(ctx *gin.Context, req customerRequest) error {
+	if req.CreditLimit == nil && req.PaymentTermsDays == nil {
+		return nil
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	if payload.Role != repository.ADMIN_ROLE {
+		return pkg.Errorf(pkg.FORBIDDEN_ERROR, "only admin users can set credit terms")
+	}
+
+	return nil
+}
+
+func (s *Server) createCustomerHandler(ctx *gin.Context) {
+	var req customerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	if req.Name == nil || *req.Name == "" {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "name is required")))
+		return
+	}
+
+	if err := s.authorizeCreditChange(ctx, req); err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	customer := &repository.Customer{
+		Name:        *req.Name,
+		PhoneNumber: req.PhoneNumber,
+		Allergies:   req.Allergies,
+		Notes:       req.Notes,
+	}
+	if req.CreditLimit != nil {
+		customer.CreditLimit = *req.CreditLimit
+	}
+	if req.PaymentTermsDays != nil {
+		customer.PaymentTermsDays = *req.PaymentTermsDays
+	}
+
+	if req.DateOfBirth != nil {
+		dob, err := pkg.StringToTime(*req.DateOfBirth)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid date of birth: %s", err.Error())))
+			return
+		}
+		customer.DateOfBirth = &dob
+	}
+
+	createdCustomer, err := s.repo.CustomerRepository.Create(ctx, customer)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": createdCustomer})
+}
+
+func (s *Server) getCustomerHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid customer ID: %s", err.Error())))
+		return
+	}
+
+	customer, err := s.repo.CustomerRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": customer})
+}
+
+func (s *Server) updateCustomerHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid customer ID: %s", err.Error())))
+		return
+	}
+
+	var req customerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	if err := s.authorizeCreditChange(ctx, req); err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	update := &repository.CustomerUpdate{
+		Name:        req.Name,
+		PhoneNumber: req.PhoneNumber,
+		Allergies:   req.Allergies,
+		Notes:       req.Notes,
+
+		CreditLimit:      req.CreditLimit,
+		PaymentTermsDays: req.PaymentTermsDays,
+	}
+
+	if req.DateOfBirth != nil {
+		dob, err := pkg.StringToTime(*req.DateOfBirth)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid date of birth: %s", err.Error())))
+			return
+		}
+		update.DateOfBirth = &dob
+	}
+
+	updatedCustomer, err := s.repo.CustomerRepository.Update(ctx, id, update)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": updatedCustomer})
+}
+
+func (s *Server) deleteCustomerHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid customer ID: %s", err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	if payload.Role != repository.ADMIN_ROLE {
+		ctx.JSON(http.StatusForbidden, errorResponse(pkg.Errorf(pkg.FORBIDDEN_ERROR, "only admin users can delete customers")))
+		return
+	}
+
+	if err := s.repo.CustomerRepository.Delete(ctx, id); err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": "customer deleted successfully"})
+}
+
+func (s *Server) listCustomersHandler(ctx *gin.Context) {
+	pageNoStr := ctx.DefaultQuery("page", "1")
+	pageNo, err := pkg.StringToInt64(pageNoStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	pageSizeStr := ctx.DefaultQuery("limit", "10")
+	pageSize, err := pkg.StringToInt64(pageSizeStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	filter := &repository.CustomerFilter{
+		Pagination: &pkg.Pagination{
+			Page:     uint32(pageNo),
+			PageSize: uint32(pageSize),
+		},
+		Search: nil,
+	}
+
+	if search := ctx.Query("search"); search != "" {
+		filter.Search = &search
+	}
+
+	customers, pagination, err := s.repo.CustomerRepository.List(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       customers,
+		"pagination": pagination,
+	})
+}
+
+// listCustomerPurchasesHandler returns every REMOVE movement linked to the customer,
+// which covers both sales and stock handed out directly.
+func (s *Server) listCustomerPurchasesHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid customer ID: %s", err.Error())))
+		return
+	}
+
+	pageNoStr := ctx.DefaultQuery("page", "1")
+	pageNo, err := pkg.StringToInt64(pageNoStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	pageSizeStr := ctx.DefaultQuery("limit", "10")
+	pageSize, err := pkg.StringToInt64(pageSizeStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	if _, err := s.repo.CustomerRepository.GetByID(ctx, id); err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	customerID := uint32(id)
+	movementType := repository.MOVEMENT_REMOVE
+	filter := &repository.MovementFilter{
+		Pagination: &pkg.Pagination{
+			Page:     uint32(pageNo),
+			PageSize: uint32(pageSize),
+		},
+		Type:       &movementType,
+		CustomerID: &customerID,
+	}
+
+	purchases, pagination, err := s.repo.ProductsRepository.ListMovements(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       purchases,
+		"pagination": pagination,
+	})
+}
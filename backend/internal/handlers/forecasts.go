@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/EmilioCliff/jonche-med/internal/forecast"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+// forecastFilterFromQuery reads the method, history and horizon overrides shared
+// by the forecast endpoints.
+func forecastFilterFromQuery(ctx *gin.Context) (*repository.ForecastFilter, error) {
+	filter := &repository.ForecastFilter{}
+
+	if method := ctx.Query("method"); method != "" {
+		method = strings.ToUpper(method)
+		if !forecast.IsValidMethod(method) {
+			return nil, pkg.Errorf(pkg.INVALID_ERROR, "invalid forecast method: %s", method)
+		}
+		filter.Method = &method
+	}
+
+	if historyStr := ctx.Query("history_days"); historyStr != "" {
+		historyDays, err := strconv.Atoi(historyStr)
+		if err != nil {
+			return nil, pkg.Errorf(pkg.INVALID_ERROR, "invalid history days: %s", err.Error())
+		}
+		filter.HistoryDays = &historyDays
+	}
+
+	if horizonStr := ctx.Query("horizon_days"); horizonStr != "" {
+		horizonDays, err := strconv.Atoi(horizonStr)
+		if err != nil {
+			return nil, pkg.Errorf(pkg.INVALID_ERROR, "invalid horizon days: %s", err.Error())
+		}
+		filter.HorizonDays = &horizonDays
+	}
+
+	return filter, nil
+}
+
+func (s *Server) getProductForecastHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid product ID: %s", err.Error())))
+		return
+	}
+
+	filter, err := forecastFilterFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	productForecast, err := s.repo.ForecastRepository.GetProductForecast(ctx, id, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": productForecast})
+}
+
+func (s *Server) listForecastsHandler(ctx *gin.Context) {
+	pageNoStr := ctx.DefaultQuery("page", "1")
+	pageNo, err := pkg.StringToInt64(pageNoStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	pageSizeStr := ctx.DefaultQuery("limit", "10")
+	pageSize, err := pkg.StringToInt64(pageSizeStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	filter, err := forecastFilterFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+
+		return
+	}
+	filter.Pagination = &pkg.Pagination{
+		Page:     uint32(pageNo),
+		PageSize: uint32(pageSize),
+	}
+
+	forecasts, pagination, err := s.repo.ForecastRepository.List(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       forecasts,
+		"pagination": pagination,
+	})
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type createInsurerRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Code        string  `json:"code" binding:"required,max=20"`
+	Client      string  `json:"client" binding:"required"`
+	Email       *string `json:"email"`
+	PhoneNumber *string `json:"phone_number"`
+}
+
+func (s *Server) createInsurerHandler(ctx *gin.Context) {
+	var req createInsurerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	if payload.Role != repository.ADMIN_ROLE {
+		ctx.JSON(http.StatusForbidden, errorResponse(pkg.Errorf(pkg.FORBIDDEN_ERROR, "only admin users can manage insurers")))
+		return
+	}
+
+	client := strings.ToUpper(req.Client)
+	if _, ok := s.insuranceClients[client]; !ok {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "unknown insurance client: %s", req.Client)))
+		return
+	}
+
+	insurer, err := s.repo.InsuranceRepository.CreateInsurer(ctx, &repository.Insurer{
+		Name:        req.Name,
+		Code:        strings.ToUpper(req.Code),
+		Client:      client,
+		Email:       req.Email,
+		PhoneNumber: req.PhoneNumber,
+	})
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": insurer})
+}
+
+func (s *Server) getInsurerHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid insurer ID: %s", err.Error())))
+		return
+	}
+
+	insurer, err := s.repo.InsuranceRepository.GetInsurer(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": insurer})
+}
+
+func (s *Server) updateInsurerHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid insurer ID: %s", err.Error())))
+		return
+	}
+
+	var req repository.InsurerUpdate
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	if payload.Role != repository.ADMIN_ROLE {
+		ctx.JSON(http.StatusForbidden, errorResponse(pkg.Errorf(pkg.FORBIDDEN_ERROR, "only admin users can manage insurers")))
+		return
+	}
+
+	if req.Client != nil {
+		client := strings.ToUpper(*req.Client)
+		if _, ok := s.insuranceClients[client]; !ok {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "unknown insurance client: %s", *req.Client)))
+			return
+		}
+		req.Client = &client
+	}
+
+	insurer, err := s.repo.InsuranceRepository.UpdateInsurer(ctx, id, &req)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": insurer})
+}
+
+func (s *Server) deleteInsurerHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid insurer ID: %s", err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	if payload.Role != repository.ADMIN_ROLE {
+		ctx.JSON(http.StatusForbidden, errorResponse(pkg.Errorf(pkg.FORBIDDEN_ERROR, "only admin users can manage insurers")))
+		return
+	}
+
+	if err := s.repo.InsuranceRepository.DeleteInsurer(ctx, id); err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": "insurer deleted successfully"})
+}
+
+func (s *Server) listInsurersHandler(ctx *gin.Context) {
+	insurers, err := s.repo.InsuranceRepository.ListInsurers(ctx)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": insurers})
+}
+
+type createSchemeRequest struct {
+	Name          string  `json:"name" binding:"required"`
+	CoPaymentRate float64 `json:"co_payment_rate" binding:"gte=0,lt=100"`
+}
+
+func (s *Server) createSchemeHandler(ctx *gin.Context) {
+	insurerID, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid insurer ID: %s", err.Error())))
+		return
+	}
+
+	var req createSchemeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	if payload.Role != repository.ADMIN_ROLE {
+		ctx.JSON(http.StatusForbidden, errorResponse(pkg.Errorf(pkg.FORBIDDEN_ERROR, "only admin users can manage insurance schemes")))
+		return
+	}
+
+	scheme, err := s.repo.InsuranceRepository.CreateScheme(ctx, &repository.InsuranceScheme{
+		InsurerID:     uint32(insurerID),
+		Name:          req.Name,
+		CoPaymentRate: req.CoPaymentRate,
+	})
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": scheme})
+}
+
+func (s *Server) updateSchemeHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid insurance scheme ID: %s", err.Error())))
+		return
+	}
+
+	var req repository.InsuranceSchemeUpdate
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	if req.CoPaymentRate != nil && (*req.CoPaymentRate < 0 || *req.CoPaymentRate >= 100) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "co_payment_rate must be between 0 and 100")))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	if payload.Role != repository.ADMIN_ROLE {
+		ctx.JSON(http.StatusForbidden, errorResponse(pkg.Errorf(pkg.FORBIDDEN_ERROR, "only admin users can manage insurance schemes")))
+		return
+	}
+
+	scheme, err := s.repo.InsuranceRepository.UpdateScheme(ctx, id, &req)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": scheme})
+}
+
+func (s *Server) listSchemesHandler(ctx *gin.Context) {
+	var insurerID *uint32
+	if insurerIDStr := ctx.Query("insurer_id"); insurerIDStr != "" {
+		id, err := pkg.StringToInt64(insurerIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid insurer ID: %s", err.Error())))
+
+			return
+		}
+		iid := uint32(id)
+		insurerID = &iid
+	}
+
+	schemes, err := s.repo.InsuranceRepository.ListSchemes(ctx, insurerID)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": schemes})
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type kitComponentRequest struct {
+	ProductID uint32 `json:"product_id" binding:"required"`
+	Quantity  int32  `json:"quantity" binding:"required,gt=0"`
+}
+
+type setKitComponentsRequest struct {
+	// an empty list turns the kit back into an ordinary product
+	Components []kitComponentRequest `json:"components" binding:"dive"`
+}
+
+func (s *Server) setKitComponentsHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid product ID: %s", err.Error())))
+		return
+	}
+
+	var req setKitComponentsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	components := make([]*repository.KitComponent, len(req.Components))
+	for i, c := range req.Components {
+		components[i] = &repository.KitComponent{
+			KitID:       uint32(id),
+			ComponentID: c.ProductID,
+			Quantity:    c.Quantity,
+		}
+	}
+
+	product, err := s.repo.ProductsRepository.SetKitComponents(ctx, id, components)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": product})
+}
+
+type assembleKitRequest struct {
+	Quantity int64   `json:"quantity" binding:"required,gt=0"`
+	Note     *string `json:"note"`
+
+	// required when a component is a controlled product
+	witnessRequest
+}
+
+func (s *Server) assembleKitHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid product ID: %s", err.Error())))
+		return
+	}
+
+	var req assembleKitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	witnessedBy, err := s.verifyWitness(ctx, payload.UserID, req.witnessRequest)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	product, err := s.repo.ProductsRepository.AssembleKit(ctx, &repository.ProductStockUpdate{
+		ID:          uint32(id),
+		PerformedBy: payload.UserID,
+		Quantity:    req.Quantity,
+		Note:        req.Note,
+		WitnessedBy: witnessedBy,
+	})
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": product})
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type paymentRequest struct {
+	Method    string  `json:"method" binding:"required,oneof=CASH CARD MPESA INSURANCE"`
+	Amount    float64 `json:"amount" binding:"required,gt=0"`
+	Reference *string `json:"reference"`
+
+	// PhoneNumber is required for M-Pesa payments and receives the STK push prompt.
+	PhoneNumber *string `json:"phone_number"`
+}
+
+func (s *Server) createPaymentHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid sale ID: %s", err.Error())))
+		return
+	}
+
+	var req paymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	if req.Method == repository.PAYMENT_MPESA && (req.PhoneNumber == nil || *req.PhoneNumber == "") {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "phone_number is required for M-Pesa payments")))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	payment, err := s.repo.PaymentRepository.Create(ctx, &repository.Payment{
+		SaleID:      uint32(id),
+		Method:      req.Method,
+		Amount:      req.Amount,
+		Reference:   req.Reference,
+		PhoneNumber: req.PhoneNumber,
+		ReceivedBy:  payload.UserID,
+	})
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if payment.Method != repository.PAYMENT_MPESA {
+		ctx.JSON(http.StatusOK, gin.H{"data": payment})
+		return
+	}
+
+	// the payment is recorded pending before the push so the amount is held against
+	// the sale's balance until the customer responds
+	push, err := s.mobileMoney.InitiateSTKPush(ctx, &services.STKPushRequest{
+		PhoneNumber:      *payment.PhoneNumber,
+		Amount:           payment.Amount,
+		AccountReference: fmt.Sprintf("SALE%d", payment.SaleID),
+		Description:      fmt.Sprintf("Payment for sale %d", payment.SaleID),
+	})
+	if err != nil {
+		if _, failErr := s.repo.PaymentRepository.Fail(ctx, int64(payment.ID), pkg.ErrorMessage(err)); failErr != nil {
+			log.Printf("failed to mark payment %d as failed: %v", payment.ID, failErr)
+		}
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if err := s.repo.PaymentRepository.SetCheckoutRequestID(ctx, int64(payment.ID), push.CheckoutRequestID); err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+	payment.CheckoutRequestID = &push.CheckoutRequestID
+
+	ctx.JSON(http.StatusOK, gin.H{"data": payment, "message": push.CustomerMessage})
+}
+
+func (s *Server) listSalePaymentsHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid sale ID: %s", err.Error())))
+		return
+	}
+
+	sale, err := s.repo.SalesRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":        sale.Payments,
+		"amount_paid": sale.AmountPaid,
+		"balance":     sale.Balance,
+	})
+}
+
+func (s *Server) mpesaCallbackHandler(ctx *gin.Context) {
+	if s.config.MPESA_CALLBACK_TOKEN == "" || ctx.Param("token") != s.config.MPESA_CALLBACK_TOKEN {
+		ctx.JSON(http.StatusForbidden, errorResponse(pkg.Errorf(pkg.FORBIDDEN_ERROR, "invalid callback token")))
+		return
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "failed to read callback body: %s", err.Error())))
+		return
+	}
+
+	callback, err := s.mobileMoney.ParseCallback(body)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	result := &repository.PaymentResult{
+		CheckoutRequestID: callback.CheckoutRequestID,
+		Success:           callback.Success,
+	}
+	if callback.Success {
+		result.Reference = &callback.ReceiptNumber
+		result.Amount = &callback.Amount
+	} else {
+		result.FailureReason = &callback.ResultDescription
+	}
+
+	// a callback that matched nothing, e.g. one that beat SetCheckoutRequestID,
+	// is refused so the provider retries it; the expiry job fails payments that never match
+	if _, err := s.repo.PaymentRepository.Resolve(ctx, result); err != nil {
+		log.Printf("failed to resolve mpesa callback %s: %v", callback.CheckoutRequestID, err)
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"ResultCode": 0, "ResultDesc": "Accepted"})
+}
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+const maxPrescriptionScanSize = 5 << 20 // 5MB
+
+var allowedScanContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"application/pdf": true,
+}
+
+type prescriptionItemRequest struct {
+	ProductID uint32  `json:"product_id" binding:"required"`
+	Quantity  int32   `json:"quantity" binding:"required,gt=0"`
+	Dosage    *string `json:"dosage"`
+}
+
+type createPrescriptionRequest struct {
+	CustomerID              uint32                    `json:"customer_id" binding:"required"`
+	PrescriberName          string                    `json:"prescriber_name" binding:"required"`
+	PrescriberLicenceNumber string                    `json:"prescriber_licence_number" binding:"required"`
+	PrescribedAt            string                    `json:"prescribed_at" binding:"required"` // mm/dd/yyyy
+	ValidUntil              *string                   `json:"valid_until"`                      // mm/dd/yyyy
+	Notes                   *string                   `json:"notes"`
+	Items                   []prescriptionItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+func (s *Server) createPrescriptionHandler(ctx *gin.Context) {
+	var req createPrescriptionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	prescribedAt, err := pkg.StringToTime(req.PrescribedAt)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid prescribed at date: %s", err.Error())))
+		return
+	}
+	if prescribedAt.After(time.Now()) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "prescribed at date cannot be in the future")))
+		return
+	}
+
+	validUntil := prescribedAt.Add(s.config.PRESCRIPTION_VALIDITY)
+	if req.ValidUntil != nil {
+		validUntil, err = pkg.StringToTime(*req.ValidUntil)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid valid until date: %s", err.Error())))
+			return
+		}
+	}
+	if validUntil.Before(prescribedAt) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "valid until date cannot be before the prescribed at date")))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	prescription := &repository.Prescription{
+		CustomerID:              req.CustomerID,
+		PrescriberName:          req.PrescriberName,
+		PrescriberLicenceNumber: req.PrescriberLicenceNumber,
+		PrescribedAt:            prescribedAt,
+		ValidUntil:              validUntil,
+		Notes:                   req.Notes,
+		CreatedBy:               payload.UserID,
+		Items:                   make([]*repository.PrescriptionItem, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		prescription.Items[i] = &repository.PrescriptionItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Dosage:    item.Dosage,
+		}
+	}
+
+	createdPrescription, err := s.repo.PrescriptionRepository.Create(ctx, prescription)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": createdPrescription})
+}
+
+func (s *Server) getPrescriptionHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid prescription ID: %s", err.Error())))
+		return
+	}
+
+	prescription, err := s.repo.PrescriptionRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": prescription})
+}
+
+func (s *Server) listPrescriptionsHandler(ctx *gin.Context) {
+	pageNoStr := ctx.DefaultQuery("page", "1")
+	pageNo, err := pkg.StringToInt64(pageNoStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	pageSizeStr := ctx.DefaultQuery("limit", "10")
+	pageSize, err := pkg.StringToInt64(pageSizeStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	filter := &repository.PrescriptionFilter{
+		Pagination: &pkg.Pagination{
+			Page:     uint32(pageNo),
+			PageSize: uint32(pageSize),
+		},
+		CustomerID: nil,
+		Search:     nil,
+	}
+
+	if customerIDStr := ctx.Query("customer_id"); customerIDStr != "" {
+		customerID, err := pkg.StringToInt64(customerIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid customer ID: %s", err.Error())))
+
+			return
+		}
+		cid := uint32(customerID)
+		filter.CustomerID = &cid
+	}
+
+	if search := ctx.Query("search"); search != "" {
+		filter.Search = &search
+	}
+
+	prescriptions, pagination, err := s.repo.PrescriptionRepository.List(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       prescriptions,
+		"pagination": pagination,
+	})
+}
+
+func (s *Server) uploadPrescriptionScanHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid prescription ID: %s", err.Error())))
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "file is required: %s", err.Error())))
+		return
+	}
+
+	if fileHeader.Size > maxPrescriptionScanSize {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "file cannot be larger than %d bytes", maxPrescriptionScanSize)))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to open file: %s", err.Error())))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to read file: %s", err.Error())))
+		return
+	}
+
+	contentType := http.DetectContentType(data)
+	if !allowedScanContentTypes[contentType] {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "unsupported file type: %s", contentType)))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	scan, err := s.repo.PrescriptionRepository.AddScan(ctx, &repository.PrescriptionScan{
+		PrescriptionID: uint32(id),
+		FileName:       fileHeader.Filename,
+		ContentType:    contentType,
+		Data:           data,
+		UploadedBy:     payload.UserID,
+	})
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": scan})
+}
+
+func (s *Server) getPrescriptionScanHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid prescription ID: %s", err.Error())))
+		return
+	}
+
+	scanID, err := pkg.StringToInt64(ctx.Param("scanId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid scan ID: %s", err.Error())))
+		return
+	}
+
+	scan, err := s.repo.PrescriptionRepository.GetScan(ctx, id, scanID)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", scan.FileName))
+	ctx.Data(http.StatusOK, scan.ContentType, scan.Data)
+}
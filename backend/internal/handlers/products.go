@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/EmilioCliff/jonche-med/internal/classification"
 	"github.com/EmilioCliff/jonche-med/internal/repository"
 	"github.com/EmilioCliff/jonche-med/pkg"
 	"github.com/gin-gonic/gin"
@@ -14,9 +16,21 @@ type createProductRequest struct {
 	Description       string  `json:"description" binding:"required"`
 	Price             float64 `json:"price" binding:"required,gt=0"`
 	Stock             int64   `json:"stock" binding:"required,gte=0"`
-	Category          string  `json:"category" binding:"required"`
+	UnitCost          float64 `json:"unit_cost" binding:"omitempty,gt=0"`
+	Category          string  `json:"category"`
+	CategoryID        *uint32 `json:"category_id"`
+	TaxClassID        uint32  `json:"tax_class_id"`
 	Unit              string  `json:"unit" binding:"required"`
 	LowStockThreshold int32   `json:"low_stock_threshold" binding:"required,gte=0"`
+	PrescriptionOnly  bool    `json:"prescription_only"`
+	Controlled        bool    `json:"controlled"`
+	GenericName       *string `json:"generic_name"`
+	Strength          *string `json:"strength"`
+	DosageForm        *string `json:"dosage_form"`
+	Route             *string `json:"route"`
+	Manufacturer      *string `json:"manufacturer"`
+	PackSize          *int32  `json:"pack_size" binding:"omitempty,gt=0"`
+	TherapeuticClass  *string `json:"therapeutic_class"`
 }
 
 func (s *Server) createProductHandler(ctx *gin.Context) {
@@ -26,14 +40,31 @@ func (s *Server) createProductHandler(ctx *gin.Context) {
 		return
 	}
 
+	if req.CategoryID == nil && req.Category == "" {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "category or category_id is required")))
+		return
+	}
+
 	product := &repository.Product{
 		Name:              req.Name,
 		Description:       req.Description,
 		Price:             req.Price,
 		Stock:             req.Stock,
+		OpeningUnitCost:   req.UnitCost,
 		Category:          req.Category,
+		CategoryID:        req.CategoryID,
+		TaxClassID:        req.TaxClassID,
 		Unit:              req.Unit,
 		LowStockThreshold: req.LowStockThreshold,
+		PrescriptionOnly:  req.PrescriptionOnly,
+		Controlled:        req.Controlled,
+		GenericName:       req.GenericName,
+		Strength:          req.Strength,
+		DosageForm:        req.DosageForm,
+		Route:             req.Route,
+		Manufacturer:      req.Manufacturer,
+		PackSize:          req.PackSize,
+		TherapeuticClass:  req.TherapeuticClass,
 	}
 
 	createdProduct, err := s.repo.ProductsRepository.Create(ctx, product)
@@ -63,6 +94,29 @@ func (s *Server) getProductHandler(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"data": product})
 }
 
+func (s *Server) listProductSubstitutesHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid product ID: %s", err.Error())))
+		return
+	}
+
+	limit, err := pkg.StringToInt64(ctx.DefaultQuery("limit", "10"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	substitutes, err := s.repo.ProductsRepository.ListSubstitutes(ctx, id, int32(limit))
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": substitutes})
+}
+
 func (s *Server) updateProductHandler(ctx *gin.Context) {
 	id, err := pkg.StringToInt64(ctx.Param("id"))
 	if err != nil {
@@ -76,6 +130,25 @@ func (s *Server) updateProductHandler(ctx *gin.Context) {
 		return
 	}
 
+	if req.Price != nil && *req.Price <= 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "price must be greater than 0")))
+		return
+	}
+
+	if req.PackSize != nil && *req.PackSize <= 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "pack_size must be greater than 0")))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+	req.UpdatedBy = payload.UserID
+
 	updatedProduct, err := s.repo.ProductsRepository.Update(ctx, id, &req)
 	if err != nil {
 		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
@@ -138,6 +211,48 @@ func (s *Server) listProductsHandler(ctx *gin.Context) {
 		filter.Status = &status
 	}
 
+	if categoryIDStr := ctx.Query("category_id"); categoryIDStr != "" {
+		categoryID, err := pkg.StringToInt64(categoryIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid category ID: %s", err.Error())))
+			return
+		}
+		v := uint32(categoryID)
+		filter.CategoryID = &v
+	}
+
+	attributeFilters := map[string]**string{
+		"generic_name":      &filter.GenericName,
+		"strength":          &filter.Strength,
+		"dosage_form":       &filter.DosageForm,
+		"route":             &filter.Route,
+		"manufacturer":      &filter.Manufacturer,
+		"therapeutic_class": &filter.TherapeuticClass,
+	}
+	for param, target := range attributeFilters {
+		if value := ctx.Query(param); value != "" {
+			*target = &value
+		}
+	}
+
+	if abcClass := ctx.Query("abc_class"); abcClass != "" {
+		abcClass = strings.ToUpper(abcClass)
+		if !classification.IsValidABC(abcClass) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid ABC class: %s", abcClass)))
+			return
+		}
+		filter.ABCClass = &abcClass
+	}
+
+	if xyzClass := ctx.Query("xyz_class"); xyzClass != "" {
+		xyzClass = strings.ToUpper(xyzClass)
+		if !classification.IsValidXYZ(xyzClass) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid XYZ class: %s", xyzClass)))
+			return
+		}
+		filter.XYZClass = &xyzClass
+	}
+
 	products, pagination, err := s.repo.ProductsRepository.List(ctx, filter)
 	if err != nil {
 		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
@@ -151,9 +266,20 @@ func (s *Server) listProductsHandler(ctx *gin.Context) {
 }
 
 type stockUpdateRequest struct {
-	Quantity    int64   `json:"quantity" binding:"required,gt=0"`
-	Note        *string `json:"note"`
-	BatchNumber *string `json:"batch_number"`
+	Quantity    int64    `json:"quantity" binding:"required,gt=0"`
+	UnitCost    *float64 `json:"unit_cost" binding:"omitempty,gt=0"`
+	Note        *string  `json:"note"`
+	BatchNumber *string  `json:"batch_number"`
+	CustomerID  *uint32  `json:"customer_id"`
+
+	// PrescriptionID is required when removing stock of a prescription-only product.
+	PrescriptionID *uint32 `json:"prescription_id"`
+
+	// WriteOff removes expired or damaged stock, which needs no prescription.
+	WriteOff bool `json:"write_off"`
+
+	// required for controlled products
+	witnessRequest
 }
 
 func (s *Server) addProductStockHandler(ctx *gin.Context) {
@@ -177,12 +303,20 @@ func (s *Server) addProductStockHandler(ctx *gin.Context) {
 	}
 	payload := authPayload.(*pkg.Payload)
 
+	witnessedBy, err := s.verifyWitness(ctx, payload.UserID, req.witnessRequest)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
 	data := &repository.ProductStockUpdate{
 		ID:          uint32(id),
 		PerformedBy: payload.UserID,
 		Quantity:    req.Quantity,
+		UnitCost:    req.UnitCost,
 		Note:        req.Note,
 		BatchNumber: req.BatchNumber,
+		WitnessedBy: witnessedBy,
 	}
 
 	updatedProduct, err := s.repo.ProductsRepository.AddStock(ctx, data)
@@ -217,11 +351,24 @@ func (s *Server) removeProductStockHandler(ctx *gin.Context) {
 	}
 	payload := authPayload.(*pkg.Payload)
 
+	witnessedBy, err := s.verifyWitness(ctx, payload.UserID, req.witnessRequest)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
 	data := &repository.ProductStockUpdate{
-		ID:          uint32(id),
-		PerformedBy: payload.UserID,
-		Quantity:    req.Quantity,
-		Note:        req.Note,
+		ID:             uint32(id),
+		PerformedBy:    payload.UserID,
+		Quantity:       req.Quantity,
+		Note:           req.Note,
+		CustomerID:     req.CustomerID,
+		PrescriptionID: req.PrescriptionID,
+		WitnessedBy:    witnessedBy,
+	}
+	if req.WriteOff {
+		reason := repository.MOVEMENT_REASON_WRITE_OFF
+		data.Reason = &reason
 	}
 
 	updatedProduct, err := s.repo.ProductsRepository.RemoveStock(ctx, data)
@@ -287,6 +434,17 @@ func (s *Server) listProductMovementsHandler(ctx *gin.Context) {
 		filter.BatchNumber = &batchNumber
 	}
 
+	if customerIDStr := ctx.Query("customer_id"); customerIDStr != "" {
+		customerID, err := pkg.StringToInt64(customerIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid customer ID: %s", err.Error())))
+
+			return
+		}
+		cid := uint32(customerID)
+		filter.CustomerID = &cid
+	}
+
 	startDateStr := ctx.DefaultQuery("from", "01/01/2025")
 	startDate, err := pkg.StringToTime(startDateStr)
 	if err != nil {
@@ -318,6 +476,49 @@ func (s *Server) listProductMovementsHandler(ctx *gin.Context) {
 	})
 }
 
+func (s *Server) listProductPriceHistoryHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid product ID: %s", err.Error())))
+		return
+	}
+
+	pageNoStr := ctx.DefaultQuery("page", "1")
+	pageNo, err := pkg.StringToInt64(pageNoStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	pageSizeStr := ctx.DefaultQuery("limit", "10")
+	pageSize, err := pkg.StringToInt64(pageSizeStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	filter := &repository.PriceHistoryFilter{
+		Pagination: &pkg.Pagination{
+			Page:     uint32(pageNo),
+			PageSize: uint32(pageSize),
+		},
+		ProductID: uint32(id),
+	}
+
+	changes, pagination, err := s.repo.ProductsRepository.ListPriceHistory(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       changes,
+		"pagination": pagination,
+	})
+}
+
 func (s *Server) getStatsHandler(ctx *gin.Context) {
 	stats, err := s.repo.ProductsRepository.GetStats(ctx)
 	if err != nil {
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type createPromotionRequest struct {
+	Name         string    `json:"name" binding:"required"`
+	Type         string    `json:"type" binding:"required,oneof=PERCENTAGE FIXED BUY_X_GET_Y"`
+	Value        float64   `json:"value" binding:"gte=0"`
+	BuyQuantity  *int32    `json:"buy_quantity" binding:"omitempty,gt=0"`
+	FreeQuantity *int32    `json:"free_quantity" binding:"omitempty,gt=0"`
+	ProductID    *uint32   `json:"product_id"`
+	CategoryID   *uint32   `json:"category_id"`
+	StartsAt     time.Time `json:"starts_at" binding:"required"`
+	EndsAt       time.Time `json:"ends_at" binding:"required"`
+}
+
+func (s *Server) createPromotionHandler(ctx *gin.Context) {
+	var req createPromotionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	promotion, err := s.repo.PromotionRepository.Create(ctx, &repository.Promotion{
+		Name:         req.Name,
+		Type:         req.Type,
+		Value:        req.Value,
+		BuyQuantity:  req.BuyQuantity,
+		FreeQuantity: req.FreeQuantity,
+		ProductID:    req.ProductID,
+		CategoryID:   req.CategoryID,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		CreatedBy:    payload.UserID,
+	})
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": promotion})
+}
+
+func (s *Server) getPromotionHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid promotion ID: %s", err.Error())))
+		return
+	}
+
+	promotion, err := s.repo.PromotionRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": promotion})
+}
+
+func (s *Server) updatePromotionHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid promotion ID: %s", err.Error())))
+		return
+	}
+
+	var req repository.PromotionUpdate
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	promotion, err := s.repo.PromotionRepository.Update(ctx, id, &req)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": promotion})
+}
+
+func (s *Server) listPromotionsHandler(ctx *gin.Context) {
+	pageNoStr := ctx.DefaultQuery("page", "1")
+	pageNo, err := pkg.StringToInt64(pageNoStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	pageSizeStr := ctx.DefaultQuery("limit", "10")
+	pageSize, err := pkg.StringToInt64(pageSizeStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	filter := &repository.PromotionFilter{
+		Pagination: &pkg.Pagination{
+			Page:     uint32(pageNo),
+			PageSize: uint32(pageSize),
+		},
+		Current:   nil,
+		ProductID: nil,
+	}
+
+	if current := ctx.Query("current"); current != "" {
+		v := current == "true"
+		filter.Current = &v
+	}
+
+	if productIDStr := ctx.Query("product_id"); productIDStr != "" {
+		productID, err := pkg.StringToInt64(productIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid product ID: %s", err.Error())))
+			return
+		}
+		v := uint32(productID)
+		filter.ProductID = &v
+	}
+
+	promotions, pagination, err := s.repo.PromotionRepository.List(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       promotions,
+		"pagination": pagination,
+	})
+}
+
+func (s *Server) getDiscountReportHandler(ctx *gin.Context) {
+	// defaults to the current month
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	endDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+
+	if fromStr := ctx.Query("from"); fromStr != "" {
+		from, err := pkg.StringToTime(fromStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid start date: %s", err.Error())))
+			return
+		}
+		startDate = from
+	}
+
+	if toStr := ctx.Query("to"); toStr != "" {
+		to, err := pkg.StringToTime(toStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid end date: %s", err.Error())))
+			return
+		}
+		endDate = to.Add(time.Hour * 24)
+	}
+
+	report, err := s.repo.PromotionRepository.GetDiscountReport(ctx, startDate, endDate)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": report})
+}
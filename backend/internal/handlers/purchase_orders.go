@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type setReorderPolicyRequest struct {
+	ReorderPoint        *int32  `json:"reorder_point" binding:"omitempty,gte=0"`
+	ReorderQuantity     *int32  `json:"reorder_quantity" binding:"omitempty,gt=0"`
+	PreferredSupplierID *uint32 `json:"preferred_supplier_id"`
+}
+
+// setReorderPolicyHandler replaces a product's reorder settings. Leaving out the
+// reorder point and quantity stops the product from being reordered.
+func (s *Server) setReorderPolicyHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid product ID: %s", err.Error())))
+		return
+	}
+
+	var req setReorderPolicyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	product, err := s.repo.ProductsRepository.SetReorderPolicy(ctx, id, &repository.ReorderPolicy{
+		ReorderPoint:        req.ReorderPoint,
+		ReorderQuantity:     req.ReorderQuantity,
+		PreferredSupplierID: req.PreferredSupplierID,
+	})
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": product})
+}
+
+// generatePurchaseOrdersHandler drafts purchase orders for products below their
+// reorder point now, without waiting for the background job.
+func (s *Server) generatePurchaseOrdersHandler(ctx *gin.Context) {
+	orders, err := s.repo.PurchaseOrderRepository.GenerateDrafts(ctx)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": orders})
+}
+
+func (s *Server) getPurchaseOrderHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid purchase order ID: %s", err.Error())))
+		return
+	}
+
+	order, err := s.repo.PurchaseOrderRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": order})
+}
+
+func (s *Server) listPurchaseOrdersHandler(ctx *gin.Context) {
+	pageNoStr := ctx.DefaultQuery("page", "1")
+	pageNo, err := pkg.StringToInt64(pageNoStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	pageSizeStr := ctx.DefaultQuery("limit", "10")
+	pageSize, err := pkg.StringToInt64(pageSizeStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	filter := &repository.PurchaseOrderFilter{
+		Pagination: &pkg.Pagination{
+			Page:     uint32(pageNo),
+			PageSize: uint32(pageSize),
+		},
+		SupplierID: nil,
+		Status:     nil,
+	}
+
+	if supplierIDStr := ctx.Query("supplier_id"); supplierIDStr != "" {
+		supplierID, err := pkg.StringToInt64(supplierIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid supplier ID: %s", err.Error())))
+
+			return
+		}
+		sid := uint32(supplierID)
+		filter.SupplierID = &sid
+	}
+
+	if status := ctx.Query("status"); status != "" {
+		status = strings.ToUpper(status)
+		filter.Status = &status
+	}
+
+	orders, pagination, err := s.repo.PurchaseOrderRepository.List(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       orders,
+		"pagination": pagination,
+	})
+}
+
+type setPurchaseOrderItemRequest struct {
+	// a zero quantity removes the item from the draft
+	Quantity *int32 `json:"quantity" binding:"required,gte=0"`
+}
+
+func (s *Server) setPurchaseOrderItemHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid purchase order ID: %s", err.Error())))
+		return
+	}
+
+	itemID, err := pkg.StringToInt64(ctx.Param("itemId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid item ID: %s", err.Error())))
+		return
+	}
+
+	var req setPurchaseOrderItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	order, err := s.repo.PurchaseOrderRepository.SetItemQuantity(ctx, id, itemID, *req.Quantity)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": order})
+}
+
+func (s *Server) orderPurchaseOrderHandler(ctx *gin.Context) {
+	s.updatePurchaseOrderStatus(ctx, repository.PURCHASE_ORDER_ORDERED)
+}
+
+func (s *Server) receivePurchaseOrderHandler(ctx *gin.Context) {
+	s.updatePurchaseOrderStatus(ctx, repository.PURCHASE_ORDER_RECEIVED)
+}
+
+func (s *Server) cancelPurchaseOrderHandler(ctx *gin.Context) {
+	s.updatePurchaseOrderStatus(ctx, repository.PURCHASE_ORDER_CANCELLED)
+}
+
+func (s *Server) updatePurchaseOrderStatus(ctx *gin.Context, status string) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid purchase order ID: %s", err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	order, err := s.repo.PurchaseOrderRepository.UpdateStatus(ctx, id, status, payload.UserID)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": order})
+}
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type quotationItemRequest struct {
+	ProductID uint32 `json:"product_id" binding:"required"`
+	Quantity  int64  `json:"quantity" binding:"required,gt=0"`
+}
+
+type createQuotationRequest struct {
+	CustomerID *uint32                `json:"customer_id"`
+	Note       *string                `json:"note"`
+	ValidDays  *int64                 `json:"valid_days" binding:"omitempty,gt=0"` // defaults to QUOTATION_VALIDITY
+	Items      []quotationItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+func (s *Server) createQuotationHandler(ctx *gin.Context) {
+	var req createQuotationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	validity := s.config.QUOTATION_VALIDITY
+	if req.ValidDays != nil {
+		validity = time.Duration(*req.ValidDays) * 24 * time.Hour
+	}
+
+	quotation := &repository.Quotation{
+		CustomerID: req.CustomerID,
+		Note:       req.Note,
+		ValidUntil: time.Now().Add(validity),
+		CreatedBy:  payload.UserID,
+		Items:      make([]*repository.QuotationItem, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		quotation.Items[i] = &repository.QuotationItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	createdQuotation, err := s.repo.QuotationRepository.Create(ctx, quotation)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": createdQuotation})
+}
+
+func (s *Server) getQuotationHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid quotation ID: %s", err.Error())))
+		return
+	}
+
+	quotation, err := s.repo.QuotationRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": quotation})
+}
+
+func (s *Server) listQuotationsHandler(ctx *gin.Context) {
+	pageNoStr := ctx.DefaultQuery("page", "1")
+	pageNo, err := pkg.StringToInt64(pageNoStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	pageSizeStr := ctx.DefaultQuery("limit", "10")
+	pageSize, err := pkg.StringToInt64(pageSizeStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	filter := &repository.QuotationFilter{
+		Pagination: &pkg.Pagination{
+			Page:     uint32(pageNo),
+			PageSize: uint32(pageSize),
+		},
+		Status:     nil,
+		CustomerID: nil,
+	}
+
+	if status := ctx.Query("status"); status != "" {
+		status = strings.ToUpper(status)
+		filter.Status = &status
+	}
+
+	if customerIDStr := ctx.Query("customer_id"); customerIDStr != "" {
+		customerID, err := pkg.StringToInt64(customerIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid customer ID: %s", err.Error())))
+
+			return
+		}
+		cid := uint32(customerID)
+		filter.CustomerID = &cid
+	}
+
+	quotations, pagination, err := s.repo.QuotationRepository.List(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       quotations,
+		"pagination": pagination,
+	})
+}
+
+func (s *Server) getQuotationDocumentHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid quotation ID: %s", err.Error())))
+		return
+	}
+
+	format := ctx.DefaultQuery("format", services.REPORT_FORMAT_PDF)
+	if format != services.REPORT_FORMAT_PDF && format != services.REPORT_FORMAT_TEXT {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid format: %s", format)))
+		return
+	}
+
+	quotation, err := s.repo.QuotationRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	document, err := s.report.Quotation(quotation, format)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if format == services.REPORT_FORMAT_TEXT {
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", document)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=%s.pdf", quotation.QuotationNumber))
+	ctx.Data(http.StatusOK, "application/pdf", document)
+}
+
+type convertQuotationRequest struct {
+	Note     *string `json:"note"`
+	Location string  `json:"location"`
+
+	// AcceptPriceChanges sells at today's prices and promotions when they differ from
+	// the quoted ones.
+	AcceptPriceChanges bool `json:"accept_price_changes"`
+	OnAccount          bool `json:"on_account"`
+
+	// Prescriptions maps product IDs to the prescription they are dispensed against.
+	Prescriptions map[uint32]uint32 `json:"prescriptions"`
+
+	Payments []paymentRequest `json:"payments" binding:"dive"`
+
+	// required when any line is a controlled product
+	witnessRequest
+}
+
+func (s *Server) convertQuotationHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid quotation ID: %s", err.Error())))
+		return
+	}
+
+	var req convertQuotationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	witnessedBy, err := s.verifyWitness(ctx, payload.UserID, req.witnessRequest)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	location := strings.ToUpper(strings.TrimSpace(req.Location))
+	if location == "" {
+		location = s.config.DEFAULT_LOCATION
+	}
+
+	sale := &repository.Sale{
+		Location:    location,
+		Note:        req.Note,
+		OnAccount:   req.OnAccount,
+		WitnessedBy: witnessedBy,
+		PerformedBy: payload.UserID,
+		Items:       make([]*repository.SaleItem, 0, len(req.Prescriptions)),
+	}
+	for productID, prescriptionID := range req.Prescriptions {
+		sale.Items = append(sale.Items, &repository.SaleItem{
+			ProductID:      productID,
+			PrescriptionID: &prescriptionID,
+		})
+	}
+	for _, payment := range req.Payments {
+		sale.Payments = append(sale.Payments, &repository.Payment{
+			Method:    payment.Method,
+			Amount:    payment.Amount,
+			Reference: payment.Reference,
+		})
+	}
+
+	createdSale, err := s.repo.QuotationRepository.Convert(ctx, id, sale, req.AcceptPriceChanges)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	s.transmitTaxInvoice(ctx, createdSale)
+
+	ctx.JSON(http.StatusOK, gin.H{"data": createdSale})
+}
+
+func (s *Server) cancelQuotationHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid quotation ID: %s", err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	quotation, err := s.repo.QuotationRepository.Cancel(ctx, id, payload.UserID)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": quotation})
+}
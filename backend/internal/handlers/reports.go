@@ -1 +1,50 @@
 package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/valuation"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) getValuationReportHandler(ctx *gin.Context) {
+	filter := &repository.ValuationFilter{}
+
+	if method := ctx.Query("method"); method != "" {
+		if !valuation.IsValidMethod(method) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid valuation method: %s", method)))
+			return
+		}
+		filter.Method = &method
+	}
+
+	if fromStr := ctx.Query("from"); fromStr != "" {
+		startDate, err := pkg.StringToTime(fromStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid start date: %s", err.Error())))
+			return
+		}
+		filter.StartDate = &startDate
+	}
+
+	if toStr := ctx.Query("to"); toStr != "" {
+		endDate, err := pkg.StringToTime(toStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid end date: %s", err.Error())))
+			return
+		}
+		endDate = endDate.Add(time.Hour * 24)
+		filter.EndDate = &endDate
+	}
+
+	report, err := s.repo.ProductsRepository.GetValuation(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": report})
+}
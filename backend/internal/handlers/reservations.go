@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type reservationItemRequest struct {
+	ProductID uint32 `json:"product_id" binding:"required"`
+	Quantity  int64  `json:"quantity" binding:"required,gt=0"`
+}
+
+type createReservationRequest struct {
+	CustomerID *uint32                  `json:"customer_id"`
+	Note       *string                  `json:"note"`
+	ExpiresIn  *int64                   `json:"expires_in" binding:"omitempty,gt=0"` // minutes, defaults to RESERVATION_DURATION
+	Items      []reservationItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+func (s *Server) createReservationHandler(ctx *gin.Context) {
+	var req createReservationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	duration := s.config.RESERVATION_DURATION
+	if req.ExpiresIn != nil {
+		duration = time.Duration(*req.ExpiresIn) * time.Minute
+	}
+
+	reservation := &repository.Reservation{
+		CustomerID: req.CustomerID,
+		Note:       req.Note,
+		ExpiresAt:  time.Now().Add(duration),
+		CreatedBy:  payload.UserID,
+		Items:      make([]*repository.ReservationItem, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		reservation.Items[i] = &repository.ReservationItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	createdReservation, err := s.repo.ReservationRepository.Create(ctx, reservation)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": createdReservation})
+}
+
+func (s *Server) getReservationHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid reservation ID: %s", err.Error())))
+		return
+	}
+
+	reservation, err := s.repo.ReservationRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": reservation})
+}
+
+func (s *Server) listReservationsHandler(ctx *gin.Context) {
+	pageNoStr := ctx.DefaultQuery("page", "1")
+	pageNo, err := pkg.StringToInt64(pageNoStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	pageSizeStr := ctx.DefaultQuery("limit", "10")
+	pageSize, err := pkg.StringToInt64(pageSizeStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	filter := &repository.ReservationFilter{
+		Pagination: &pkg.Pagination{
+			Page:     uint32(pageNo),
+			PageSize: uint32(pageSize),
+		},
+		Status:     nil,
+		CustomerID: nil,
+	}
+
+	if status := ctx.Query("status"); status != "" {
+		status = strings.ToUpper(status)
+		filter.Status = &status
+	}
+
+	if customerIDStr := ctx.Query("customer_id"); customerIDStr != "" {
+		customerID, err := pkg.StringToInt64(customerIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid customer ID: %s", err.Error())))
+
+			return
+		}
+		cid := uint32(customerID)
+		filter.CustomerID = &cid
+	}
+
+	reservations, pagination, err := s.repo.ReservationRepository.List(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       reservations,
+		"pagination": pagination,
+	})
+}
+
+type convertReservationRequest struct {
+	Note     *string `json:"note"`
+	Location string  `json:"location"`
+
+	// Prescriptions maps product IDs to the prescription they are dispensed against.
+	Prescriptions map[uint32]uint32 `json:"prescriptions"`
+
+	// required when any line is a controlled product
+	witnessRequest
+}
+
+func (s *Server) convertReservationHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid reservation ID: %s", err.Error())))
+		return
+	}
+
+	var req convertReservationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	witnessedBy, err := s.verifyWitness(ctx, payload.UserID, req.witnessRequest)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	location := strings.ToUpper(strings.TrimSpace(req.Location))
+	if location == "" {
+		location = s.config.DEFAULT_LOCATION
+	}
+
+	sale := &repository.Sale{
+		Location:    location,
+		Note:        req.Note,
+		WitnessedBy: witnessedBy,
+		PerformedBy: payload.UserID,
+		Items:       make([]*repository.SaleItem, 0, len(req.Prescriptions)),
+	}
+	for productID, prescriptionID := range req.Prescriptions {
+		sale.Items = append(sale.Items, &repository.SaleItem{
+			ProductID:      productID,
+			PrescriptionID: &prescriptionID,
+		})
+	}
+
+	createdSale, err := s.repo.ReservationRepository.Convert(ctx, id, sale)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	s.transmitTaxInvoice(ctx, createdSale)
+
+	ctx.JSON(http.StatusOK, gin.H{"data": createdSale})
+}
+
+func (s *Server) releaseReservationHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid reservation ID: %s", err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	reservation, err := s.repo.ReservationRepository.Release(ctx, id, payload.UserID)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": reservation})
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+var returnDispositions = map[string]string{
+	"restock":    repository.RETURN_RESTOCKED,
+	"quarantine": repository.RETURN_QUARANTINED,
+}
+
+var returnResolutions = map[string]string{
+	"restock": repository.RETURN_RESTOCKED,
+	"dispose": repository.RETURN_DISPOSED,
+}
+
+type createReturnRequest struct {
+	MovementID  *uint32 `json:"movement_id"`
+	SaleItemID  *uint32 `json:"sale_item_id"`
+	Quantity    int32   `json:"quantity" binding:"required,gt=0"`
+	Disposition string  `json:"disposition" binding:"required,oneof=restock quarantine"`
+	Reason      *string `json:"reason"`
+	BatchNumber *string `json:"batch_number"`
+	witnessRequest
+}
+
+func (s *Server) createReturnHandler(ctx *gin.Context) {
+	var req createReturnRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	if req.MovementID == nil && req.SaleItemID == nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "either movement_id or sale_item_id is required")))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	witnessedBy, err := s.verifyWitness(ctx, payload.UserID, req.witnessRequest)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ret := &repository.Return{
+		SaleItemID:  req.SaleItemID,
+		Quantity:    req.Quantity,
+		Status:      returnDispositions[req.Disposition],
+		Reason:      req.Reason,
+		BatchNumber: req.BatchNumber,
+		PerformedBy: payload.UserID,
+		WitnessedBy: witnessedBy,
+	}
+	if req.MovementID != nil {
+		ret.MovementID = *req.MovementID
+	}
+
+	createdReturn, err := s.repo.ReturnRepository.Create(ctx, ret)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": createdReturn})
+}
+
+type resolveReturnRequest struct {
+	Action string `json:"action" binding:"required,oneof=restock dispose"`
+	witnessRequest
+}
+
+func (s *Server) resolveReturnHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid return ID: %s", err.Error())))
+		return
+	}
+
+	var req resolveReturnRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	witnessedBy, err := s.verifyWitness(ctx, payload.UserID, req.witnessRequest)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ret, err := s.repo.ReturnRepository.Resolve(ctx, id, returnResolutions[req.Action], payload.UserID, witnessedBy)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": ret})
+}
+
+func (s *Server) getReturnHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid return ID: %s", err.Error())))
+		return
+	}
+
+	ret, err := s.repo.ReturnRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": ret})
+}
+
+func (s *Server) listReturnsHandler(ctx *gin.Context) {
+	pageNoStr := ctx.DefaultQuery("page", "1")
+	pageNo, err := pkg.StringToInt64(pageNoStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	pageSizeStr := ctx.DefaultQuery("limit", "10")
+	pageSize, err := pkg.StringToInt64(pageSizeStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	filter := &repository.ReturnFilter{
+		Pagination: &pkg.Pagination{
+			Page:     uint32(pageNo),
+			PageSize: uint32(pageSize),
+		},
+		Status:    nil,
+		ProductID: nil,
+		SaleID:    nil,
+	}
+
+	if status := ctx.Query("status"); status != "" {
+		filter.Status = &status
+	}
+
+	if productIDStr := ctx.Query("product_id"); productIDStr != "" {
+		productID, err := pkg.StringToInt64(productIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid product ID: %s", err.Error())))
+
+			return
+		}
+		pid := uint32(productID)
+		filter.ProductID = &pid
+	}
+
+	if saleIDStr := ctx.Query("sale_id"); saleIDStr != "" {
+		saleID, err := pkg.StringToInt64(saleIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid sale ID: %s", err.Error())))
+
+			return
+		}
+		sid := uint32(saleID)
+		filter.SaleID = &sid
+	}
+
+	returns, pagination, err := s.repo.ReturnRepository.List(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       returns,
+		"pagination": pagination,
+	})
+}
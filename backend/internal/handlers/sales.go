@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type saleItemRequest struct {
+	ProductID uint32 `json:"product_id" binding:"required"`
+	Quantity  int64  `json:"quantity" binding:"required,gt=0"`
+
+	// PrescriptionID is required for prescription-only products.
+	PrescriptionID *uint32 `json:"prescription_id"`
+}
+
+type createSaleRequest struct {
+	Items      []saleItemRequest `json:"items" binding:"required,min=1,dive"`
+	Note       *string           `json:"note"`
+	Location   string            `json:"location"`
+	CustomerID *uint32           `json:"customer_id"`
+
+	// OnAccount invoices the sale to the customer's credit account.
+	OnAccount bool `json:"on_account"`
+
+	// required for insurance tenders
+	SchemeID     *uint32 `json:"scheme_id"`
+	MemberNumber *string `json:"member_number"`
+
+	// tenders taken at the till; M-Pesa is requested separately once the sale exists
+	Payments []paymentRequest `json:"payments" binding:"dive"`
+
+	// required when any line is a controlled product
+	witnessRequest
+}
+
+// resolveLocation normalises a sale or shift location, which prefixes receipt
+// numbers, and checks it against the configured locations and the default one,
+// which is also used when no location is given.
+func (s *Server) resolveLocation(location string) (string, error) {
+	location = strings.ToUpper(strings.TrimSpace(location))
+	if location == "" || strings.EqualFold(location, s.config.DEFAULT_LOCATION) {
+		return s.config.DEFAULT_LOCATION, nil
+	}
+
+	for _, known := range s.config.LOCATIONS {
+		if strings.EqualFold(known, location) {
+			return location, nil
+		}
+	}
+
+	return "", pkg.Errorf(pkg.INVALID_ERROR, "unknown location: %s", location)
+}
+
+func (s *Server) createSaleHandler(ctx *gin.Context) {
+	var req createSaleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	witnessedBy, err := s.verifyWitness(ctx, payload.UserID, req.witnessRequest)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	location, err := s.resolveLocation(req.Location)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	sale := &repository.Sale{
+		Location:     location,
+		Note:         req.Note,
+		CustomerID:   req.CustomerID,
+		OnAccount:    req.OnAccount,
+		SchemeID:     req.SchemeID,
+		MemberNumber: req.MemberNumber,
+		WitnessedBy:  witnessedBy,
+		PerformedBy:  payload.UserID,
+		Items:        make([]*repository.SaleItem, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		sale.Items[i] = &repository.SaleItem{
+			ProductID:      item.ProductID,
+			Quantity:       item.Quantity,
+			PrescriptionID: item.PrescriptionID,
+		}
+	}
+	for _, payment := range req.Payments {
+		sale.Payments = append(sale.Payments, &repository.Payment{
+			Method:    payment.Method,
+			Amount:    payment.Amount,
+			Reference: payment.Reference,
+		})
+	}
+
+	createdSale, err := s.repo.SalesRepository.Create(ctx, sale)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	s.transmitTaxInvoice(ctx, createdSale)
+
+	// invalidate products cache
+
+	ctx.JSON(http.StatusOK, gin.H{"data": createdSale})
+}
+
+func (s *Server) getSaleHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid sale ID: %s", err.Error())))
+		return
+	}
+
+	sale, err := s.repo.SalesRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": sale})
+}
+
+func (s *Server) listSalesHandler(ctx *gin.Context) {
+	pageNoStr := ctx.DefaultQuery("page", "1")
+	pageNo, err := pkg.StringToInt64(pageNoStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	pageSizeStr := ctx.DefaultQuery("limit", "10")
+	pageSize, err := pkg.StringToInt64(pageSizeStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	filter := &repository.SaleFilter{
+		Pagination: &pkg.Pagination{
+			Page:     uint32(pageNo),
+			PageSize: uint32(pageSize),
+		},
+		Location:    nil,
+		PerformedBy: nil,
+		CustomerID:  nil,
+		StartDate:   nil,
+		EndDate:     nil,
+	}
+
+	if location := ctx.Query("location"); location != "" {
+		location = strings.ToUpper(location)
+		filter.Location = &location
+	}
+
+	if customerIDStr := ctx.Query("customer_id"); customerIDStr != "" {
+		customerID, err := pkg.StringToInt64(customerIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid customer ID: %s", err.Error())))
+
+			return
+		}
+		cid := uint32(customerID)
+		filter.CustomerID = &cid
+	}
+
+	if performedByStr := ctx.Query("performed_by"); performedByStr != "" {
+		performedBy, err := pkg.StringToInt64(performedByStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid user ID: %s", err.Error())))
+
+			return
+		}
+		uid := uint32(performedBy)
+		filter.PerformedBy = &uid
+	}
+
+	startDateStr := ctx.DefaultQuery("from", "01/01/2025")
+	startDate, err := pkg.StringToTime(startDateStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid start date: %s", err.Error())))
+
+		return
+	}
+	filter.StartDate = &startDate
+
+	toDateStr := ctx.DefaultQuery("to", time.Now().Add(time.Hour*24).Format("01/02/2006"))
+	endDate, err := pkg.StringToTime(toDateStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid end date: %s", err.Error())))
+
+		return
+	}
+	endDate = endDate.Add(time.Hour * 24)
+	filter.EndDate = &endDate
+
+	sales, pagination, err := s.repo.SalesRepository.List(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       sales,
+		"pagination": pagination,
+	})
+}
+
+// printSaleReceiptHandler renders the sale's receipt and counts the print, so
+// reprints are marked as copies.
+func (s *Server) printSaleReceiptHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid sale ID: %s", err.Error())))
+		return
+	}
+
+	format := ctx.DefaultQuery("format", services.REPORT_FORMAT_PDF)
+	if format != services.REPORT_FORMAT_PDF && format != services.REPORT_FORMAT_TEXT {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid format: %s", format)))
+		return
+	}
+
+	sale, err := s.repo.SalesRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	printCount, err := s.repo.SalesRepository.RecordReceiptPrint(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+	sale.ReceiptPrintCount = printCount
+
+	receipt, err := s.report.SaleReceipt(sale, format)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if format == services.REPORT_FORMAT_TEXT {
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", receipt)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=%s.pdf", sale.ReceiptNumber))
+	ctx.Data(http.StatusOK, "application/pdf", receipt)
+}
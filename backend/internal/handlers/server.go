@@ -25,11 +25,16 @@ type Server struct {
 	tokenMaker pkg.JWTMaker
 	repo       *postgres.PostgresRepo
 
-	cache  services.CacheService
-	report services.ReportService
+	cache       services.CacheService
+	report      services.ReportService
+	mobileMoney services.MobileMoneyService
+
+	// insuranceClients are keyed by the client name insurers are configured with
+	insuranceClients map[string]services.InsuranceClient
+	taxInvoices      services.TaxInvoiceService
 }
 
-func NewServer(config pkg.Config, tokenMaker pkg.JWTMaker, repo *postgres.PostgresRepo, cache services.CacheService, report services.ReportService) *Server {
+func NewServer(config pkg.Config, tokenMaker pkg.JWTMaker, repo *postgres.PostgresRepo, cache services.CacheService, report services.ReportService, mobileMoney services.MobileMoneyService, insuranceClients map[string]services.InsuranceClient, taxInvoices services.TaxInvoiceService) *Server {
 	if config.ENVIRONMENT == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -44,8 +49,12 @@ func NewServer(config pkg.Config, tokenMaker pkg.JWTMaker, repo *postgres.Postgr
 		tokenMaker: tokenMaker,
 		repo:       repo,
 
-		cache:  cache,
-		report: report,
+		cache:       cache,
+		report:      report,
+		mobileMoney: mobileMoney,
+
+		insuranceClients: insuranceClients,
+		taxInvoices:      taxInvoices,
 	}
 
 	s.setUpRoutes()
@@ -92,10 +101,148 @@ func (s *Server) setUpRoutes() {
 	authRoute.POST("/products/:id/add-stock", s.addProductStockHandler)
 	authRoute.POST("/products/:id/remove-stock", s.removeProductStockHandler)
 	cacheRoute.GET("/products/movements", s.listProductMovementsHandler)
+	cacheRoute.GET("/products/:id/price-history", s.listProductPriceHistoryHandler)
+	cacheRoute.GET("/products/:id/substitutes", s.listProductSubstitutesHandler)
+	authRoute.PUT("/products/:id/components", s.setKitComponentsHandler)
+	authRoute.POST("/products/:id/assemble", s.assembleKitHandler)
+	authRoute.PUT("/products/:id/reorder-policy", s.setReorderPolicyHandler)
+	authRoute.POST("/products/classify", s.classifyProductsHandler)
 	cacheRoute.GET("/stats", s.getStatsHandler)
 	cacheRoute.GET("/dashboard", s.GetDashboardData)
 
+	// sales routes
+	authRoute.POST("/sales", s.createSaleHandler)
+	cacheRoute.GET("/sales/:id", s.getSaleHandler)
+	cacheRoute.GET("/sales", s.listSalesHandler)
+	authRoute.POST("/sales/:id/receipt", s.printSaleReceiptHandler)
+	authRoute.POST("/sales/:id/payments", s.createPaymentHandler)
+	cacheRoute.GET("/sales/:id/payments", s.listSalePaymentsHandler)
+	authRoute.POST("/sales/:id/tax-invoice", s.retryTaxInvoiceHandler)
+
+	// payments routes
+	// the token in the path is the only check on callbacks since the provider cannot authenticate
+	v1.POST("/payments/mpesa/callback/:token", s.mpesaCallbackHandler)
+
+	// shifts routes
+	authRoute.POST("/shifts", s.openShiftHandler)
+	authRoute.GET("/shifts/current", s.getCurrentShiftHandler)
+	authRoute.GET("/shifts/:id", s.getShiftHandler)
+	authRoute.POST("/shifts/:id/close", s.closeShiftHandler)
+	cacheRoute.GET("/shifts", s.listShiftsHandler)
+
+	// reservations routes
+	authRoute.POST("/reservations", s.createReservationHandler)
+	cacheRoute.GET("/reservations/:id", s.getReservationHandler)
+	cacheRoute.GET("/reservations", s.listReservationsHandler)
+	authRoute.POST("/reservations/:id/convert", s.convertReservationHandler)
+	authRoute.POST("/reservations/:id/release", s.releaseReservationHandler)
+
+	// quotations routes
+	authRoute.POST("/quotations", s.createQuotationHandler)
+	cacheRoute.GET("/quotations/:id", s.getQuotationHandler)
+	cacheRoute.GET("/quotations", s.listQuotationsHandler)
+	authRoute.GET("/quotations/:id/document", s.getQuotationDocumentHandler)
+	authRoute.POST("/quotations/:id/convert", s.convertQuotationHandler)
+	authRoute.POST("/quotations/:id/cancel", s.cancelQuotationHandler)
+
+	// insurance routes
+	authRoute.POST("/insurers", s.createInsurerHandler)
+	cacheRoute.GET("/insurers/:id", s.getInsurerHandler)
+	authRoute.PUT("/insurers/:id", s.updateInsurerHandler)
+	authRoute.DELETE("/insurers/:id", s.deleteInsurerHandler)
+	cacheRoute.GET("/insurers", s.listInsurersHandler)
+	authRoute.POST("/insurers/:id/schemes", s.createSchemeHandler)
+	authRoute.PUT("/insurance-schemes/:id", s.updateSchemeHandler)
+	cacheRoute.GET("/insurance-schemes", s.listSchemesHandler)
+
+	// claims routes
+	authRoute.POST("/claim-batches", s.createClaimBatchHandler)
+	authRoute.GET("/claim-batches/:id", s.getClaimBatchHandler)
+	cacheRoute.GET("/claim-batches", s.listClaimBatchesHandler)
+	authRoute.POST("/claim-batches/:id/submit", s.submitClaimBatchHandler)
+	authRoute.POST("/claim-batches/:id/refresh", s.refreshClaimBatchHandler)
+	authRoute.POST("/claim-batches/:id/claims/:claimId/decision", s.decideClaimHandler)
+
+	// tax invoices routes
+	cacheRoute.GET("/tax-invoices", s.listTaxInvoicesHandler)
+
+	// customers routes
+	authRoute.POST("/customers", s.createCustomerHandler)
+	cacheRoute.GET("/customers/:id", s.getCustomerHandler)
+	authRoute.PUT("/customers/:id", s.updateCustomerHandler)
+	authRoute.DELETE("/customers/:id", s.deleteCustomerHandler)
+	cacheRoute.GET("/customers", s.listCustomersHandler)
+	cacheRoute.GET("/customers/:id/purchases", s.listCustomerPurchasesHandler)
+	cacheRoute.GET("/customers/:id/account", s.getCustomerAccountHandler)
+	authRoute.POST("/customers/:id/account/payments", s.recordAccountPaymentHandler)
+	authRoute.GET("/customers/:id/statement", s.getCustomerStatementHandler)
+
+	// prescriptions routes
+	authRoute.POST("/prescriptions", s.createPrescriptionHandler)
+	cacheRoute.GET("/prescriptions/:id", s.getPrescriptionHandler)
+	cacheRoute.GET("/prescriptions", s.listPrescriptionsHandler)
+	authRoute.POST("/prescriptions/:id/scans", s.uploadPrescriptionScanHandler)
+	authRoute.GET("/prescriptions/:id/scans/:scanId", s.getPrescriptionScanHandler)
+
+	// returns routes
+	authRoute.POST("/returns", s.createReturnHandler)
+	cacheRoute.GET("/returns/:id", s.getReturnHandler)
+	cacheRoute.GET("/returns", s.listReturnsHandler)
+	authRoute.POST("/returns/:id/resolve", s.resolveReturnHandler)
+
+	// categories routes
+	authRoute.POST("/categories", s.createCategoryHandler)
+	cacheRoute.GET("/categories/:id", s.getCategoryHandler)
+	authRoute.PUT("/categories/:id", s.updateCategoryHandler)
+	authRoute.DELETE("/categories/:id", s.deleteCategoryHandler)
+	cacheRoute.GET("/categories", s.listCategoriesHandler)
+
+	// tax classes routes
+	authRoute.POST("/tax-classes", s.createTaxClassHandler)
+	cacheRoute.GET("/tax-classes/:id", s.getTaxClassHandler)
+	authRoute.PUT("/tax-classes/:id", s.updateTaxClassHandler)
+	cacheRoute.GET("/tax-classes", s.listTaxClassesHandler)
+
+	// promotions routes
+	authRoute.POST("/promotions", s.createPromotionHandler)
+	cacheRoute.GET("/promotions/:id", s.getPromotionHandler)
+	authRoute.PUT("/promotions/:id", s.updatePromotionHandler)
+	cacheRoute.GET("/promotions", s.listPromotionsHandler)
+
+	// suppliers routes
+	authRoute.POST("/suppliers", s.createSupplierHandler)
+	cacheRoute.GET("/suppliers/:id", s.getSupplierHandler)
+	authRoute.PUT("/suppliers/:id", s.updateSupplierHandler)
+	authRoute.DELETE("/suppliers/:id", s.deleteSupplierHandler)
+	cacheRoute.GET("/suppliers", s.listSuppliersHandler)
+
+	// purchase orders routes
+	authRoute.POST("/purchase-orders/generate", s.generatePurchaseOrdersHandler)
+	cacheRoute.GET("/purchase-orders/:id", s.getPurchaseOrderHandler)
+	cacheRoute.GET("/purchase-orders", s.listPurchaseOrdersHandler)
+	authRoute.PUT("/purchase-orders/:id/items/:itemId", s.setPurchaseOrderItemHandler)
+	authRoute.POST("/purchase-orders/:id/order", s.orderPurchaseOrderHandler)
+	authRoute.POST("/purchase-orders/:id/receive", s.receivePurchaseOrderHandler)
+	authRoute.POST("/purchase-orders/:id/cancel", s.cancelPurchaseOrderHandler)
+
+	// forecasts routes
+	cacheRoute.GET("/forecasts", s.listForecastsHandler)
+	cacheRoute.GET("/products/:id/forecast", s.getProductForecastHandler)
+
+	// supplier returns routes
+	authRoute.POST("/supplier-returns", s.createSupplierReturnHandler)
+	cacheRoute.GET("/supplier-returns/:id", s.getSupplierReturnHandler)
+	cacheRoute.GET("/supplier-returns", s.listSupplierReturnsHandler)
+	authRoute.POST("/supplier-returns/:id/credits", s.recordSupplierCreditHandler)
+	authRoute.GET("/supplier-returns/:id/debit-note", s.getDebitNoteHandler)
+
 	// reports routes
+	cacheRoute.GET("/reports/valuation", s.getValuationReportHandler)
+	authRoute.GET("/reports/controlled-drugs", s.getControlledDrugRegisterHandler)
+	authRoute.GET("/reports/tax-summary", s.getTaxSummaryHandler)
+	authRoute.GET("/reports/discounts", s.getDiscountReportHandler)
+	authRoute.GET("/reports/ageing", s.getAgeingReportHandler)
+	authRoute.GET("/reports/z-report", s.getZReportHandler)
 
 	s.srv = &http.Server{
 		Addr:         s.config.SERVER_ADDRESS,
@@ -140,10 +287,15 @@ func (s *Server) GetPort() int {
 }
 
 func errorResponse(err error) gin.H {
-	return gin.H{
+	resp := gin.H{
 		"status_code": pkg.ErrorCode(err),
 		"message":     pkg.ErrorMessage(err),
 	}
+	if details := pkg.ErrorDetails(err); details != nil {
+		resp["details"] = details
+	}
+
+	return resp
 }
 
 func constructCacheKey(path string, queryParams map[string][]string) string {
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type openShiftRequest struct {
+	Location     string  `json:"location"`
+	OpeningFloat float64 `json:"opening_float" binding:"gte=0"`
+	Note         *string `json:"note"`
+}
+
+type closeShiftRequest struct {
+	// Counted is the amount counted per payment method, e.g. {"CASH": 5200, "MPESA": 12000}.
+	Counted map[string]float64 `json:"counted" binding:"required"`
+	Note    *string            `json:"note"`
+}
+
+func (s *Server) openShiftHandler(ctx *gin.Context) {
+	var req openShiftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	location, err := s.resolveLocation(req.Location)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	shift, err := s.repo.ShiftRepository.Open(ctx, &repository.Shift{
+		UserID:       payload.UserID,
+		Location:     location,
+		OpeningFloat: req.OpeningFloat,
+		OpeningNote:  req.Note,
+	})
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": shift})
+}
+
+func (s *Server) getShiftHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid shift ID: %s", err.Error())))
+		return
+	}
+
+	shift, err := s.repo.ShiftRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": shift})
+}
+
+func (s *Server) getCurrentShiftHandler(ctx *gin.Context) {
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	shift, err := s.repo.ShiftRepository.GetOpen(ctx, payload.UserID)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": shift})
+}
+
+func (s *Server) closeShiftHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid shift ID: %s", err.Error())))
+		return
+	}
+
+	var req closeShiftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	shift, err := s.repo.ShiftRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if shift.UserID != payload.UserID && payload.Role != repository.ADMIN_ROLE {
+		ctx.JSON(http.StatusForbidden, errorResponse(pkg.Errorf(pkg.FORBIDDEN_ERROR, "only the cashier or an admin can close this shift")))
+		return
+	}
+
+	counted := make(map[string]float64, len(req.Counted))
+	for method, amount := range req.Counted {
+		counted[strings.ToUpper(method)] = amount
+	}
+
+	closedShift, err := s.repo.ShiftRepository.Close(ctx, id, &repository.ShiftClose{
+		Counted: counted,
+		Note:    req.Note,
+	})
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": closedShift})
+}
+
+func (s *Server) listShiftsHandler(ctx *gin.Context) {
+	pageNoStr := ctx.DefaultQuery("page", "1")
+	pageNo, err := pkg.StringToInt64(pageNoStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	pageSizeStr := ctx.DefaultQuery("limit", "10")
+	pageSize, err := pkg.StringToInt64(pageSizeStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	filter := &repository.ShiftFilter{
+		Pagination: &pkg.Pagination{
+			Page:     uint32(pageNo),
+			PageSize: uint32(pageSize),
+		},
+		UserID:    nil,
+		Location:  nil,
+		Status:    nil,
+		StartDate: nil,
+		EndDate:   nil,
+	}
+
+	if location := ctx.Query("location"); location != "" {
+		location = strings.ToUpper(location)
+		filter.Location = &location
+	}
+
+	if status := ctx.Query("status"); status != "" {
+		status = strings.ToUpper(status)
+		filter.Status = &status
+	}
+
+	if userIDStr := ctx.Query("user_id"); userIDStr != "" {
+		userID, err := pkg.StringToInt64(userIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid user ID: %s", err.Error())))
+
+			return
+		}
+		uid := uint32(userID)
+		filter.UserID = &uid
+	}
+
+	if fromStr, toStr := ctx.Query("from"), ctx.Query("to"); fromStr != "" && toStr != "" {
+		startDate, err := pkg.StringToTime(fromStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid start date: %s", err.Error())))
+
+			return
+		}
+		endDate, err := pkg.StringToTime(toStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid end date: %s", err.Error())))
+
+			return
+		}
+		endDate = endDate.Add(time.Hour * 24)
+		filter.StartDate = &startDate
+		filter.EndDate = &endDate
+	}
+
+	shifts, pagination, err := s.repo.ShiftRepository.List(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       shifts,
+		"pagination": pagination,
+	})
+}
+
+func (s *Server) getZReportHandler(ctx *gin.Context) {
+	location := strings.ToUpper(strings.TrimSpace(ctx.Query("location")))
+	if location == "" {
+		location = s.config.DEFAULT_LOCATION
+	}
+
+	// defaults to today
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if dateStr := ctx.Query("date"); dateStr != "" {
+		date, err := pkg.StringToTime(dateStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid date: %s", err.Error())))
+			return
+		}
+		startDate = date
+	}
+	endDate := startDate.Add(time.Hour * 24)
+
+	format := ctx.DefaultQuery("format", "json")
+	if format != "json" && format != services.REPORT_FORMAT_PDF && format != services.REPORT_FORMAT_TEXT {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid format: %s", format)))
+		return
+	}
+
+	report, err := s.repo.ShiftRepository.GetZReport(ctx, location, startDate, endDate)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if format == "json" {
+		ctx.JSON(http.StatusOK, gin.H{"data": report})
+		return
+	}
+
+	document, err := s.report.ZReport(report, format)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if format == services.REPORT_FORMAT_TEXT {
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", document)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=z-report-%s-%s.pdf", location, startDate.Format("2006-01-02")))
+	ctx.Data(http.StatusOK, "application/pdf", document)
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type supplierReturnItemRequest struct {
+	ProductID   uint32   `json:"product_id" binding:"required"`
+	BatchNumber *string  `json:"batch_number"`
+	Quantity    int64    `json:"quantity" binding:"gte=0"`
+	UnitCost    *float64 `json:"unit_cost" binding:"omitempty,gt=0"`
+	Reason      *string  `json:"reason"` // e.g. EXPIRED, DAMAGED
+	ReturnID    *uint32  `json:"return_id"`
+}
+
+type createSupplierReturnRequest struct {
+	SupplierID uint32                      `json:"supplier_id" binding:"required"`
+	Note       *string                     `json:"note"`
+	Items      []supplierReturnItemRequest `json:"items" binding:"required,min=1,dive"`
+	witnessRequest
+}
+
+func (s *Server) createSupplierReturnHandler(ctx *gin.Context) {
+	var req createSupplierReturnRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	witnessedBy, err := s.verifyWitness(ctx, payload.UserID, req.witnessRequest)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	supplierReturn := &repository.SupplierReturn{
+		SupplierID:  req.SupplierID,
+		Note:        req.Note,
+		PerformedBy: payload.UserID,
+		WitnessedBy: witnessedBy,
+		Items:       make([]*repository.SupplierReturnItem, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		// quarantined returns go back in full so their quantity can be left out
+		if item.ReturnID == nil && item.Quantity == 0 {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "quantity is required for product %d", item.ProductID)))
+			return
+		}
+
+		supplierReturn.Items[i] = &repository.SupplierReturnItem{
+			ProductID:   item.ProductID,
+			BatchNumber: item.BatchNumber,
+			Quantity:    item.Quantity,
+			Reason:      item.Reason,
+			ReturnID:    item.ReturnID,
+		}
+		if item.UnitCost != nil {
+			supplierReturn.Items[i].UnitCost = *item.UnitCost
+		}
+	}
+
+	createdSupplierReturn, err := s.repo.SupplierReturnRepository.Create(ctx, supplierReturn)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": createdSupplierReturn})
+}
+
+func (s *Server) getSupplierReturnHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid supplier return ID: %s", err.Error())))
+		return
+	}
+
+	supplierReturn, err := s.repo.SupplierReturnRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": supplierReturn})
+}
+
+func (s *Server) listSupplierReturnsHandler(ctx *gin.Context) {
+	pageNoStr := ctx.DefaultQuery("page", "1")
+	pageNo, err := pkg.StringToInt64(pageNoStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	pageSizeStr := ctx.DefaultQuery("limit", "10")
+	pageSize, err := pkg.StringToInt64(pageSizeStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	filter := &repository.SupplierReturnFilter{
+		Pagination: &pkg.Pagination{
+			Page:     uint32(pageNo),
+			PageSize: uint32(pageSize),
+		},
+		SupplierID:   nil,
+		CreditStatus: nil,
+	}
+
+	if supplierIDStr := ctx.Query("supplier_id"); supplierIDStr != "" {
+		supplierID, err := pkg.StringToInt64(supplierIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid supplier ID: %s", err.Error())))
+
+			return
+		}
+		sid := uint32(supplierID)
+		filter.SupplierID = &sid
+	}
+
+	if creditStatus := ctx.Query("credit_status"); creditStatus != "" {
+		filter.CreditStatus = &creditStatus
+	}
+
+	supplierReturns, pagination, err := s.repo.SupplierReturnRepository.List(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       supplierReturns,
+		"pagination": pagination,
+	})
+}
+
+type supplierCreditRequest struct {
+	Amount     float64 `json:"amount" binding:"required,gt=0"`
+	Reference  *string `json:"reference"`
+	ReceivedAt *string `json:"received_at"` // mm/dd/yyyy, defaults to today
+}
+
+func (s *Server) recordSupplierCreditHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid supplier return ID: %s", err.Error())))
+		return
+	}
+
+	var req supplierCreditRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	receivedAt := time.Now()
+	if req.ReceivedAt != nil {
+		receivedAt, err = pkg.StringToTime(*req.ReceivedAt)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid received at date: %s", err.Error())))
+			return
+		}
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	supplierReturn, err := s.repo.SupplierReturnRepository.RecordCredit(ctx, &repository.SupplierCredit{
+		SupplierReturnID: uint32(id),
+		Amount:           req.Amount,
+		Reference:        req.Reference,
+		ReceivedAt:       receivedAt,
+		RecordedBy:       payload.UserID,
+	})
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": supplierReturn})
+}
+
+func (s *Server) getDebitNoteHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid supplier return ID: %s", err.Error())))
+		return
+	}
+
+	format := ctx.DefaultQuery("format", services.REPORT_FORMAT_PDF)
+	if format != services.REPORT_FORMAT_PDF && format != services.REPORT_FORMAT_TEXT {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid format: %s", format)))
+		return
+	}
+
+	supplierReturn, err := s.repo.SupplierReturnRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	supplier, err := s.repo.SupplierRepository.GetByID(ctx, int64(supplierReturn.SupplierID))
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	debitNote, err := s.report.DebitNote(supplierReturn, supplier, format)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if format == services.REPORT_FORMAT_TEXT {
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", debitNote)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=%s.pdf", supplierReturn.DebitNoteNumber))
+	ctx.Data(http.StatusOK, "application/pdf", debitNote)
+}
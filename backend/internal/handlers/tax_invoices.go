@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+// taxInvoiceTimeout bounds how long a sale waits for its tax invoice to be
+// signed before the sale is returned with the invoice still queued.
+const taxInvoiceTimeout = 10 * time.Second
+
+// transmitTaxInvoice sends a new sale's tax invoice so the receipt can carry the
+// signature. A failure does not fail the sale; the invoice stays queued and is
+// retried in the background.
+func (s *Server) transmitTaxInvoice(ctx context.Context, sale *repository.Sale) {
+	ctx, cancel := context.WithTimeout(ctx, taxInvoiceTimeout)
+	defer cancel()
+
+	invoice, err := s.taxInvoices.Transmit(ctx, int64(sale.ID))
+	if err != nil {
+		log.Printf("failed to transmit tax invoice for sale %d: %v", sale.ID, err)
+		return
+	}
+	sale.TaxInvoice = invoice
+}
+
+func (s *Server) listTaxInvoicesHandler(ctx *gin.Context) {
+	pageNoStr := ctx.DefaultQuery("page", "1")
+	pageNo, err := pkg.StringToInt64(pageNoStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	pageSizeStr := ctx.DefaultQuery("limit", "10")
+	pageSize, err := pkg.StringToInt64(pageSizeStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+
+		return
+	}
+
+	filter := &repository.TaxInvoiceFilter{
+		Pagination: &pkg.Pagination{
+			Page:     uint32(pageNo),
+			PageSize: uint32(pageSize),
+		},
+		Status: nil,
+	}
+
+	if status := ctx.Query("status"); status != "" {
+		status = strings.ToUpper(status)
+		filter.Status = &status
+	}
+
+	invoices, pagination, err := s.repo.TaxInvoiceRepository.List(ctx, filter)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       invoices,
+		"pagination": pagination,
+	})
+}
+
+// retryTaxInvoiceHandler sends a sale's unsigned tax invoice now rather than
+// waiting for its next retry. Rejected invoices are sent again too, once whatever
+// they were rejected for has been fixed.
+func (s *Server) retryTaxInvoiceHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid sale ID: %s", err.Error())))
+		return
+	}
+
+	invoice, err := s.repo.TaxInvoiceRepository.GetBySale(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if invoice.Status == repository.TAX_INVOICE_SIGNED {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "tax invoice for sale %d is already signed", id)))
+		return
+	}
+
+	if _, err := s.repo.TaxInvoiceRepository.Requeue(ctx, id); err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	invoice, err = s.taxInvoices.Transmit(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": invoice})
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+type createTaxClassRequest struct {
+	Code string   `json:"code" binding:"required,max=5"`
+	Name string   `json:"name" binding:"required"`
+	Type string   `json:"type" binding:"required,oneof=STANDARD ZERO_RATED EXEMPT"`
+	Rate *float64 `json:"rate" binding:"omitempty,gte=0,lt=100"`
+}
+
+func (s *Server) createTaxClassHandler(ctx *gin.Context) {
+	var req createTaxClassRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	if payload.Role != repository.ADMIN_ROLE {
+		ctx.JSON(http.StatusForbidden, errorResponse(pkg.Errorf(pkg.FORBIDDEN_ERROR, "only admin users can manage tax classes")))
+		return
+	}
+
+	taxClass := &repository.TaxClass{
+		Code: req.Code,
+		Name: req.Name,
+		Type: req.Type,
+	}
+	if req.Rate != nil {
+		taxClass.Rate = *req.Rate
+	}
+
+	createdTaxClass, err := s.repo.TaxClassRepository.Create(ctx, taxClass)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": createdTaxClass})
+}
+
+func (s *Server) getTaxClassHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid tax class ID: %s", err.Error())))
+		return
+	}
+
+	taxClass, err := s.repo.TaxClassRepository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": taxClass})
+}
+
+func (s *Server) updateTaxClassHandler(ctx *gin.Context) {
+	id, err := pkg.StringToInt64(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid tax class ID: %s", err.Error())))
+		return
+	}
+
+	var req repository.TaxClassUpdate
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, err.Error())))
+		return
+	}
+
+	if req.Rate != nil && (*req.Rate < 0 || *req.Rate >= 100) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "rate must be between 0 and 100")))
+		return
+	}
+
+	// get user from context
+	authPayload, ok := ctx.Get(authorizationPayloadKey)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get auth payload")))
+		return
+	}
+	payload := authPayload.(*pkg.Payload)
+
+	if payload.Role != repository.ADMIN_ROLE {
+		ctx.JSON(http.StatusForbidden, errorResponse(pkg.Errorf(pkg.FORBIDDEN_ERROR, "only admin users can manage tax classes")))
+		return
+	}
+
+	taxClass, err := s.repo.TaxClassRepository.Update(ctx, id, &req)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": taxClass})
+}
+
+func (s *Server) listTaxClassesHandler(ctx *gin.Context) {
+	taxClasses, err := s.repo.TaxClassRepository.List(ctx)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": taxClasses})
+}
+
+func (s *Server) getTaxSummaryHandler(ctx *gin.Context) {
+	// defaults to the current month
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	endDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+
+	if fromStr := ctx.Query("from"); fromStr != "" {
+		from, err := pkg.StringToTime(fromStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid start date: %s", err.Error())))
+			return
+		}
+		startDate = from
+	}
+
+	if toStr := ctx.Query("to"); toStr != "" {
+		to, err := pkg.StringToTime(toStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid end date: %s", err.Error())))
+			return
+		}
+		endDate = to.Add(time.Hour * 24)
+	}
+
+	if !endDate.After(startDate) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "end date must not be before start date")))
+		return
+	}
+
+	format := ctx.DefaultQuery("format", "json")
+	if format != "json" && format != services.REPORT_FORMAT_PDF && format != services.REPORT_FORMAT_TEXT {
+		ctx.JSON(http.StatusBadRequest, errorResponse(pkg.Errorf(pkg.INVALID_ERROR, "invalid format: %s", format)))
+		return
+	}
+
+	summary, err := s.repo.TaxClassRepository.GetTaxSummary(ctx, startDate, endDate)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if format == "json" {
+		ctx.JSON(http.StatusOK, gin.H{"data": summary})
+		return
+	}
+
+	document, err := s.report.TaxSummary(summary, format)
+	if err != nil {
+		ctx.JSON(pkg.ErrorToStatusCode(err), errorResponse(err))
+		return
+	}
+
+	if format == services.REPORT_FORMAT_TEXT {
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", document)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=tax-summary-%s.pdf", startDate.Format("2006-01-02")))
+	ctx.Data(http.StatusOK, "application/pdf", document)
+}
@@ -12,7 +12,7 @@ type createUserRequest struct {
 	Name        string `json:"name" binding:"required"`
 	Email       string `json:"email" binding:"required,email"`
 	PhoneNumber string `json:"phone_number" binding:"required"`
-	Role        string `json:"role" binding:"required,oneof=admin staff"`
+	Role        string `json:"role" binding:"required,oneof=admin pharmacist staff"`
 }
 
 func (s *Server) createUserHandler(ctx *gin.Context) {
@@ -0,0 +1,69 @@
+package insurance
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+// STUB_CLIENT is the name insurers are configured with to use the stub client.
+const STUB_CLIENT = "STUB"
+
+var _ services.InsuranceClient = (*stubClient)(nil)
+
+// stubClient stands in for an insurer's claims system during development. It
+// accepts every submission and approves each claim in full. Submissions are only
+// kept in memory, so their statuses are lost when the server restarts.
+type stubClient struct {
+	mu          sync.Mutex
+	submissions map[string][]*services.ClaimLine
+}
+
+func NewStubClient() services.InsuranceClient {
+	return &stubClient{
+		submissions: make(map[string][]*services.ClaimLine),
+	}
+}
+
+func (c *stubClient) SubmitClaims(ctx context.Context, submission *services.ClaimSubmission) (*services.ClaimSubmissionResult, error) {
+	if len(submission.Claims) == 0 {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "a claim submission must have at least one claim")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reference := fmt.Sprintf("%s-%s-%d", submission.InsurerCode, submission.BatchNumber, len(c.submissions)+1)
+	c.submissions[reference] = submission.Claims
+
+	return &services.ClaimSubmissionResult{
+		Reference: reference,
+		Message:   fmt.Sprintf("%d claims received", len(submission.Claims)),
+	}, nil
+}
+
+func (c *stubClient) GetClaimStatuses(ctx context.Context, insurerCode string, reference string) ([]*services.ClaimStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	claims, ok := c.submissions[reference]
+	if !ok {
+		return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "no submission with reference %s", reference)
+	}
+
+	statuses := make([]*services.ClaimStatus, len(claims))
+	for i, claim := range claims {
+		statuses[i] = &services.ClaimStatus{
+			ClaimID:        claim.ClaimID,
+			Status:         repository.CLAIM_APPROVED,
+			ApprovedAmount: claim.Amount,
+			Reference:      fmt.Sprintf("%s/%d", reference, i+1),
+		}
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+type job struct {
+	name     string
+	interval time.Duration
+	fn       func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs at a fixed interval until it is stopped.
+type Scheduler struct {
+	jobs   []job
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers fn to run once every interval. Jobs must be registered before Start.
+func (s *Scheduler) Every(name string, interval time.Duration, fn func(ctx context.Context) error) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, fn: fn})
+}
+
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go func(j job) {
+			defer s.wg.Done()
+
+			ticker := time.NewTicker(j.interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := j.fn(ctx); err != nil {
+						log.Printf("job %s failed: %v", j.name, err)
+					}
+				}
+			}
+		}(j)
+	}
+}
+
+func (s *Scheduler) Stop() {
+	log.Println("Shutting down job scheduler...")
+
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
@@ -0,0 +1,276 @@
+package mpesa
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+var _ services.MobileMoneyService = (*darajaClient)(nil)
+
+const (
+	tokenPath   = "/oauth/v1/generate?grant_type=client_credentials"
+	stkPushPath = "/mpesa/stkpush/v1/processrequest"
+
+	// timestampLayout is the yyyyMMddHHmmss format Daraja expects in the
+	// password and timestamp fields.
+	timestampLayout = "20060102150405"
+)
+
+// darajaClient talks to Safaricom's Daraja API. The base url is configurable so
+// the client can be pointed at the sandbox, production or a local stand-in server.
+type darajaClient struct {
+	baseURL        string
+	consumerKey    string
+	consumerSecret string
+	shortcode      string
+	passkey        string
+	callbackURL    string
+	httpClient     *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func NewMpesaClient(config pkg.Config) services.MobileMoneyService {
+	return &darajaClient{
+		baseURL:        strings.TrimRight(config.MPESA_BASE_URL, "/"),
+		consumerKey:    config.MPESA_CONSUMER_KEY,
+		consumerSecret: config.MPESA_CONSUMER_SECRET,
+		shortcode:      config.MPESA_SHORTCODE,
+		passkey:        config.MPESA_PASSKEY,
+		callbackURL:    config.MPESA_CALLBACK_URL,
+		httpClient:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type stkPushBody struct {
+	BusinessShortCode string `json:"BusinessShortCode"`
+	Password          string `json:"Password"`
+	Timestamp         string `json:"Timestamp"`
+	TransactionType   string `json:"TransactionType"`
+	Amount            int64  `json:"Amount"`
+	PartyA            string `json:"PartyA"`
+	PartyB            string `json:"PartyB"`
+	PhoneNumber       string `json:"PhoneNumber"`
+	CallBackURL       string `json:"CallBackURL"`
+	AccountReference  string `json:"AccountReference"`
+	TransactionDesc   string `json:"TransactionDesc"`
+}
+
+type stkPushResult struct {
+	MerchantRequestID   string `json:"MerchantRequestID"`
+	CheckoutRequestID   string `json:"CheckoutRequestID"`
+	ResponseCode        string `json:"ResponseCode"`
+	ResponseDescription string `json:"ResponseDescription"`
+	CustomerMessage     string `json:"CustomerMessage"`
+	ErrorCode           string `json:"errorCode"`
+	ErrorMessage        string `json:"errorMessage"`
+}
+
+func (c *darajaClient) InitiateSTKPush(ctx context.Context, req *services.STKPushRequest) (*services.STKPushResponse, error) {
+	// M-Pesa only moves whole shillings
+	if req.Amount != math.Trunc(req.Amount) {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "M-Pesa amounts must be whole shillings")
+	}
+
+	phone, err := normalizePhoneNumber(req.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now().Format(timestampLayout)
+	body, err := json.Marshal(stkPushBody{
+		BusinessShortCode: c.shortcode,
+		Password:          base64.StdEncoding.EncodeToString([]byte(c.shortcode + c.passkey + timestamp)),
+		Timestamp:         timestamp,
+		TransactionType:   "CustomerPayBillOnline",
+		Amount:            int64(req.Amount),
+		PartyA:            phone,
+		PartyB:            c.shortcode,
+		PhoneNumber:       phone,
+		CallBackURL:       c.callbackURL,
+		AccountReference:  req.AccountReference,
+		TransactionDesc:   req.Description,
+	})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to encode stk push request: %s", err.Error())
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+stkPushPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to build stk push request: %s", err.Error())
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to send stk push: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	var result stkPushResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to decode stk push response: %s", err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK || result.ResponseCode != "0" {
+		message := result.ErrorMessage
+		if message == "" {
+			message = result.ResponseDescription
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "stk push rejected (status %d): %s", resp.StatusCode, message)
+	}
+
+	return &services.STKPushResponse{
+		CheckoutRequestID:   result.CheckoutRequestID,
+		MerchantRequestID:   result.MerchantRequestID,
+		CustomerMessage:     result.CustomerMessage,
+		ResponseDescription: result.ResponseDescription,
+	}, nil
+}
+
+type callbackBody struct {
+	Body struct {
+		StkCallback struct {
+			MerchantRequestID string `json:"MerchantRequestID"`
+			CheckoutRequestID string `json:"CheckoutRequestID"`
+			ResultCode        int    `json:"ResultCode"`
+			ResultDesc        string `json:"ResultDesc"`
+			CallbackMetadata  *struct {
+				Item []struct {
+					Name  string `json:"Name"`
+					Value any    `json:"Value"`
+				} `json:"Item"`
+			} `json:"CallbackMetadata"`
+		} `json:"stkCallback"`
+	} `json:"Body"`
+}
+
+func (c *darajaClient) ParseCallback(body []byte) (*services.MobileMoneyCallback, error) {
+	var cb callbackBody
+	if err := json.Unmarshal(body, &cb); err != nil {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "invalid callback body: %s", err.Error())
+	}
+
+	stk := cb.Body.StkCallback
+	if stk.CheckoutRequestID == "" {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "callback has no checkout request id")
+	}
+
+	callback := &services.MobileMoneyCallback{
+		CheckoutRequestID: stk.CheckoutRequestID,
+		MerchantRequestID: stk.MerchantRequestID,
+		Success:           stk.ResultCode == 0,
+		ResultDescription: stk.ResultDesc,
+	}
+
+	// metadata is only sent for successful payments
+	if stk.CallbackMetadata != nil {
+		for _, item := range stk.CallbackMetadata.Item {
+			switch item.Name {
+			case "Amount":
+				if v, ok := item.Value.(float64); ok {
+					callback.Amount = v
+				}
+			case "MpesaReceiptNumber":
+				callback.ReceiptNumber = fmt.Sprint(item.Value)
+			case "PhoneNumber":
+				// json numbers decode to float64, which would print in exponent form
+				if v, ok := item.Value.(float64); ok {
+					callback.PhoneNumber = fmt.Sprintf("%.0f", v)
+				} else {
+					callback.PhoneNumber = fmt.Sprint(item.Value)
+				}
+			}
+		}
+	}
+
+	return callback, nil
+}
+
+type tokenResult struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   string `json:"expires_in"`
+}
+
+// accessToken returns a cached OAuth token, fetching a new one shortly before
+// the current one expires.
+func (c *darajaClient) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+tokenPath, nil)
+	if err != nil {
+		return "", pkg.Errorf(pkg.INTERNAL_ERROR, "failed to build token request: %s", err.Error())
+	}
+	req.SetBasicAuth(c.consumerKey, c.consumerSecret)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get mpesa access token: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get mpesa access token (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result tokenResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", pkg.Errorf(pkg.INTERNAL_ERROR, "failed to decode mpesa access token: %s", err.Error())
+	}
+
+	expiresIn := time.Hour
+	if seconds, err := time.ParseDuration(result.ExpiresIn + "s"); err == nil {
+		expiresIn = seconds
+	}
+
+	c.token = result.AccessToken
+	c.tokenExpiry = time.Now().Add(expiresIn - time.Minute)
+
+	return c.token, nil
+}
+
+// normalizePhoneNumber converts 07XXXXXXXX, 01XXXXXXXX, +254... and 254... to the
+// 2547XXXXXXXX form M-Pesa expects.
+func normalizePhoneNumber(phone string) (string, error) {
+	phone = strings.TrimPrefix(strings.ReplaceAll(strings.TrimSpace(phone), " ", ""), "+")
+	if strings.HasPrefix(phone, "0") {
+		phone = "254" + phone[1:]
+	}
+
+	if len(phone) != 12 || !strings.HasPrefix(phone, "254") {
+		return "", pkg.Errorf(pkg.INVALID_ERROR, "invalid M-Pesa phone number")
+	}
+	for _, r := range phone {
+		if r < '0' || r > '9' {
+			return "", pkg.Errorf(pkg.INVALID_ERROR, "invalid M-Pesa phone number")
+		}
+	}
+
+	return phone, nil
+}
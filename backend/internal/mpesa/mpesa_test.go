@@ -0,0 +1,252 @@
+package mpesa
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+// standinServer is a minimal Daraja stand-in. It counts token requests so token
+// caching can be checked and records the last STK push it accepted.
+type standinServer struct {
+	*httptest.Server
+	tokenRequests atomic.Int32
+	rejectPush    atomic.Bool
+
+	mu       sync.Mutex
+	lastPush stkPushBody
+}
+
+func (s *standinServer) lastPushBody() stkPushBody {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastPush
+}
+
+func newStandinServer(t *testing.T) *standinServer {
+	t.Helper()
+
+	s := &standinServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/v1/generate", func(w http.ResponseWriter, r *http.Request) {
+		key, secret, ok := r.BasicAuth()
+		if !ok || key != "key" || secret != "secret" {
+			http.Error(w, "invalid credentials", http.StatusUnauthorized)
+			return
+		}
+		s.tokenRequests.Add(1)
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "test-token", "expires_in": "3599"})
+	})
+	mux.HandleFunc(stkPushPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, "invalid access token", http.StatusUnauthorized)
+			return
+		}
+		var push stkPushBody
+		if err := json.NewDecoder(r.Body).Decode(&push); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.lastPush = push
+		s.mu.Unlock()
+
+		if s.rejectPush.Load() {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"errorCode": "400.002.02", "errorMessage": "Bad Request - Invalid Amount"})
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"MerchantRequestID":   "merchant-1",
+			"CheckoutRequestID":   "ws_CO_1",
+			"ResponseCode":        "0",
+			"ResponseDescription": "Success. Request accepted for processing",
+			"CustomerMessage":     "Success. Request accepted for processing",
+		})
+	})
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func newTestClient(baseURL string) services.MobileMoneyService {
+	return NewMpesaClient(pkg.Config{
+		MPESA_BASE_URL:        baseURL,
+		MPESA_CONSUMER_KEY:    "key",
+		MPESA_CONSUMER_SECRET: "secret",
+		MPESA_SHORTCODE:       "174379",
+		MPESA_PASSKEY:         "passkey",
+		MPESA_CALLBACK_URL:    "https://example.com/payments/mpesa/callback",
+	})
+}
+
+func TestInitiateSTKPush(t *testing.T) {
+	server := newStandinServer(t)
+	client := newTestClient(server.URL + "/")
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.InitiateSTKPush(context.Background(), &services.STKPushRequest{
+			PhoneNumber:      "0712 345 678",
+			Amount:           150,
+			AccountReference: "RCP-000001",
+			Description:      "Payment",
+		})
+		if err != nil {
+			t.Fatalf("InitiateSTKPush: %v", err)
+		}
+		if resp.CheckoutRequestID != "ws_CO_1" || resp.MerchantRequestID != "merchant-1" {
+			t.Fatalf("unexpected response: %+v", resp)
+		}
+	}
+
+	if n := server.tokenRequests.Load(); n != 1 {
+		t.Errorf("token requested %d times, want 1", n)
+	}
+
+	push := server.lastPushBody()
+	if push.PhoneNumber != "254712345678" || push.PartyA != "254712345678" {
+		t.Errorf("phone number sent as %q/%q, want 254712345678", push.PhoneNumber, push.PartyA)
+	}
+	if push.Amount != 150 || push.PartyB != "174379" || push.BusinessShortCode != "174379" {
+		t.Errorf("unexpected push body: %+v", push)
+	}
+	if push.CallBackURL != "https://example.com/payments/mpesa/callback" || push.AccountReference != "RCP-000001" {
+		t.Errorf("unexpected push body: %+v", push)
+	}
+	wantPassword := base64.StdEncoding.EncodeToString([]byte("174379" + "passkey" + push.Timestamp))
+	if push.Password != wantPassword {
+		t.Errorf("password %q, want %q", push.Password, wantPassword)
+	}
+}
+
+func TestInitiateSTKPushRejected(t *testing.T) {
+	server := newStandinServer(t)
+	server.rejectPush.Store(true)
+	client := newTestClient(server.URL)
+
+	_, err := client.InitiateSTKPush(context.Background(), &services.STKPushRequest{PhoneNumber: "0712345678", Amount: 150})
+	if err == nil {
+		t.Fatal("expected a rejected push to fail")
+	}
+	if code := pkg.ErrorCode(err); code != pkg.INTERNAL_ERROR {
+		t.Errorf("error code %q, want %q", code, pkg.INTERNAL_ERROR)
+	}
+}
+
+func TestInitiateSTKPushInvalidRequest(t *testing.T) {
+	server := newStandinServer(t)
+	client := newTestClient(server.URL)
+
+	tests := map[string]*services.STKPushRequest{
+		"fractional amount": {PhoneNumber: "0712345678", Amount: 10.5},
+		"invalid phone":     {PhoneNumber: "12345", Amount: 10},
+	}
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := client.InitiateSTKPush(context.Background(), req)
+			if code := pkg.ErrorCode(err); code != pkg.INVALID_ERROR {
+				t.Errorf("error code %q, want %q", code, pkg.INVALID_ERROR)
+			}
+		})
+	}
+
+	if n := server.tokenRequests.Load(); n != 0 {
+		t.Errorf("invalid requests reached the server %d times", n)
+	}
+}
+
+func TestParseCallback(t *testing.T) {
+	client := newTestClient("http://localhost")
+
+	success := []byte(`{"Body":{"stkCallback":{
+		"MerchantRequestID":"merchant-1",
+		"CheckoutRequestID":"ws_CO_1",
+		"ResultCode":0,
+		"ResultDesc":"The service request is processed successfully.",
+		"CallbackMetadata":{"Item":[
+			{"Name":"Amount","Value":150.00},
+			{"Name":"MpesaReceiptNumber","Value":"NLJ7RT61SV"},
+			{"Name":"TransactionDate","Value":20191219102115},
+			{"Name":"PhoneNumber","Value":254712345678}
+		]}
+	}}}`)
+	callback, err := client.ParseCallback(success)
+	if err != nil {
+		t.Fatalf("ParseCallback: %v", err)
+	}
+	want := services.MobileMoneyCallback{
+		CheckoutRequestID: "ws_CO_1",
+		MerchantRequestID: "merchant-1",
+		Success:           true,
+		ResultDescription: "The service request is processed successfully.",
+		ReceiptNumber:     "NLJ7RT61SV",
+		Amount:            150,
+		PhoneNumber:       "254712345678",
+	}
+	if *callback != want {
+		t.Errorf("callback %+v, want %+v", *callback, want)
+	}
+
+	cancelled := []byte(`{"Body":{"stkCallback":{
+		"MerchantRequestID":"merchant-2",
+		"CheckoutRequestID":"ws_CO_2",
+		"ResultCode":1032,
+		"ResultDesc":"Request cancelled by user"
+	}}}`)
+	callback, err = client.ParseCallback(cancelled)
+	if err != nil {
+		t.Fatalf("ParseCallback: %v", err)
+	}
+	if callback.Success || callback.CheckoutRequestID != "ws_CO_2" || callback.ReceiptNumber != "" {
+		t.Errorf("unexpected cancelled callback: %+v", *callback)
+	}
+
+	for _, body := range []string{`not json`, `{"Body":{"stkCallback":{"ResultCode":0}}}`} {
+		if _, err := client.ParseCallback([]byte(body)); pkg.ErrorCode(err) != pkg.INVALID_ERROR {
+			t.Errorf("ParseCallback(%s) error %v, want an invalid error", body, err)
+		}
+	}
+}
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+		valid bool
+	}{
+		{"0712345678", "254712345678", true},
+		{"0112345678", "254112345678", true},
+		{"+254712345678", "254712345678", true},
+		{"254712345678", "254712345678", true},
+		{" 0712 345 678 ", "254712345678", true},
+		{"071234567", "", false},
+		{"2557123456789", "", false},
+		{"0712abc678", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, err := normalizePhoneNumber(tt.phone)
+		if tt.valid {
+			if err != nil || got != tt.want {
+				t.Errorf("normalizePhoneNumber(%q) = %q, %v; want %q", tt.phone, got, err, tt.want)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("normalizePhoneNumber(%q) = %q, want an error", tt.phone, got)
+		}
+	}
+}
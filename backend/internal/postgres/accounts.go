@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const defaultPaymentTermsDays = 30
+
+var _ repository.CustomerAccountRepository = (*CustomerAccountRepository)(nil)
+
+type CustomerAccountRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewCustomerAccountRepository(db *Store) *CustomerAccountRepository {
+	return &CustomerAccountRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (ar *CustomerAccountRepository) GetAccount(ctx context.Context, customerID int64) (*repository.CustomerAccount, error) {
+	customer, err := ar.queries.GetCustomerByID(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "customer with id %d not found", customerID)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get customer by id: %s", err.Error())
+	}
+
+	invoices, outstanding, err := openInvoicesTx(ctx, ar.queries, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	creditLimit := pkg.PgTypeNumericToFloat64(customer.CreditLimit)
+
+	return &repository.CustomerAccount{
+		CustomerID:       uint32(customer.ID),
+		CustomerName:     customer.Name,
+		CreditLimit:      creditLimit,
+		PaymentTermsDays: customer.PaymentTermsDays,
+		Outstanding:      outstanding,
+		AvailableCredit:  max(creditLimit-outstanding, 0),
+		OpenInvoices:     invoices,
+	}, nil
+}
+
+func (ar *CustomerAccountRepository) RecordPayment(ctx context.Context, payment *repository.AccountPayment) ([]*repository.Payment, error) {
+	if payment.Method == repository.PAYMENT_MPESA {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "M-Pesa payments must be requested against a single invoice")
+	}
+
+	var payments []*repository.Payment
+	err := ar.db.ExecTx(ctx, func(q *generated.Queries) error {
+		// the customer row serialises account payments with new sales on account
+		if _, err := q.GetCustomerCreditForUpdate(ctx, int64(payment.CustomerID)); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "customer with id %d not found", payment.CustomerID)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get customer credit: %s", err.Error())
+		}
+
+		invoices, outstanding, err := openInvoicesTx(ctx, q, int64(payment.CustomerID))
+		if err != nil {
+			return err
+		}
+		if toCents(payment.Amount) > toCents(outstanding) {
+			return pkg.Errorf(pkg.INVALID_ERROR, "payment of %.2f exceeds the outstanding balance of %.2f", payment.Amount, outstanding)
+		}
+
+		remaining := toCents(payment.Amount)
+		for _, invoice := range invoices {
+			if remaining == 0 {
+				break
+			}
+
+			allocated := min(remaining, toCents(invoice.Balance))
+			pgPayment, err := createPaymentTx(ctx, q, &repository.Payment{
+				SaleID:     invoice.SaleID,
+				Method:     payment.Method,
+				Amount:     float64(allocated) / 100,
+				Reference:  payment.Reference,
+				ReceivedBy: payment.ReceivedBy,
+			})
+			if err != nil {
+				return err
+			}
+
+			payments = append(payments, pgPaymentToRepoPayment(pgPayment))
+			remaining -= allocated
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+func (ar *CustomerAccountRepository) GetAgeingReport(ctx context.Context, asOf time.Time, customerID *uint32) (*repository.AgeingReport, error) {
+	customerParam := pgtype.Int8{Valid: false}
+	if customerID != nil {
+		customerParam = pgtype.Int8{Int64: int64(*customerID), Valid: true}
+	}
+
+	rows, err := ar.queries.GetAgeingReport(ctx, generated.GetAgeingReportParams{
+		AsOf:       asOf,
+		CustomerID: customerParam,
+	})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get ageing report: %s", err.Error())
+	}
+
+	report := &repository.AgeingReport{
+		AsOf:  asOf,
+		Lines: make([]*repository.AgeingLine, len(rows)),
+	}
+	for i, row := range rows {
+		line := &repository.AgeingLine{
+			CustomerID:   uint32(row.CustomerID),
+			CustomerName: row.CustomerName,
+			CreditLimit:  pkg.PgTypeNumericToFloat64(row.CreditLimit),
+			Current:      pkg.PgTypeNumericToFloat64(row.CurrentAmount),
+			Days31To60:   pkg.PgTypeNumericToFloat64(row.Days3160),
+			Days61To90:   pkg.PgTypeNumericToFloat64(row.Days6190),
+			Over90:       pkg.PgTypeNumericToFloat64(row.Over90),
+			Total:        pkg.PgTypeNumericToFloat64(row.TotalOutstanding),
+		}
+		report.Lines[i] = line
+
+		report.Totals.CreditLimit += line.CreditLimit
+		report.Totals.Current += line.Current
+		report.Totals.Days31To60 += line.Days31To60
+		report.Totals.Days61To90 += line.Days61To90
+		report.Totals.Over90 += line.Over90
+		report.Totals.Total += line.Total
+	}
+
+	return report, nil
+}
+
+func (ar *CustomerAccountRepository) GetStatement(ctx context.Context, customerID int64, startDate, endDate time.Time) (*repository.CustomerStatement, error) {
+	customer, err := ar.queries.GetCustomerByID(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "customer with id %d not found", customerID)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get customer by id: %s", err.Error())
+	}
+
+	customerParam := pgtype.Int8{Int64: customerID, Valid: true}
+	opening, err := ar.queries.GetStatementOpeningBalance(ctx, generated.GetStatementOpeningBalanceParams{
+		CustomerID: customerParam,
+		StartDate:  startDate,
+	})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get opening balance: %s", err.Error())
+	}
+
+	rows, err := ar.queries.ListStatementEntries(ctx, generated.ListStatementEntriesParams{
+		CustomerID: customerParam,
+		StartDate:  startDate,
+		EndDate:    endDate,
+	})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list statement entries: %s", err.Error())
+	}
+
+	statement := &repository.CustomerStatement{
+		Customer:       pgCustomerToRepoCustomer(customer),
+		StartDate:      startDate,
+		EndDate:        endDate,
+		OpeningBalance: pkg.PgTypeNumericToFloat64(opening),
+		Entries:        make([]*repository.StatementEntry, len(rows)),
+	}
+
+	// running balance in cents so a long statement does not drift
+	balance := toCents(statement.OpeningBalance)
+	for i, row := range rows {
+		entry := &repository.StatementEntry{
+			Date:      row.EntryDate,
+			Type:      row.EntryType,
+			SaleID:    uint32(row.SaleID),
+			Reference: row.Reference,
+		}
+		amount := pkg.PgTypeNumericToFloat64(row.Amount)
+		if row.EntryType == repository.STATEMENT_INVOICE {
+			entry.Debit = amount
+			balance += toCents(amount)
+		} else {
+			entry.Credit = amount
+			balance -= toCents(amount)
+		}
+		entry.Balance = float64(balance) / 100
+		statement.Entries[i] = entry
+	}
+	statement.ClosingBalance = float64(balance) / 100
+
+	// the ageing of the closing balance is shown at the foot of the statement
+	ageing, err := ar.GetAgeingReport(ctx, endDate, &statement.Customer.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ageing.Lines) > 0 {
+		statement.Ageing = ageing.Lines[0]
+	}
+
+	return statement, nil
+}
+
+// openInvoicesTx lists the customer's unpaid sales on account, oldest first, and
+// their total outstanding balance.
+func openInvoicesTx(ctx context.Context, q *generated.Queries, customerID int64) ([]*repository.Invoice, float64, error) {
+	rows, err := q.ListOpenInvoices(ctx, pgtype.Int8{Int64: customerID, Valid: true})
+	if err != nil {
+		return nil, 0, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list open invoices: %s", err.Error())
+	}
+
+	var outstanding int64
+	now := time.Now()
+	invoices := make([]*repository.Invoice, len(rows))
+	for i, row := range rows {
+		invoice := &repository.Invoice{
+			SaleID:        uint32(row.ID),
+			ReceiptNumber: row.ReceiptNumber,
+			TotalAmount:   pkg.PgTypeNumericToFloat64(row.TotalAmount),
+			AmountPaid:    pkg.PgTypeNumericToFloat64(row.AmountPaid),
+			CreatedAt:     row.CreatedAt,
+			DueDate:       pgTimestamptzToTime(row.DueDate),
+		}
+		balance := toCents(invoice.TotalAmount) - toCents(invoice.AmountPaid)
+		invoice.Balance = float64(balance) / 100
+		if invoice.DueDate != nil && now.After(*invoice.DueDate) {
+			invoice.DaysOverdue = int64(now.Sub(*invoice.DueDate).Hours() / 24)
+		}
+
+		invoices[i] = invoice
+		outstanding += balance
+	}
+
+	return invoices, float64(outstanding) / 100, nil
+}
@@ -0,0 +1,264 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.CategoryRepository = (*CategoryRepository)(nil)
+
+type CategoryRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewCategoryRepository(db *Store) *CategoryRepository {
+	return &CategoryRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (cr *CategoryRepository) Create(ctx context.Context, category *repository.Category) (*repository.Category, error) {
+	params := generated.CreateCategoryParams{
+		Name:     strings.TrimSpace(category.Name),
+		ParentID: pgtype.Int8{Valid: false},
+	}
+
+	var created *repository.Category
+	err := cr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		if category.ParentID != nil {
+			if _, err := getCategoryTx(ctx, q, int64(*category.ParentID)); err != nil {
+				return err
+			}
+			params.ParentID = pgtype.Int8{Int64: int64(*category.ParentID), Valid: true}
+		}
+
+		pgCategory, err := q.CreateCategory(ctx, params)
+		if err != nil {
+			if pkg.PgxErrorCode(err) == pkg.UNIQUE_VIOLATION {
+				return pkg.Errorf(pkg.ALREADY_EXISTS_ERROR, "category %q already exists", params.Name)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create category: %s", err.Error())
+		}
+		created = pgCategoryToRepoCategory(pgCategory)
+
+		return nil
+	})
+
+	return created, err
+}
+
+func (cr *CategoryRepository) GetByID(ctx context.Context, id int64) (*repository.Category, error) {
+	categories, err := cr.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if category := findCategory(categories, uint32(id)); category != nil {
+		return category, nil
+	}
+
+	return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "category with id %d not found", id)
+}
+
+func (cr *CategoryRepository) Update(ctx context.Context, id int64, categoryUpdate *repository.CategoryUpdate) (*repository.Category, error) {
+	params := generated.UpdateCategoryParams{
+		ID:        id,
+		Name:      pgtype.Text{Valid: false},
+		SetParent: false,
+		ParentID:  pgtype.Int8{Valid: false},
+	}
+	if categoryUpdate.Name != nil {
+		params.Name = pgtype.Text{String: strings.TrimSpace(*categoryUpdate.Name), Valid: true}
+	}
+	if categoryUpdate.MakeRoot {
+		params.SetParent = true
+	}
+
+	var updated *repository.Category
+	err := cr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		if _, err := getCategoryTx(ctx, q, id); err != nil {
+			return err
+		}
+
+		if categoryUpdate.ParentID != nil && !categoryUpdate.MakeRoot {
+			if _, err := getCategoryTx(ctx, q, int64(*categoryUpdate.ParentID)); err != nil {
+				return err
+			}
+
+			// a category cannot be moved under itself or one of its own subcategories
+			subtree, err := q.ListCategorySubtreeIDs(ctx, id)
+			if err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list subcategories: %s", err.Error())
+			}
+			if slices.Contains(subtree, int64(*categoryUpdate.ParentID)) {
+				return pkg.Errorf(pkg.INVALID_ERROR, "category cannot be moved under itself or its subcategories")
+			}
+
+			params.SetParent = true
+			params.ParentID = pgtype.Int8{Int64: int64(*categoryUpdate.ParentID), Valid: true}
+		}
+
+		pgCategory, err := q.UpdateCategory(ctx, params)
+		if err != nil {
+			if pkg.PgxErrorCode(err) == pkg.UNIQUE_VIOLATION {
+				return pkg.Errorf(pkg.ALREADY_EXISTS_ERROR, "category %q already exists", params.Name.String)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update category: %s", err.Error())
+		}
+
+		if params.Name.Valid {
+			if err := q.SyncProductCategoryName(ctx, id); err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update product categories: %s", err.Error())
+			}
+		}
+		updated = pgCategoryToRepoCategory(pgCategory)
+
+		return nil
+	})
+
+	return updated, err
+}
+
+func (cr *CategoryRepository) Delete(ctx context.Context, id int64, moveTo *uint32) error {
+	return cr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		category, err := getCategoryTx(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		if moveTo != nil {
+			if int64(*moveTo) == id {
+				return pkg.Errorf(pkg.INVALID_ERROR, "cannot move products to the category being deleted")
+			}
+			if _, err := getCategoryTx(ctx, q, int64(*moveTo)); err != nil {
+				return err
+			}
+
+			if err := q.MoveCategoryProducts(ctx, generated.MoveCategoryProductsParams{
+				ToCategoryID:   int64(*moveTo),
+				FromCategoryID: id,
+			}); err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to move category products: %s", err.Error())
+			}
+		} else {
+			total, err := q.CountCategoryProducts(ctx, id)
+			if err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count category products: %s", err.Error())
+			}
+			if total > 0 {
+				return pkg.Errorf(pkg.INVALID_ERROR, "category has %d products, choose a category to move them to", total)
+			}
+		}
+
+		if err := q.MoveCategoryChildren(ctx, generated.MoveCategoryChildrenParams{
+			ToParentID:   category.ParentID,
+			FromParentID: id,
+		}); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to move subcategories: %s", err.Error())
+		}
+
+		if err := q.DeleteCategory(ctx, id); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to delete category: %s", err.Error())
+		}
+
+		return nil
+	})
+}
+
+func (cr *CategoryRepository) List(ctx context.Context) ([]*repository.Category, error) {
+	rows, err := cr.queries.ListCategories(ctx)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list categories: %s", err.Error())
+	}
+
+	byID := make(map[uint32]*repository.Category, len(rows))
+	for _, row := range rows {
+		byID[uint32(row.ID)] = &repository.Category{
+			ID:           uint32(row.ID),
+			Name:         row.Name,
+			ParentID:     pgInt8ToUint32(row.ParentID),
+			ProductCount: row.ProductCount,
+			CreatedAt:    row.CreatedAt,
+		}
+	}
+
+	// rows are ordered by name, so children keep that order under their parent
+	roots := []*repository.Category{}
+	for _, row := range rows {
+		category := byID[uint32(row.ID)]
+		if category.ParentID == nil {
+			roots = append(roots, category)
+			continue
+		}
+		parent := byID[*category.ParentID]
+		parent.Children = append(parent.Children, category)
+	}
+
+	return roots, nil
+}
+
+func findCategory(categories []*repository.Category, id uint32) *repository.Category {
+	for _, category := range categories {
+		if category.ID == id {
+			return category
+		}
+		if found := findCategory(category.Children, id); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+func getCategoryTx(ctx context.Context, q *generated.Queries, id int64) (generated.Category, error) {
+	category, err := q.GetCategoryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return generated.Category{}, pkg.Errorf(pkg.NOT_FOUND_ERROR, "category with id %d not found", id)
+		}
+		return generated.Category{}, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get category: %s", err.Error())
+	}
+
+	return category, nil
+}
+
+// resolveCategoryTx finds a product's category by id, or else by name ignoring case.
+// Products can only be assigned to existing categories.
+func resolveCategoryTx(ctx context.Context, q *generated.Queries, id *uint32, name string) (generated.Category, error) {
+	if id != nil {
+		return getCategoryTx(ctx, q, int64(*id))
+	}
+
+	if strings.TrimSpace(name) == "" {
+		return generated.Category{}, pkg.Errorf(pkg.INVALID_ERROR, "category is required")
+	}
+
+	category, err := q.GetCategoryByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return generated.Category{}, pkg.Errorf(pkg.NOT_FOUND_ERROR, "category %q not found", name)
+		}
+		return generated.Category{}, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get category: %s", err.Error())
+	}
+
+	return category, nil
+}
+
+func pgCategoryToRepoCategory(c generated.Category) *repository.Category {
+	return &repository.Category{
+		ID:        uint32(c.ID),
+		Name:      c.Name,
+		ParentID:  pgInt8ToUint32(c.ParentID),
+		CreatedAt: c.CreatedAt,
+	}
+}
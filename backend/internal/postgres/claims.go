@@ -0,0 +1,340 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.ClaimRepository = (*ClaimRepository)(nil)
+
+type ClaimRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewClaimRepository(db *Store) *ClaimRepository {
+	return &ClaimRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (cr *ClaimRepository) CreateBatch(ctx context.Context, batch *repository.ClaimBatch) (*repository.ClaimBatch, error) {
+	if !batch.PeriodEnd.After(batch.PeriodStart) {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "the claim period must end after it starts")
+	}
+
+	var batchID int64
+	err := cr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		insurer, err := q.GetInsurerByID(ctx, int64(batch.InsurerID))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "insurer with id %d not found", batch.InsurerID)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get insurer: %s", err.Error())
+		}
+
+		cb, err := q.CreateClaimBatch(ctx, generated.CreateClaimBatchParams{
+			InsurerID:   insurer.ID,
+			PeriodStart: batch.PeriodStart,
+			PeriodEnd:   batch.PeriodEnd,
+			CreatedBy:   int64(batch.CreatedBy),
+		})
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create claim batch: %s", err.Error())
+		}
+
+		// a sale can only be claimed once, so sales already in another batch are skipped
+		if err := q.CreateBatchClaims(ctx, generated.CreateBatchClaimsParams{
+			BatchID:     cb.ID,
+			InsurerID:   insurer.ID,
+			PeriodStart: batch.PeriodStart,
+			PeriodEnd:   batch.PeriodEnd,
+		}); err != nil {
+			if pkg.PgxErrorCode(err) == pkg.UNIQUE_VIOLATION {
+				return pkg.Errorf(pkg.ALREADY_EXISTS_ERROR, "some of the sales are being claimed in another batch, try again")
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create claims: %s", err.Error())
+		}
+
+		if err := q.UpdateClaimBatchTotals(ctx, cb.ID); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update claim batch totals: %s", err.Error())
+		}
+
+		cb, err = q.GetClaimBatchForUpdate(ctx, cb.ID)
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get claim batch: %s", err.Error())
+		}
+		if cb.TotalClaims == 0 {
+			return pkg.Errorf(pkg.INVALID_ERROR, "%s has no unclaimed insurance sales in the period", insurer.Name)
+		}
+		batchID = cb.ID
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cr.GetBatch(ctx, batchID)
+}
+
+func (cr *ClaimRepository) MarkSubmitted(ctx context.Context, id int64, reference *string, submittedBy uint32) (*repository.ClaimBatch, error) {
+	err := cr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		cb, err := getClaimBatchForUpdateTx(ctx, q, id)
+		if err != nil {
+			return err
+		}
+		if cb.Status != repository.CLAIM_BATCH_DRAFT {
+			return pkg.Errorf(pkg.INVALID_ERROR, "claim batch %s has already been submitted", cb.BatchNumber)
+		}
+
+		if err := q.SubmitClaimBatch(ctx, generated.SubmitClaimBatchParams{
+			ID:                id,
+			ExternalReference: stringToPgText(reference),
+			SubmittedBy:       pgtype.Int8{Int64: int64(submittedBy), Valid: true},
+		}); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to submit claim batch: %s", err.Error())
+		}
+
+		if err := q.MarkBatchClaimsSubmitted(ctx, id); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to mark claims submitted: %s", err.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cr.GetBatch(ctx, id)
+}
+
+func (cr *ClaimRepository) Decide(ctx context.Context, batchID int64, decisions []*repository.ClaimDecision) (*repository.ClaimBatch, error) {
+	err := cr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		cb, err := getClaimBatchForUpdateTx(ctx, q, batchID)
+		if err != nil {
+			return err
+		}
+		if cb.Status == repository.CLAIM_BATCH_DRAFT {
+			return pkg.Errorf(pkg.INVALID_ERROR, "claim batch %s has not been submitted", cb.BatchNumber)
+		}
+
+		for _, decision := range decisions {
+			claim, err := q.GetClaimForUpdate(ctx, int64(decision.ClaimID))
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get claim: %s", err.Error())
+			}
+			if errors.Is(err, sql.ErrNoRows) || claim.BatchID != batchID {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "claim %d is not in batch %s", decision.ClaimID, cb.BatchNumber)
+			}
+
+			// insurers report every claim on each status check, decided ones included
+			if claim.Status != repository.CLAIM_SUBMITTED {
+				continue
+			}
+
+			amount := pkg.PgTypeNumericToFloat64(claim.Amount)
+			approved := decision.ApprovedAmount
+			switch decision.Status {
+			case repository.CLAIM_APPROVED:
+				if approved <= 0 {
+					approved = amount
+				}
+				if toCents(approved) > toCents(amount) {
+					return pkg.Errorf(pkg.INVALID_ERROR, "approved amount %.2f exceeds the %.2f claimed on claim %d", approved, amount, claim.ID)
+				}
+			case repository.CLAIM_REJECTED:
+				approved = 0
+			default:
+				// still under review
+				continue
+			}
+
+			if err := q.ResolveClaim(ctx, generated.ResolveClaimParams{
+				ID:                claim.ID,
+				Status:            decision.Status,
+				ApprovedAmount:    pkg.Float64ToPgTypeNumeric(approved),
+				RejectionReason:   stringToPgText(decision.RejectionReason),
+				ExternalReference: stringToPgText(decision.ExternalReference),
+			}); err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to resolve claim: %s", err.Error())
+			}
+
+			if shortfall := toCents(amount) - toCents(approved); shortfall > 0 {
+				reason := fmt.Sprintf("claim %d rejected by the insurer", claim.ID)
+				if decision.Status == repository.CLAIM_APPROVED {
+					reason = fmt.Sprintf("claim %d approved at %.2f of %.2f", claim.ID, approved, amount)
+				}
+				if err := reverseInsuranceShortfallTx(ctx, q, claim.SaleID, shortfall, reason); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := q.UpdateClaimBatchTotals(ctx, batchID); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update claim batch totals: %s", err.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cr.GetBatch(ctx, batchID)
+}
+
+// reverseInsuranceShortfallTx takes the part of a claim the insurer did not pay off
+// the sale's insurance payments, newest first, so the shortfall is owed by the customer.
+// Payments wiped out entirely are failed, since payment amounts must stay positive.
+func reverseInsuranceShortfallTx(ctx context.Context, q *generated.Queries, saleID int64, shortfall int64, reason string) error {
+	payments, err := q.ListSaleInsurancePaymentsForUpdate(ctx, saleID)
+	if err != nil {
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list insurance payments: %s", err.Error())
+	}
+
+	for _, p := range payments {
+		if shortfall <= 0 {
+			break
+		}
+
+		paid := toCents(pkg.PgTypeNumericToFloat64(p.Amount))
+		if paid <= shortfall {
+			if err := q.ReversePayment(ctx, generated.ReversePaymentParams{
+				ID:            p.ID,
+				FailureReason: pgtype.Text{String: reason, Valid: true},
+			}); err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to reverse insurance payment: %s", err.Error())
+			}
+			shortfall -= paid
+			continue
+		}
+
+		if err := q.ReducePayment(ctx, generated.ReducePaymentParams{
+			ID:        p.ID,
+			Reduction: pkg.Float64ToPgTypeNumeric(float64(shortfall) / 100),
+		}); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to reduce insurance payment: %s", err.Error())
+		}
+		shortfall = 0
+	}
+
+	return nil
+}
+
+func getClaimBatchForUpdateTx(ctx context.Context, q *generated.Queries, id int64) (generated.ClaimBatch, error) {
+	cb, err := q.GetClaimBatchForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return cb, pkg.Errorf(pkg.NOT_FOUND_ERROR, "claim batch with id %d not found", id)
+		}
+		return cb, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get claim batch: %s", err.Error())
+	}
+
+	return cb, nil
+}
+
+func (cr *ClaimRepository) GetBatch(ctx context.Context, id int64) (*repository.ClaimBatch, error) {
+	cb, err := cr.queries.GetClaimBatchByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "claim batch with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get claim batch: %s", err.Error())
+	}
+
+	claims, err := cr.queries.ListBatchClaims(ctx, id)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list claims: %s", err.Error())
+	}
+
+	batch := pgClaimBatchToRepoClaimBatch(generated.ListClaimBatchesRow(cb))
+	batch.Claims = make([]*repository.Claim, len(claims))
+	for i, claim := range claims {
+		batch.Claims[i] = &repository.Claim{
+			ID:                uint32(claim.ID),
+			BatchID:           uint32(claim.BatchID),
+			SaleID:            uint32(claim.SaleID),
+			SchemeID:          uint32(claim.SchemeID),
+			MemberNumber:      claim.MemberNumber,
+			Amount:            pkg.PgTypeNumericToFloat64(claim.Amount),
+			Status:            claim.Status,
+			ApprovedAmount:    pkg.PgTypeNumericToFloat64(claim.ApprovedAmount),
+			RejectionReason:   pgTextToString(claim.RejectionReason),
+			ExternalReference: pgTextToString(claim.ExternalReference),
+			ResolvedAt:        pgTimestamptzToTime(claim.ResolvedAt),
+
+			ReceiptNumber: claim.ReceiptNumber,
+			SaleDate:      claim.SaleDate,
+			SaleAmount:    pkg.PgTypeNumericToFloat64(claim.SaleAmount),
+			SchemeName:    claim.SchemeName,
+			CustomerName:  pgTextToString(claim.CustomerName),
+		}
+	}
+
+	return batch, nil
+}
+
+func (cr *ClaimRepository) ListBatches(ctx context.Context, filter *repository.ClaimBatchFilter) ([]*repository.ClaimBatch, *pkg.Pagination, error) {
+	listParams := generated.ListClaimBatchesParams{
+		Limit:     int32(filter.Pagination.PageSize),
+		Offset:    pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+		InsurerID: pgtype.Int8{Valid: false},
+		Status:    stringToPgText(filter.Status),
+	}
+	if filter.InsurerID != nil {
+		listParams.InsurerID = pgtype.Int8{Int64: int64(*filter.InsurerID), Valid: true}
+	}
+
+	batches, err := cr.queries.ListClaimBatches(ctx, listParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list claim batches: %s", err.Error())
+	}
+
+	totalCount, err := cr.queries.ListClaimBatchesCount(ctx, generated.ListClaimBatchesCountParams{
+		InsurerID: listParams.InsurerID,
+		Status:    listParams.Status,
+	})
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count claim batches: %s", err.Error())
+	}
+
+	repoBatches := make([]*repository.ClaimBatch, len(batches))
+	for i, cb := range batches {
+		repoBatches[i] = pgClaimBatchToRepoClaimBatch(cb)
+	}
+
+	return repoBatches, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
+}
+
+func pgClaimBatchToRepoClaimBatch(cb generated.ListClaimBatchesRow) *repository.ClaimBatch {
+	return &repository.ClaimBatch{
+		ID:                uint32(cb.ID),
+		BatchNumber:       cb.BatchNumber,
+		InsurerID:         uint32(cb.InsurerID),
+		PeriodStart:       cb.PeriodStart,
+		PeriodEnd:         cb.PeriodEnd,
+		Status:            cb.Status,
+		TotalClaims:       cb.TotalClaims,
+		TotalAmount:       pkg.PgTypeNumericToFloat64(cb.TotalAmount),
+		ApprovedAmount:    pkg.PgTypeNumericToFloat64(cb.ApprovedAmount),
+		ExternalReference: pgTextToString(cb.ExternalReference),
+		SubmittedBy:       pgInt8ToUint32(cb.SubmittedBy),
+		SubmittedAt:       pgTimestamptzToTime(cb.SubmittedAt),
+		CreatedBy:         uint32(cb.CreatedBy),
+		CreatedAt:         cb.CreatedAt,
+
+		InsurerName:   cb.InsurerName,
+		InsurerCode:   cb.InsurerCode,
+		InsurerClient: cb.InsurerClient,
+		UserName:      cb.UserName,
+	}
+}
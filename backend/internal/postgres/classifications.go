@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/classification"
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.ClassificationRepository = (*ClassificationRepository)(nil)
+
+// demand variability is measured over whole weeks, so a period needs at least
+// two of them
+const minClassificationWeeks = 2
+
+type ClassificationRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewClassificationRepository(db *Store) *ClassificationRepository {
+	return &ClassificationRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (cr *ClassificationRepository) Classify(ctx context.Context, filter *repository.ClassificationFilter) (*repository.ClassificationReport, error) {
+	endDate := time.Now()
+	if filter.EndDate != nil {
+		endDate = *filter.EndDate
+	}
+	startDate := endDate.AddDate(-1, 0, 0)
+	if filter.StartDate != nil {
+		startDate = *filter.StartDate
+	}
+	if !startDate.Before(endDate) {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "start date must be before end date")
+	}
+
+	// weeks are counted back from the end date; a partial week at the start
+	// counts towards consumption value but not demand variability
+	weeks := int(endDate.Sub(startDate) / (7 * 24 * time.Hour))
+	if weeks < minClassificationWeeks {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "period must cover at least %d weeks", minClassificationWeeks)
+	}
+
+	report := &repository.ClassificationReport{
+		StartDate:    startDate,
+		EndDate:      endDate,
+		Weeks:        weeks,
+		ClassifiedAt: time.Now(),
+		Counts:       make(map[string]int),
+	}
+
+	err := cr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		consumption, err := q.ListProductConsumption(ctx, generated.ListProductConsumptionParams{
+			StartDate: startDate,
+			EndDate:   endDate,
+		})
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list product consumption: %s", err.Error())
+		}
+
+		weeklyDemand, err := q.ListWeeklyDemand(ctx, generated.ListWeeklyDemandParams{
+			EndDate:   endDate,
+			StartDate: startDate,
+		})
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list weekly demand: %s", err.Error())
+		}
+
+		demand := make(map[int64][]float64, len(consumption))
+		for _, d := range weeklyDemand {
+			if int(d.WeeksAgo) >= weeks {
+				continue
+			}
+			if _, ok := demand[d.ProductID]; !ok {
+				demand[d.ProductID] = make([]float64, weeks)
+			}
+			demand[d.ProductID][weeks-1-int(d.WeeksAgo)] = float64(d.Quantity)
+		}
+
+		items := make([]classification.Item, len(consumption))
+		products := make(map[int64]generated.ListProductConsumptionRow, len(consumption))
+		for i, c := range consumption {
+			history, ok := demand[c.ID]
+			if !ok {
+				history = make([]float64, weeks)
+			}
+			items[i] = classification.Item{
+				ID:     c.ID,
+				Value:  pkg.PgTypeNumericToFloat64(c.ConsumptionValue),
+				Demand: history,
+			}
+			products[c.ID] = c
+			report.TotalValue += items[i].Value
+		}
+
+		results := classification.Classify(items)
+		report.Products = make([]*repository.ProductClassification, len(results))
+		for i, result := range results {
+			if err := q.SetProductClasses(ctx, generated.SetProductClassesParams{
+				AbcClass:     pgtype.Text{String: result.ABC, Valid: true},
+				XyzClass:     stringToPgText(result.XYZ),
+				ClassifiedAt: report.ClassifiedAt,
+				ID:           result.ID,
+			}); err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to set product classes: %s", err.Error())
+			}
+
+			class := result.ABC
+			if result.XYZ != nil {
+				class += *result.XYZ
+			}
+			report.Counts[class]++
+
+			product := products[result.ID]
+			report.Products[i] = &repository.ProductClassification{
+				ProductID:              uint32(result.ID),
+				ProductName:            product.Name,
+				ConsumptionQuantity:    product.ConsumptionQuantity,
+				ConsumptionValue:       result.Value,
+				ValueShare:             result.Share,
+				CumulativeShare:        result.CumulativeShare,
+				ABCClass:               result.ABC,
+				MeanWeeklyDemand:       result.MeanDemand,
+				CoefficientOfVariation: result.CoefficientOfVariation,
+				XYZClass:               result.XYZ,
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// genesisHash is the previous hash of the first register entry of every product.
+var genesisHash = strings.Repeat("0", 64)
+
+// recordControlledMovementTx appends the movement to the product's register. It must
+// run in the same transaction that changed the stock; the product row is locked by
+// that update so entries for one product are always chained in order.
+func recordControlledMovementTx(ctx context.Context, q *generated.Queries, p generated.Product, m generated.Movement) error {
+	previousHash := genesisHash
+	last, err := q.GetLastRegisterEntry(ctx, p.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get last register entry: %s", err.Error())
+	}
+	if err == nil {
+		previousHash = last.Hash
+	}
+
+	entry := generated.ControlledDrugRegister{
+		ProductID:      p.ID,
+		MovementID:     m.ID,
+		EntryType:      m.Type,
+		Quantity:       m.Quantity,
+		Balance:        p.Stock,
+		Reference:      m.BatchNumber,
+		CustomerID:     m.CustomerID,
+		PrescriptionID: m.PrescriptionID,
+		PerformedBy:    m.PerformedBy,
+		WitnessedBy:    m.WitnessedBy.Int64,
+		PreviousHash:   previousHash,
+		// postgres keeps microseconds, truncate so the stored time hashes the same
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+	if !entry.Reference.Valid {
+		entry.Reference = m.Note
+	}
+	entry.Hash = registerEntryHash(entry)
+
+	_, err = q.CreateRegisterEntry(ctx, generated.CreateRegisterEntryParams{
+		ProductID:      entry.ProductID,
+		MovementID:     entry.MovementID,
+		EntryType:      entry.EntryType,
+		Quantity:       entry.Quantity,
+		Balance:        entry.Balance,
+		Reference:      entry.Reference,
+		CustomerID:     entry.CustomerID,
+		PrescriptionID: entry.PrescriptionID,
+		PerformedBy:    entry.PerformedBy,
+		WitnessedBy:    entry.WitnessedBy,
+		PreviousHash:   entry.PreviousHash,
+		Hash:           entry.Hash,
+		CreatedAt:      entry.CreatedAt,
+	})
+	if err != nil {
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create register entry: %s", err.Error())
+	}
+
+	return nil
+}
+
+// registerEntryHash hashes every recorded field of the entry together with the hash
+// of the entry before it.
+func registerEntryHash(e generated.ControlledDrugRegister) string {
+	payload := fmt.Sprintf("%s|%d|%d|%s|%d|%d|%s|%s|%s|%d|%d|%s",
+		e.PreviousHash,
+		e.ProductID,
+		e.MovementID,
+		e.EntryType,
+		e.Quantity,
+		e.Balance,
+		pgTextToHashField(e.Reference),
+		pgInt8ToHashField(e.CustomerID),
+		pgInt8ToHashField(e.PrescriptionID),
+		e.PerformedBy,
+		e.WitnessedBy,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	)
+	sum := sha256.Sum256([]byte(payload))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func signedQuantity(entryType string, quantity int32) int64 {
+	if entryType == repository.MOVEMENT_ADD {
+		return int64(quantity)
+	}
+
+	return -int64(quantity)
+}
+
+func pgTextToHashField(t pgtype.Text) string {
+	if !t.Valid {
+		return ""
+	}
+
+	return t.String
+}
+
+func pgInt8ToHashField(i pgtype.Int8) string {
+	if !i.Valid {
+		return ""
+	}
+
+	return fmt.Sprintf("%d", i.Int64)
+}
+
+// GetControlledDrugRegister returns the register of every controlled product (or the
+// filtered one) for the period. The whole chain up to the end date is verified, not
+// just the entries in the period, since a change to an earlier entry breaks every
+// later hash.
+func (pr *ProductRepository) GetControlledDrugRegister(ctx context.Context, filter *repository.ControlledDrugFilter) (*repository.ControlledDrugReport, error) {
+	endDate := time.Now()
+	if filter.EndDate != nil {
+		endDate = *filter.EndDate
+	}
+	startDate := time.Time{}
+	if filter.StartDate != nil {
+		startDate = *filter.StartDate
+	}
+
+	params := generated.ListRegisterEntriesParams{
+		ProductID: pgtype.Int8{Valid: false},
+		EndDate:   endDate,
+	}
+	if filter.ProductID != nil {
+		params.ProductID = pgtype.Int8{Int64: int64(*filter.ProductID), Valid: true}
+	}
+
+	entries, err := pr.queries.ListRegisterEntries(ctx, params)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list register entries: %s", err.Error())
+	}
+
+	report := &repository.ControlledDrugReport{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Registers: []*repository.ControlledDrugRegister{},
+	}
+
+	var (
+		register     *repository.ControlledDrugRegister
+		previousHash string
+	)
+	for _, e := range entries {
+		if register == nil || register.ProductID != uint32(e.ProductID) {
+			register = &repository.ControlledDrugRegister{
+				ProductID:   uint32(e.ProductID),
+				ProductName: e.ProductName,
+				Unit:        e.ProductUnit,
+				Verified:    true,
+				Entries:     []*repository.ControlledDrugEntry{},
+			}
+			// the balance before the first entry, replaced below by the balance of
+			// the last entry before the period if there is one
+			register.OpeningBalance = e.Balance - signedQuantity(e.EntryType, e.Quantity)
+			register.ClosingBalance = register.OpeningBalance
+			report.Registers = append(report.Registers, register)
+			previousHash = genesisHash
+		}
+
+		stored := generated.ControlledDrugRegister{
+			ProductID:      e.ProductID,
+			MovementID:     e.MovementID,
+			EntryType:      e.EntryType,
+			Quantity:       e.Quantity,
+			Balance:        e.Balance,
+			Reference:      e.Reference,
+			CustomerID:     e.CustomerID,
+			PrescriptionID: e.PrescriptionID,
+			PerformedBy:    e.PerformedBy,
+			WitnessedBy:    e.WitnessedBy,
+			PreviousHash:   e.PreviousHash,
+			CreatedAt:      e.CreatedAt,
+		}
+		if register.Verified && (e.PreviousHash != previousHash || registerEntryHash(stored) != e.Hash) {
+			register.Verified = false
+			id := uint32(e.ID)
+			register.TamperedEntryID = &id
+		}
+		previousHash = e.Hash
+
+		if e.CreatedAt.Before(startDate) {
+			register.OpeningBalance = e.Balance
+			register.ClosingBalance = e.Balance
+			continue
+		}
+
+		if e.EntryType == repository.MOVEMENT_ADD {
+			register.TotalReceived += int64(e.Quantity)
+		} else {
+			register.TotalIssued += int64(e.Quantity)
+		}
+		register.ClosingBalance = e.Balance
+
+		register.Entries = append(register.Entries, &repository.ControlledDrugEntry{
+			ID:             uint32(e.ID),
+			ProductID:      uint32(e.ProductID),
+			MovementID:     uint32(e.MovementID),
+			EntryType:      e.EntryType,
+			Quantity:       e.Quantity,
+			Balance:        e.Balance,
+			Reference:      pgTextToString(e.Reference),
+			CustomerID:     pgInt8ToUint32(e.CustomerID),
+			PrescriptionID: pgInt8ToUint32(e.PrescriptionID),
+			PerformedBy:    uint32(e.PerformedBy),
+			WitnessedBy:    uint32(e.WitnessedBy),
+			PreviousHash:   e.PreviousHash,
+			Hash:           e.Hash,
+			CreatedAt:      e.CreatedAt,
+
+			PerformedByName:         e.PerformedByName,
+			WitnessedByName:         e.WitnessedByName,
+			CustomerName:            pgTextToString(e.CustomerName),
+			PrescriberName:          pgTextToString(e.PrescriberName),
+			PrescriberLicenceNumber: pgTextToString(e.PrescriberLicenceNumber),
+		})
+	}
+
+	return report, nil
+}
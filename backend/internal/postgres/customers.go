@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.CustomerRepository = (*CustomerRepository)(nil)
+
+type CustomerRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewCustomerRepository(db *Store) *CustomerRepository {
+	return &CustomerRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (cr *CustomerRepository) Create(ctx context.Context, customer *repository.Customer) (*repository.Customer, error) {
+	params := generated.CreateCustomerParams{
+		Name:        customer.Name,
+		PhoneNumber: pgtype.Text{Valid: false},
+		DateOfBirth: pgtype.Date{Valid: false},
+		Allergies:   pgtype.Text{Valid: false},
+		Notes:       pgtype.Text{Valid: false},
+
+		CreditLimit:      pkg.Float64ToPgTypeNumeric(customer.CreditLimit),
+		PaymentTermsDays: customer.PaymentTermsDays,
+	}
+	if params.PaymentTermsDays == 0 {
+		params.PaymentTermsDays = defaultPaymentTermsDays
+	}
+	if customer.PhoneNumber != nil {
+		params.PhoneNumber = pgtype.Text{String: *customer.PhoneNumber, Valid: true}
+	}
+	if customer.DateOfBirth != nil {
+		params.DateOfBirth = pgtype.Date{Time: *customer.DateOfBirth, Valid: true}
+	}
+	if customer.Allergies != nil {
+		params.Allergies = pgtype.Text{String: *customer.Allergies, Valid: true}
+	}
+	if customer.Notes != nil {
+		params.Notes = pgtype.Text{String: *customer.Notes, Valid: true}
+	}
+
+	pgCustomer, err := cr.queries.CreateCustomer(ctx, params)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create customer: %s", err.Error())
+	}
+
+	return pgCustomerToRepoCustomer(pgCustomer), nil
+}
+
+func (cr *CustomerRepository) GetByID(ctx context.Context, id int64) (*repository.Customer, error) {
+	pgCustomer, err := cr.queries.GetCustomerByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "customer with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get customer by id: %s", err.Error())
+	}
+
+	return pgCustomerToRepoCustomer(pgCustomer), nil
+}
+
+func (cr *CustomerRepository) Update(ctx context.Context, id int64, customerUpdate *repository.CustomerUpdate) (*repository.Customer, error) {
+	params := generated.UpdateCustomerParams{
+		ID:          id,
+		Name:        pgtype.Text{Valid: false},
+		PhoneNumber: pgtype.Text{Valid: false},
+		DateOfBirth: pgtype.Date{Valid: false},
+		Allergies:   pgtype.Text{Valid: false},
+		Notes:       pgtype.Text{Valid: false},
+
+		CreditLimit:      pgtype.Numeric{Valid: false},
+		PaymentTermsDays: int32ToPgInt4(customerUpdate.PaymentTermsDays),
+	}
+
+	if customerUpdate.Name != nil {
+		params.Name = pgtype.Text{String: *customerUpdate.Name, Valid: true}
+	}
+	if customerUpdate.PhoneNumber != nil {
+		params.PhoneNumber = pgtype.Text{String: *customerUpdate.PhoneNumber, Valid: true}
+	}
+	if customerUpdate.DateOfBirth != nil {
+		params.DateOfBirth = pgtype.Date{Time: *customerUpdate.DateOfBirth, Valid: true}
+	}
+	if customerUpdate.Allergies != nil {
+		params.Allergies = pgtype.Text{String: *customerUpdate.Allergies, Valid: true}
+	}
+	if customerUpdate.Notes != nil {
+		params.Notes = pgtype.Text{String: *customerUpdate.Notes, Valid: true}
+	}
+	if customerUpdate.CreditLimit != nil {
+		params.CreditLimit = pkg.Float64ToPgTypeNumeric(*customerUpdate.CreditLimit)
+	}
+
+	pgCustomer, err := cr.queries.UpdateCustomer(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "customer with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update customer: %s", err.Error())
+	}
+
+	return pgCustomerToRepoCustomer(pgCustomer), nil
+}
+
+func (cr *CustomerRepository) Delete(ctx context.Context, id int64) error {
+	if err := cr.queries.DeleteCustomer(ctx, id); err != nil {
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to delete customer: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (cr *CustomerRepository) List(ctx context.Context, filter *repository.CustomerFilter) ([]*repository.Customer, *pkg.Pagination, error) {
+	listParams := generated.ListCustomersParams{
+		Limit:  int32(filter.Pagination.PageSize),
+		Offset: pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+		Search: pgtype.Text{Valid: false},
+	}
+
+	countSearch := pgtype.Text{Valid: false}
+
+	if filter.Search != nil {
+		s := strings.ToLower(*filter.Search)
+		listParams.Search = pgtype.Text{String: "%" + s + "%", Valid: true}
+		countSearch = pgtype.Text{String: "%" + s + "%", Valid: true}
+	}
+
+	pgCustomers, err := cr.queries.ListCustomers(ctx, listParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list customers: %s", err.Error())
+	}
+
+	totalCount, err := cr.queries.ListCustomersCount(ctx, countSearch)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count customers: %s", err.Error())
+	}
+
+	customers := make([]*repository.Customer, 0, len(pgCustomers))
+	for _, pgCustomer := range pgCustomers {
+		customers = append(customers, pgCustomerToRepoCustomer(pgCustomer))
+	}
+
+	return customers, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
+}
+
+func pgCustomerToRepoCustomer(pgCustomer generated.Customer) *repository.Customer {
+	return &repository.Customer{
+		ID:          uint32(pgCustomer.ID),
+		Name:        pgCustomer.Name,
+		PhoneNumber: pgTextToString(pgCustomer.PhoneNumber),
+		DateOfBirth: pgDateToTime(pgCustomer.DateOfBirth),
+		Allergies:   pgTextToString(pgCustomer.Allergies),
+		Notes:       pgTextToString(pgCustomer.Notes),
+		Deleted:     pgCustomer.Deleted,
+		CreatedAt:   pgCustomer.CreatedAt,
+
+		CreditLimit:      pkg.PgTypeNumericToFloat64(pgCustomer.CreditLimit),
+		PaymentTermsDays: pgCustomer.PaymentTermsDays,
+	}
+}
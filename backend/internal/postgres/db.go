@@ -14,14 +14,54 @@ import (
 )
 
 type PostgresRepo struct {
-	UserRepository     *UserRepository
-	ProductsRepository *ProductRepository
+	UserRepository            *UserRepository
+	ProductsRepository        *ProductRepository
+	SalesRepository           *SaleRepository
+	CustomerRepository        *CustomerRepository
+	PrescriptionRepository    *PrescriptionRepository
+	ReturnRepository          *ReturnRepository
+	SupplierRepository        *SupplierRepository
+	SupplierReturnRepository  *SupplierReturnRepository
+	ReservationRepository     *ReservationRepository
+	CategoryRepository        *CategoryRepository
+	TaxClassRepository        *TaxClassRepository
+	PromotionRepository       *PromotionRepository
+	PaymentRepository         *PaymentRepository
+	CustomerAccountRepository *CustomerAccountRepository
+	ShiftRepository           *ShiftRepository
+	QuotationRepository       *QuotationRepository
+	InsuranceRepository       *InsuranceRepository
+	ClaimRepository           *ClaimRepository
+	TaxInvoiceRepository      *TaxInvoiceRepository
+	PurchaseOrderRepository   *PurchaseOrderRepository
+	ForecastRepository        *ForecastRepository
+	ClassificationRepository  *ClassificationRepository
 }
 
 func NewPostgresRepo(store *Store) *PostgresRepo {
 	return &PostgresRepo{
-		UserRepository:     NewUserRepository(store),
-		ProductsRepository: NewProductRepository(store),
+		UserRepository:            NewUserRepository(store),
+		ProductsRepository:        NewProductRepository(store),
+		SalesRepository:           NewSaleRepository(store),
+		CustomerRepository:        NewCustomerRepository(store),
+		PrescriptionRepository:    NewPrescriptionRepository(store),
+		ReturnRepository:          NewReturnRepository(store),
+		SupplierRepository:        NewSupplierRepository(store),
+		SupplierReturnRepository:  NewSupplierReturnRepository(store),
+		ReservationRepository:     NewReservationRepository(store),
+		CategoryRepository:        NewCategoryRepository(store),
+		TaxClassRepository:        NewTaxClassRepository(store),
+		PromotionRepository:       NewPromotionRepository(store),
+		PaymentRepository:         NewPaymentRepository(store),
+		CustomerAccountRepository: NewCustomerAccountRepository(store),
+		ShiftRepository:           NewShiftRepository(store),
+		QuotationRepository:       NewQuotationRepository(store),
+		InsuranceRepository:       NewInsuranceRepository(store),
+		ClaimRepository:           NewClaimRepository(store),
+		TaxInvoiceRepository:      NewTaxInvoiceRepository(store),
+		PurchaseOrderRepository:   NewPurchaseOrderRepository(store),
+		ForecastRepository:        NewForecastRepository(store),
+		ClassificationRepository:  NewClassificationRepository(store),
 	}
 }
 
@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/forecast"
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+var _ repository.ForecastRepository = (*ForecastRepository)(nil)
+
+const (
+	maxForecastHistoryDays = 730
+	maxForecastHorizonDays = 180
+
+	dayKeyLayout = "2006-01-02"
+)
+
+type ForecastRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewForecastRepository(db *Store) *ForecastRepository {
+	return &ForecastRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (fr *ForecastRepository) GetProductForecast(ctx context.Context, productID int64, filter *repository.ForecastFilter) (*repository.ProductForecast, error) {
+	settings, err := newForecastSettings(fr.db.config, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := fr.queries.ListForecastProducts(ctx, generated.ListForecastProductsParams{
+		ProductIds: []int64{productID},
+		Offset:     0,
+		Limit:      1,
+	})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get product: %s", err.Error())
+	}
+	if len(products) == 0 {
+		return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "product %d not found or is a kit", productID)
+	}
+
+	forecasts, err := forecastProductsTx(ctx, fr.queries, settings, products)
+	if err != nil {
+		return nil, err
+	}
+
+	return forecasts[0], nil
+}
+
+func (fr *ForecastRepository) List(ctx context.Context, filter *repository.ForecastFilter) ([]*repository.ProductForecast, *pkg.Pagination, error) {
+	settings, err := newForecastSettings(fr.db.config, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	products, err := fr.queries.ListForecastProducts(ctx, generated.ListForecastProductsParams{
+		ProductIds: nil,
+		Offset:     pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+		Limit:      int32(filter.Pagination.PageSize),
+	})
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list products: %s", err.Error())
+	}
+
+	totalCount, err := fr.queries.ListForecastProductsCount(ctx)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count products: %s", err.Error())
+	}
+
+	forecasts, err := forecastProductsTx(ctx, fr.queries, settings, products)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return forecasts, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
+}
+
+type forecastSettings struct {
+	forecaster  *forecast.Forecaster
+	historyDays int
+	horizonDays int
+}
+
+// newForecastSettings applies the filter's overrides to the configured settings.
+func newForecastSettings(config pkg.Config, filter *repository.ForecastFilter) (*forecastSettings, error) {
+	method := config.FORECAST_METHOD
+	if filter.Method != nil {
+		method = *filter.Method
+	}
+	forecaster, err := forecast.NewForecaster(method)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &forecastSettings{
+		forecaster:  forecaster,
+		historyDays: config.FORECAST_HISTORY_DAYS,
+		horizonDays: config.FORECAST_HORIZON_DAYS,
+	}
+	if filter.HistoryDays != nil {
+		settings.historyDays = *filter.HistoryDays
+	}
+	if filter.HorizonDays != nil {
+		settings.horizonDays = *filter.HorizonDays
+	}
+
+	if settings.historyDays <= 0 || settings.historyDays > maxForecastHistoryDays {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "history days must be between 1 and %d", maxForecastHistoryDays)
+	}
+	if settings.horizonDays <= 0 || settings.horizonDays > maxForecastHorizonDays {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "horizon days must be between 1 and %d", maxForecastHorizonDays)
+	}
+
+	return settings, nil
+}
+
+// forecastProductsTx forecasts each product from its daily demand over the
+// history days up to yesterday, so today's partial demand is left out. History
+// starts no earlier than the day the product was created.
+func forecastProductsTx(ctx context.Context, q *generated.Queries, settings *forecastSettings, products []generated.ListForecastProductsRow) ([]*repository.ProductForecast, error) {
+	forecasts := make([]*repository.ProductForecast, 0, len(products))
+	if len(products) == 0 {
+		return forecasts, nil
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := today.AddDate(0, 0, -settings.historyDays)
+
+	ids := make([]int64, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+
+	demand, err := q.ListDailyDemand(ctx, generated.ListDailyDemandParams{
+		ProductIds: ids,
+		StartDate:  start,
+		EndDate:    today,
+	})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list daily demand: %s", err.Error())
+	}
+
+	demandByDay := make(map[int64]map[string]float64, len(products))
+	for _, d := range demand {
+		if demandByDay[d.ProductID] == nil {
+			demandByDay[d.ProductID] = make(map[string]float64)
+		}
+		demandByDay[d.ProductID][d.Day.Time.Format(dayKeyLayout)] = float64(d.Quantity)
+	}
+
+	for _, p := range products {
+		first := start
+		created := p.CreatedAt.In(now.Location())
+		created = time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, now.Location())
+		if created.After(first) {
+			first = created
+		}
+
+		// days without demand are zeros in the history
+		history := []float64{}
+		for day := first; day.Before(today); day = day.AddDate(0, 0, 1) {
+			history = append(history, demandByDay[p.ID][day.Format(dayKeyLayout)])
+		}
+
+		result := settings.forecaster.Forecast(history, today.AddDate(0, 0, -1), settings.horizonDays)
+
+		pf := &repository.ProductForecast{
+			ProductID:   uint32(p.ID),
+			ProductName: p.Name,
+			Method:      result.Method,
+			HistoryDays: len(history),
+			HorizonDays: settings.horizonDays,
+			Points:      make([]*repository.ForecastPoint, len(result.Points)),
+			TotalDemand: result.Total,
+			TotalLower:  result.TotalLower,
+			TotalUpper:  result.TotalUpper,
+
+			AvailableStock: p.AvailableStock,
+			OnOrder:        p.OnOrder,
+			ReorderPoint:   pgInt4ToInt32(p.ReorderPoint),
+		}
+		for i, point := range result.Points {
+			pf.Points[i] = &repository.ForecastPoint{
+				Date:     point.Date,
+				Quantity: point.Quantity,
+				Lower:    point.Lower,
+				Upper:    point.Upper,
+			}
+		}
+
+		if dailyDemand := result.Total / float64(settings.horizonDays); dailyDemand > 0 {
+			cover := math.Round(float64(max(p.AvailableStock, 0))/dailyDemand*10) / 10
+			pf.DaysOfCover = &cover
+		}
+		pf.SuggestedOrderQuantity = max(int64(math.Ceil(result.TotalUpper))-p.AvailableStock-p.OnOrder, 0)
+
+		forecasts = append(forecasts, pf)
+	}
+
+	return forecasts, nil
+}
@@ -10,29 +10,381 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type Category struct {
+	ID        int64       `json:"id"`
+	Name      string      `json:"name"`
+	ParentID  pgtype.Int8 `json:"parent_id"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type Claim struct {
+	ID                int64              `json:"id"`
+	BatchID           int64              `json:"batch_id"`
+	SaleID            int64              `json:"sale_id"`
+	SchemeID          int64              `json:"scheme_id"`
+	MemberNumber      string             `json:"member_number"`
+	Amount            pgtype.Numeric     `json:"amount"`
+	Status            string             `json:"status"`
+	ApprovedAmount    pgtype.Numeric     `json:"approved_amount"`
+	RejectionReason   pgtype.Text        `json:"rejection_reason"`
+	ExternalReference pgtype.Text        `json:"external_reference"`
+	ResolvedAt        pgtype.Timestamptz `json:"resolved_at"`
+}
+
+type ClaimBatch struct {
+	ID                int64              `json:"id"`
+	BatchNumber       string             `json:"batch_number"`
+	InsurerID         int64              `json:"insurer_id"`
+	PeriodStart       time.Time          `json:"period_start"`
+	PeriodEnd         time.Time          `json:"period_end"`
+	Status            string             `json:"status"`
+	TotalClaims       int64              `json:"total_claims"`
+	TotalAmount       pgtype.Numeric     `json:"total_amount"`
+	ApprovedAmount    pgtype.Numeric     `json:"approved_amount"`
+	ExternalReference pgtype.Text        `json:"external_reference"`
+	SubmittedBy       pgtype.Int8        `json:"submitted_by"`
+	SubmittedAt       pgtype.Timestamptz `json:"submitted_at"`
+	CreatedBy         int64              `json:"created_by"`
+	CreatedAt         time.Time          `json:"created_at"`
+}
+
+type ControlledDrugRegister struct {
+	ID             int64       `json:"id"`
+	ProductID      int64       `json:"product_id"`
+	MovementID     int64       `json:"movement_id"`
+	EntryType      string      `json:"entry_type"`
+	Quantity       int32       `json:"quantity"`
+	Balance        int64       `json:"balance"`
+	Reference      pgtype.Text `json:"reference"`
+	CustomerID     pgtype.Int8 `json:"customer_id"`
+	PrescriptionID pgtype.Int8 `json:"prescription_id"`
+	PerformedBy    int64       `json:"performed_by"`
+	WitnessedBy    int64       `json:"witnessed_by"`
+	PreviousHash   string      `json:"previous_hash"`
+	Hash           string      `json:"hash"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+type Customer struct {
+	ID               int64          `json:"id"`
+	Name             string         `json:"name"`
+	PhoneNumber      pgtype.Text    `json:"phone_number"`
+	DateOfBirth      pgtype.Date    `json:"date_of_birth"`
+	Allergies        pgtype.Text    `json:"allergies"`
+	Notes            pgtype.Text    `json:"notes"`
+	Deleted          bool           `json:"deleted"`
+	CreatedAt        time.Time      `json:"created_at"`
+	CreditLimit      pgtype.Numeric `json:"credit_limit"`
+	PaymentTermsDays int32          `json:"payment_terms_days"`
+}
+
+type InsuranceScheme struct {
+	ID            int64          `json:"id"`
+	InsurerID     int64          `json:"insurer_id"`
+	Name          string         `json:"name"`
+	CoPaymentRate pgtype.Numeric `json:"co_payment_rate"`
+	Active        bool           `json:"active"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+type Insurer struct {
+	ID          int64       `json:"id"`
+	Name        string      `json:"name"`
+	Code        string      `json:"code"`
+	Client      string      `json:"client"`
+	Email       pgtype.Text `json:"email"`
+	PhoneNumber pgtype.Text `json:"phone_number"`
+	Deleted     bool        `json:"deleted"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+type KitComponent struct {
+	KitID       int64 `json:"kit_id"`
+	ComponentID int64 `json:"component_id"`
+	Quantity    int32 `json:"quantity"`
+}
+
 type Movement struct {
-	ID          int64          `json:"id"`
-	ProductID   int64          `json:"product_id"`
-	Quantity    int32          `json:"quantity"`
-	Price       pgtype.Numeric `json:"price"`
-	Type        string         `json:"type"`
-	Note        pgtype.Text    `json:"note"`
-	BatchNumber pgtype.Text    `json:"batch_number"`
-	PerformedBy int64          `json:"performed_by"`
-	CreatedAt   time.Time      `json:"created_at"`
+	ID             int64          `json:"id"`
+	ProductID      int64          `json:"product_id"`
+	Quantity       int32          `json:"quantity"`
+	Price          pgtype.Numeric `json:"price"`
+	Type           string         `json:"type"`
+	Note           pgtype.Text    `json:"note"`
+	BatchNumber    pgtype.Text    `json:"batch_number"`
+	PerformedBy    int64          `json:"performed_by"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UnitCost       pgtype.Numeric `json:"unit_cost"`
+	SaleID         pgtype.Int8    `json:"sale_id"`
+	CustomerID     pgtype.Int8    `json:"customer_id"`
+	PrescriptionID pgtype.Int8    `json:"prescription_id"`
+	WitnessedBy    pgtype.Int8    `json:"witnessed_by"`
+	Reason         pgtype.Text    `json:"reason"`
+}
+
+type Payment struct {
+	ID                int64              `json:"id"`
+	SaleID            int64              `json:"sale_id"`
+	Method            string             `json:"method"`
+	Amount            pgtype.Numeric     `json:"amount"`
+	Status            string             `json:"status"`
+	Reference         pgtype.Text        `json:"reference"`
+	PhoneNumber       pgtype.Text        `json:"phone_number"`
+	CheckoutRequestID pgtype.Text        `json:"checkout_request_id"`
+	FailureReason     pgtype.Text        `json:"failure_reason"`
+	ReceivedBy        int64              `json:"received_by"`
+	CompletedAt       pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt         time.Time          `json:"created_at"`
+	ShiftID           pgtype.Int8        `json:"shift_id"`
+}
+
+type Prescription struct {
+	ID                      int64       `json:"id"`
+	CustomerID              int64       `json:"customer_id"`
+	PrescriberName          string      `json:"prescriber_name"`
+	PrescriberLicenceNumber string      `json:"prescriber_licence_number"`
+	PrescribedAt            pgtype.Date `json:"prescribed_at"`
+	ValidUntil              pgtype.Date `json:"valid_until"`
+	Notes                   pgtype.Text `json:"notes"`
+	CreatedBy               int64       `json:"created_by"`
+	CreatedAt               time.Time   `json:"created_at"`
+}
+
+type PrescriptionItem struct {
+	ID                int64       `json:"id"`
+	PrescriptionID    int64       `json:"prescription_id"`
+	ProductID         int64       `json:"product_id"`
+	Quantity          int32       `json:"quantity"`
+	DispensedQuantity int32       `json:"dispensed_quantity"`
+	Dosage            pgtype.Text `json:"dosage"`
+}
+
+type PrescriptionScan struct {
+	ID             int64     `json:"id"`
+	PrescriptionID int64     `json:"prescription_id"`
+	FileName       string    `json:"file_name"`
+	ContentType    string    `json:"content_type"`
+	Data           []byte    `json:"data"`
+	UploadedBy     int64     `json:"uploaded_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type PriceHistory struct {
+	ID          int64              `json:"id"`
+	ProductID   int64              `json:"product_id"`
+	OldPrice    pgtype.Numeric     `json:"old_price"`
+	NewPrice    pgtype.Numeric     `json:"new_price"`
+	EffectiveAt time.Time          `json:"effective_at"`
+	Applied     bool               `json:"applied"`
+	AppliedAt   pgtype.Timestamptz `json:"applied_at"`
+	Note        pgtype.Text        `json:"note"`
+	ChangedBy   int64              `json:"changed_by"`
+	CreatedAt   time.Time          `json:"created_at"`
 }
 
 type Product struct {
-	ID                int64          `json:"id"`
-	Name              string         `json:"name"`
-	Description       pgtype.Text    `json:"description"`
-	Price             pgtype.Numeric `json:"price"`
-	Stock             int64          `json:"stock"`
-	Category          string         `json:"category"`
-	Unit              string         `json:"unit"`
-	LowStockThreshold int32          `json:"low_stock_threshold"`
-	Deleted           bool           `json:"deleted"`
-	CreatedAt         time.Time      `json:"created_at"`
+	ID                  int64              `json:"id"`
+	Name                string             `json:"name"`
+	Description         pgtype.Text        `json:"description"`
+	Price               pgtype.Numeric     `json:"price"`
+	Stock               int64              `json:"stock"`
+	Category            string             `json:"category"`
+	Unit                string             `json:"unit"`
+	LowStockThreshold   int32              `json:"low_stock_threshold"`
+	Deleted             bool               `json:"deleted"`
+	CreatedAt           time.Time          `json:"created_at"`
+	OpeningUnitCost     pgtype.Numeric     `json:"opening_unit_cost"`
+	PrescriptionOnly    bool               `json:"prescription_only"`
+	Controlled          bool               `json:"controlled"`
+	QuarantinedStock    int64              `json:"quarantined_stock"`
+	ReservedStock       int64              `json:"reserved_stock"`
+	IsKit               bool               `json:"is_kit"`
+	CategoryID          pgtype.Int8        `json:"category_id"`
+	GenericName         pgtype.Text        `json:"generic_name"`
+	Strength            pgtype.Text        `json:"strength"`
+	DosageForm          pgtype.Text        `json:"dosage_form"`
+	Route               pgtype.Text        `json:"route"`
+	Manufacturer        pgtype.Text        `json:"manufacturer"`
+	PackSize            pgtype.Int4        `json:"pack_size"`
+	TherapeuticClass    pgtype.Text        `json:"therapeutic_class"`
+	TaxClassID          int64              `json:"tax_class_id"`
+	ReorderPoint        pgtype.Int4        `json:"reorder_point"`
+	ReorderQuantity     pgtype.Int4        `json:"reorder_quantity"`
+	PreferredSupplierID pgtype.Int8        `json:"preferred_supplier_id"`
+	AbcClass            pgtype.Text        `json:"abc_class"`
+	XyzClass            pgtype.Text        `json:"xyz_class"`
+	ClassifiedAt        pgtype.Timestamptz `json:"classified_at"`
+}
+
+type Promotion struct {
+	ID           int64          `json:"id"`
+	Name         string         `json:"name"`
+	Type         string         `json:"type"`
+	Value        pgtype.Numeric `json:"value"`
+	BuyQuantity  pgtype.Int4    `json:"buy_quantity"`
+	FreeQuantity pgtype.Int4    `json:"free_quantity"`
+	ProductID    pgtype.Int8    `json:"product_id"`
+	CategoryID   pgtype.Int8    `json:"category_id"`
+	StartsAt     time.Time      `json:"starts_at"`
+	EndsAt       time.Time      `json:"ends_at"`
+	Active       bool           `json:"active"`
+	CreatedBy    int64          `json:"created_by"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+type PurchaseOrder struct {
+	ID            int64              `json:"id"`
+	OrderNumber   string             `json:"order_number"`
+	SupplierID    int64              `json:"supplier_id"`
+	Status        string             `json:"status"`
+	TotalQuantity int64              `json:"total_quantity"`
+	TotalAmount   pgtype.Numeric     `json:"total_amount"`
+	Note          pgtype.Text        `json:"note"`
+	CreatedBy     pgtype.Int8        `json:"created_by"`
+	OrderedBy     pgtype.Int8        `json:"ordered_by"`
+	OrderedAt     pgtype.Timestamptz `json:"ordered_at"`
+	ClosedAt      pgtype.Timestamptz `json:"closed_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+type PurchaseOrderItem struct {
+	ID              int64          `json:"id"`
+	PurchaseOrderID int64          `json:"purchase_order_id"`
+	ProductID       int64          `json:"product_id"`
+	Quantity        int32          `json:"quantity"`
+	UnitCost        pgtype.Numeric `json:"unit_cost"`
+	LineTotal       pgtype.Numeric `json:"line_total"`
+}
+
+type Quotation struct {
+	ID              int64              `json:"id"`
+	QuotationNumber pgtype.Text        `json:"quotation_number"`
+	CustomerID      pgtype.Int8        `json:"customer_id"`
+	Status          string             `json:"status"`
+	Note            pgtype.Text        `json:"note"`
+	ValidUntil      time.Time          `json:"valid_until"`
+	TotalQuantity   int64              `json:"total_quantity"`
+	TotalAmount     pgtype.Numeric     `json:"total_amount"`
+	TotalTax        pgtype.Numeric     `json:"total_tax"`
+	TotalDiscount   pgtype.Numeric     `json:"total_discount"`
+	SaleID          pgtype.Int8        `json:"sale_id"`
+	CreatedBy       int64              `json:"created_by"`
+	ResolvedBy      pgtype.Int8        `json:"resolved_by"`
+	ResolvedAt      pgtype.Timestamptz `json:"resolved_at"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+type QuotationItem struct {
+	ID             int64          `json:"id"`
+	QuotationID    int64          `json:"quotation_id"`
+	ProductID      int64          `json:"product_id"`
+	Quantity       int32          `json:"quantity"`
+	UnitPrice      pgtype.Numeric `json:"unit_price"`
+	DiscountAmount pgtype.Numeric `json:"discount_amount"`
+	LineTotal      pgtype.Numeric `json:"line_total"`
+	PromotionID    pgtype.Int8    `json:"promotion_id"`
+	TaxCode        string         `json:"tax_code"`
+	TaxRate        pgtype.Numeric `json:"tax_rate"`
+	TaxAmount      pgtype.Numeric `json:"tax_amount"`
+}
+
+type ReceiptSequence struct {
+	Location   string `json:"location"`
+	LastNumber int64  `json:"last_number"`
+}
+
+type Reservation struct {
+	ID         int64              `json:"id"`
+	CustomerID pgtype.Int8        `json:"customer_id"`
+	Status     string             `json:"status"`
+	Note       pgtype.Text        `json:"note"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	SaleID     pgtype.Int8        `json:"sale_id"`
+	CreatedBy  int64              `json:"created_by"`
+	ResolvedBy pgtype.Int8        `json:"resolved_by"`
+	ResolvedAt pgtype.Timestamptz `json:"resolved_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type ReservationItem struct {
+	ID            int64 `json:"id"`
+	ReservationID int64 `json:"reservation_id"`
+	ProductID     int64 `json:"product_id"`
+	Quantity      int32 `json:"quantity"`
+}
+
+type Return struct {
+	ID                int64              `json:"id"`
+	MovementID        int64              `json:"movement_id"`
+	SaleID            pgtype.Int8        `json:"sale_id"`
+	ProductID         int64              `json:"product_id"`
+	CustomerID        pgtype.Int8        `json:"customer_id"`
+	Quantity          int32              `json:"quantity"`
+	Status            string             `json:"status"`
+	Reason            pgtype.Text        `json:"reason"`
+	BatchNumber       pgtype.Text        `json:"batch_number"`
+	RestockMovementID pgtype.Int8        `json:"restock_movement_id"`
+	PerformedBy       int64              `json:"performed_by"`
+	ResolvedBy        pgtype.Int8        `json:"resolved_by"`
+	ResolvedAt        pgtype.Timestamptz `json:"resolved_at"`
+	CreatedAt         time.Time          `json:"created_at"`
+}
+
+type Sale struct {
+	ID                int64              `json:"id"`
+	TotalQuantity     int64              `json:"total_quantity"`
+	TotalAmount       pgtype.Numeric     `json:"total_amount"`
+	Note              pgtype.Text        `json:"note"`
+	PerformedBy       int64              `json:"performed_by"`
+	CreatedAt         time.Time          `json:"created_at"`
+	Location          string             `json:"location"`
+	ReceiptNumber     string             `json:"receipt_number"`
+	ReceiptPrintCount int32              `json:"receipt_print_count"`
+	CustomerID        pgtype.Int8        `json:"customer_id"`
+	TotalTax          pgtype.Numeric     `json:"total_tax"`
+	TotalDiscount     pgtype.Numeric     `json:"total_discount"`
+	OnAccount         bool               `json:"on_account"`
+	DueDate           pgtype.Timestamptz `json:"due_date"`
+	ShiftID           pgtype.Int8        `json:"shift_id"`
+	SchemeID          pgtype.Int8        `json:"scheme_id"`
+	MemberNumber      pgtype.Text        `json:"member_number"`
+}
+
+type SaleItem struct {
+	ID             int64          `json:"id"`
+	SaleID         int64          `json:"sale_id"`
+	ProductID      int64          `json:"product_id"`
+	MovementID     int64          `json:"movement_id"`
+	Quantity       int32          `json:"quantity"`
+	UnitPrice      pgtype.Numeric `json:"unit_price"`
+	LineTotal      pgtype.Numeric `json:"line_total"`
+	CreatedAt      time.Time      `json:"created_at"`
+	TaxClassID     int64          `json:"tax_class_id"`
+	TaxCode        string         `json:"tax_code"`
+	TaxRate        pgtype.Numeric `json:"tax_rate"`
+	TaxAmount      pgtype.Numeric `json:"tax_amount"`
+	PromotionID    pgtype.Int8    `json:"promotion_id"`
+	DiscountAmount pgtype.Numeric `json:"discount_amount"`
+}
+
+type Shift struct {
+	ID           int64              `json:"id"`
+	UserID       int64              `json:"user_id"`
+	Location     string             `json:"location"`
+	Status       string             `json:"status"`
+	OpeningFloat pgtype.Numeric     `json:"opening_float"`
+	OpeningNote  pgtype.Text        `json:"opening_note"`
+	ClosingNote  pgtype.Text        `json:"closing_note"`
+	OpenedAt     time.Time          `json:"opened_at"`
+	ClosedAt     pgtype.Timestamptz `json:"closed_at"`
+}
+
+type ShiftTender struct {
+	ShiftID        int64          `json:"shift_id"`
+	Method         string         `json:"method"`
+	ExpectedAmount pgtype.Numeric `json:"expected_amount"`
+	CountedAmount  pgtype.Numeric `json:"counted_amount"`
+	Variance       pgtype.Numeric `json:"variance"`
 }
 
 type Stat struct {
@@ -48,6 +400,77 @@ type Stat struct {
 	TotalValue              pgtype.Numeric `json:"total_value"`
 }
 
+type Supplier struct {
+	ID            int64       `json:"id"`
+	Name          string      `json:"name"`
+	ContactPerson pgtype.Text `json:"contact_person"`
+	PhoneNumber   pgtype.Text `json:"phone_number"`
+	Email         pgtype.Text `json:"email"`
+	Address       pgtype.Text `json:"address"`
+	Deleted       bool        `json:"deleted"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+type SupplierCredit struct {
+	ID               int64          `json:"id"`
+	SupplierReturnID int64          `json:"supplier_return_id"`
+	Amount           pgtype.Numeric `json:"amount"`
+	Reference        pgtype.Text    `json:"reference"`
+	ReceivedAt       pgtype.Date    `json:"received_at"`
+	RecordedBy       int64          `json:"recorded_by"`
+	CreatedAt        time.Time      `json:"created_at"`
+}
+
+type SupplierReturn struct {
+	ID              int64          `json:"id"`
+	SupplierID      int64          `json:"supplier_id"`
+	DebitNoteNumber string         `json:"debit_note_number"`
+	TotalQuantity   int64          `json:"total_quantity"`
+	TotalAmount     pgtype.Numeric `json:"total_amount"`
+	CreditReceived  pgtype.Numeric `json:"credit_received"`
+	CreditStatus    string         `json:"credit_status"`
+	Note            pgtype.Text    `json:"note"`
+	PerformedBy     int64          `json:"performed_by"`
+	CreatedAt       time.Time      `json:"created_at"`
+}
+
+type SupplierReturnItem struct {
+	ID               int64          `json:"id"`
+	SupplierReturnID int64          `json:"supplier_return_id"`
+	ProductID        int64          `json:"product_id"`
+	BatchNumber      pgtype.Text    `json:"batch_number"`
+	Quantity         int32          `json:"quantity"`
+	UnitCost         pgtype.Numeric `json:"unit_cost"`
+	LineTotal        pgtype.Numeric `json:"line_total"`
+	Reason           pgtype.Text    `json:"reason"`
+	MovementID       pgtype.Int8    `json:"movement_id"`
+	ReturnID         pgtype.Int8    `json:"return_id"`
+}
+
+type TaxClass struct {
+	ID        int64          `json:"id"`
+	Code      string         `json:"code"`
+	Name      string         `json:"name"`
+	Type      string         `json:"type"`
+	Rate      pgtype.Numeric `json:"rate"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type TaxInvoice struct {
+	ID               int64              `json:"id"`
+	SaleID           int64              `json:"sale_id"`
+	Status           string             `json:"status"`
+	Attempts         int32              `json:"attempts"`
+	LastError        pgtype.Text        `json:"last_error"`
+	NextAttemptAt    time.Time          `json:"next_attempt_at"`
+	ControlNumber    pgtype.Text        `json:"control_number"`
+	InternalData     pgtype.Text        `json:"internal_data"`
+	ReceiptSignature pgtype.Text        `json:"receipt_signature"`
+	QrCode           pgtype.Text        `json:"qr_code"`
+	SignedAt         pgtype.Timestamptz `json:"signed_at"`
+	CreatedAt        time.Time          `json:"created_at"`
+}
+
 type User struct {
 	ID           int64       `json:"id"`
 	Name         string      `json:"name"`
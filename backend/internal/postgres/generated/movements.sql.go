@@ -13,19 +13,25 @@ import (
 )
 
 const createMovement = `-- name: CreateMovement :one
-INSERT INTO movements (product_id, quantity, price, type, note, batch_number, performed_by)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, product_id, quantity, price, type, note, batch_number, performed_by, created_at
+INSERT INTO movements (product_id, quantity, price, type, note, batch_number, performed_by, unit_cost, sale_id, customer_id, prescription_id, witnessed_by, reason)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+RETURNING id, product_id, quantity, price, type, note, batch_number, performed_by, created_at, unit_cost, sale_id, customer_id, prescription_id, witnessed_by, reason
 `
 
 type CreateMovementParams struct {
-	ProductID   int64          `json:"product_id"`
-	Quantity    int32          `json:"quantity"`
-	Price       pgtype.Numeric `json:"price"`
-	Type        string         `json:"type"`
-	Note        pgtype.Text    `json:"note"`
-	BatchNumber pgtype.Text    `json:"batch_number"`
-	PerformedBy int64          `json:"performed_by"`
+	ProductID      int64          `json:"product_id"`
+	Quantity       int32          `json:"quantity"`
+	Price          pgtype.Numeric `json:"price"`
+	Type           string         `json:"type"`
+	Note           pgtype.Text    `json:"note"`
+	BatchNumber    pgtype.Text    `json:"batch_number"`
+	PerformedBy    int64          `json:"performed_by"`
+	UnitCost       pgtype.Numeric `json:"unit_cost"`
+	SaleID         pgtype.Int8    `json:"sale_id"`
+	CustomerID     pgtype.Int8    `json:"customer_id"`
+	PrescriptionID pgtype.Int8    `json:"prescription_id"`
+	WitnessedBy    pgtype.Int8    `json:"witnessed_by"`
+	Reason         pgtype.Text    `json:"reason"`
 }
 
 func (q *Queries) CreateMovement(ctx context.Context, arg CreateMovementParams) (Movement, error) {
@@ -37,6 +43,12 @@ func (q *Queries) CreateMovement(ctx context.Context, arg CreateMovementParams)
 		arg.Note,
 		arg.BatchNumber,
 		arg.PerformedBy,
+		arg.UnitCost,
+		arg.SaleID,
+		arg.CustomerID,
+		arg.PrescriptionID,
+		arg.WitnessedBy,
+		arg.Reason,
 	)
 	var i Movement
 	err := row.Scan(
@@ -49,12 +61,18 @@ func (q *Queries) CreateMovement(ctx context.Context, arg CreateMovementParams)
 		&i.BatchNumber,
 		&i.PerformedBy,
 		&i.CreatedAt,
+		&i.UnitCost,
+		&i.SaleID,
+		&i.CustomerID,
+		&i.PrescriptionID,
+		&i.WitnessedBy,
+		&i.Reason,
 	)
 	return i, err
 }
 
 const getMovementByID = `-- name: GetMovementByID :one
-SELECT id, product_id, quantity, price, type, note, batch_number, performed_by, created_at FROM movements WHERE id = $1
+SELECT id, product_id, quantity, price, type, note, batch_number, performed_by, created_at, unit_cost, sale_id, customer_id, prescription_id, witnessed_by, reason FROM movements WHERE id = $1
 `
 
 func (q *Queries) GetMovementByID(ctx context.Context, id int64) (Movement, error) {
@@ -70,13 +88,19 @@ func (q *Queries) GetMovementByID(ctx context.Context, id int64) (Movement, erro
 		&i.BatchNumber,
 		&i.PerformedBy,
 		&i.CreatedAt,
+		&i.UnitCost,
+		&i.SaleID,
+		&i.CustomerID,
+		&i.PrescriptionID,
+		&i.WitnessedBy,
+		&i.Reason,
 	)
 	return i, err
 }
 
 const listMovements = `-- name: ListMovements :many
 SELECT 
-    m.id, m.product_id, m.quantity, m.price, m.type, m.note, m.batch_number, m.performed_by, m.created_at,
+    m.id, m.product_id, m.quantity, m.price, m.type, m.note, m.batch_number, m.performed_by, m.created_at, m.unit_cost, m.sale_id, m.customer_id, m.prescription_id, m.witnessed_by, m.reason,
     p.name AS product_name,
     u.name AS user_name
 FROM movements AS m
@@ -96,18 +120,23 @@ WHERE
         OR m.batch_number = $3
     )
     AND (
-        $4::timestamptz IS NULL
-        OR m.created_at BETWEEN $4::timestamptz 
-            AND COALESCE($5::timestamptz, now())
+        $4::bigint IS NULL 
+        OR m.customer_id = $4
+    )
+    AND (
+        $5::timestamptz IS NULL
+        OR m.created_at BETWEEN $5::timestamptz 
+            AND COALESCE($6::timestamptz, now())
     )
 ORDER BY m.created_at DESC
-LIMIT $7 OFFSET $6
+LIMIT $8 OFFSET $7
 `
 
 type ListMovementsParams struct {
 	ProductID   pgtype.Int8        `json:"product_id"`
 	Type        pgtype.Text        `json:"type"`
 	BatchNumber pgtype.Text        `json:"batch_number"`
+	CustomerID  pgtype.Int8        `json:"customer_id"`
 	StartDate   pgtype.Timestamptz `json:"start_date"`
 	EndDate     pgtype.Timestamptz `json:"end_date"`
 	Offset      int32              `json:"offset"`
@@ -115,17 +144,23 @@ type ListMovementsParams struct {
 }
 
 type ListMovementsRow struct {
-	ID          int64          `json:"id"`
-	ProductID   int64          `json:"product_id"`
-	Quantity    int32          `json:"quantity"`
-	Price       pgtype.Numeric `json:"price"`
-	Type        string         `json:"type"`
-	Note        pgtype.Text    `json:"note"`
-	BatchNumber pgtype.Text    `json:"batch_number"`
-	PerformedBy int64          `json:"performed_by"`
-	CreatedAt   time.Time      `json:"created_at"`
-	ProductName string         `json:"product_name"`
-	UserName    string         `json:"user_name"`
+	ID             int64          `json:"id"`
+	ProductID      int64          `json:"product_id"`
+	Quantity       int32          `json:"quantity"`
+	Price          pgtype.Numeric `json:"price"`
+	Type           string         `json:"type"`
+	Note           pgtype.Text    `json:"note"`
+	BatchNumber    pgtype.Text    `json:"batch_number"`
+	PerformedBy    int64          `json:"performed_by"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UnitCost       pgtype.Numeric `json:"unit_cost"`
+	SaleID         pgtype.Int8    `json:"sale_id"`
+	CustomerID     pgtype.Int8    `json:"customer_id"`
+	PrescriptionID pgtype.Int8    `json:"prescription_id"`
+	WitnessedBy    pgtype.Int8    `json:"witnessed_by"`
+	Reason         pgtype.Text    `json:"reason"`
+	ProductName    string         `json:"product_name"`
+	UserName       string         `json:"user_name"`
 }
 
 func (q *Queries) ListMovements(ctx context.Context, arg ListMovementsParams) ([]ListMovementsRow, error) {
@@ -133,6 +168,7 @@ func (q *Queries) ListMovements(ctx context.Context, arg ListMovementsParams) ([
 		arg.ProductID,
 		arg.Type,
 		arg.BatchNumber,
+		arg.CustomerID,
 		arg.StartDate,
 		arg.EndDate,
 		arg.Offset,
@@ -155,6 +191,12 @@ func (q *Queries) ListMovements(ctx context.Context, arg ListMovementsParams) ([
 			&i.BatchNumber,
 			&i.PerformedBy,
 			&i.CreatedAt,
+			&i.UnitCost,
+			&i.SaleID,
+			&i.CustomerID,
+			&i.PrescriptionID,
+			&i.WitnessedBy,
+			&i.Reason,
 			&i.ProductName,
 			&i.UserName,
 		); err != nil {
@@ -185,8 +227,12 @@ WHERE
         OR batch_number = $3
     )
     AND (
-        $4::timestamptz IS NULL
-        OR created_at BETWEEN $4::timestamptz AND COALESCE($5::timestamptz, now())
+        $4::bigint IS NULL 
+        OR customer_id = $4
+    )
+    AND (
+        $5::timestamptz IS NULL
+        OR created_at BETWEEN $5::timestamptz AND COALESCE($6::timestamptz, now())
     )
 `
 
@@ -194,6 +240,7 @@ type ListMovementsCountParams struct {
 	ProductID   pgtype.Int8        `json:"product_id"`
 	Type        pgtype.Text        `json:"type"`
 	BatchNumber pgtype.Text        `json:"batch_number"`
+	CustomerID  pgtype.Int8        `json:"customer_id"`
 	StartDate   pgtype.Timestamptz `json:"start_date"`
 	EndDate     pgtype.Timestamptz `json:"end_date"`
 }
@@ -203,6 +250,7 @@ func (q *Queries) ListMovementsCount(ctx context.Context, arg ListMovementsCount
 		arg.ProductID,
 		arg.Type,
 		arg.BatchNumber,
+		arg.CustomerID,
 		arg.StartDate,
 		arg.EndDate,
 	)
@@ -210,3 +258,44 @@ func (q *Queries) ListMovementsCount(ctx context.Context, arg ListMovementsCount
 	err := row.Scan(&total_movements)
 	return total_movements, err
 }
+
+const listValuationMovements = `-- name: ListValuationMovements :many
+SELECT product_id, type, quantity, unit_cost, created_at
+FROM movements
+WHERE created_at <= $1
+ORDER BY created_at ASC, id ASC
+`
+
+type ListValuationMovementsRow struct {
+	ProductID int64          `json:"product_id"`
+	Type      string         `json:"type"`
+	Quantity  int32          `json:"quantity"`
+	UnitCost  pgtype.Numeric `json:"unit_cost"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+func (q *Queries) ListValuationMovements(ctx context.Context, endDate time.Time) ([]ListValuationMovementsRow, error) {
+	rows, err := q.db.Query(ctx, listValuationMovements, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListValuationMovementsRow{}
+	for rows.Next() {
+		var i ListValuationMovementsRow
+		if err := rows.Scan(
+			&i.ProductID,
+			&i.Type,
+			&i.Quantity,
+			&i.UnitCost,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -7,6 +7,7 @@ package generated
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -15,7 +16,7 @@ const addStock = `-- name: AddStock :one
 UPDATE products
 SET stock = stock + $1
 WHERE id = $2
-RETURNING id, name, description, price, stock, category, unit, low_stock_threshold, deleted, created_at
+RETURNING id, name, description, price, stock, category, unit, low_stock_threshold, deleted, created_at, opening_unit_cost, prescription_only, controlled, quarantined_stock, reserved_stock, is_kit, category_id, generic_name, strength, dosage_form, route, manufacturer, pack_size, therapeutic_class, tax_class_id, reorder_point, reorder_quantity, preferred_supplier_id, abc_class, xyz_class, classified_at
 `
 
 type AddStockParams struct {
@@ -37,14 +38,38 @@ func (q *Queries) AddStock(ctx context.Context, arg AddStockParams) (Product, er
 		&i.LowStockThreshold,
 		&i.Deleted,
 		&i.CreatedAt,
+		&i.OpeningUnitCost,
+		&i.PrescriptionOnly,
+		&i.Controlled,
+		&i.QuarantinedStock,
+		&i.ReservedStock,
+		&i.IsKit,
+		&i.CategoryID,
+		&i.GenericName,
+		&i.Strength,
+		&i.DosageForm,
+		&i.Route,
+		&i.Manufacturer,
+		&i.PackSize,
+		&i.TherapeuticClass,
+		&i.TaxClassID,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
+		&i.PreferredSupplierID,
+		&i.AbcClass,
+		&i.XyzClass,
+		&i.ClassifiedAt,
 	)
 	return i, err
 }
 
 const createProduct = `-- name: CreateProduct :one
-INSERT INTO products (name, description, price, stock, category, unit, low_stock_threshold)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, name, description, price, stock, category, unit, low_stock_threshold, deleted, created_at
+INSERT INTO products (
+    name, description, price, stock, category, unit, low_stock_threshold, prescription_only, controlled, category_id,
+    generic_name, strength, dosage_form, route, manufacturer, pack_size, therapeutic_class, tax_class_id, opening_unit_cost
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+RETURNING id, name, description, price, stock, category, unit, low_stock_threshold, deleted, created_at, opening_unit_cost, prescription_only, controlled, quarantined_stock, reserved_stock, is_kit, category_id, generic_name, strength, dosage_form, route, manufacturer, pack_size, therapeutic_class, tax_class_id, reorder_point, reorder_quantity, preferred_supplier_id, abc_class, xyz_class, classified_at
 `
 
 type CreateProductParams struct {
@@ -55,6 +80,18 @@ type CreateProductParams struct {
 	Category          string         `json:"category"`
 	Unit              string         `json:"unit"`
 	LowStockThreshold int32          `json:"low_stock_threshold"`
+	PrescriptionOnly  bool           `json:"prescription_only"`
+	Controlled        bool           `json:"controlled"`
+	CategoryID        pgtype.Int8    `json:"category_id"`
+	GenericName       pgtype.Text    `json:"generic_name"`
+	Strength          pgtype.Text    `json:"strength"`
+	DosageForm        pgtype.Text    `json:"dosage_form"`
+	Route             pgtype.Text    `json:"route"`
+	Manufacturer      pgtype.Text    `json:"manufacturer"`
+	PackSize          pgtype.Int4    `json:"pack_size"`
+	TherapeuticClass  pgtype.Text    `json:"therapeutic_class"`
+	TaxClassID        int64          `json:"tax_class_id"`
+	OpeningUnitCost   pgtype.Numeric `json:"opening_unit_cost"`
 }
 
 func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error) {
@@ -66,6 +103,18 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		arg.Category,
 		arg.Unit,
 		arg.LowStockThreshold,
+		arg.PrescriptionOnly,
+		arg.Controlled,
+		arg.CategoryID,
+		arg.GenericName,
+		arg.Strength,
+		arg.DosageForm,
+		arg.Route,
+		arg.Manufacturer,
+		arg.PackSize,
+		arg.TherapeuticClass,
+		arg.TaxClassID,
+		arg.OpeningUnitCost,
 	)
 	var i Product
 	err := row.Scan(
@@ -79,6 +128,27 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		&i.LowStockThreshold,
 		&i.Deleted,
 		&i.CreatedAt,
+		&i.OpeningUnitCost,
+		&i.PrescriptionOnly,
+		&i.Controlled,
+		&i.QuarantinedStock,
+		&i.ReservedStock,
+		&i.IsKit,
+		&i.CategoryID,
+		&i.GenericName,
+		&i.Strength,
+		&i.DosageForm,
+		&i.Route,
+		&i.Manufacturer,
+		&i.PackSize,
+		&i.TherapeuticClass,
+		&i.TaxClassID,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
+		&i.PreferredSupplierID,
+		&i.AbcClass,
+		&i.XyzClass,
+		&i.ClassifiedAt,
 	)
 	return i, err
 }
@@ -95,7 +165,7 @@ func (q *Queries) DeleteProduct(ctx context.Context, id int64) error {
 }
 
 const getProductByID = `-- name: GetProductByID :one
-SELECT id, name, description, price, stock, category, unit, low_stock_threshold, deleted, created_at FROM products WHERE id = $1 AND deleted = false
+SELECT id, name, description, price, stock, category, unit, low_stock_threshold, deleted, created_at, opening_unit_cost, prescription_only, controlled, quarantined_stock, reserved_stock, is_kit, category_id, generic_name, strength, dosage_form, route, manufacturer, pack_size, therapeutic_class, tax_class_id, reorder_point, reorder_quantity, preferred_supplier_id, abc_class, xyz_class, classified_at FROM products WHERE id = $1 AND deleted = false
 `
 
 func (q *Queries) GetProductByID(ctx context.Context, id int64) (Product, error) {
@@ -112,18 +182,190 @@ func (q *Queries) GetProductByID(ctx context.Context, id int64) (Product, error)
 		&i.LowStockThreshold,
 		&i.Deleted,
 		&i.CreatedAt,
+		&i.OpeningUnitCost,
+		&i.PrescriptionOnly,
+		&i.Controlled,
+		&i.QuarantinedStock,
+		&i.ReservedStock,
+		&i.IsKit,
+		&i.CategoryID,
+		&i.GenericName,
+		&i.Strength,
+		&i.DosageForm,
+		&i.Route,
+		&i.Manufacturer,
+		&i.PackSize,
+		&i.TherapeuticClass,
+		&i.TaxClassID,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
+		&i.PreferredSupplierID,
+		&i.AbcClass,
+		&i.XyzClass,
+		&i.ClassifiedAt,
 	)
 	return i, err
 }
 
+const getValuationVersion = `-- name: GetValuationVersion :one
+SELECT
+    (SELECT COALESCE(MAX(id), 0) FROM movements)::bigint AS last_movement_id,
+    COUNT(*)::bigint AS total_products,
+    COALESCE(MAX(id), 0)::bigint AS last_product_id,
+    COALESCE(SUM(stock), 0)::bigint AS total_stock
+FROM products
+WHERE deleted = false
+`
+
+type GetValuationVersionRow struct {
+	LastMovementID int64 `json:"last_movement_id"`
+	TotalProducts  int64 `json:"total_products"`
+	LastProductID  int64 `json:"last_product_id"`
+	TotalStock     int64 `json:"total_stock"`
+}
+
+func (q *Queries) GetValuationVersion(ctx context.Context) (GetValuationVersionRow, error) {
+	row := q.db.QueryRow(ctx, getValuationVersion)
+	var i GetValuationVersionRow
+	err := row.Scan(
+		&i.LastMovementID,
+		&i.TotalProducts,
+		&i.LastProductID,
+		&i.TotalStock,
+	)
+	return i, err
+}
+
+const listProductOpeningStock = `-- name: ListProductOpeningStock :many
+SELECT
+    p.id,
+    p.name,
+    p.opening_unit_cost,
+    p.created_at,
+    (p.stock - COALESCE(SUM(CASE WHEN m.type = 'ADD' THEN m.quantity ELSE -m.quantity END), 0))::bigint AS opening_stock
+FROM products AS p
+LEFT JOIN movements AS m ON m.product_id = p.id
+WHERE p.deleted = false
+GROUP BY p.id
+ORDER BY p.id
+`
+
+type ListProductOpeningStockRow struct {
+	ID              int64          `json:"id"`
+	Name            string         `json:"name"`
+	OpeningUnitCost pgtype.Numeric `json:"opening_unit_cost"`
+	CreatedAt       time.Time      `json:"created_at"`
+	OpeningStock    int64          `json:"opening_stock"`
+}
+
+func (q *Queries) ListProductOpeningStock(ctx context.Context) ([]ListProductOpeningStockRow, error) {
+	rows, err := q.db.Query(ctx, listProductOpeningStock)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListProductOpeningStockRow{}
+	for rows.Next() {
+		var i ListProductOpeningStockRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.OpeningUnitCost,
+			&i.CreatedAt,
+			&i.OpeningStock,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductSubstitutes = `-- name: ListProductSubstitutes :many
+SELECT s.id, s.name, s.description, s.price, s.stock, s.category, s.unit, s.low_stock_threshold, s.deleted, s.created_at, s.opening_unit_cost, s.prescription_only, s.controlled, s.quarantined_stock, s.reserved_stock, s.is_kit, s.category_id, s.generic_name, s.strength, s.dosage_form, s.route, s.manufacturer, s.pack_size, s.therapeutic_class, s.tax_class_id, s.reorder_point, s.reorder_quantity, s.preferred_supplier_id, s.abc_class, s.xyz_class, s.classified_at
+FROM products p
+JOIN products s ON s.id <> p.id
+    AND LOWER(s.generic_name) = LOWER(p.generic_name)
+    AND REPLACE(LOWER(s.strength), ' ', '') IS NOT DISTINCT FROM REPLACE(LOWER(p.strength), ' ', '')
+    AND LOWER(s.dosage_form) IS NOT DISTINCT FROM LOWER(p.dosage_form)
+WHERE p.id = $1
+    AND s.deleted = false
+    AND s.stock - s.reserved_stock > 0
+ORDER BY s.price ASC, s.stock - s.reserved_stock DESC
+LIMIT $2
+`
+
+type ListProductSubstitutesParams struct {
+	ProductID int64 `json:"product_id"`
+	Limit     int32 `json:"limit"`
+}
+
+func (q *Queries) ListProductSubstitutes(ctx context.Context, arg ListProductSubstitutesParams) ([]Product, error) {
+	rows, err := q.db.Query(ctx, listProductSubstitutes, arg.ProductID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Product{}
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.Stock,
+			&i.Category,
+			&i.Unit,
+			&i.LowStockThreshold,
+			&i.Deleted,
+			&i.CreatedAt,
+			&i.OpeningUnitCost,
+			&i.PrescriptionOnly,
+			&i.Controlled,
+			&i.QuarantinedStock,
+			&i.ReservedStock,
+			&i.IsKit,
+			&i.CategoryID,
+			&i.GenericName,
+			&i.Strength,
+			&i.DosageForm,
+			&i.Route,
+			&i.Manufacturer,
+			&i.PackSize,
+			&i.TherapeuticClass,
+			&i.TaxClassID,
+			&i.ReorderPoint,
+			&i.ReorderQuantity,
+			&i.PreferredSupplierID,
+			&i.AbcClass,
+			&i.XyzClass,
+			&i.ClassifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProducts = `-- name: ListProducts :many
-SELECT id, name, description, price, stock, category, unit, low_stock_threshold, deleted, created_at FROM products
+SELECT id, name, description, price, stock, category, unit, low_stock_threshold, deleted, created_at, opening_unit_cost, prescription_only, controlled, quarantined_stock, reserved_stock, is_kit, category_id, generic_name, strength, dosage_form, route, manufacturer, pack_size, therapeutic_class, tax_class_id, reorder_point, reorder_quantity, preferred_supplier_id, abc_class, xyz_class, classified_at FROM products
 WHERE 
     (
         COALESCE($1, '') = '' 
         OR LOWER(name) LIKE $1
         OR LOWER(description) LIKE $1
         OR LOWER(category) LIKE $1
+        OR LOWER(generic_name) LIKE $1
+        OR LOWER(manufacturer) LIKE $1
+        OR LOWER(therapeutic_class) LIKE $1
     )
     AND (
         $2::boolean IS NULL
@@ -135,22 +377,60 @@ WHERE
             END
         )
     )
+    AND (
+        $3::bigint IS NULL
+        OR category_id IN (
+            WITH RECURSIVE subtree AS (
+                SELECT id FROM categories WHERE id = $3
+                UNION ALL
+                SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+            )
+            SELECT id FROM subtree
+        )
+    )
+    AND ($4::text IS NULL OR LOWER(generic_name) = LOWER($4))
+    -- strengths match regardless of spacing, so "500 mg" finds "500mg"
+    AND ($5::text IS NULL OR REPLACE(LOWER(strength), ' ', '') = REPLACE(LOWER($5), ' ', ''))
+    AND ($6::text IS NULL OR LOWER(dosage_form) = LOWER($6))
+    AND ($7::text IS NULL OR LOWER(route) = LOWER($7))
+    AND ($8::text IS NULL OR LOWER(manufacturer) = LOWER($8))
+    AND ($9::text IS NULL OR LOWER(therapeutic_class) = LOWER($9))
+    AND ($10::text IS NULL OR abc_class = $10)
+    AND ($11::text IS NULL OR xyz_class = $11)
     AND deleted = false
 ORDER BY created_at DESC
-LIMIT $4 OFFSET $3
+LIMIT $13 OFFSET $12
 `
 
 type ListProductsParams struct {
-	Search  interface{} `json:"search"`
-	InStock pgtype.Bool `json:"in_stock"`
-	Offset  int32       `json:"offset"`
-	Limit   int32       `json:"limit"`
+	Search           interface{} `json:"search"`
+	InStock          pgtype.Bool `json:"in_stock"`
+	CategoryID       pgtype.Int8 `json:"category_id"`
+	GenericName      pgtype.Text `json:"generic_name"`
+	Strength         pgtype.Text `json:"strength"`
+	DosageForm       pgtype.Text `json:"dosage_form"`
+	Route            pgtype.Text `json:"route"`
+	Manufacturer     pgtype.Text `json:"manufacturer"`
+	TherapeuticClass pgtype.Text `json:"therapeutic_class"`
+	AbcClass         pgtype.Text `json:"abc_class"`
+	XyzClass         pgtype.Text `json:"xyz_class"`
+	Offset           int32       `json:"offset"`
+	Limit            int32       `json:"limit"`
 }
 
 func (q *Queries) ListProducts(ctx context.Context, arg ListProductsParams) ([]Product, error) {
 	rows, err := q.db.Query(ctx, listProducts,
 		arg.Search,
 		arg.InStock,
+		arg.CategoryID,
+		arg.GenericName,
+		arg.Strength,
+		arg.DosageForm,
+		arg.Route,
+		arg.Manufacturer,
+		arg.TherapeuticClass,
+		arg.AbcClass,
+		arg.XyzClass,
 		arg.Offset,
 		arg.Limit,
 	)
@@ -172,6 +452,27 @@ func (q *Queries) ListProducts(ctx context.Context, arg ListProductsParams) ([]P
 			&i.LowStockThreshold,
 			&i.Deleted,
 			&i.CreatedAt,
+			&i.OpeningUnitCost,
+			&i.PrescriptionOnly,
+			&i.Controlled,
+			&i.QuarantinedStock,
+			&i.ReservedStock,
+			&i.IsKit,
+			&i.CategoryID,
+			&i.GenericName,
+			&i.Strength,
+			&i.DosageForm,
+			&i.Route,
+			&i.Manufacturer,
+			&i.PackSize,
+			&i.TherapeuticClass,
+			&i.TaxClassID,
+			&i.ReorderPoint,
+			&i.ReorderQuantity,
+			&i.PreferredSupplierID,
+			&i.AbcClass,
+			&i.XyzClass,
+			&i.ClassifiedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -192,6 +493,9 @@ WHERE
         OR LOWER(name) LIKE $1
         OR LOWER(description) LIKE $1
         OR LOWER(category) LIKE $1
+        OR LOWER(generic_name) LIKE $1
+        OR LOWER(manufacturer) LIKE $1
+        OR LOWER(therapeutic_class) LIKE $1
     )
     AND (
         $2::boolean IS NULL
@@ -203,16 +507,57 @@ WHERE
             END
         )
     )
+    AND (
+        $3::bigint IS NULL
+        OR category_id IN (
+            WITH RECURSIVE subtree AS (
+                SELECT id FROM categories WHERE id = $3
+                UNION ALL
+                SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+            )
+            SELECT id FROM subtree
+        )
+    )
+    AND ($4::text IS NULL OR LOWER(generic_name) = LOWER($4))
+    -- strengths match regardless of spacing, so "500 mg" finds "500mg"
+    AND ($5::text IS NULL OR REPLACE(LOWER(strength), ' ', '') = REPLACE(LOWER($5), ' ', ''))
+    AND ($6::text IS NULL OR LOWER(dosage_form) = LOWER($6))
+    AND ($7::text IS NULL OR LOWER(route) = LOWER($7))
+    AND ($8::text IS NULL OR LOWER(manufacturer) = LOWER($8))
+    AND ($9::text IS NULL OR LOWER(therapeutic_class) = LOWER($9))
+    AND ($10::text IS NULL OR abc_class = $10)
+    AND ($11::text IS NULL OR xyz_class = $11)
     AND deleted = false
 `
 
 type ListProductsCountParams struct {
-	Search  interface{} `json:"search"`
-	InStock pgtype.Bool `json:"in_stock"`
+	Search           interface{} `json:"search"`
+	InStock          pgtype.Bool `json:"in_stock"`
+	CategoryID       pgtype.Int8 `json:"category_id"`
+	GenericName      pgtype.Text `json:"generic_name"`
+	Strength         pgtype.Text `json:"strength"`
+	DosageForm       pgtype.Text `json:"dosage_form"`
+	Route            pgtype.Text `json:"route"`
+	Manufacturer     pgtype.Text `json:"manufacturer"`
+	TherapeuticClass pgtype.Text `json:"therapeutic_class"`
+	AbcClass         pgtype.Text `json:"abc_class"`
+	XyzClass         pgtype.Text `json:"xyz_class"`
 }
 
 func (q *Queries) ListProductsCount(ctx context.Context, arg ListProductsCountParams) (int64, error) {
-	row := q.db.QueryRow(ctx, listProductsCount, arg.Search, arg.InStock)
+	row := q.db.QueryRow(ctx, listProductsCount,
+		arg.Search,
+		arg.InStock,
+		arg.CategoryID,
+		arg.GenericName,
+		arg.Strength,
+		arg.DosageForm,
+		arg.Route,
+		arg.Manufacturer,
+		arg.TherapeuticClass,
+		arg.AbcClass,
+		arg.XyzClass,
+	)
 	var total_products int64
 	err := row.Scan(&total_products)
 	return total_products, err
@@ -249,11 +594,62 @@ func (q *Queries) ProductHelpers(ctx context.Context) ([]ProductHelpersRow, erro
 	return items, nil
 }
 
+const quarantineStock = `-- name: QuarantineStock :one
+UPDATE products
+SET quarantined_stock = quarantined_stock + $1
+WHERE id = $2
+RETURNING id, name, description, price, stock, category, unit, low_stock_threshold, deleted, created_at, opening_unit_cost, prescription_only, controlled, quarantined_stock, reserved_stock, is_kit, category_id, generic_name, strength, dosage_form, route, manufacturer, pack_size, therapeutic_class, tax_class_id, reorder_point, reorder_quantity, preferred_supplier_id, abc_class, xyz_class, classified_at
+`
+
+type QuarantineStockParams struct {
+	Quantity int64 `json:"quantity"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) QuarantineStock(ctx context.Context, arg QuarantineStockParams) (Product, error) {
+	row := q.db.QueryRow(ctx, quarantineStock, arg.Quantity, arg.ID)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.Stock,
+		&i.Category,
+		&i.Unit,
+		&i.LowStockThreshold,
+		&i.Deleted,
+		&i.CreatedAt,
+		&i.OpeningUnitCost,
+		&i.PrescriptionOnly,
+		&i.Controlled,
+		&i.QuarantinedStock,
+		&i.ReservedStock,
+		&i.IsKit,
+		&i.CategoryID,
+		&i.GenericName,
+		&i.Strength,
+		&i.DosageForm,
+		&i.Route,
+		&i.Manufacturer,
+		&i.PackSize,
+		&i.TherapeuticClass,
+		&i.TaxClassID,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
+		&i.PreferredSupplierID,
+		&i.AbcClass,
+		&i.XyzClass,
+		&i.ClassifiedAt,
+	)
+	return i, err
+}
+
 const removeStock = `-- name: RemoveStock :one
 UPDATE products
 SET stock = stock - $1
 WHERE id = $2
-RETURNING id, name, description, price, stock, category, unit, low_stock_threshold, deleted, created_at
+RETURNING id, name, description, price, stock, category, unit, low_stock_threshold, deleted, created_at, opening_unit_cost, prescription_only, controlled, quarantined_stock, reserved_stock, is_kit, category_id, generic_name, strength, dosage_form, route, manufacturer, pack_size, therapeutic_class, tax_class_id, reorder_point, reorder_quantity, preferred_supplier_id, abc_class, xyz_class, classified_at
 `
 
 type RemoveStockParams struct {
@@ -275,6 +671,138 @@ func (q *Queries) RemoveStock(ctx context.Context, arg RemoveStockParams) (Produ
 		&i.LowStockThreshold,
 		&i.Deleted,
 		&i.CreatedAt,
+		&i.OpeningUnitCost,
+		&i.PrescriptionOnly,
+		&i.Controlled,
+		&i.QuarantinedStock,
+		&i.ReservedStock,
+		&i.IsKit,
+		&i.CategoryID,
+		&i.GenericName,
+		&i.Strength,
+		&i.DosageForm,
+		&i.Route,
+		&i.Manufacturer,
+		&i.PackSize,
+		&i.TherapeuticClass,
+		&i.TaxClassID,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
+		&i.PreferredSupplierID,
+		&i.AbcClass,
+		&i.XyzClass,
+		&i.ClassifiedAt,
+	)
+	return i, err
+}
+
+const reserveStock = `-- name: ReserveStock :one
+UPDATE products
+SET reserved_stock = reserved_stock + $1
+WHERE id = $2 AND deleted = false AND stock - reserved_stock >= $1
+RETURNING id, name, description, price, stock, category, unit, low_stock_threshold, deleted, created_at, opening_unit_cost, prescription_only, controlled, quarantined_stock, reserved_stock, is_kit, category_id, generic_name, strength, dosage_form, route, manufacturer, pack_size, therapeutic_class, tax_class_id, reorder_point, reorder_quantity, preferred_supplier_id, abc_class, xyz_class, classified_at
+`
+
+type ReserveStockParams struct {
+	Quantity int64 `json:"quantity"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) ReserveStock(ctx context.Context, arg ReserveStockParams) (Product, error) {
+	row := q.db.QueryRow(ctx, reserveStock, arg.Quantity, arg.ID)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.Stock,
+		&i.Category,
+		&i.Unit,
+		&i.LowStockThreshold,
+		&i.Deleted,
+		&i.CreatedAt,
+		&i.OpeningUnitCost,
+		&i.PrescriptionOnly,
+		&i.Controlled,
+		&i.QuarantinedStock,
+		&i.ReservedStock,
+		&i.IsKit,
+		&i.CategoryID,
+		&i.GenericName,
+		&i.Strength,
+		&i.DosageForm,
+		&i.Route,
+		&i.Manufacturer,
+		&i.PackSize,
+		&i.TherapeuticClass,
+		&i.TaxClassID,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
+		&i.PreferredSupplierID,
+		&i.AbcClass,
+		&i.XyzClass,
+		&i.ClassifiedAt,
+	)
+	return i, err
+}
+
+const setProductReorderPolicy = `-- name: SetProductReorderPolicy :one
+UPDATE products
+SET reorder_point = $1,
+    reorder_quantity = $2,
+    preferred_supplier_id = $3
+WHERE id = $4 AND deleted = false
+RETURNING id, name, description, price, stock, category, unit, low_stock_threshold, deleted, created_at, opening_unit_cost, prescription_only, controlled, quarantined_stock, reserved_stock, is_kit, category_id, generic_name, strength, dosage_form, route, manufacturer, pack_size, therapeutic_class, tax_class_id, reorder_point, reorder_quantity, preferred_supplier_id, abc_class, xyz_class, classified_at
+`
+
+type SetProductReorderPolicyParams struct {
+	ReorderPoint        pgtype.Int4 `json:"reorder_point"`
+	ReorderQuantity     pgtype.Int4 `json:"reorder_quantity"`
+	PreferredSupplierID pgtype.Int8 `json:"preferred_supplier_id"`
+	ID                  int64       `json:"id"`
+}
+
+func (q *Queries) SetProductReorderPolicy(ctx context.Context, arg SetProductReorderPolicyParams) (Product, error) {
+	row := q.db.QueryRow(ctx, setProductReorderPolicy,
+		arg.ReorderPoint,
+		arg.ReorderQuantity,
+		arg.PreferredSupplierID,
+		arg.ID,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.Stock,
+		&i.Category,
+		&i.Unit,
+		&i.LowStockThreshold,
+		&i.Deleted,
+		&i.CreatedAt,
+		&i.OpeningUnitCost,
+		&i.PrescriptionOnly,
+		&i.Controlled,
+		&i.QuarantinedStock,
+		&i.ReservedStock,
+		&i.IsKit,
+		&i.CategoryID,
+		&i.GenericName,
+		&i.Strength,
+		&i.DosageForm,
+		&i.Route,
+		&i.Manufacturer,
+		&i.PackSize,
+		&i.TherapeuticClass,
+		&i.TaxClassID,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
+		&i.PreferredSupplierID,
+		&i.AbcClass,
+		&i.XyzClass,
+		&i.ClassifiedAt,
 	)
 	return i, err
 }
@@ -285,10 +813,21 @@ SET name = coalesce($1, name),
     description = coalesce($2, description),
     price = coalesce($3, price),
     category = coalesce($4, category),
-    unit = coalesce($5, unit),
-    low_stock_threshold = coalesce($6, low_stock_threshold)
-WHERE id = $7
-RETURNING id, name, description, price, stock, category, unit, low_stock_threshold, deleted, created_at
+    category_id = coalesce($5, category_id),
+    unit = coalesce($6, unit),
+    low_stock_threshold = coalesce($7, low_stock_threshold),
+    prescription_only = coalesce($8, prescription_only),
+    controlled = coalesce($9, controlled),
+    generic_name = coalesce($10, generic_name),
+    strength = coalesce($11, strength),
+    dosage_form = coalesce($12, dosage_form),
+    route = coalesce($13, route),
+    manufacturer = coalesce($14, manufacturer),
+    pack_size = coalesce($15, pack_size),
+    therapeutic_class = coalesce($16, therapeutic_class),
+    tax_class_id = coalesce($17, tax_class_id)
+WHERE id = $18
+RETURNING id, name, description, price, stock, category, unit, low_stock_threshold, deleted, created_at, opening_unit_cost, prescription_only, controlled, quarantined_stock, reserved_stock, is_kit, category_id, generic_name, strength, dosage_form, route, manufacturer, pack_size, therapeutic_class, tax_class_id, reorder_point, reorder_quantity, preferred_supplier_id, abc_class, xyz_class, classified_at
 `
 
 type UpdateProductParams struct {
@@ -296,8 +835,19 @@ type UpdateProductParams struct {
 	Description       pgtype.Text    `json:"description"`
 	Price             pgtype.Numeric `json:"price"`
 	Category          pgtype.Text    `json:"category"`
+	CategoryID        pgtype.Int8    `json:"category_id"`
 	Unit              pgtype.Text    `json:"unit"`
 	LowStockThreshold pgtype.Int4    `json:"low_stock_threshold"`
+	PrescriptionOnly  pgtype.Bool    `json:"prescription_only"`
+	Controlled        pgtype.Bool    `json:"controlled"`
+	GenericName       pgtype.Text    `json:"generic_name"`
+	Strength          pgtype.Text    `json:"strength"`
+	DosageForm        pgtype.Text    `json:"dosage_form"`
+	Route             pgtype.Text    `json:"route"`
+	Manufacturer      pgtype.Text    `json:"manufacturer"`
+	PackSize          pgtype.Int4    `json:"pack_size"`
+	TherapeuticClass  pgtype.Text    `json:"therapeutic_class"`
+	TaxClassID        pgtype.Int8    `json:"tax_class_id"`
 	ID                int64          `json:"id"`
 }
 
@@ -307,8 +857,19 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		arg.Description,
 		arg.Price,
 		arg.Category,
+		arg.CategoryID,
 		arg.Unit,
 		arg.LowStockThreshold,
+		arg.PrescriptionOnly,
+		arg.Controlled,
+		arg.GenericName,
+		arg.Strength,
+		arg.DosageForm,
+		arg.Route,
+		arg.Manufacturer,
+		arg.PackSize,
+		arg.TherapeuticClass,
+		arg.TaxClassID,
 		arg.ID,
 	)
 	var i Product
@@ -323,6 +884,27 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		&i.LowStockThreshold,
 		&i.Deleted,
 		&i.CreatedAt,
+		&i.OpeningUnitCost,
+		&i.PrescriptionOnly,
+		&i.Controlled,
+		&i.QuarantinedStock,
+		&i.ReservedStock,
+		&i.IsKit,
+		&i.CategoryID,
+		&i.GenericName,
+		&i.Strength,
+		&i.DosageForm,
+		&i.Route,
+		&i.Manufacturer,
+		&i.PackSize,
+		&i.TherapeuticClass,
+		&i.TaxClassID,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
+		&i.PreferredSupplierID,
+		&i.AbcClass,
+		&i.XyzClass,
+		&i.ClassifiedAt,
 	)
 	return i, err
 }
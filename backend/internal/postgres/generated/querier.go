@@ -6,33 +6,236 @@ package generated
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
 	AddStock(ctx context.Context, arg AddStockParams) (Product, error)
+	ApplySupplierCredit(ctx context.Context, arg ApplySupplierCreditParams) (SupplierReturn, error)
+	CloseShift(ctx context.Context, arg CloseShiftParams) (Shift, error)
+	CompletePayment(ctx context.Context, arg CompletePaymentParams) (Payment, error)
+	CountCategoryProducts(ctx context.Context, categoryID int64) (int64, error)
+	CountKitsUsingComponent(ctx context.Context, componentID int64) (int64, error)
+	CreateBatchClaims(ctx context.Context, arg CreateBatchClaimsParams) error
+	CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error)
+	CreateClaimBatch(ctx context.Context, arg CreateClaimBatchParams) (ClaimBatch, error)
+	CreateCustomer(ctx context.Context, arg CreateCustomerParams) (Customer, error)
+	CreateInsuranceScheme(ctx context.Context, arg CreateInsuranceSchemeParams) (InsuranceScheme, error)
+	CreateInsurer(ctx context.Context, arg CreateInsurerParams) (Insurer, error)
+	CreateKitComponent(ctx context.Context, arg CreateKitComponentParams) (KitComponent, error)
 	CreateMovement(ctx context.Context, arg CreateMovementParams) (Movement, error)
+	CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error)
+	CreatePrescription(ctx context.Context, arg CreatePrescriptionParams) (Prescription, error)
+	CreatePrescriptionItem(ctx context.Context, arg CreatePrescriptionItemParams) (PrescriptionItem, error)
+	CreatePrescriptionScan(ctx context.Context, arg CreatePrescriptionScanParams) (CreatePrescriptionScanRow, error)
+	CreatePriceChange(ctx context.Context, arg CreatePriceChangeParams) (PriceHistory, error)
 	CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error)
+	CreatePromotion(ctx context.Context, arg CreatePromotionParams) (Promotion, error)
+	CreatePurchaseOrder(ctx context.Context, arg CreatePurchaseOrderParams) (PurchaseOrder, error)
+	CreatePurchaseOrderItem(ctx context.Context, arg CreatePurchaseOrderItemParams) (PurchaseOrderItem, error)
+	CreateQuotation(ctx context.Context, arg CreateQuotationParams) (Quotation, error)
+	CreateQuotationItem(ctx context.Context, arg CreateQuotationItemParams) (QuotationItem, error)
+	CreateRegisterEntry(ctx context.Context, arg CreateRegisterEntryParams) (ControlledDrugRegister, error)
+	CreateReservation(ctx context.Context, arg CreateReservationParams) (Reservation, error)
+	CreateReservationItem(ctx context.Context, arg CreateReservationItemParams) (ReservationItem, error)
+	CreateReturn(ctx context.Context, arg CreateReturnParams) (Return, error)
+	CreateSale(ctx context.Context, arg CreateSaleParams) (Sale, error)
+	CreateSaleItem(ctx context.Context, arg CreateSaleItemParams) (SaleItem, error)
+	CreateShift(ctx context.Context, arg CreateShiftParams) (Shift, error)
+	CreateShiftTender(ctx context.Context, arg CreateShiftTenderParams) (ShiftTender, error)
+	CreateSupplier(ctx context.Context, arg CreateSupplierParams) (Supplier, error)
+	CreateSupplierCredit(ctx context.Context, arg CreateSupplierCreditParams) (SupplierCredit, error)
+	CreateSupplierReturn(ctx context.Context, arg CreateSupplierReturnParams) (SupplierReturn, error)
+	CreateSupplierReturnItem(ctx context.Context, arg CreateSupplierReturnItemParams) (SupplierReturnItem, error)
+	CreateTaxClass(ctx context.Context, arg CreateTaxClassParams) (TaxClass, error)
+	CreateTaxInvoice(ctx context.Context, saleID int64) error
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeleteCategory(ctx context.Context, id int64) error
+	DeleteCustomer(ctx context.Context, id int64) error
+	DeleteInsurer(ctx context.Context, id int64) error
+	DeleteKitComponents(ctx context.Context, kitID int64) error
 	DeleteProduct(ctx context.Context, id int64) error
+	DeletePurchaseOrderItem(ctx context.Context, arg DeletePurchaseOrderItemParams) (PurchaseOrderItem, error)
+	DeleteSupplier(ctx context.Context, id int64) error
 	DeleteUser(ctx context.Context, id int64) error
+	DispensePrescriptionItem(ctx context.Context, arg DispensePrescriptionItemParams) (DispensePrescriptionItemRow, error)
+	FailPayment(ctx context.Context, arg FailPaymentParams) (Payment, error)
+	FailTaxInvoice(ctx context.Context, arg FailTaxInvoiceParams) (TaxInvoice, error)
+	GetAgeingReport(ctx context.Context, arg GetAgeingReportParams) ([]GetAgeingReportRow, error)
+	GetBatchUnitCost(ctx context.Context, arg GetBatchUnitCostParams) (pgtype.Numeric, error)
+	GetCategoryByID(ctx context.Context, id int64) (Category, error)
+	GetCategoryByName(ctx context.Context, name string) (Category, error)
+	GetClaimBatchByID(ctx context.Context, id int64) (GetClaimBatchByIDRow, error)
+	GetClaimBatchForUpdate(ctx context.Context, id int64) (ClaimBatch, error)
+	GetClaimForUpdate(ctx context.Context, id int64) (Claim, error)
+	GetCustomerByID(ctx context.Context, id int64) (Customer, error)
+	GetCustomerCreditForUpdate(ctx context.Context, id int64) (GetCustomerCreditForUpdateRow, error)
 	GetDashboardData(ctx context.Context) ([]byte, error)
+	GetDefaultTaxClass(ctx context.Context) (TaxClass, error)
+	GetDiscountReport(ctx context.Context, arg GetDiscountReportParams) ([]GetDiscountReportRow, error)
+	GetDraftPurchaseOrderForSupplier(ctx context.Context, supplierID int64) (PurchaseOrder, error)
+	GetInsuranceSchemeByID(ctx context.Context, id int64) (GetInsuranceSchemeByIDRow, error)
+	GetInsurerByID(ctx context.Context, id int64) (Insurer, error)
+	GetLastRegisterEntry(ctx context.Context, productID int64) (ControlledDrugRegister, error)
+	GetLastUnitCost(ctx context.Context, productID int64) (pgtype.Numeric, error)
+	GetLocationPaymentTotals(ctx context.Context, arg GetLocationPaymentTotalsParams) ([]GetLocationPaymentTotalsRow, error)
+	GetLocationSalesTotals(ctx context.Context, arg GetLocationSalesTotalsParams) (GetLocationSalesTotalsRow, error)
 	GetMovementByID(ctx context.Context, id int64) (Movement, error)
+	GetMovementForUpdate(ctx context.Context, id int64) (Movement, error)
+	GetOpenShiftByUser(ctx context.Context, userID int64) (Shift, error)
+	GetPaymentByCheckoutRequestID(ctx context.Context, checkoutRequestID pgtype.Text) (Payment, error)
+	GetPaymentByID(ctx context.Context, id int64) (Payment, error)
+	GetPrescriptionByID(ctx context.Context, id int64) (GetPrescriptionByIDRow, error)
+	GetPrescriptionScan(ctx context.Context, arg GetPrescriptionScanParams) (PrescriptionScan, error)
 	GetProductByID(ctx context.Context, id int64) (Product, error)
+	GetPromotionByID(ctx context.Context, id int64) (GetPromotionByIDRow, error)
+	GetPurchaseOrderByID(ctx context.Context, id int64) (GetPurchaseOrderByIDRow, error)
+	GetPurchaseOrderForUpdate(ctx context.Context, id int64) (PurchaseOrder, error)
+	GetQuotationByID(ctx context.Context, id int64) (GetQuotationByIDRow, error)
+	GetQuotationForUpdate(ctx context.Context, id int64) (Quotation, error)
+	GetReservationByID(ctx context.Context, id int64) (GetReservationByIDRow, error)
+	GetReservationForUpdate(ctx context.Context, id int64) (Reservation, error)
+	GetRetailValue(ctx context.Context) (pgtype.Numeric, error)
+	GetReturnByID(ctx context.Context, id int64) (GetReturnByIDRow, error)
+	GetReturnForUpdate(ctx context.Context, id int64) (Return, error)
+	GetReturnedQuantity(ctx context.Context, movementID int64) (int64, error)
+	GetSaleBalanceForUpdate(ctx context.Context, id int64) (GetSaleBalanceForUpdateRow, error)
+	GetSaleByID(ctx context.Context, id int64) (GetSaleByIDRow, error)
+	GetSaleItemMovementID(ctx context.Context, id int64) (int64, error)
+	GetSalesTotals(ctx context.Context, arg GetSalesTotalsParams) (GetSalesTotalsRow, error)
+	GetShiftByID(ctx context.Context, id int64) (GetShiftByIDRow, error)
+	GetShiftForUpdate(ctx context.Context, id int64) (Shift, error)
+	GetShiftPaymentTotals(ctx context.Context, shiftID pgtype.Int8) ([]GetShiftPaymentTotalsRow, error)
+	GetShiftSalesTotals(ctx context.Context, shiftID pgtype.Int8) (GetShiftSalesTotalsRow, error)
+	GetStatementOpeningBalance(ctx context.Context, arg GetStatementOpeningBalanceParams) (pgtype.Numeric, error)
 	GetStats(ctx context.Context) (Stat, error)
+	GetSupplierByID(ctx context.Context, id int64) (Supplier, error)
+	GetSupplierCreditSummary(ctx context.Context, supplierID int64) (GetSupplierCreditSummaryRow, error)
+	GetSupplierReturnByID(ctx context.Context, id int64) (GetSupplierReturnByIDRow, error)
+	GetTaxClassByID(ctx context.Context, id int64) (TaxClass, error)
+	GetTaxInvoiceBySale(ctx context.Context, saleID int64) (TaxInvoice, error)
+	GetTaxSummary(ctx context.Context, arg GetTaxSummaryParams) ([]GetTaxSummaryRow, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id int64) (User, error)
+	GetValuationVersion(ctx context.Context) (GetValuationVersionRow, error)
 	GetWeeklySales(ctx context.Context) ([]GetWeeklySalesRow, error)
+	IncrementReceiptPrintCount(ctx context.Context, id int64) (int32, error)
+	LeaseDueTaxInvoices(ctx context.Context, arg LeaseDueTaxInvoicesParams) ([]TaxInvoice, error)
+	LeaseTaxInvoice(ctx context.Context, arg LeaseTaxInvoiceParams) (TaxInvoice, error)
+	ListApplicablePromotions(ctx context.Context, arg ListApplicablePromotionsParams) ([]Promotion, error)
+	ListBatchClaims(ctx context.Context, batchID int64) ([]ListBatchClaimsRow, error)
+	ListCategories(ctx context.Context) ([]ListCategoriesRow, error)
+	ListCategorySubtreeIDs(ctx context.Context, id int64) ([]int64, error)
+	ListClaimBatches(ctx context.Context, arg ListClaimBatchesParams) ([]ListClaimBatchesRow, error)
+	ListClaimBatchesCount(ctx context.Context, arg ListClaimBatchesCountParams) (int64, error)
+	ListCustomers(ctx context.Context, arg ListCustomersParams) ([]Customer, error)
+	ListCustomersCount(ctx context.Context, search interface{}) (int64, error)
+	ListDailyDemand(ctx context.Context, arg ListDailyDemandParams) ([]ListDailyDemandRow, error)
+	ListDuePriceChanges(ctx context.Context) ([]PriceHistory, error)
+	ListExpiredReservationIDs(ctx context.Context) ([]int64, error)
+	ListForecastProducts(ctx context.Context, arg ListForecastProductsParams) ([]ListForecastProductsRow, error)
+	ListForecastProductsCount(ctx context.Context) (int64, error)
+	ListInsuranceSchemes(ctx context.Context, insurerID pgtype.Int8) ([]ListInsuranceSchemesRow, error)
+	ListInsurers(ctx context.Context) ([]Insurer, error)
+	ListKitBuildableQuantities(ctx context.Context, kitIds []int64) ([]ListKitBuildableQuantitiesRow, error)
+	ListKitComponents(ctx context.Context, kitID int64) ([]ListKitComponentsRow, error)
+	ListLocationShifts(ctx context.Context, arg ListLocationShiftsParams) ([]ListLocationShiftsRow, error)
 	ListMovements(ctx context.Context, arg ListMovementsParams) ([]ListMovementsRow, error)
 	ListMovementsCount(ctx context.Context, arg ListMovementsCountParams) (int64, error)
+	ListOpenInvoices(ctx context.Context, customerID pgtype.Int8) ([]ListOpenInvoicesRow, error)
+	ListPrescriptionItems(ctx context.Context, prescriptionID int64) ([]ListPrescriptionItemsRow, error)
+	ListPrescriptionScans(ctx context.Context, prescriptionID int64) ([]ListPrescriptionScansRow, error)
+	ListPrescriptions(ctx context.Context, arg ListPrescriptionsParams) ([]ListPrescriptionsRow, error)
+	ListPrescriptionsCount(ctx context.Context, arg ListPrescriptionsCountParams) (int64, error)
+	ListPriceHistory(ctx context.Context, arg ListPriceHistoryParams) ([]ListPriceHistoryRow, error)
+	ListPriceHistoryCount(ctx context.Context, productID int64) (int64, error)
+	ListProductConsumption(ctx context.Context, arg ListProductConsumptionParams) ([]ListProductConsumptionRow, error)
+	ListProductOpeningStock(ctx context.Context) ([]ListProductOpeningStockRow, error)
+	ListProductSubstitutes(ctx context.Context, arg ListProductSubstitutesParams) ([]Product, error)
 	ListProducts(ctx context.Context, arg ListProductsParams) ([]Product, error)
 	ListProductsCount(ctx context.Context, arg ListProductsCountParams) (int64, error)
+	ListPromotions(ctx context.Context, arg ListPromotionsParams) ([]ListPromotionsRow, error)
+	ListPromotionsCount(ctx context.Context, arg ListPromotionsCountParams) (int64, error)
+	ListPurchaseOrderItems(ctx context.Context, purchaseOrderID int64) ([]ListPurchaseOrderItemsRow, error)
+	ListPurchaseOrders(ctx context.Context, arg ListPurchaseOrdersParams) ([]ListPurchaseOrdersRow, error)
+	ListPurchaseOrdersCount(ctx context.Context, arg ListPurchaseOrdersCountParams) (int64, error)
+	ListQuotationItems(ctx context.Context, quotationID int64) ([]ListQuotationItemsRow, error)
+	ListQuotations(ctx context.Context, arg ListQuotationsParams) ([]ListQuotationsRow, error)
+	ListQuotationsCount(ctx context.Context, arg ListQuotationsCountParams) (int64, error)
+	ListRegisterEntries(ctx context.Context, arg ListRegisterEntriesParams) ([]ListRegisterEntriesRow, error)
+	ListReorderSuggestions(ctx context.Context) ([]ListReorderSuggestionsRow, error)
+	ListReservationItems(ctx context.Context, reservationID int64) ([]ListReservationItemsRow, error)
+	ListReservations(ctx context.Context, arg ListReservationsParams) ([]ListReservationsRow, error)
+	ListReservationsCount(ctx context.Context, arg ListReservationsCountParams) (int64, error)
+	ListReturns(ctx context.Context, arg ListReturnsParams) ([]ListReturnsRow, error)
+	ListReturnsCount(ctx context.Context, arg ListReturnsCountParams) (int64, error)
+	ListSaleInsurancePaymentsForUpdate(ctx context.Context, saleID int64) ([]Payment, error)
+	ListSaleItems(ctx context.Context, saleID int64) ([]ListSaleItemsRow, error)
+	ListSalePayments(ctx context.Context, saleID int64) ([]Payment, error)
+	ListSales(ctx context.Context, arg ListSalesParams) ([]ListSalesRow, error)
+	ListSalesCount(ctx context.Context, arg ListSalesCountParams) (int64, error)
+	ListShiftTenders(ctx context.Context, shiftID int64) ([]ShiftTender, error)
+	ListShifts(ctx context.Context, arg ListShiftsParams) ([]ListShiftsRow, error)
+	ListShiftsCount(ctx context.Context, arg ListShiftsCountParams) (int64, error)
+	ListStalePendingPaymentIDs(ctx context.Context, createdBefore time.Time) ([]int64, error)
+	ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error)
+	ListSupplierCredits(ctx context.Context, supplierReturnID int64) ([]ListSupplierCreditsRow, error)
+	ListSupplierReturnItems(ctx context.Context, supplierReturnID int64) ([]ListSupplierReturnItemsRow, error)
+	ListSupplierReturns(ctx context.Context, arg ListSupplierReturnsParams) ([]ListSupplierReturnsRow, error)
+	ListSupplierReturnsCount(ctx context.Context, arg ListSupplierReturnsCountParams) (int64, error)
+	ListSuppliers(ctx context.Context, arg ListSuppliersParams) ([]Supplier, error)
+	ListSuppliersCount(ctx context.Context, search interface{}) (int64, error)
+	ListTaxClasses(ctx context.Context) ([]TaxClass, error)
+	ListTaxInvoices(ctx context.Context, arg ListTaxInvoicesParams) ([]ListTaxInvoicesRow, error)
+	ListTaxInvoicesCount(ctx context.Context, status pgtype.Text) (int64, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
 	ListUsersCount(ctx context.Context, arg ListUsersCountParams) (int64, error)
+	ListValuationMovements(ctx context.Context, endDate time.Time) ([]ListValuationMovementsRow, error)
+	ListWeeklyDemand(ctx context.Context, arg ListWeeklyDemandParams) ([]ListWeeklyDemandRow, error)
+	LockReorderRun(ctx context.Context) error
+	MarkBatchClaimsSubmitted(ctx context.Context, batchID int64) error
+	MarkPriceChangeApplied(ctx context.Context, arg MarkPriceChangeAppliedParams) error
+	MoveCategoryChildren(ctx context.Context, arg MoveCategoryChildrenParams) error
+	MoveCategoryProducts(ctx context.Context, arg MoveCategoryProductsParams) error
+	NextReceiptNumber(ctx context.Context, location string) (int64, error)
 	ProductHelpers(ctx context.Context) ([]ProductHelpersRow, error)
+	QuarantineStock(ctx context.Context, arg QuarantineStockParams) (Product, error)
 	RecalculateStatsStock(ctx context.Context) error
+	ReducePayment(ctx context.Context, arg ReducePaymentParams) error
+	ReleaseReservedStock(ctx context.Context, reservationID int64) error
 	RemoveStock(ctx context.Context, arg RemoveStockParams) (Product, error)
+	RequeueTaxInvoice(ctx context.Context, saleID int64) (TaxInvoice, error)
+	ReserveStock(ctx context.Context, arg ReserveStockParams) (Product, error)
+	ResolveClaim(ctx context.Context, arg ResolveClaimParams) error
+	ResolveQuotation(ctx context.Context, arg ResolveQuotationParams) error
+	ResolveReservation(ctx context.Context, arg ResolveReservationParams) (Reservation, error)
+	ResolveReturn(ctx context.Context, arg ResolveReturnParams) (Return, error)
+	ReversePayment(ctx context.Context, arg ReversePaymentParams) error
+	SetPaymentCheckoutRequestID(ctx context.Context, arg SetPaymentCheckoutRequestIDParams) error
+	SetProductClasses(ctx context.Context, arg SetProductClassesParams) error
+	SetProductKit(ctx context.Context, arg SetProductKitParams) (Product, error)
+	SetProductReorderPolicy(ctx context.Context, arg SetProductReorderPolicyParams) (Product, error)
+	SetPurchaseOrderStatus(ctx context.Context, arg SetPurchaseOrderStatusParams) error
+	SignTaxInvoice(ctx context.Context, arg SignTaxInvoiceParams) (TaxInvoice, error)
+	SubmitClaimBatch(ctx context.Context, arg SubmitClaimBatchParams) error
+	SyncProductCategoryName(ctx context.Context, categoryID int64) error
+	UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error)
+	UpdateClaimBatchTotals(ctx context.Context, batchID int64) error
+	UpdateCustomer(ctx context.Context, arg UpdateCustomerParams) (Customer, error)
+	UpdateInsuranceScheme(ctx context.Context, arg UpdateInsuranceSchemeParams) error
+	UpdateInsurer(ctx context.Context, arg UpdateInsurerParams) (Insurer, error)
 	UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error)
+	UpdatePromotion(ctx context.Context, arg UpdatePromotionParams) (Promotion, error)
+	UpdatePurchaseOrderItemQuantity(ctx context.Context, arg UpdatePurchaseOrderItemQuantityParams) (PurchaseOrderItem, error)
+	UpdatePurchaseOrderTotals(ctx context.Context, purchaseOrderID int64) error
+	UpdateQuotationTotals(ctx context.Context, arg UpdateQuotationTotalsParams) error
+	UpdateSaleTotals(ctx context.Context, arg UpdateSaleTotalsParams) (Sale, error)
 	UpdateStats(ctx context.Context, arg UpdateStatsParams) (Stat, error)
+	UpdateSupplier(ctx context.Context, arg UpdateSupplierParams) (Supplier, error)
+	UpdateSupplierReturnTotals(ctx context.Context, arg UpdateSupplierReturnTotalsParams) (SupplierReturn, error)
+	UpdateTaxClass(ctx context.Context, arg UpdateTaxClassParams) (TaxClass, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 }
 
@@ -11,6 +11,19 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const getRetailValue = `-- name: GetRetailValue :one
+SELECT COALESCE(SUM(price * stock), 0)::numeric AS retail_value
+FROM products
+WHERE deleted = false
+`
+
+func (q *Queries) GetRetailValue(ctx context.Context) (pgtype.Numeric, error) {
+	row := q.db.QueryRow(ctx, getRetailValue)
+	var retail_value pgtype.Numeric
+	err := row.Scan(&retail_value)
+	return retail_value, err
+}
+
 const getStats = `-- name: GetStats :one
 SELECT id, total_users, total_products, total_low_stock, total_out_of_stock, total_stocks_added, total_stocks_added_value, total_stocks_removed, total_stocks_removed_value, total_value FROM stats
 WHERE id = 1
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func pgTextToString(t pgtype.Text) *string {
+	if !t.Valid {
+		return nil
+	}
+
+	return &t.String
+}
+
+func stringToPgText(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{Valid: false}
+	}
+
+	return pgtype.Text{String: *s, Valid: true}
+}
+
+func pgInt4ToInt32(i pgtype.Int4) *int32 {
+	if !i.Valid {
+		return nil
+	}
+
+	return &i.Int32
+}
+
+func int32ToPgInt4(i *int32) pgtype.Int4 {
+	if i == nil {
+		return pgtype.Int4{Valid: false}
+	}
+
+	return pgtype.Int4{Int32: *i, Valid: true}
+}
+
+func pgInt8ToUint32(i pgtype.Int8) *uint32 {
+	if !i.Valid {
+		return nil
+	}
+
+	v := uint32(i.Int64)
+	return &v
+}
+
+func pgTimestamptzToTime(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	return &t.Time
+}
+
+func pgDateToTime(d pgtype.Date) *time.Time {
+	if !d.Valid {
+		return nil
+	}
+
+	return &d.Time
+}
@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.InsuranceRepository = (*InsuranceRepository)(nil)
+
+type InsuranceRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewInsuranceRepository(db *Store) *InsuranceRepository {
+	return &InsuranceRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (ir *InsuranceRepository) CreateInsurer(ctx context.Context, insurer *repository.Insurer) (*repository.Insurer, error) {
+	pgInsurer, err := ir.queries.CreateInsurer(ctx, generated.CreateInsurerParams{
+		Name:        insurer.Name,
+		Code:        insurer.Code,
+		Client:      insurer.Client,
+		Email:       stringToPgText(insurer.Email),
+		PhoneNumber: stringToPgText(insurer.PhoneNumber),
+	})
+	if err != nil {
+		if pkg.PgxErrorCode(err) == pkg.UNIQUE_VIOLATION {
+			return nil, pkg.Errorf(pkg.ALREADY_EXISTS_ERROR, "an insurer named %s or with code %s already exists", insurer.Name, insurer.Code)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create insurer: %s", err.Error())
+	}
+
+	return pgInsurerToRepoInsurer(pgInsurer), nil
+}
+
+func (ir *InsuranceRepository) GetInsurer(ctx context.Context, id int64) (*repository.Insurer, error) {
+	pgInsurer, err := ir.queries.GetInsurerByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "insurer with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get insurer by id: %s", err.Error())
+	}
+
+	return pgInsurerToRepoInsurer(pgInsurer), nil
+}
+
+func (ir *InsuranceRepository) UpdateInsurer(ctx context.Context, id int64, insurerUpdate *repository.InsurerUpdate) (*repository.Insurer, error) {
+	pgInsurer, err := ir.queries.UpdateInsurer(ctx, generated.UpdateInsurerParams{
+		ID:          id,
+		Name:        stringToPgText(insurerUpdate.Name),
+		Client:      stringToPgText(insurerUpdate.Client),
+		Email:       stringToPgText(insurerUpdate.Email),
+		PhoneNumber: stringToPgText(insurerUpdate.PhoneNumber),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "insurer with id %d not found", id)
+		}
+		if pkg.PgxErrorCode(err) == pkg.UNIQUE_VIOLATION {
+			return nil, pkg.Errorf(pkg.ALREADY_EXISTS_ERROR, "an insurer named %s already exists", *insurerUpdate.Name)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update insurer: %s", err.Error())
+	}
+
+	return pgInsurerToRepoInsurer(pgInsurer), nil
+}
+
+func (ir *InsuranceRepository) DeleteInsurer(ctx context.Context, id int64) error {
+	if err := ir.queries.DeleteInsurer(ctx, id); err != nil {
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to delete insurer: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (ir *InsuranceRepository) ListInsurers(ctx context.Context) ([]*repository.Insurer, error) {
+	pgInsurers, err := ir.queries.ListInsurers(ctx)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list insurers: %s", err.Error())
+	}
+
+	insurers := make([]*repository.Insurer, len(pgInsurers))
+	for i, insurer := range pgInsurers {
+		insurers[i] = pgInsurerToRepoInsurer(insurer)
+	}
+
+	return insurers, nil
+}
+
+func (ir *InsuranceRepository) CreateScheme(ctx context.Context, scheme *repository.InsuranceScheme) (*repository.InsuranceScheme, error) {
+	if _, err := ir.GetInsurer(ctx, int64(scheme.InsurerID)); err != nil {
+		return nil, err
+	}
+
+	pgScheme, err := ir.queries.CreateInsuranceScheme(ctx, generated.CreateInsuranceSchemeParams{
+		InsurerID:     int64(scheme.InsurerID),
+		Name:          scheme.Name,
+		CoPaymentRate: pkg.Float64ToPgTypeNumeric(scheme.CoPaymentRate),
+	})
+	if err != nil {
+		if pkg.PgxErrorCode(err) == pkg.UNIQUE_VIOLATION {
+			return nil, pkg.Errorf(pkg.ALREADY_EXISTS_ERROR, "the insurer already has a scheme named %s", scheme.Name)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create insurance scheme: %s", err.Error())
+	}
+
+	return ir.GetScheme(ctx, pgScheme.ID)
+}
+
+func (ir *InsuranceRepository) GetScheme(ctx context.Context, id int64) (*repository.InsuranceScheme, error) {
+	pgScheme, err := ir.queries.GetInsuranceSchemeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "insurance scheme with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get insurance scheme by id: %s", err.Error())
+	}
+
+	return pgSchemeToRepoScheme(generated.ListInsuranceSchemesRow(pgScheme)), nil
+}
+
+func (ir *InsuranceRepository) UpdateScheme(ctx context.Context, id int64, schemeUpdate *repository.InsuranceSchemeUpdate) (*repository.InsuranceScheme, error) {
+	params := generated.UpdateInsuranceSchemeParams{
+		ID:            id,
+		Name:          stringToPgText(schemeUpdate.Name),
+		CoPaymentRate: pgtype.Numeric{Valid: false},
+		Active:        pgtype.Bool{Valid: false},
+	}
+	if schemeUpdate.CoPaymentRate != nil {
+		params.CoPaymentRate = pkg.Float64ToPgTypeNumeric(*schemeUpdate.CoPaymentRate)
+	}
+	if schemeUpdate.Active != nil {
+		params.Active = pgtype.Bool{Bool: *schemeUpdate.Active, Valid: true}
+	}
+
+	if err := ir.queries.UpdateInsuranceScheme(ctx, params); err != nil {
+		if pkg.PgxErrorCode(err) == pkg.UNIQUE_VIOLATION {
+			return nil, pkg.Errorf(pkg.ALREADY_EXISTS_ERROR, "the insurer already has a scheme named %s", *schemeUpdate.Name)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update insurance scheme: %s", err.Error())
+	}
+
+	return ir.GetScheme(ctx, id)
+}
+
+func (ir *InsuranceRepository) ListSchemes(ctx context.Context, insurerID *uint32) ([]*repository.InsuranceScheme, error) {
+	insurerParam := pgtype.Int8{Valid: false}
+	if insurerID != nil {
+		insurerParam = pgtype.Int8{Int64: int64(*insurerID), Valid: true}
+	}
+
+	pgSchemes, err := ir.queries.ListInsuranceSchemes(ctx, insurerParam)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list insurance schemes: %s", err.Error())
+	}
+
+	schemes := make([]*repository.InsuranceScheme, len(pgSchemes))
+	for i, scheme := range pgSchemes {
+		schemes[i] = pgSchemeToRepoScheme(scheme)
+	}
+
+	return schemes, nil
+}
+
+func pgInsurerToRepoInsurer(insurer generated.Insurer) *repository.Insurer {
+	return &repository.Insurer{
+		ID:          uint32(insurer.ID),
+		Name:        insurer.Name,
+		Code:        insurer.Code,
+		Client:      insurer.Client,
+		Email:       pgTextToString(insurer.Email),
+		PhoneNumber: pgTextToString(insurer.PhoneNumber),
+		Deleted:     insurer.Deleted,
+		CreatedAt:   insurer.CreatedAt,
+	}
+}
+
+func pgSchemeToRepoScheme(scheme generated.ListInsuranceSchemesRow) *repository.InsuranceScheme {
+	return &repository.InsuranceScheme{
+		ID:            uint32(scheme.ID),
+		InsurerID:     uint32(scheme.InsurerID),
+		Name:          scheme.Name,
+		CoPaymentRate: pkg.PgTypeNumericToFloat64(scheme.CoPaymentRate),
+		Active:        scheme.Active,
+		CreatedAt:     scheme.CreatedAt,
+
+		InsurerName: scheme.InsurerName,
+	}
+}
@@ -0,0 +1,227 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+// SetKitComponents replaces the kit's bill of materials. An empty list turns the
+// product back into an ordinary product. Kits cannot be components of other kits,
+// and prescription-only products cannot be components at all.
+func (pr *ProductRepository) SetKitComponents(ctx context.Context, kitID int64, components []*repository.KitComponent) (*repository.Product, error) {
+	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		seen := make(map[uint32]bool, len(components))
+		for _, c := range components {
+			if int64(c.ComponentID) == kitID {
+				return pkg.Errorf(pkg.INVALID_ERROR, "a kit cannot contain itself")
+			}
+			if seen[c.ComponentID] {
+				return pkg.Errorf(pkg.INVALID_ERROR, "product %d is listed more than once", c.ComponentID)
+			}
+			seen[c.ComponentID] = true
+
+			component, err := q.GetProductByID(ctx, int64(c.ComponentID))
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return pkg.Errorf(pkg.NOT_FOUND_ERROR, "product %d not found", c.ComponentID)
+				}
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get product: %s", err.Error())
+			}
+			if component.IsKit {
+				return pkg.Errorf(pkg.INVALID_ERROR, "%s is a kit and cannot be a component", component.Name)
+			}
+			// kits are assembled and sold without a prescription for their components
+			if component.PrescriptionOnly {
+				return pkg.Errorf(pkg.INVALID_ERROR, "%s is prescription-only and cannot be a kit component", component.Name)
+			}
+		}
+
+		if len(components) > 0 {
+			used, err := q.CountKitsUsingComponent(ctx, kitID)
+			if err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to check kit usage: %s", err.Error())
+			}
+			if used > 0 {
+				return pkg.Errorf(pkg.INVALID_ERROR, "product %d is a component of another kit and cannot be a kit", kitID)
+			}
+		}
+
+		if _, err := q.SetProductKit(ctx, generated.SetProductKitParams{
+			ID:    kitID,
+			IsKit: len(components) > 0,
+		}); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "product not found")
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update product: %s", err.Error())
+		}
+
+		if err := q.DeleteKitComponents(ctx, kitID); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to delete kit components: %s", err.Error())
+		}
+
+		for _, c := range components {
+			if _, err := q.CreateKitComponent(ctx, generated.CreateKitComponentParams{
+				KitID:       kitID,
+				ComponentID: int64(c.ComponentID),
+				Quantity:    c.Quantity,
+			}); err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create kit component: %s", err.Error())
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pr.GetByID(ctx, kitID)
+}
+
+// AssembleKit builds kits from their components ahead of time and puts them into stock.
+func (pr *ProductRepository) AssembleKit(ctx context.Context, data *repository.ProductStockUpdate) (*repository.Product, error) {
+	var product *repository.Product
+	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		p, _, err := assembleKitTx(ctx, q, data)
+		if err != nil {
+			return err
+		}
+		product = pgProductToRepoProduct(p)
+
+		return nil
+	})
+	return product, err
+}
+
+// assembleKitTx removes the components of data.Quantity kits from stock and adds the
+// kits at the combined last purchase cost of their components.
+func assembleKitTx(ctx context.Context, q *generated.Queries, data *repository.ProductStockUpdate) (generated.Product, generated.Movement, error) {
+	components, err := q.ListKitComponents(ctx, int64(data.ID))
+	if err != nil {
+		return generated.Product{}, generated.Movement{}, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list kit components: %s", err.Error())
+	}
+	if len(components) == 0 {
+		return generated.Product{}, generated.Movement{}, pkg.Errorf(pkg.INVALID_ERROR, "product %d is not a kit", data.ID)
+	}
+
+	reason := repository.MOVEMENT_REASON_KIT_ASSEMBLY
+	note := fmt.Sprintf("assembled into kit #%d", data.ID)
+
+	var unitCost float64
+	for _, c := range components {
+		p, _, err := removeStockTx(ctx, q, &repository.ProductStockUpdate{
+			ID:          uint32(c.ComponentID),
+			PerformedBy: data.PerformedBy,
+			Quantity:    data.Quantity * int64(c.Quantity),
+			Note:        &note,
+			WitnessedBy: data.WitnessedBy,
+			Reason:      &reason,
+		})
+		if err != nil {
+			return p, generated.Movement{}, err
+		}
+
+		cost, ok, err := lastUnitCostTx(ctx, q, p)
+		if err != nil {
+			return p, generated.Movement{}, err
+		}
+		if !ok {
+			return p, generated.Movement{}, pkg.Errorf(pkg.INVALID_ERROR, "%s has no purchase cost to assemble the kit at", p.Name)
+		}
+		unitCost += pkg.PgTypeNumericToFloat64(cost) * float64(c.Quantity)
+	}
+
+	return addStockTx(ctx, q, &repository.ProductStockUpdate{
+		ID:          data.ID,
+		PerformedBy: data.PerformedBy,
+		Quantity:    data.Quantity,
+		UnitCost:    &unitCost,
+		Note:        data.Note,
+		WitnessedBy: data.WitnessedBy,
+		Reason:      &reason,
+	})
+}
+
+// ensureKitStockTx assembles whatever a kit is short of before it is sold.
+func ensureKitStockTx(ctx context.Context, q *generated.Queries, data *repository.ProductStockUpdate) error {
+	p, err := q.GetProductByID(ctx, int64(data.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// removing the stock reports the missing product
+			return nil
+		}
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get product: %s", err.Error())
+	}
+
+	shortfall := data.Quantity - (p.Stock - p.ReservedStock)
+	if !p.IsKit || shortfall <= 0 {
+		return nil
+	}
+
+	_, _, err = assembleKitTx(ctx, q, &repository.ProductStockUpdate{
+		ID:          data.ID,
+		PerformedBy: data.PerformedBy,
+		Quantity:    shortfall,
+		Note:        data.Note,
+		WitnessedBy: data.WitnessedBy,
+	})
+
+	return err
+}
+
+// addKitAvailability adds the kits their components can still make to the available
+// stock of kits, and loads the components when requested.
+func (pr *ProductRepository) addKitAvailability(ctx context.Context, products []*repository.Product, withComponents bool) error {
+	kits := make(map[int64]*repository.Product)
+	kitIDs := make([]int64, 0)
+	for _, p := range products {
+		if p.IsKit {
+			kits[int64(p.ID)] = p
+			kitIDs = append(kitIDs, int64(p.ID))
+		}
+	}
+	if len(kitIDs) == 0 {
+		return nil
+	}
+
+	buildable, err := pr.queries.ListKitBuildableQuantities(ctx, kitIDs)
+	if err != nil {
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get kit availability: %s", err.Error())
+	}
+	for _, b := range buildable {
+		kits[b.KitID].AvailableStock += b.Buildable
+	}
+
+	if !withComponents {
+		return nil
+	}
+
+	for _, kitID := range kitIDs {
+		components, err := pr.queries.ListKitComponents(ctx, kitID)
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list kit components: %s", err.Error())
+		}
+
+		kits[kitID].Components = make([]*repository.KitComponent, len(components))
+		for i, c := range components {
+			kits[kitID].Components[i] = &repository.KitComponent{
+				KitID:       uint32(c.KitID),
+				ComponentID: uint32(c.ComponentID),
+				Quantity:    c.Quantity,
+
+				ProductName:    c.ProductName,
+				Unit:           c.Unit,
+				AvailableStock: c.AvailableStock,
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+CREATE TABLE "price_history" (
+    "id" bigserial PRIMARY KEY,
+    "product_id" bigint NOT NULL,
+    "old_price" numeric(10,2) NOT NULL,
+    "new_price" numeric(10,2) NOT NULL,
+    "effective_at" timestamptz NOT NULL DEFAULT (now()),
+    "applied" boolean NOT NULL DEFAULT false,
+    "applied_at" timestamptz,
+    "note" text,
+    "changed_by" bigint NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "price_history_product_id_fkey" FOREIGN KEY ("product_id") REFERENCES "products" ("id"),
+    CONSTRAINT "price_history_changed_by_fkey" FOREIGN KEY ("changed_by") REFERENCES "users" ("id")
+);
+
+CREATE INDEX idx_price_history_product_id ON "price_history" (product_id);
+CREATE INDEX idx_price_history_pending ON "price_history" (effective_at) WHERE applied = false;
+
+-- price changes are recorded in price_history and no longer revalue stock. stock is
+-- valued from movement costs and the retail value is read from products when asked
+-- for, so the trigger only keeps the low and out of stock counts.
+CREATE OR REPLACE FUNCTION recalc_stats()
+RETURNS TRIGGER AS $$
+BEGIN
+  UPDATE stats
+  SET
+      total_low_stock = subquery.low_stock,
+      total_out_of_stock = subquery.out_of_stock
+  FROM (
+      SELECT
+          COUNT(*) FILTER (WHERE stock > 0 AND stock <= low_stock_threshold AND deleted = false) AS low_stock,
+          COUNT(*) FILTER (WHERE stock <= 0 AND deleted = false) AS out_of_stock
+      FROM products
+      WHERE deleted = false
+  ) AS subquery
+  WHERE stats.id = 1;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_recalc_stats ON products;
+CREATE TRIGGER trg_recalc_stats
+AFTER UPDATE OF deleted, low_stock_threshold ON products
+FOR EACH STATEMENT
+EXECUTE FUNCTION recalc_stats();
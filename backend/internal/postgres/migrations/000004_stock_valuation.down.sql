@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_movements_created_at;
+
+ALTER TABLE "products" DROP COLUMN IF EXISTS "opening_unit_cost";
+ALTER TABLE "movements" DROP COLUMN IF EXISTS "unit_cost";
@@ -0,0 +1,11 @@
+ALTER TABLE "movements" ADD COLUMN "unit_cost" numeric(10,2) NOT NULL DEFAULT 0;
+
+-- existing receipts have no recorded cost, so they are valued at the price they were moved at
+UPDATE "movements" SET "unit_cost" = "price";
+
+-- stock a product is created with is valued at the cost given for it; existing
+-- products have none recorded so they take their price
+ALTER TABLE "products" ADD COLUMN "opening_unit_cost" numeric(10,2) NOT NULL DEFAULT 0;
+UPDATE "products" SET "opening_unit_cost" = "price";
+
+CREATE INDEX idx_movements_created_at ON "movements" (created_at);
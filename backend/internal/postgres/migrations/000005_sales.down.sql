@@ -0,0 +1,10 @@
+ALTER TABLE "movements" DROP CONSTRAINT "movements_sale_id_fkey";
+ALTER TABLE "movements" DROP COLUMN IF EXISTS "sale_id";
+
+ALTER TABLE "sale_items" DROP CONSTRAINT "sale_items_sale_id_fkey";
+ALTER TABLE "sale_items" DROP CONSTRAINT "sale_items_product_id_fkey";
+ALTER TABLE "sale_items" DROP CONSTRAINT "sale_items_movement_id_fkey";
+ALTER TABLE "sales" DROP CONSTRAINT "sales_performed_by_fkey";
+
+DROP TABLE IF EXISTS "sale_items";
+DROP TABLE IF EXISTS "sales";
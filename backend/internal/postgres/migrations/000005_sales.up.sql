@@ -0,0 +1,32 @@
+CREATE TABLE "sales" (
+    "id" bigserial PRIMARY KEY,
+    "total_quantity" bigint NOT NULL DEFAULT 0,
+    "total_amount" numeric(12,2) NOT NULL DEFAULT 0,
+    "note" text,
+    "performed_by" bigint NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "sales_performed_by_fkey" FOREIGN KEY ("performed_by") REFERENCES "users" ("id")
+);
+
+CREATE TABLE "sale_items" (
+    "id" bigserial PRIMARY KEY,
+    "sale_id" bigint NOT NULL,
+    "product_id" bigint NOT NULL,
+    "movement_id" bigint NOT NULL,
+    "quantity" integer NOT NULL CHECK (quantity > 0),
+    "unit_price" numeric(10,2) NOT NULL,
+    "line_total" numeric(12,2) NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "sale_items_sale_id_fkey" FOREIGN KEY ("sale_id") REFERENCES "sales" ("id"),
+    CONSTRAINT "sale_items_product_id_fkey" FOREIGN KEY ("product_id") REFERENCES "products" ("id"),
+    CONSTRAINT "sale_items_movement_id_fkey" FOREIGN KEY ("movement_id") REFERENCES "movements" ("id")
+);
+
+ALTER TABLE "movements" ADD COLUMN "sale_id" bigint;
+ALTER TABLE "movements" ADD CONSTRAINT "movements_sale_id_fkey" FOREIGN KEY ("sale_id") REFERENCES "sales" ("id");
+
+CREATE INDEX idx_sales_created_at ON "sales" (created_at);
+CREATE INDEX idx_sale_items_sale_id ON "sale_items" (sale_id);
+CREATE INDEX idx_movements_sale_id ON "movements" (sale_id);
@@ -0,0 +1,7 @@
+ALTER TABLE "sales" DROP CONSTRAINT "sales_receipt_number_key";
+
+DROP TABLE IF EXISTS "receipt_sequences";
+
+ALTER TABLE "sales" DROP COLUMN IF EXISTS "receipt_print_count";
+ALTER TABLE "sales" DROP COLUMN IF EXISTS "receipt_number";
+ALTER TABLE "sales" DROP COLUMN IF EXISTS "location";
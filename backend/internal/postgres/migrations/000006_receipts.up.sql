@@ -0,0 +1,24 @@
+ALTER TABLE "sales" ADD COLUMN "location" varchar(50) NOT NULL DEFAULT 'MAIN';
+ALTER TABLE "sales" ADD COLUMN "receipt_number" varchar(50);
+ALTER TABLE "sales" ADD COLUMN "receipt_print_count" integer NOT NULL DEFAULT 0;
+
+CREATE TABLE "receipt_sequences" (
+    "location" varchar(50) PRIMARY KEY,
+    "last_number" bigint NOT NULL DEFAULT 0
+);
+
+-- number existing sales in the order they were made
+WITH numbered AS (
+    SELECT id, location, row_number() OVER (PARTITION BY location ORDER BY id) AS n
+    FROM sales
+)
+UPDATE sales
+SET receipt_number = numbered.location || '-' || lpad(numbered.n::text, 6, '0')
+FROM numbered
+WHERE sales.id = numbered.id;
+
+INSERT INTO receipt_sequences (location, last_number)
+SELECT location, COUNT(*) FROM sales GROUP BY location;
+
+ALTER TABLE "sales" ALTER COLUMN "receipt_number" SET NOT NULL;
+ALTER TABLE "sales" ADD CONSTRAINT "sales_receipt_number_key" UNIQUE ("receipt_number");
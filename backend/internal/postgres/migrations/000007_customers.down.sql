@@ -0,0 +1,7 @@
+ALTER TABLE "movements" DROP CONSTRAINT "movements_customer_id_fkey";
+ALTER TABLE "movements" DROP COLUMN IF EXISTS "customer_id";
+
+ALTER TABLE "sales" DROP CONSTRAINT "sales_customer_id_fkey";
+ALTER TABLE "sales" DROP COLUMN IF EXISTS "customer_id";
+
+DROP TABLE IF EXISTS "customers";
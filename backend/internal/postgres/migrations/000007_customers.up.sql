@@ -0,0 +1,21 @@
+CREATE TABLE "customers" (
+    "id" bigserial PRIMARY KEY,
+    "name" varchar(100) NOT NULL,
+    "phone_number" varchar(50),
+    "date_of_birth" date,
+    "allergies" text,
+    "notes" text,
+    "deleted" boolean NOT NULL DEFAULT false,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "sales" ADD COLUMN "customer_id" bigint;
+ALTER TABLE "sales" ADD CONSTRAINT "sales_customer_id_fkey" FOREIGN KEY ("customer_id") REFERENCES "customers" ("id");
+
+ALTER TABLE "movements" ADD COLUMN "customer_id" bigint;
+ALTER TABLE "movements" ADD CONSTRAINT "movements_customer_id_fkey" FOREIGN KEY ("customer_id") REFERENCES "customers" ("id");
+
+CREATE INDEX idx_customers_name ON "customers" (LOWER(name));
+CREATE INDEX idx_customers_phone_number ON "customers" (phone_number);
+CREATE INDEX idx_sales_customer_id ON "sales" (customer_id);
+CREATE INDEX idx_movements_customer_id ON "movements" (customer_id);
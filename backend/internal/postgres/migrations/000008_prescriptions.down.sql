@@ -0,0 +1,8 @@
+ALTER TABLE "movements" DROP CONSTRAINT "movements_prescription_id_fkey";
+ALTER TABLE "movements" DROP COLUMN IF EXISTS "prescription_id";
+
+DROP TABLE IF EXISTS "prescription_scans";
+DROP TABLE IF EXISTS "prescription_items";
+DROP TABLE IF EXISTS "prescriptions";
+
+ALTER TABLE "products" DROP COLUMN IF EXISTS "prescription_only";
@@ -0,0 +1,51 @@
+ALTER TABLE "products" ADD COLUMN "prescription_only" boolean NOT NULL DEFAULT false;
+
+CREATE TABLE "prescriptions" (
+    "id" bigserial PRIMARY KEY,
+    "customer_id" bigint NOT NULL,
+    "prescriber_name" varchar(100) NOT NULL,
+    "prescriber_licence_number" varchar(50) NOT NULL,
+    "prescribed_at" date NOT NULL,
+    "valid_until" date NOT NULL,
+    "notes" text,
+    "created_by" bigint NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "prescriptions_customer_id_fkey" FOREIGN KEY ("customer_id") REFERENCES "customers" ("id"),
+    CONSTRAINT "prescriptions_created_by_fkey" FOREIGN KEY ("created_by") REFERENCES "users" ("id"),
+    CONSTRAINT "prescriptions_valid_until_check" CHECK (valid_until >= prescribed_at)
+);
+
+CREATE TABLE "prescription_items" (
+    "id" bigserial PRIMARY KEY,
+    "prescription_id" bigint NOT NULL,
+    "product_id" bigint NOT NULL,
+    "quantity" integer NOT NULL CHECK (quantity > 0),
+    "dispensed_quantity" integer NOT NULL DEFAULT 0,
+    "dosage" text,
+
+    CONSTRAINT "prescription_items_prescription_id_fkey" FOREIGN KEY ("prescription_id") REFERENCES "prescriptions" ("id"),
+    CONSTRAINT "prescription_items_product_id_fkey" FOREIGN KEY ("product_id") REFERENCES "products" ("id"),
+    CONSTRAINT "prescription_items_product_key" UNIQUE ("prescription_id", "product_id"),
+    CONSTRAINT "prescription_items_dispensed_check" CHECK (dispensed_quantity >= 0 AND dispensed_quantity <= quantity)
+);
+
+CREATE TABLE "prescription_scans" (
+    "id" bigserial PRIMARY KEY,
+    "prescription_id" bigint NOT NULL,
+    "file_name" varchar(255) NOT NULL,
+    "content_type" varchar(100) NOT NULL,
+    "data" bytea NOT NULL,
+    "uploaded_by" bigint NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "prescription_scans_prescription_id_fkey" FOREIGN KEY ("prescription_id") REFERENCES "prescriptions" ("id"),
+    CONSTRAINT "prescription_scans_uploaded_by_fkey" FOREIGN KEY ("uploaded_by") REFERENCES "users" ("id")
+);
+
+ALTER TABLE "movements" ADD COLUMN "prescription_id" bigint;
+ALTER TABLE "movements" ADD CONSTRAINT "movements_prescription_id_fkey" FOREIGN KEY ("prescription_id") REFERENCES "prescriptions" ("id");
+
+CREATE INDEX idx_prescriptions_customer_id ON "prescriptions" (customer_id);
+CREATE INDEX idx_prescription_scans_prescription_id ON "prescription_scans" (prescription_id);
+CREATE INDEX idx_movements_prescription_id ON "movements" (prescription_id);
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS "controlled_drug_register";
+
+UPDATE "users" SET "role" = 'staff' WHERE "role" = 'pharmacist';
+ALTER TABLE "users" DROP CONSTRAINT "users_role_check";
+ALTER TABLE "users" ADD CONSTRAINT "users_role_check" CHECK (role IN ('admin', 'staff'));
+
+ALTER TABLE "movements" DROP CONSTRAINT "movements_witnessed_by_fkey";
+ALTER TABLE "movements" DROP COLUMN IF EXISTS "witnessed_by";
+
+ALTER TABLE "products" DROP COLUMN IF EXISTS "controlled";
@@ -0,0 +1,37 @@
+ALTER TABLE "products" ADD COLUMN "controlled" boolean NOT NULL DEFAULT false;
+
+ALTER TABLE "movements" ADD COLUMN "witnessed_by" bigint;
+ALTER TABLE "movements" ADD CONSTRAINT "movements_witnessed_by_fkey" FOREIGN KEY ("witnessed_by") REFERENCES "users" ("id");
+
+-- only pharmacists and admins can witness movements of controlled products
+ALTER TABLE "users" DROP CONSTRAINT "users_role_check";
+ALTER TABLE "users" ADD CONSTRAINT "users_role_check" CHECK (role IN ('admin', 'pharmacist', 'staff'));
+
+-- every movement of a controlled product gets an entry here. each entry stores the
+-- hash of the previous entry for the same product so edits and deletions can be detected.
+CREATE TABLE "controlled_drug_register" (
+    "id" bigserial PRIMARY KEY,
+    "product_id" bigint NOT NULL,
+    "movement_id" bigint UNIQUE NOT NULL,
+    "entry_type" varchar(20) NOT NULL,
+    "quantity" integer NOT NULL,
+    "balance" bigint NOT NULL,
+    "reference" text,
+    "customer_id" bigint,
+    "prescription_id" bigint,
+    "performed_by" bigint NOT NULL,
+    "witnessed_by" bigint NOT NULL,
+    "previous_hash" varchar(64) NOT NULL,
+    "hash" varchar(64) NOT NULL,
+    "created_at" timestamptz NOT NULL,
+
+    CONSTRAINT "controlled_drug_register_product_id_fkey" FOREIGN KEY ("product_id") REFERENCES "products" ("id"),
+    CONSTRAINT "controlled_drug_register_movement_id_fkey" FOREIGN KEY ("movement_id") REFERENCES "movements" ("id"),
+    CONSTRAINT "controlled_drug_register_customer_id_fkey" FOREIGN KEY ("customer_id") REFERENCES "customers" ("id"),
+    CONSTRAINT "controlled_drug_register_prescription_id_fkey" FOREIGN KEY ("prescription_id") REFERENCES "prescriptions" ("id"),
+    CONSTRAINT "controlled_drug_register_performed_by_fkey" FOREIGN KEY ("performed_by") REFERENCES "users" ("id"),
+    CONSTRAINT "controlled_drug_register_witnessed_by_fkey" FOREIGN KEY ("witnessed_by") REFERENCES "users" ("id"),
+    CONSTRAINT "controlled_drug_register_witness_check" CHECK (witnessed_by <> performed_by)
+);
+
+CREATE INDEX idx_controlled_drug_register_product_id ON "controlled_drug_register" (product_id, id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "returns";
+
+ALTER TABLE "products" DROP COLUMN IF EXISTS "quarantined_stock";
+ALTER TABLE "movements" DROP COLUMN IF EXISTS "reason";
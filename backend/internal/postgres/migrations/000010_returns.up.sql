@@ -0,0 +1,30 @@
+ALTER TABLE "movements" ADD COLUMN "reason" varchar(50);
+ALTER TABLE "products" ADD COLUMN "quarantined_stock" bigint NOT NULL DEFAULT 0 CHECK (quarantined_stock >= 0);
+
+CREATE TABLE "returns" (
+    "id" bigserial PRIMARY KEY,
+    "movement_id" bigint NOT NULL,
+    "sale_id" bigint,
+    "product_id" bigint NOT NULL,
+    "customer_id" bigint,
+    "quantity" integer NOT NULL CHECK (quantity > 0),
+    "status" varchar(20) NOT NULL CHECK (status IN ('RESTOCKED', 'QUARANTINED', 'DISPOSED')),
+    "reason" text,
+    "batch_number" varchar(50),
+    "restock_movement_id" bigint,
+    "performed_by" bigint NOT NULL,
+    "resolved_by" bigint,
+    "resolved_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "returns_movement_id_fkey" FOREIGN KEY ("movement_id") REFERENCES "movements" ("id"),
+    CONSTRAINT "returns_sale_id_fkey" FOREIGN KEY ("sale_id") REFERENCES "sales" ("id"),
+    CONSTRAINT "returns_product_id_fkey" FOREIGN KEY ("product_id") REFERENCES "products" ("id"),
+    CONSTRAINT "returns_customer_id_fkey" FOREIGN KEY ("customer_id") REFERENCES "customers" ("id"),
+    CONSTRAINT "returns_restock_movement_id_fkey" FOREIGN KEY ("restock_movement_id") REFERENCES "movements" ("id"),
+    CONSTRAINT "returns_performed_by_fkey" FOREIGN KEY ("performed_by") REFERENCES "users" ("id"),
+    CONSTRAINT "returns_resolved_by_fkey" FOREIGN KEY ("resolved_by") REFERENCES "users" ("id")
+);
+
+CREATE INDEX idx_returns_movement_id ON "returns" (movement_id);
+CREATE INDEX idx_returns_status ON "returns" (status);
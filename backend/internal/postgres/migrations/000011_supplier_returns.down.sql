@@ -0,0 +1,8 @@
+ALTER TABLE "returns" DROP CONSTRAINT "returns_status_check";
+ALTER TABLE "returns" ADD CONSTRAINT "returns_status_check" CHECK (status IN ('RESTOCKED', 'QUARANTINED', 'DISPOSED'));
+
+DROP TABLE IF EXISTS "supplier_credits";
+DROP TABLE IF EXISTS "supplier_return_items";
+DROP TABLE IF EXISTS "supplier_returns";
+DROP SEQUENCE IF EXISTS "debit_note_number_seq";
+DROP TABLE IF EXISTS "suppliers";
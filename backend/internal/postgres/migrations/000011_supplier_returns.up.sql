@@ -0,0 +1,69 @@
+CREATE TABLE "suppliers" (
+    "id" bigserial PRIMARY KEY,
+    "name" varchar(100) NOT NULL,
+    "contact_person" varchar(100),
+    "phone_number" varchar(50),
+    "email" varchar(100),
+    "address" text,
+    "deleted" boolean NOT NULL DEFAULT false,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE SEQUENCE "debit_note_number_seq";
+
+CREATE TABLE "supplier_returns" (
+    "id" bigserial PRIMARY KEY,
+    "supplier_id" bigint NOT NULL,
+    "debit_note_number" varchar(20) NOT NULL UNIQUE DEFAULT ('DN-' || lpad(nextval('debit_note_number_seq')::text, 6, '0')),
+    "total_quantity" bigint NOT NULL DEFAULT 0,
+    "total_amount" numeric(12,2) NOT NULL DEFAULT 0,
+    "credit_received" numeric(12,2) NOT NULL DEFAULT 0,
+    "credit_status" varchar(20) NOT NULL DEFAULT 'PENDING' CHECK (credit_status IN ('PENDING', 'PARTIAL', 'RECEIVED')),
+    "note" text,
+    "performed_by" bigint NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "supplier_returns_supplier_id_fkey" FOREIGN KEY ("supplier_id") REFERENCES "suppliers" ("id"),
+    CONSTRAINT "supplier_returns_performed_by_fkey" FOREIGN KEY ("performed_by") REFERENCES "users" ("id"),
+    CONSTRAINT "supplier_returns_credit_check" CHECK (credit_received <= total_amount)
+);
+
+CREATE TABLE "supplier_return_items" (
+    "id" bigserial PRIMARY KEY,
+    "supplier_return_id" bigint NOT NULL,
+    "product_id" bigint NOT NULL,
+    "batch_number" varchar(50),
+    "quantity" integer NOT NULL CHECK (quantity > 0),
+    "unit_cost" numeric(10,2) NOT NULL,
+    "line_total" numeric(12,2) NOT NULL,
+    "reason" varchar(50),
+    "movement_id" bigint,
+    "return_id" bigint,
+
+    CONSTRAINT "supplier_return_items_supplier_return_id_fkey" FOREIGN KEY ("supplier_return_id") REFERENCES "supplier_returns" ("id"),
+    CONSTRAINT "supplier_return_items_product_id_fkey" FOREIGN KEY ("product_id") REFERENCES "products" ("id"),
+    CONSTRAINT "supplier_return_items_movement_id_fkey" FOREIGN KEY ("movement_id") REFERENCES "movements" ("id"),
+    CONSTRAINT "supplier_return_items_return_id_fkey" FOREIGN KEY ("return_id") REFERENCES "returns" ("id")
+);
+
+CREATE TABLE "supplier_credits" (
+    "id" bigserial PRIMARY KEY,
+    "supplier_return_id" bigint NOT NULL,
+    "amount" numeric(12,2) NOT NULL CHECK (amount > 0),
+    "reference" varchar(100),
+    "received_at" date NOT NULL,
+    "recorded_by" bigint NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "supplier_credits_supplier_return_id_fkey" FOREIGN KEY ("supplier_return_id") REFERENCES "supplier_returns" ("id"),
+    CONSTRAINT "supplier_credits_recorded_by_fkey" FOREIGN KEY ("recorded_by") REFERENCES "users" ("id")
+);
+
+-- quarantined customer returns can be sent back to the supplier
+ALTER TABLE "returns" DROP CONSTRAINT "returns_status_check";
+ALTER TABLE "returns" ADD CONSTRAINT "returns_status_check" CHECK (status IN ('RESTOCKED', 'QUARANTINED', 'DISPOSED', 'SUPPLIER_RETURNED'));
+
+CREATE INDEX idx_suppliers_name ON "suppliers" (LOWER(name));
+CREATE INDEX idx_supplier_returns_supplier_id ON "supplier_returns" (supplier_id);
+CREATE INDEX idx_supplier_return_items_supplier_return_id ON "supplier_return_items" (supplier_return_id);
+CREATE INDEX idx_supplier_credits_supplier_return_id ON "supplier_credits" (supplier_return_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "reservation_items";
+DROP TABLE IF EXISTS "reservations";
+
+ALTER TABLE "products" DROP COLUMN IF EXISTS "reserved_stock";
@@ -0,0 +1,33 @@
+ALTER TABLE "products" ADD COLUMN "reserved_stock" bigint NOT NULL DEFAULT 0 CHECK (reserved_stock >= 0);
+
+CREATE TABLE "reservations" (
+    "id" bigserial PRIMARY KEY,
+    "customer_id" bigint,
+    "status" varchar(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'CONVERTED', 'RELEASED', 'EXPIRED')),
+    "note" text,
+    "expires_at" timestamptz NOT NULL,
+    "sale_id" bigint,
+    "created_by" bigint NOT NULL,
+    "resolved_by" bigint,
+    "resolved_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "reservations_customer_id_fkey" FOREIGN KEY ("customer_id") REFERENCES "customers" ("id"),
+    CONSTRAINT "reservations_sale_id_fkey" FOREIGN KEY ("sale_id") REFERENCES "sales" ("id"),
+    CONSTRAINT "reservations_created_by_fkey" FOREIGN KEY ("created_by") REFERENCES "users" ("id"),
+    CONSTRAINT "reservations_resolved_by_fkey" FOREIGN KEY ("resolved_by") REFERENCES "users" ("id")
+);
+
+CREATE TABLE "reservation_items" (
+    "id" bigserial PRIMARY KEY,
+    "reservation_id" bigint NOT NULL,
+    "product_id" bigint NOT NULL,
+    "quantity" integer NOT NULL CHECK (quantity > 0),
+
+    CONSTRAINT "reservation_items_reservation_id_fkey" FOREIGN KEY ("reservation_id") REFERENCES "reservations" ("id"),
+    CONSTRAINT "reservation_items_product_id_fkey" FOREIGN KEY ("product_id") REFERENCES "products" ("id")
+);
+
+CREATE INDEX idx_reservations_status_expires_at ON "reservations" (status, expires_at);
+CREATE INDEX idx_reservations_customer_id ON "reservations" (customer_id);
+CREATE INDEX idx_reservation_items_reservation_id ON "reservation_items" (reservation_id);
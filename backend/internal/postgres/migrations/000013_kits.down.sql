@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "kit_components";
+
+ALTER TABLE "products" DROP COLUMN IF EXISTS "is_kit";
@@ -0,0 +1,14 @@
+ALTER TABLE "products" ADD COLUMN "is_kit" boolean NOT NULL DEFAULT false;
+
+CREATE TABLE "kit_components" (
+    "kit_id" bigint NOT NULL,
+    "component_id" bigint NOT NULL,
+    "quantity" integer NOT NULL CHECK (quantity > 0),
+
+    PRIMARY KEY ("kit_id", "component_id"),
+    CONSTRAINT "kit_components_kit_id_fkey" FOREIGN KEY ("kit_id") REFERENCES "products" ("id"),
+    CONSTRAINT "kit_components_component_id_fkey" FOREIGN KEY ("component_id") REFERENCES "products" ("id"),
+    CONSTRAINT "kit_components_self_check" CHECK (kit_id <> component_id)
+);
+
+CREATE INDEX idx_kit_components_component_id ON "kit_components" (component_id);
@@ -0,0 +1,48 @@
+CREATE TABLE "categories" (
+    "id" bigserial PRIMARY KEY,
+    "name" varchar(100) NOT NULL,
+    "parent_id" bigint,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "categories_parent_id_fkey" FOREIGN KEY ("parent_id") REFERENCES "categories" ("id"),
+    CONSTRAINT "categories_parent_self_check" CHECK (parent_id <> id)
+);
+
+CREATE UNIQUE INDEX idx_categories_lower_name ON "categories" (LOWER(name));
+CREATE INDEX idx_categories_parent_id ON "categories" (parent_id);
+
+-- existing free-text categories are merged on a key that ignores case, repeated
+-- whitespace and simple plurals ("Antibiotics" and "antibiotic"), keeping the most
+-- used spelling. Categories still split after this are merged by deleting the stray
+-- one with DELETE /categories/:id?move_to=<id>, which moves its products across.
+CREATE TEMPORARY TABLE category_keys AS
+SELECT 
+    id,
+    regexp_replace(TRIM(category), '\s+', ' ', 'g') AS name,
+    regexp_replace(regexp_replace(LOWER(regexp_replace(TRIM(category), '\s+', ' ', 'g')), 'ies$', 'y'), 's$', '') AS key
+FROM products
+WHERE TRIM(category) <> '';
+
+CREATE TEMPORARY TABLE category_merge AS
+SELECT DISTINCT ON (key) key, name
+FROM category_keys
+GROUP BY key, name
+ORDER BY key, COUNT(*) DESC, name;
+
+INSERT INTO "categories" (name)
+SELECT name FROM category_merge;
+
+ALTER TABLE "products" ADD COLUMN "category_id" bigint;
+ALTER TABLE "products" ADD CONSTRAINT "products_category_id_fkey" FOREIGN KEY ("category_id") REFERENCES "categories" ("id");
+CREATE INDEX idx_products_category_id ON "products" (category_id);
+
+-- products.category keeps the category name for search and existing clients
+UPDATE products p
+SET category_id = c.id,
+    category = c.name
+FROM category_keys k
+JOIN category_merge m ON m.key = k.key
+JOIN categories c ON c.name = m.name
+WHERE p.id = k.id;
+
+DROP TABLE category_keys, category_merge;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_products_lower_generic_name;
+
+ALTER TABLE "products" DROP COLUMN IF EXISTS "therapeutic_class";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "pack_size";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "manufacturer";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "route";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "dosage_form";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "strength";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "generic_name";
@@ -0,0 +1,9 @@
+ALTER TABLE "products" ADD COLUMN "generic_name" varchar(150);
+ALTER TABLE "products" ADD COLUMN "strength" varchar(50);
+ALTER TABLE "products" ADD COLUMN "dosage_form" varchar(50);
+ALTER TABLE "products" ADD COLUMN "route" varchar(50);
+ALTER TABLE "products" ADD COLUMN "manufacturer" varchar(150);
+ALTER TABLE "products" ADD COLUMN "pack_size" integer CHECK (pack_size > 0);
+ALTER TABLE "products" ADD COLUMN "therapeutic_class" varchar(100);
+
+CREATE INDEX idx_products_lower_generic_name ON "products" (LOWER(generic_name));
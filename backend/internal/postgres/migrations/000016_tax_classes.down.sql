@@ -0,0 +1,10 @@
+ALTER TABLE "sales" DROP COLUMN IF EXISTS "total_tax";
+
+ALTER TABLE "sale_items" DROP COLUMN IF EXISTS "tax_amount";
+ALTER TABLE "sale_items" DROP COLUMN IF EXISTS "tax_rate";
+ALTER TABLE "sale_items" DROP COLUMN IF EXISTS "tax_code";
+ALTER TABLE "sale_items" DROP COLUMN IF EXISTS "tax_class_id";
+
+ALTER TABLE "products" DROP COLUMN IF EXISTS "tax_class_id";
+
+DROP TABLE IF EXISTS "tax_classes";
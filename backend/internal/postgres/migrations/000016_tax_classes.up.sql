@@ -0,0 +1,34 @@
+-- codes follow the KRA tax bands printed on receipts
+CREATE TABLE "tax_classes" (
+    "id" bigserial PRIMARY KEY,
+    "code" varchar(5) NOT NULL UNIQUE,
+    "name" varchar(100) NOT NULL,
+    "type" varchar(20) NOT NULL CHECK (type IN ('STANDARD', 'ZERO_RATED', 'EXEMPT')),
+    "rate" numeric(5,2) NOT NULL DEFAULT 0,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "tax_classes_rate_check" CHECK (rate >= 0 AND (type = 'STANDARD' OR rate = 0))
+);
+
+INSERT INTO "tax_classes" (code, name, type, rate) VALUES
+    ('A', 'Exempt', 'EXEMPT', 0),
+    ('B', 'VAT 16%', 'STANDARD', 16),
+    ('C', 'Zero rated', 'ZERO_RATED', 0);
+
+-- most medicines are VAT exempt, so existing products start out exempt
+ALTER TABLE "products" ADD COLUMN "tax_class_id" bigint;
+UPDATE products SET tax_class_id = (SELECT id FROM tax_classes WHERE code = 'A');
+ALTER TABLE "products" ALTER COLUMN "tax_class_id" SET NOT NULL;
+ALTER TABLE "products" ADD CONSTRAINT "products_tax_class_id_fkey" FOREIGN KEY ("tax_class_id") REFERENCES "tax_classes" ("id");
+
+-- prices are tax inclusive. the rate is copied onto each line so later rate changes
+-- do not alter past sales
+ALTER TABLE "sale_items" ADD COLUMN "tax_class_id" bigint;
+ALTER TABLE "sale_items" ADD COLUMN "tax_code" varchar(5) NOT NULL DEFAULT 'A';
+ALTER TABLE "sale_items" ADD COLUMN "tax_rate" numeric(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE "sale_items" ADD COLUMN "tax_amount" numeric(12,2) NOT NULL DEFAULT 0;
+UPDATE sale_items SET tax_class_id = (SELECT id FROM tax_classes WHERE code = 'A');
+ALTER TABLE "sale_items" ALTER COLUMN "tax_class_id" SET NOT NULL;
+ALTER TABLE "sale_items" ADD CONSTRAINT "sale_items_tax_class_id_fkey" FOREIGN KEY ("tax_class_id") REFERENCES "tax_classes" ("id");
+
+ALTER TABLE "sales" ADD COLUMN "total_tax" numeric(12,2) NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+ALTER TABLE "sales" DROP COLUMN IF EXISTS "total_discount";
+
+ALTER TABLE "sale_items" DROP COLUMN IF EXISTS "discount_amount";
+ALTER TABLE "sale_items" DROP COLUMN IF EXISTS "promotion_id";
+
+DROP TABLE IF EXISTS "promotions";
@@ -0,0 +1,39 @@
+CREATE TABLE "promotions" (
+    "id" bigserial PRIMARY KEY,
+    "name" varchar(100) NOT NULL,
+    "type" varchar(20) NOT NULL CHECK (type IN ('PERCENTAGE', 'FIXED', 'BUY_X_GET_Y')),
+    "value" numeric(12,2) NOT NULL DEFAULT 0,
+    "buy_quantity" integer,
+    "free_quantity" integer,
+    "product_id" bigint,
+    "category_id" bigint,
+    "starts_at" timestamptz NOT NULL,
+    "ends_at" timestamptz NOT NULL,
+    "active" boolean NOT NULL DEFAULT true,
+    "created_by" bigint NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "promotions_product_id_fkey" FOREIGN KEY ("product_id") REFERENCES "products" ("id"),
+    CONSTRAINT "promotions_category_id_fkey" FOREIGN KEY ("category_id") REFERENCES "categories" ("id"),
+    CONSTRAINT "promotions_created_by_fkey" FOREIGN KEY ("created_by") REFERENCES "users" ("id"),
+    CONSTRAINT "promotions_window_check" CHECK (ends_at > starts_at),
+    -- a promotion applies either to one product or to a category and its subcategories
+    CONSTRAINT "promotions_scope_check" CHECK ((product_id IS NULL) <> (category_id IS NULL)),
+    CONSTRAINT "promotions_value_check" CHECK (
+        (type = 'PERCENTAGE' AND value > 0 AND value <= 100)
+        OR (type = 'FIXED' AND value > 0)
+        OR (type = 'BUY_X_GET_Y' AND buy_quantity > 0 AND free_quantity > 0)
+    )
+);
+
+CREATE INDEX idx_promotions_product_id ON "promotions" (product_id);
+CREATE INDEX idx_promotions_category_id ON "promotions" (category_id);
+CREATE INDEX idx_promotions_window ON "promotions" (starts_at, ends_at) WHERE active = true;
+
+-- line_total is what the customer paid, after the discount
+ALTER TABLE "sale_items" ADD COLUMN "promotion_id" bigint;
+ALTER TABLE "sale_items" ADD COLUMN "discount_amount" numeric(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE "sale_items" ADD CONSTRAINT "sale_items_promotion_id_fkey" FOREIGN KEY ("promotion_id") REFERENCES "promotions" ("id");
+CREATE INDEX idx_sale_items_promotion_id ON "sale_items" (promotion_id);
+
+ALTER TABLE "sales" ADD COLUMN "total_discount" numeric(12,2) NOT NULL DEFAULT 0;
@@ -0,0 +1,20 @@
+CREATE TABLE "payments" (
+    "id" bigserial PRIMARY KEY,
+    "sale_id" bigint NOT NULL,
+    "method" varchar(20) NOT NULL CHECK (method IN ('CASH', 'CARD', 'MPESA', 'INSURANCE')),
+    "amount" numeric(12,2) NOT NULL CHECK (amount > 0),
+    "status" varchar(20) NOT NULL DEFAULT 'COMPLETED' CHECK (status IN ('PENDING', 'COMPLETED', 'FAILED')),
+    -- card approval code, M-Pesa receipt number or insurance claim reference
+    "reference" varchar(100),
+    "phone_number" varchar(20),
+    "checkout_request_id" varchar(100) UNIQUE,
+    "failure_reason" text,
+    "received_by" bigint NOT NULL,
+    "completed_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "payments_sale_id_fkey" FOREIGN KEY ("sale_id") REFERENCES "sales" ("id"),
+    CONSTRAINT "payments_received_by_fkey" FOREIGN KEY ("received_by") REFERENCES "users" ("id")
+);
+
+CREATE INDEX idx_payments_sale_id ON "payments" (sale_id);
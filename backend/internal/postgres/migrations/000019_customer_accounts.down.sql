@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_sales_on_account_customer;
+
+ALTER TABLE "sales" DROP CONSTRAINT IF EXISTS "sales_on_account_check";
+ALTER TABLE "sales" DROP COLUMN IF EXISTS "due_date";
+ALTER TABLE "sales" DROP COLUMN IF EXISTS "on_account";
+
+ALTER TABLE "customers" DROP COLUMN IF EXISTS "payment_terms_days";
+ALTER TABLE "customers" DROP COLUMN IF EXISTS "credit_limit";
@@ -0,0 +1,10 @@
+-- a credit limit of zero means the customer cannot buy on account
+ALTER TABLE "customers" ADD COLUMN "credit_limit" numeric(12,2) NOT NULL DEFAULT 0 CHECK (credit_limit >= 0);
+ALTER TABLE "customers" ADD COLUMN "payment_terms_days" integer NOT NULL DEFAULT 30 CHECK (payment_terms_days > 0);
+
+-- a sale on account is the customer's invoice; its balance is settled by later payments
+ALTER TABLE "sales" ADD COLUMN "on_account" boolean NOT NULL DEFAULT false;
+ALTER TABLE "sales" ADD COLUMN "due_date" timestamptz;
+ALTER TABLE "sales" ADD CONSTRAINT "sales_on_account_check" CHECK (NOT on_account OR (customer_id IS NOT NULL AND due_date IS NOT NULL));
+
+CREATE INDEX idx_sales_on_account_customer ON "sales" (customer_id) WHERE on_account;
@@ -0,0 +1,8 @@
+ALTER TABLE "payments" DROP CONSTRAINT IF EXISTS "payments_shift_id_fkey";
+ALTER TABLE "payments" DROP COLUMN IF EXISTS "shift_id";
+
+ALTER TABLE "sales" DROP CONSTRAINT IF EXISTS "sales_shift_id_fkey";
+ALTER TABLE "sales" DROP COLUMN IF EXISTS "shift_id";
+
+DROP TABLE IF EXISTS "shift_tenders";
+DROP TABLE IF EXISTS "shifts";
@@ -0,0 +1,37 @@
+CREATE TABLE "shifts" (
+    "id" bigserial PRIMARY KEY,
+    "user_id" bigint NOT NULL,
+    "location" varchar(50) NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'CLOSED')),
+    "opening_float" numeric(12,2) NOT NULL DEFAULT 0 CHECK (opening_float >= 0),
+    "opening_note" text,
+    "closing_note" text,
+    "opened_at" timestamptz NOT NULL DEFAULT (now()),
+    "closed_at" timestamptz,
+
+    CONSTRAINT "shifts_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id")
+);
+
+-- a user works one till at a time
+CREATE UNIQUE INDEX idx_shifts_open_user ON "shifts" (user_id) WHERE status = 'OPEN';
+CREATE INDEX idx_shifts_location_closed_at ON "shifts" (location, closed_at);
+
+-- expected is what the shift's payments say the till should hold; variance is counted - expected
+CREATE TABLE "shift_tenders" (
+    "shift_id" bigint NOT NULL,
+    "method" varchar(20) NOT NULL,
+    "expected_amount" numeric(12,2) NOT NULL,
+    "counted_amount" numeric(12,2) NOT NULL,
+    "variance" numeric(12,2) NOT NULL,
+
+    PRIMARY KEY ("shift_id", "method"),
+    CONSTRAINT "shift_tenders_shift_id_fkey" FOREIGN KEY ("shift_id") REFERENCES "shifts" ("id") ON DELETE CASCADE
+);
+
+ALTER TABLE "sales" ADD COLUMN "shift_id" bigint;
+ALTER TABLE "sales" ADD CONSTRAINT "sales_shift_id_fkey" FOREIGN KEY ("shift_id") REFERENCES "shifts" ("id");
+CREATE INDEX idx_sales_shift_id ON "sales" (shift_id);
+
+ALTER TABLE "payments" ADD COLUMN "shift_id" bigint;
+ALTER TABLE "payments" ADD CONSTRAINT "payments_shift_id_fkey" FOREIGN KEY ("shift_id") REFERENCES "shifts" ("id");
+CREATE INDEX idx_payments_shift_id ON "payments" (shift_id);
@@ -0,0 +1,45 @@
+CREATE TABLE "quotations" (
+    "id" bigserial PRIMARY KEY,
+    "quotation_number" varchar(20) GENERATED ALWAYS AS ('QT-' || lpad(id::text, 6, '0')) STORED,
+    "customer_id" bigint,
+    "status" varchar(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'CONVERTED', 'CANCELLED')),
+    "note" text,
+    "valid_until" timestamptz NOT NULL,
+    "total_quantity" bigint NOT NULL DEFAULT 0,
+    "total_amount" numeric(12,2) NOT NULL DEFAULT 0,
+    "total_tax" numeric(12,2) NOT NULL DEFAULT 0,
+    "total_discount" numeric(12,2) NOT NULL DEFAULT 0,
+    "sale_id" bigint,
+    "created_by" bigint NOT NULL,
+    "resolved_by" bigint,
+    "resolved_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "quotations_customer_id_fkey" FOREIGN KEY ("customer_id") REFERENCES "customers" ("id"),
+    CONSTRAINT "quotations_sale_id_fkey" FOREIGN KEY ("sale_id") REFERENCES "sales" ("id"),
+    CONSTRAINT "quotations_created_by_fkey" FOREIGN KEY ("created_by") REFERENCES "users" ("id"),
+    CONSTRAINT "quotations_resolved_by_fkey" FOREIGN KEY ("resolved_by") REFERENCES "users" ("id")
+);
+
+-- lines are priced as a sale would be at the time of quoting; no stock is held
+CREATE TABLE "quotation_items" (
+    "id" bigserial PRIMARY KEY,
+    "quotation_id" bigint NOT NULL,
+    "product_id" bigint NOT NULL,
+    "quantity" integer NOT NULL CHECK (quantity > 0),
+    "unit_price" numeric(10,2) NOT NULL,
+    "discount_amount" numeric(12,2) NOT NULL DEFAULT 0,
+    "line_total" numeric(12,2) NOT NULL,
+    "promotion_id" bigint,
+    "tax_code" varchar(5) NOT NULL,
+    "tax_rate" numeric(5,2) NOT NULL,
+    "tax_amount" numeric(12,2) NOT NULL,
+
+    CONSTRAINT "quotation_items_quotation_id_fkey" FOREIGN KEY ("quotation_id") REFERENCES "quotations" ("id"),
+    CONSTRAINT "quotation_items_product_id_fkey" FOREIGN KEY ("product_id") REFERENCES "products" ("id"),
+    CONSTRAINT "quotation_items_promotion_id_fkey" FOREIGN KEY ("promotion_id") REFERENCES "promotions" ("id")
+);
+
+CREATE INDEX idx_quotations_customer_id ON "quotations" (customer_id);
+CREATE INDEX idx_quotations_status ON "quotations" (status);
+CREATE INDEX idx_quotation_items_quotation_id ON "quotation_items" (quotation_id);
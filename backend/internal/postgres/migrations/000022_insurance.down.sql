@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS "claims";
+DROP TABLE IF EXISTS "claim_batches";
+DROP SEQUENCE IF EXISTS "claim_batch_number_seq";
+
+ALTER TABLE "sales" DROP CONSTRAINT IF EXISTS "sales_member_number_check";
+ALTER TABLE "sales" DROP CONSTRAINT IF EXISTS "sales_scheme_id_fkey";
+ALTER TABLE "sales" DROP COLUMN IF EXISTS "member_number";
+ALTER TABLE "sales" DROP COLUMN IF EXISTS "scheme_id";
+
+DROP TABLE IF EXISTS "insurance_schemes";
+DROP TABLE IF EXISTS "insurers";
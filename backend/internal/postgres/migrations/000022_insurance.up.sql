@@ -0,0 +1,78 @@
+-- client names the insurance client implementation claims are submitted through
+CREATE TABLE "insurers" (
+    "id" bigserial PRIMARY KEY,
+    "name" varchar(100) NOT NULL UNIQUE,
+    "code" varchar(20) NOT NULL UNIQUE,
+    "client" varchar(20) NOT NULL,
+    "email" varchar(100),
+    "phone_number" varchar(50),
+    "deleted" boolean NOT NULL DEFAULT false,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- co_payment_rate is the percentage of each sale the member pays themselves
+CREATE TABLE "insurance_schemes" (
+    "id" bigserial PRIMARY KEY,
+    "insurer_id" bigint NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "co_payment_rate" numeric(5,2) NOT NULL DEFAULT 0 CHECK (co_payment_rate >= 0 AND co_payment_rate < 100),
+    "active" boolean NOT NULL DEFAULT true,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "insurance_schemes_insurer_id_fkey" FOREIGN KEY ("insurer_id") REFERENCES "insurers" ("id"),
+    CONSTRAINT "insurance_schemes_insurer_id_name_key" UNIQUE ("insurer_id", "name")
+);
+
+ALTER TABLE "sales" ADD COLUMN "scheme_id" bigint;
+ALTER TABLE "sales" ADD COLUMN "member_number" varchar(50);
+ALTER TABLE "sales" ADD CONSTRAINT "sales_scheme_id_fkey" FOREIGN KEY ("scheme_id") REFERENCES "insurance_schemes" ("id");
+ALTER TABLE "sales" ADD CONSTRAINT "sales_member_number_check" CHECK ((scheme_id IS NULL) = (member_number IS NULL));
+CREATE INDEX idx_sales_scheme_id ON "sales" (scheme_id);
+
+CREATE SEQUENCE "claim_batch_number_seq";
+
+CREATE TABLE "claim_batches" (
+    "id" bigserial PRIMARY KEY,
+    "batch_number" varchar(20) NOT NULL UNIQUE DEFAULT ('CB-' || lpad(nextval('claim_batch_number_seq')::text, 6, '0')),
+    "insurer_id" bigint NOT NULL,
+    "period_start" timestamptz NOT NULL,
+    "period_end" timestamptz NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'DRAFT' CHECK (status IN ('DRAFT', 'SUBMITTED', 'COMPLETED')),
+    "total_claims" bigint NOT NULL DEFAULT 0,
+    "total_amount" numeric(12,2) NOT NULL DEFAULT 0,
+    "approved_amount" numeric(12,2) NOT NULL DEFAULT 0,
+    -- the insurer's reference for the submission
+    "external_reference" varchar(100),
+    "submitted_by" bigint,
+    "submitted_at" timestamptz,
+    "created_by" bigint NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "claim_batches_insurer_id_fkey" FOREIGN KEY ("insurer_id") REFERENCES "insurers" ("id"),
+    CONSTRAINT "claim_batches_submitted_by_fkey" FOREIGN KEY ("submitted_by") REFERENCES "users" ("id"),
+    CONSTRAINT "claim_batches_created_by_fkey" FOREIGN KEY ("created_by") REFERENCES "users" ("id")
+);
+
+-- a sale is claimed once; amount is the sale's completed insurance tenders
+CREATE TABLE "claims" (
+    "id" bigserial PRIMARY KEY,
+    "batch_id" bigint NOT NULL,
+    "sale_id" bigint NOT NULL UNIQUE,
+    "scheme_id" bigint NOT NULL,
+    "member_number" varchar(50) NOT NULL,
+    "amount" numeric(12,2) NOT NULL CHECK (amount > 0),
+    "status" varchar(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SUBMITTED', 'APPROVED', 'REJECTED')),
+    "approved_amount" numeric(12,2) NOT NULL DEFAULT 0,
+    "rejection_reason" text,
+    "external_reference" varchar(100),
+    "resolved_at" timestamptz,
+
+    CONSTRAINT "claims_batch_id_fkey" FOREIGN KEY ("batch_id") REFERENCES "claim_batches" ("id"),
+    CONSTRAINT "claims_sale_id_fkey" FOREIGN KEY ("sale_id") REFERENCES "sales" ("id"),
+    CONSTRAINT "claims_scheme_id_fkey" FOREIGN KEY ("scheme_id") REFERENCES "insurance_schemes" ("id"),
+    CONSTRAINT "claims_approved_amount_check" CHECK (approved_amount >= 0 AND approved_amount <= amount)
+);
+
+CREATE INDEX idx_insurance_schemes_insurer_id ON "insurance_schemes" (insurer_id);
+CREATE INDEX idx_claim_batches_insurer_id ON "claim_batches" (insurer_id);
+CREATE INDEX idx_claims_batch_id ON "claims" (batch_id);
@@ -0,0 +1,21 @@
+-- every sale is queued for transmission to the tax authority; pending invoices are
+-- retried from next_attempt_at until they are signed or rejected
+CREATE TABLE "tax_invoices" (
+    "id" bigserial PRIMARY KEY,
+    "sale_id" bigint NOT NULL UNIQUE,
+    "status" varchar(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SIGNED', 'REJECTED')),
+    "attempts" integer NOT NULL DEFAULT 0,
+    "last_error" text,
+    "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+    "control_number" varchar(100),
+    "internal_data" varchar(255),
+    "receipt_signature" varchar(255),
+    "qr_code" text,
+    "signed_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "tax_invoices_sale_id_fkey" FOREIGN KEY ("sale_id") REFERENCES "sales" ("id")
+);
+
+CREATE INDEX idx_tax_invoices_pending ON "tax_invoices" (next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX idx_tax_invoices_status ON "tax_invoices" (status);
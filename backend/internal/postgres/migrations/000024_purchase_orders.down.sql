@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS "purchase_order_items";
+DROP TABLE IF EXISTS "purchase_orders";
+DROP SEQUENCE IF EXISTS "purchase_order_number_seq";
+
+ALTER TABLE "products" DROP CONSTRAINT IF EXISTS "products_reorder_check";
+ALTER TABLE "products" DROP CONSTRAINT IF EXISTS "products_preferred_supplier_id_fkey";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "preferred_supplier_id";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "reorder_quantity";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "reorder_point";
@@ -0,0 +1,48 @@
+-- products below their reorder point are ordered from their preferred supplier;
+-- both quantities are set or neither is
+ALTER TABLE "products" ADD COLUMN "reorder_point" integer CHECK (reorder_point >= 0);
+ALTER TABLE "products" ADD COLUMN "reorder_quantity" integer CHECK (reorder_quantity > 0);
+ALTER TABLE "products" ADD COLUMN "preferred_supplier_id" bigint;
+ALTER TABLE "products" ADD CONSTRAINT "products_preferred_supplier_id_fkey" FOREIGN KEY ("preferred_supplier_id") REFERENCES "suppliers" ("id");
+ALTER TABLE "products" ADD CONSTRAINT "products_reorder_check" CHECK ((reorder_point IS NULL) = (reorder_quantity IS NULL));
+
+CREATE SEQUENCE "purchase_order_number_seq";
+
+CREATE TABLE "purchase_orders" (
+    "id" bigserial PRIMARY KEY,
+    "order_number" varchar(20) NOT NULL UNIQUE DEFAULT ('PO-' || lpad(nextval('purchase_order_number_seq')::text, 6, '0')),
+    "supplier_id" bigint NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'DRAFT' CHECK (status IN ('DRAFT', 'ORDERED', 'RECEIVED', 'CANCELLED')),
+    "total_quantity" bigint NOT NULL DEFAULT 0,
+    "total_amount" numeric(12,2) NOT NULL DEFAULT 0,
+    "note" text,
+    -- created_by is null for drafts generated from reorder points
+    "created_by" bigint,
+    "ordered_by" bigint,
+    "ordered_at" timestamptz,
+    "closed_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+    CONSTRAINT "purchase_orders_supplier_id_fkey" FOREIGN KEY ("supplier_id") REFERENCES "suppliers" ("id"),
+    CONSTRAINT "purchase_orders_created_by_fkey" FOREIGN KEY ("created_by") REFERENCES "users" ("id"),
+    CONSTRAINT "purchase_orders_ordered_by_fkey" FOREIGN KEY ("ordered_by") REFERENCES "users" ("id")
+);
+
+-- unit_cost is the product's last purchase cost when the line was added
+CREATE TABLE "purchase_order_items" (
+    "id" bigserial PRIMARY KEY,
+    "purchase_order_id" bigint NOT NULL,
+    "product_id" bigint NOT NULL,
+    "quantity" integer NOT NULL CHECK (quantity > 0),
+    "unit_cost" numeric(10,2) NOT NULL DEFAULT 0,
+    "line_total" numeric(12,2) NOT NULL DEFAULT 0,
+
+    CONSTRAINT "purchase_order_items_purchase_order_id_fkey" FOREIGN KEY ("purchase_order_id") REFERENCES "purchase_orders" ("id"),
+    CONSTRAINT "purchase_order_items_product_id_fkey" FOREIGN KEY ("product_id") REFERENCES "products" ("id"),
+    CONSTRAINT "purchase_order_items_product_unique" UNIQUE ("purchase_order_id", "product_id")
+);
+
+CREATE INDEX idx_products_preferred_supplier_id ON "products" (preferred_supplier_id);
+CREATE INDEX idx_purchase_orders_supplier_id ON "purchase_orders" (supplier_id);
+CREATE INDEX idx_purchase_orders_status ON "purchase_orders" (status);
+CREATE INDEX idx_purchase_order_items_product_id ON "purchase_order_items" (product_id);
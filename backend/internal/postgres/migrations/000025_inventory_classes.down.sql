@@ -0,0 +1,3 @@
+ALTER TABLE "products" DROP COLUMN IF EXISTS "classified_at";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "xyz_class";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "abc_class";
@@ -0,0 +1,7 @@
+-- abc_class ranks products by consumption value and xyz_class by how much their
+-- weekly demand varies; both are set by the latest classification run
+ALTER TABLE "products" ADD COLUMN "abc_class" varchar(1) CHECK (abc_class IN ('A', 'B', 'C'));
+ALTER TABLE "products" ADD COLUMN "xyz_class" varchar(1) CHECK (xyz_class IN ('X', 'Y', 'Z'));
+ALTER TABLE "products" ADD COLUMN "classified_at" timestamptz;
+
+CREATE INDEX idx_products_abc_xyz_class ON "products" (abc_class, xyz_class);
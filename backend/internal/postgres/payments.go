@@ -0,0 +1,285 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.PaymentRepository = (*PaymentRepository)(nil)
+
+type PaymentRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewPaymentRepository(db *Store) *PaymentRepository {
+	return &PaymentRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (pr *PaymentRepository) Create(ctx context.Context, payment *repository.Payment) (*repository.Payment, error) {
+	var pgPayment generated.Payment
+	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		var err error
+		pgPayment, err = createPaymentTx(ctx, q, payment)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pgPaymentToRepoPayment(pgPayment), nil
+}
+
+// createPaymentTx locks the sale and records the payment if it fits within the
+// sale's outstanding balance. M-Pesa payments are created pending.
+func createPaymentTx(ctx context.Context, q *generated.Queries, payment *repository.Payment) (generated.Payment, error) {
+	if payment.Amount <= 0 {
+		return generated.Payment{}, pkg.Errorf(pkg.INVALID_ERROR, "payment amount must be greater than zero")
+	}
+
+	balance, err := q.GetSaleBalanceForUpdate(ctx, int64(payment.SaleID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return generated.Payment{}, pkg.Errorf(pkg.NOT_FOUND_ERROR, "sale not found")
+		}
+		return generated.Payment{}, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get sale balance: %s", err.Error())
+	}
+
+	// compare in cents so rounding in float sums does not reject an exact settlement
+	outstanding := toCents(pkg.PgTypeNumericToFloat64(balance.TotalAmount)) - toCents(pkg.PgTypeNumericToFloat64(balance.CommittedAmount))
+	if toCents(payment.Amount) > outstanding {
+		return generated.Payment{}, pkg.Errorf(pkg.INVALID_ERROR, "payment of %.2f exceeds the outstanding balance of %.2f", payment.Amount, float64(outstanding)/100)
+	}
+
+	if payment.Method == repository.PAYMENT_INSURANCE {
+		if err := checkInsuredAmount(balance, payment.Amount); err != nil {
+			return generated.Payment{}, err
+		}
+	}
+
+	// takings are counted against the till of whoever received them
+	shiftID, _, err := openShiftIDTx(ctx, q, payment.ReceivedBy)
+	if err != nil {
+		return generated.Payment{}, err
+	}
+
+	createParams := generated.CreatePaymentParams{
+		SaleID:      int64(payment.SaleID),
+		Method:      payment.Method,
+		Amount:      pkg.Float64ToPgTypeNumeric(payment.Amount),
+		Status:      repository.PAYMENT_COMPLETED,
+		Reference:   stringToPgText(payment.Reference),
+		PhoneNumber: stringToPgText(payment.PhoneNumber),
+		ReceivedBy:  int64(payment.ReceivedBy),
+		CompletedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		ShiftID:     shiftID,
+	}
+	if payment.Method == repository.PAYMENT_MPESA {
+		createParams.Status = repository.PAYMENT_PENDING
+		createParams.CompletedAt = pgtype.Timestamptz{Valid: false}
+	}
+
+	pgPayment, err := q.CreatePayment(ctx, createParams)
+	if err != nil {
+		return generated.Payment{}, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create payment: %s", err.Error())
+	}
+
+	return pgPayment, nil
+}
+
+// checkInsuredAmount limits insurance tenders to the part of a scheme member's sale
+// their scheme covers, and only while the sale has not been claimed.
+func checkInsuredAmount(balance generated.GetSaleBalanceForUpdateRow, amount float64) error {
+	if !balance.SchemeID.Valid {
+		return pkg.Errorf(pkg.INVALID_ERROR, "insurance payments need the sale to be made to a scheme member")
+	}
+	if balance.Claimed {
+		return pkg.Errorf(pkg.INVALID_ERROR, "the sale has already been claimed from the insurer")
+	}
+
+	total := pkg.PgTypeNumericToFloat64(balance.TotalAmount)
+	covered := total - total*pkg.PgTypeNumericToFloat64(balance.CoPaymentRate)/100
+	available := toCents(covered) - toCents(pkg.PgTypeNumericToFloat64(balance.InsuredAmount))
+	if toCents(amount) > available {
+		return pkg.Errorf(pkg.INVALID_ERROR, "insurance payment of %.2f exceeds the %.2f the scheme covers", amount, float64(max(available, 0))/100)
+	}
+
+	return nil
+}
+
+func (pr *PaymentRepository) GetByID(ctx context.Context, id int64) (*repository.Payment, error) {
+	pgPayment, err := pr.queries.GetPaymentByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "payment not found")
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get payment: %s", err.Error())
+	}
+
+	return pgPaymentToRepoPayment(pgPayment), nil
+}
+
+func (pr *PaymentRepository) ListBySale(ctx context.Context, saleID int64) ([]*repository.Payment, error) {
+	pgPayments, err := pr.queries.ListSalePayments(ctx, saleID)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list sale payments: %s", err.Error())
+	}
+
+	payments := make([]*repository.Payment, len(pgPayments))
+	for i, p := range pgPayments {
+		payments[i] = pgPaymentToRepoPayment(p)
+	}
+
+	return payments, nil
+}
+
+func (pr *PaymentRepository) SetCheckoutRequestID(ctx context.Context, id int64, checkoutRequestID string) error {
+	err := pr.queries.SetPaymentCheckoutRequestID(ctx, generated.SetPaymentCheckoutRequestIDParams{
+		ID:                id,
+		CheckoutRequestID: pgtype.Text{String: checkoutRequestID, Valid: true},
+	})
+	if err != nil {
+		if pkg.PgxErrorCode(err) == pkg.UNIQUE_VIOLATION {
+			return pkg.Errorf(pkg.ALREADY_EXISTS_ERROR, "checkout request %s is already linked to a payment", checkoutRequestID)
+		}
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to set checkout request id: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (pr *PaymentRepository) Fail(ctx context.Context, id int64, reason string) (*repository.Payment, error) {
+	pgPayment, err := pr.queries.FailPayment(ctx, generated.FailPaymentParams{
+		ID:            id,
+		FailureReason: pgtype.Text{String: reason, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "pending payment not found")
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to fail payment: %s", err.Error())
+	}
+
+	return pgPaymentToRepoPayment(pgPayment), nil
+}
+
+func (pr *PaymentRepository) Resolve(ctx context.Context, result *repository.PaymentResult) (*repository.Payment, error) {
+	var pgPayment generated.Payment
+	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		var err error
+		pgPayment, err = q.GetPaymentByCheckoutRequestID(ctx, pgtype.Text{String: result.CheckoutRequestID, Valid: true})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "no payment for checkout request %s", result.CheckoutRequestID)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get payment: %s", err.Error())
+		}
+
+		// providers retry callbacks, so an already resolved payment is returned as is
+		if pgPayment.Status != repository.PAYMENT_PENDING {
+			return nil
+		}
+
+		if result.Success && result.Amount != nil && toCents(*result.Amount) != toCents(pkg.PgTypeNumericToFloat64(pgPayment.Amount)) {
+			reason := fmt.Sprintf("amount paid %.2f does not match the %.2f requested", *result.Amount, pkg.PgTypeNumericToFloat64(pgPayment.Amount))
+			if result.Reference != nil {
+				reason += fmt.Sprintf(" (receipt %s)", *result.Reference)
+			}
+			pgPayment, err = q.FailPayment(ctx, generated.FailPaymentParams{
+				ID:            pgPayment.ID,
+				FailureReason: pgtype.Text{String: reason, Valid: true},
+			})
+		} else if result.Success {
+			pgPayment, err = q.CompletePayment(ctx, generated.CompletePaymentParams{
+				ID:        pgPayment.ID,
+				Reference: stringToPgText(result.Reference),
+			})
+		} else {
+			pgPayment, err = q.FailPayment(ctx, generated.FailPaymentParams{
+				ID:            pgPayment.ID,
+				FailureReason: stringToPgText(result.FailureReason),
+			})
+		}
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to resolve payment: %s", err.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pgPaymentToRepoPayment(pgPayment), nil
+}
+
+func (pr *PaymentRepository) ExpirePending(ctx context.Context) (int64, error) {
+	var expired int64
+	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		ids, err := q.ListStalePendingPaymentIDs(ctx, time.Now().Add(-pr.db.config.MPESA_PAYMENT_TIMEOUT))
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list stale payments: %s", err.Error())
+		}
+
+		for _, id := range ids {
+			if _, err := q.FailPayment(ctx, generated.FailPaymentParams{
+				ID:            id,
+				FailureReason: pgtype.Text{String: "no response from M-Pesa", Valid: true},
+			}); err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to expire payment: %s", err.Error())
+			}
+		}
+		expired = int64(len(ids))
+
+		return nil
+	})
+
+	return expired, err
+}
+
+// settlePayments sums the completed payments and the balance left on a sale.
+func settlePayments(total float64, payments []*repository.Payment) (float64, float64) {
+	var paid int64
+	for _, p := range payments {
+		if p.Status == repository.PAYMENT_COMPLETED {
+			paid += toCents(p.Amount)
+		}
+	}
+
+	return float64(paid) / 100, float64(toCents(total)-paid) / 100
+}
+
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func pgPaymentToRepoPayment(p generated.Payment) *repository.Payment {
+	return &repository.Payment{
+		ID:                uint32(p.ID),
+		SaleID:            uint32(p.SaleID),
+		Method:            p.Method,
+		Amount:            pkg.PgTypeNumericToFloat64(p.Amount),
+		Status:            p.Status,
+		Reference:         pgTextToString(p.Reference),
+		PhoneNumber:       pgTextToString(p.PhoneNumber),
+		CheckoutRequestID: pgTextToString(p.CheckoutRequestID),
+		FailureReason:     pgTextToString(p.FailureReason),
+		ReceivedBy:        uint32(p.ReceivedBy),
+		CompletedAt:       pgTimestamptzToTime(p.CompletedAt),
+		CreatedAt:         p.CreatedAt,
+		ShiftID:           pgInt8ToUint32(p.ShiftID),
+	}
+}
@@ -0,0 +1,249 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.PrescriptionRepository = (*PrescriptionRepository)(nil)
+
+type PrescriptionRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewPrescriptionRepository(db *Store) *PrescriptionRepository {
+	return &PrescriptionRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (pr *PrescriptionRepository) Create(ctx context.Context, prescription *repository.Prescription) (*repository.Prescription, error) {
+	if len(prescription.Items) == 0 {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "a prescription must have at least one item")
+	}
+
+	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		createParams := generated.CreatePrescriptionParams{
+			CustomerID:              int64(prescription.CustomerID),
+			PrescriberName:          prescription.PrescriberName,
+			PrescriberLicenceNumber: prescription.PrescriberLicenceNumber,
+			PrescribedAt:            pgtype.Date{Time: prescription.PrescribedAt, Valid: true},
+			ValidUntil:              pgtype.Date{Time: prescription.ValidUntil, Valid: true},
+			Notes:                   pgtype.Text{Valid: false},
+			CreatedBy:               int64(prescription.CreatedBy),
+		}
+		if prescription.Notes != nil {
+			createParams.Notes = pgtype.Text{String: *prescription.Notes, Valid: true}
+		}
+
+		p, err := q.CreatePrescription(ctx, createParams)
+		if err != nil {
+			if pkg.PgxErrorCode(err) == pkg.FOREIGN_KEY_VIOLATION {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "customer %d not found", prescription.CustomerID)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create prescription: %s", err.Error())
+		}
+
+		for _, item := range prescription.Items {
+			itemParams := generated.CreatePrescriptionItemParams{
+				PrescriptionID: p.ID,
+				ProductID:      int64(item.ProductID),
+				Quantity:       item.Quantity,
+				Dosage:         pgtype.Text{Valid: false},
+			}
+			if item.Dosage != nil {
+				itemParams.Dosage = pgtype.Text{String: *item.Dosage, Valid: true}
+			}
+
+			pi, err := q.CreatePrescriptionItem(ctx, itemParams)
+			if err != nil {
+				switch pkg.PgxErrorCode(err) {
+				case pkg.FOREIGN_KEY_VIOLATION:
+					return pkg.Errorf(pkg.NOT_FOUND_ERROR, "product %d not found", item.ProductID)
+				case pkg.UNIQUE_VIOLATION:
+					return pkg.Errorf(pkg.INVALID_ERROR, "product %d is listed more than once", item.ProductID)
+				}
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create prescription item: %s", err.Error())
+			}
+
+			item.ID = uint32(pi.ID)
+			item.PrescriptionID = uint32(p.ID)
+		}
+
+		prescription.ID = uint32(p.ID)
+		prescription.CreatedAt = p.CreatedAt
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pr.GetByID(ctx, int64(prescription.ID))
+}
+
+func (pr *PrescriptionRepository) GetByID(ctx context.Context, id int64) (*repository.Prescription, error) {
+	p, err := pr.queries.GetPrescriptionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "prescription with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get prescription: %s", err.Error())
+	}
+
+	items, err := pr.queries.ListPrescriptionItems(ctx, id)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list prescription items: %s", err.Error())
+	}
+
+	scans, err := pr.queries.ListPrescriptionScans(ctx, id)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list prescription scans: %s", err.Error())
+	}
+
+	prescription := &repository.Prescription{
+		ID:                      uint32(p.ID),
+		CustomerID:              uint32(p.CustomerID),
+		PrescriberName:          p.PrescriberName,
+		PrescriberLicenceNumber: p.PrescriberLicenceNumber,
+		PrescribedAt:            p.PrescribedAt.Time,
+		ValidUntil:              p.ValidUntil.Time,
+		Notes:                   pgTextToString(p.Notes),
+		CreatedBy:               uint32(p.CreatedBy),
+		CreatedAt:               p.CreatedAt,
+		Items:                   make([]*repository.PrescriptionItem, len(items)),
+		Scans:                   make([]*repository.PrescriptionScan, len(scans)),
+
+		CustomerName: p.CustomerName,
+	}
+	for i, item := range items {
+		prescription.Items[i] = &repository.PrescriptionItem{
+			ID:                uint32(item.ID),
+			PrescriptionID:    uint32(item.PrescriptionID),
+			ProductID:         uint32(item.ProductID),
+			Quantity:          item.Quantity,
+			DispensedQuantity: item.DispensedQuantity,
+			Dosage:            pgTextToString(item.Dosage),
+
+			ProductName: item.ProductName,
+		}
+	}
+	for i, scan := range scans {
+		prescription.Scans[i] = &repository.PrescriptionScan{
+			ID:             uint32(scan.ID),
+			PrescriptionID: uint32(scan.PrescriptionID),
+			FileName:       scan.FileName,
+			ContentType:    scan.ContentType,
+			UploadedBy:     uint32(scan.UploadedBy),
+			CreatedAt:      scan.CreatedAt,
+		}
+	}
+
+	return prescription, nil
+}
+
+func (pr *PrescriptionRepository) List(ctx context.Context, filter *repository.PrescriptionFilter) ([]*repository.Prescription, *pkg.Pagination, error) {
+	listParams := generated.ListPrescriptionsParams{
+		Limit:      int32(filter.Pagination.PageSize),
+		Offset:     pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+		CustomerID: pgtype.Int8{Valid: false},
+		Search:     pgtype.Text{Valid: false},
+	}
+
+	countParams := generated.ListPrescriptionsCountParams{
+		CustomerID: pgtype.Int8{Valid: false},
+		Search:     pgtype.Text{Valid: false},
+	}
+
+	if filter.CustomerID != nil {
+		listParams.CustomerID = pgtype.Int8{Int64: int64(*filter.CustomerID), Valid: true}
+		countParams.CustomerID = pgtype.Int8{Int64: int64(*filter.CustomerID), Valid: true}
+	}
+	if filter.Search != nil {
+		s := strings.ToLower(*filter.Search)
+		listParams.Search = pgtype.Text{String: "%" + s + "%", Valid: true}
+		countParams.Search = pgtype.Text{String: "%" + s + "%", Valid: true}
+	}
+
+	prescriptions, err := pr.queries.ListPrescriptions(ctx, listParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list prescriptions: %s", err.Error())
+	}
+
+	totalCount, err := pr.queries.ListPrescriptionsCount(ctx, countParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count prescriptions: %s", err.Error())
+	}
+
+	repoPrescriptions := make([]*repository.Prescription, len(prescriptions))
+	for i, p := range prescriptions {
+		repoPrescriptions[i] = &repository.Prescription{
+			ID:                      uint32(p.ID),
+			CustomerID:              uint32(p.CustomerID),
+			PrescriberName:          p.PrescriberName,
+			PrescriberLicenceNumber: p.PrescriberLicenceNumber,
+			PrescribedAt:            p.PrescribedAt.Time,
+			ValidUntil:              p.ValidUntil.Time,
+			Notes:                   pgTextToString(p.Notes),
+			CreatedBy:               uint32(p.CreatedBy),
+			CreatedAt:               p.CreatedAt,
+
+			CustomerName: p.CustomerName,
+		}
+	}
+
+	return repoPrescriptions, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
+}
+
+func (pr *PrescriptionRepository) AddScan(ctx context.Context, scan *repository.PrescriptionScan) (*repository.PrescriptionScan, error) {
+	s, err := pr.queries.CreatePrescriptionScan(ctx, generated.CreatePrescriptionScanParams{
+		PrescriptionID: int64(scan.PrescriptionID),
+		FileName:       scan.FileName,
+		ContentType:    scan.ContentType,
+		Data:           scan.Data,
+		UploadedBy:     int64(scan.UploadedBy),
+	})
+	if err != nil {
+		if pkg.PgxErrorCode(err) == pkg.FOREIGN_KEY_VIOLATION {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "prescription %d not found", scan.PrescriptionID)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to save prescription scan: %s", err.Error())
+	}
+
+	scan.ID = uint32(s.ID)
+	scan.CreatedAt = s.CreatedAt
+
+	return scan, nil
+}
+
+func (pr *PrescriptionRepository) GetScan(ctx context.Context, prescriptionID, scanID int64) (*repository.PrescriptionScan, error) {
+	s, err := pr.queries.GetPrescriptionScan(ctx, generated.GetPrescriptionScanParams{
+		ID:             scanID,
+		PrescriptionID: prescriptionID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "prescription scan not found")
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get prescription scan: %s", err.Error())
+	}
+
+	return &repository.PrescriptionScan{
+		ID:             uint32(s.ID),
+		PrescriptionID: uint32(s.PrescriptionID),
+		FileName:       s.FileName,
+		ContentType:    s.ContentType,
+		Data:           s.Data,
+		UploadedBy:     uint32(s.UploadedBy),
+		CreatedAt:      s.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+func (pr *ProductRepository) ListPriceHistory(ctx context.Context, filter *repository.PriceHistoryFilter) ([]*repository.PriceChange, *pkg.Pagination, error) {
+	changes, err := pr.queries.ListPriceHistory(ctx, generated.ListPriceHistoryParams{
+		ProductID: int64(filter.ProductID),
+		Limit:     int32(filter.Pagination.PageSize),
+		Offset:    pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+	})
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list price history: %s", err.Error())
+	}
+
+	totalCount, err := pr.queries.ListPriceHistoryCount(ctx, int64(filter.ProductID))
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count price history: %s", err.Error())
+	}
+
+	repoChanges := make([]*repository.PriceChange, len(changes))
+	for i, c := range changes {
+		repoChanges[i] = &repository.PriceChange{
+			ID:          uint32(c.ID),
+			ProductID:   uint32(c.ProductID),
+			OldPrice:    pkg.PgTypeNumericToFloat64(c.OldPrice),
+			NewPrice:    pkg.PgTypeNumericToFloat64(c.NewPrice),
+			EffectiveAt: c.EffectiveAt,
+			Applied:     c.Applied,
+			AppliedAt:   pgTimestamptzToTime(c.AppliedAt),
+			Note:        pgTextToString(c.Note),
+			ChangedBy:   uint32(c.ChangedBy),
+			CreatedAt:   c.CreatedAt,
+
+			UserName: c.UserName,
+		}
+	}
+
+	return repoChanges, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
+}
+
+// ApplyDuePriceChanges sets the price of every product whose scheduled price change
+// has reached its effective date and returns the number of changes applied. Changes
+// for products deleted in the meantime are marked applied without updating the product.
+func (pr *ProductRepository) ApplyDuePriceChanges(ctx context.Context) (int, error) {
+	applied := 0
+	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		changes, err := q.ListDuePriceChanges(ctx)
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list due price changes: %s", err.Error())
+		}
+
+		for _, c := range changes {
+			current, err := q.GetProductByID(ctx, c.ProductID)
+			if err != nil {
+				if !errors.Is(err, sql.ErrNoRows) {
+					return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get product %d: %s", c.ProductID, err.Error())
+				}
+
+				// product was deleted after the change was scheduled, so the change is
+				// retired without touching the deleted row
+				err = q.MarkPriceChangeApplied(ctx, generated.MarkPriceChangeAppliedParams{
+					ID:       c.ID,
+					OldPrice: c.OldPrice,
+				})
+				if err != nil {
+					return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to mark price change applied: %s", err.Error())
+				}
+				continue
+			}
+
+			_, err = q.UpdateProduct(ctx, generated.UpdateProductParams{
+				ID:    c.ProductID,
+				Price: c.NewPrice,
+			})
+			if err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update product price: %s", err.Error())
+			}
+
+			// old price is the one in force when the change is applied, not when it was scheduled
+			err = q.MarkPriceChangeApplied(ctx, generated.MarkPriceChangeAppliedParams{
+				ID:       c.ID,
+				OldPrice: current.Price,
+			})
+			if err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to mark price change applied: %s", err.Error())
+			}
+			applied++
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
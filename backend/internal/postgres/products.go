@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
 	"github.com/EmilioCliff/jonche-med/internal/repository"
@@ -16,28 +17,60 @@ import (
 var _ repository.ProductRepository = (*ProductRepository)(nil)
 
 type ProductRepository struct {
-	queries *generated.Queries
-	db      *Store
+	queries    *generated.Queries
+	db         *Store
+	valuations *valuationCache
 }
 
 func NewProductRepository(db *Store) *ProductRepository {
 	return &ProductRepository{
-		db:      db,
-		queries: generated.New(db.pool),
+		db:         db,
+		queries:    generated.New(db.pool),
+		valuations: &valuationCache{},
 	}
 }
 
 func (pr *ProductRepository) Create(ctx context.Context, product *repository.Product) (*repository.Product, error) {
+	if product.Stock > 0 && product.OpeningUnitCost <= 0 {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "a unit cost is required for the opening stock")
+	}
+
 	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		category, err := resolveCategoryTx(ctx, q, product.CategoryID, product.Category)
+		if err != nil {
+			return err
+		}
+		categoryID := uint32(category.ID)
+		product.Category = category.Name
+		product.CategoryID = &categoryID
+
+		taxClass, err := productTaxClassTx(ctx, q, product.TaxClassID)
+		if err != nil {
+			return err
+		}
+		product.TaxClassID = uint32(taxClass.ID)
+
 		// create product
 		createParams := generated.CreateProductParams{
 			Name:              product.Name,
 			Description:       pgtype.Text{Valid: false},
 			Price:             pkg.Float64ToPgTypeNumeric(product.Price),
 			Stock:             product.Stock,
+			OpeningUnitCost:   pkg.Float64ToPgTypeNumeric(product.OpeningUnitCost),
 			Category:          product.Category,
 			Unit:              product.Unit,
 			LowStockThreshold: product.LowStockThreshold,
+			PrescriptionOnly:  product.PrescriptionOnly,
+			Controlled:        product.Controlled,
+			CategoryID:        pgtype.Int8{Int64: category.ID, Valid: true},
+			GenericName:       stringToPgText(product.GenericName),
+			Strength:          stringToPgText(product.Strength),
+			DosageForm:        stringToPgText(product.DosageForm),
+			Route:             stringToPgText(product.Route),
+			Manufacturer:      stringToPgText(product.Manufacturer),
+			PackSize:          int32ToPgInt4(product.PackSize),
+			TherapeuticClass:  stringToPgText(product.TherapeuticClass),
+			TaxClassID:        taxClass.ID,
 		}
 
 		if product.Description != "" {
@@ -64,7 +97,6 @@ func (pr *ProductRepository) Create(ctx context.Context, product *repository.Pro
 			newTotalOutOfStock       *int64
 			newTotalStocksAdded      *int64
 			newTotalStocksAddedValue *float64
-			newTotalValue            *float64
 		)
 
 		// Only update fields if conditions are met
@@ -80,8 +112,7 @@ func (pr *ProductRepository) Create(ctx context.Context, product *repository.Pro
 			val := stats.TotalStocksAdded + p.Stock
 			newTotalStocksAdded = &val
 
-			valF := pkg.PgTypeNumericToFloat64(stats.TotalValue) + float64(p.Stock)*pkg.PgTypeNumericToFloat64(p.Price)
-			newTotalValue = &valF
+			valF := pkg.PgTypeNumericToFloat64(stats.TotalStocksAddedValue) + float64(p.Stock)*pkg.PgTypeNumericToFloat64(p.OpeningUnitCost)
 			newTotalStocksAddedValue = &valF
 		}
 
@@ -95,7 +126,7 @@ func (pr *ProductRepository) Create(ctx context.Context, product *repository.Pro
 			newTotalStocksAddedValue,
 			nil, // totalStocksRemoved
 			nil, // totalStocksRemovedValue
-			newTotalValue,
+			nil, // totalValue
 		)
 		if err != nil {
 			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update stats: %s", err.Error())
@@ -115,7 +146,43 @@ func (pr *ProductRepository) GetByID(ctx context.Context, id int64) (*repository
 		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get product: %s", err.Error())
 	}
 
-	return pgProductToRepoProduct(p), nil
+	product := pgProductToRepoProduct(p)
+	if err := pr.addKitAvailability(ctx, []*repository.Product{product}, true); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (pr *ProductRepository) ListSubstitutes(ctx context.Context, id int64, limit int32) ([]*repository.Product, error) {
+	if _, err := pr.queries.GetProductByID(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "product not found")
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get product: %s", err.Error())
+	}
+
+	return listSubstitutesTx(ctx, pr.queries, id, limit)
+}
+
+// substituteSuggestionLimit caps the substitutes offered when stock is insufficient.
+const substituteSuggestionLimit = 5
+
+func listSubstitutesTx(ctx context.Context, q *generated.Queries, id int64, limit int32) ([]*repository.Product, error) {
+	pgProducts, err := q.ListProductSubstitutes(ctx, generated.ListProductSubstitutesParams{
+		ProductID: id,
+		Limit:     limit,
+	})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list product substitutes: %s", err.Error())
+	}
+
+	substitutes := make([]*repository.Product, len(pgProducts))
+	for i, p := range pgProducts {
+		substitutes[i] = pgProductToRepoProduct(p)
+	}
+
+	return substitutes, nil
 }
 
 func (pr *ProductRepository) Update(ctx context.Context, id int64, productUpdate *repository.ProductUpdate) (*repository.Product, error) {
@@ -125,8 +192,19 @@ func (pr *ProductRepository) Update(ctx context.Context, id int64, productUpdate
 		Description:       pgtype.Text{Valid: false},
 		Price:             pgtype.Numeric{Valid: false},
 		Category:          pgtype.Text{Valid: false},
+		CategoryID:        pgtype.Int8{Valid: false},
 		Unit:              pgtype.Text{Valid: false},
 		LowStockThreshold: pgtype.Int4{Valid: false},
+		PrescriptionOnly:  pgtype.Bool{Valid: false},
+		Controlled:        pgtype.Bool{Valid: false},
+		GenericName:       stringToPgText(productUpdate.GenericName),
+		Strength:          stringToPgText(productUpdate.Strength),
+		DosageForm:        stringToPgText(productUpdate.DosageForm),
+		Route:             stringToPgText(productUpdate.Route),
+		Manufacturer:      stringToPgText(productUpdate.Manufacturer),
+		PackSize:          int32ToPgInt4(productUpdate.PackSize),
+		TherapeuticClass:  stringToPgText(productUpdate.TherapeuticClass),
+		TaxClassID:        pgtype.Int8{Valid: false},
 	}
 	if productUpdate.Name != nil {
 		updateParams.Name = pgtype.Text{String: *productUpdate.Name, Valid: true}
@@ -134,25 +212,101 @@ func (pr *ProductRepository) Update(ctx context.Context, id int64, productUpdate
 	if productUpdate.Description != nil {
 		updateParams.Description = pgtype.Text{String: *productUpdate.Description, Valid: true}
 	}
-	if productUpdate.Price != nil {
-		updateParams.Price = pkg.Float64ToPgTypeNumeric(*productUpdate.Price)
-	}
-	if productUpdate.Category != nil {
-		updateParams.Category = pgtype.Text{String: *productUpdate.Category, Valid: true}
-	}
 	if productUpdate.Unit != nil {
 		updateParams.Unit = pgtype.Text{String: *productUpdate.Unit, Valid: true}
 	}
 	if productUpdate.LowStockThreshold != nil {
 		updateParams.LowStockThreshold = pgtype.Int4{Int32: *productUpdate.LowStockThreshold, Valid: true}
 	}
-
-	p, err := pr.queries.UpdateProduct(ctx, updateParams)
-	if err != nil {
-		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update product: %s", err.Error())
+	if productUpdate.PrescriptionOnly != nil {
+		updateParams.PrescriptionOnly = pgtype.Bool{Bool: *productUpdate.PrescriptionOnly, Valid: true}
+	}
+	if productUpdate.Controlled != nil {
+		updateParams.Controlled = pgtype.Bool{Bool: *productUpdate.Controlled, Valid: true}
 	}
 
-	return pgProductToRepoProduct(p), nil
+	var product *repository.Product
+	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		if productUpdate.Category != nil || productUpdate.CategoryID != nil {
+			var name string
+			if productUpdate.Category != nil {
+				name = *productUpdate.Category
+			}
+			category, err := resolveCategoryTx(ctx, q, productUpdate.CategoryID, name)
+			if err != nil {
+				return err
+			}
+			updateParams.Category = pgtype.Text{String: category.Name, Valid: true}
+			updateParams.CategoryID = pgtype.Int8{Int64: category.ID, Valid: true}
+		}
+
+		// kits are sold without a prescription for their components, see SetKitComponents
+		if productUpdate.PrescriptionOnly != nil && *productUpdate.PrescriptionOnly {
+			used, err := q.CountKitsUsingComponent(ctx, id)
+			if err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to check kit usage: %s", err.Error())
+			}
+			if used > 0 {
+				return pkg.Errorf(pkg.INVALID_ERROR, "product %d is a kit component and cannot be prescription-only", id)
+			}
+		}
+
+		if productUpdate.TaxClassID != nil {
+			taxClass, err := getTaxClassTx(ctx, q, int64(*productUpdate.TaxClassID))
+			if err != nil {
+				return err
+			}
+			updateParams.TaxClassID = pgtype.Int8{Int64: taxClass.ID, Valid: true}
+		}
+
+		if productUpdate.Price != nil {
+			current, err := q.GetProductByID(ctx, id)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return pkg.Errorf(pkg.NOT_FOUND_ERROR, "product not found")
+				}
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get product: %s", err.Error())
+			}
+
+			now := time.Now()
+			priceParams := generated.CreatePriceChangeParams{
+				ProductID:   id,
+				OldPrice:    current.Price,
+				NewPrice:    pkg.Float64ToPgTypeNumeric(*productUpdate.Price),
+				EffectiveAt: now,
+				Applied:     true,
+				AppliedAt:   pgtype.Timestamptz{Time: now, Valid: true},
+				Note:        pgtype.Text{Valid: false},
+				ChangedBy:   int64(productUpdate.UpdatedBy),
+			}
+			if productUpdate.PriceNote != nil {
+				priceParams.Note = pgtype.Text{String: *productUpdate.PriceNote, Valid: true}
+			}
+
+			// scheduled changes are applied later by ApplyDuePriceChanges
+			if productUpdate.PriceEffectiveAt != nil && productUpdate.PriceEffectiveAt.After(now) {
+				priceParams.EffectiveAt = *productUpdate.PriceEffectiveAt
+				priceParams.Applied = false
+				priceParams.AppliedAt = pgtype.Timestamptz{Valid: false}
+			} else {
+				updateParams.Price = priceParams.NewPrice
+			}
+
+			if _, err := q.CreatePriceChange(ctx, priceParams); err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to record price change: %s", err.Error())
+			}
+		}
+
+		p, err := q.UpdateProduct(ctx, updateParams)
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update product: %s", err.Error())
+		}
+		product = pgProductToRepoProduct(p)
+
+		return nil
+	})
+
+	return product, err
 }
 
 func (pr *ProductRepository) Delete(ctx context.Context, id int64) error {
@@ -192,16 +346,91 @@ func (pr *ProductRepository) Delete(ctx context.Context, id int64) error {
 	return err
 }
 
+func (pr *ProductRepository) SetReorderPolicy(ctx context.Context, id int64, policy *repository.ReorderPolicy) (*repository.Product, error) {
+	if (policy.ReorderPoint == nil) != (policy.ReorderQuantity == nil) {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "reorder point and reorder quantity must be set together")
+	}
+	if policy.ReorderPoint != nil && policy.PreferredSupplierID == nil {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "a preferred supplier is required to reorder a product")
+	}
+
+	params := generated.SetProductReorderPolicyParams{
+		ID:                  id,
+		ReorderPoint:        int32ToPgInt4(policy.ReorderPoint),
+		ReorderQuantity:     int32ToPgInt4(policy.ReorderQuantity),
+		PreferredSupplierID: pgtype.Int8{Valid: false},
+	}
+
+	var product *repository.Product
+	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		current, err := q.GetProductByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "product not found")
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get product: %s", err.Error())
+		}
+		// kits are assembled from their components rather than bought
+		if current.IsKit && policy.ReorderPoint != nil {
+			return pkg.Errorf(pkg.INVALID_ERROR, "kits cannot be reordered; set reorder points on their components")
+		}
+
+		if policy.PreferredSupplierID != nil {
+			supplier, err := q.GetSupplierByID(ctx, int64(*policy.PreferredSupplierID))
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return pkg.Errorf(pkg.NOT_FOUND_ERROR, "supplier %d not found", *policy.PreferredSupplierID)
+				}
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get supplier: %s", err.Error())
+			}
+			params.PreferredSupplierID = pgtype.Int8{Int64: supplier.ID, Valid: true}
+		}
+
+		p, err := q.SetProductReorderPolicy(ctx, params)
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to set reorder policy: %s", err.Error())
+		}
+		product = pgProductToRepoProduct(p)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
 func (pr *ProductRepository) List(ctx context.Context, filter *repository.ProductFilter) ([]*repository.Product, *pkg.Pagination, error) {
 	listParams := generated.ListProductsParams{
-		Limit:   int32(filter.Pagination.PageSize),
-		Offset:  pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
-		Search:  pgtype.Text{Valid: false},
-		InStock: pgtype.Bool{Valid: false},
+		Limit:      int32(filter.Pagination.PageSize),
+		Offset:     pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+		Search:     pgtype.Text{Valid: false},
+		InStock:    pgtype.Bool{Valid: false},
+		CategoryID: pgtype.Int8{Valid: false},
+
+		GenericName:      stringToPgText(filter.GenericName),
+		Strength:         stringToPgText(filter.Strength),
+		DosageForm:       stringToPgText(filter.DosageForm),
+		Route:            stringToPgText(filter.Route),
+		Manufacturer:     stringToPgText(filter.Manufacturer),
+		TherapeuticClass: stringToPgText(filter.TherapeuticClass),
+		AbcClass:         stringToPgText(filter.ABCClass),
+		XyzClass:         stringToPgText(filter.XYZClass),
 	}
 	countParams := generated.ListProductsCountParams{
-		Search:  pgtype.Text{Valid: false},
-		InStock: pgtype.Bool{Valid: false},
+		Search:     pgtype.Text{Valid: false},
+		InStock:    pgtype.Bool{Valid: false},
+		CategoryID: pgtype.Int8{Valid: false},
+
+		GenericName:      listParams.GenericName,
+		Strength:         listParams.Strength,
+		DosageForm:       listParams.DosageForm,
+		Route:            listParams.Route,
+		Manufacturer:     listParams.Manufacturer,
+		TherapeuticClass: listParams.TherapeuticClass,
+		AbcClass:         listParams.AbcClass,
+		XyzClass:         listParams.XyzClass,
 	}
 
 	if filter.Search != nil {
@@ -218,6 +447,10 @@ func (pr *ProductRepository) List(ctx context.Context, filter *repository.Produc
 			countParams.InStock = pgtype.Bool{Bool: false, Valid: true}
 		}
 	}
+	if filter.CategoryID != nil {
+		listParams.CategoryID = pgtype.Int8{Int64: int64(*filter.CategoryID), Valid: true}
+		countParams.CategoryID = pgtype.Int8{Int64: int64(*filter.CategoryID), Valid: true}
+	}
 
 	products, err := pr.queries.ListProducts(ctx, listParams)
 	if err != nil {
@@ -233,6 +466,9 @@ func (pr *ProductRepository) List(ctx context.Context, filter *repository.Produc
 	for i, p := range products {
 		repoProducts[i] = pgProductToRepoProduct(p)
 	}
+	if err := pr.addKitAvailability(ctx, repoProducts, false); err != nil {
+		return nil, nil, err
+	}
 
 	return repoProducts, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
 }
@@ -240,178 +476,332 @@ func (pr *ProductRepository) List(ctx context.Context, filter *repository.Produc
 func (pr *ProductRepository) AddStock(ctx context.Context, data *repository.ProductStockUpdate) (*repository.Product, error) {
 	var product *repository.Product
 	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
-		// add stock
-		p, err := q.AddStock(ctx, generated.AddStockParams{
-			ID:       int64(data.ID),
-			Quantity: data.Quantity,
-		})
+		p, _, err := addStockTx(ctx, q, data)
 		if err != nil {
-			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to add stock: %s", err.Error())
+			return err
 		}
 		product = pgProductToRepoProduct(p)
 
-		// create movement
-		movementParam := generated.CreateMovementParams{
-			ProductID:   int64(data.ID),
-			Quantity:    int32(data.Quantity),
-			Price:       p.Price,
-			Type:        repository.MOVEMENT_ADD,
-			BatchNumber: pgtype.Text{Valid: false},
-			Note:        pgtype.Text{Valid: false},
-			PerformedBy: int64(data.PerformedBy),
-		}
-		if data.Note != nil {
-			movementParam.Note = pgtype.Text{String: *data.Note, Valid: true}
-		}
-		if data.BatchNumber != nil {
-			movementParam.BatchNumber = pgtype.Text{String: *data.BatchNumber, Valid: true}
-		}
+		return nil
+	})
+	return product, err
+}
 
-		_, err = q.CreateMovement(ctx, movementParam)
-		if err != nil {
-			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create movement: %s", err.Error())
+// addStockTx adds stock, records the ADD movement and updates stats using the
+// queries of an already open transaction.
+func addStockTx(ctx context.Context, q *generated.Queries, data *repository.ProductStockUpdate) (generated.Product, generated.Movement, error) {
+	// add stock
+	p, err := q.AddStock(ctx, generated.AddStockParams{
+		ID:       int64(data.ID),
+		Quantity: data.Quantity,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return p, generated.Movement{}, pkg.Errorf(pkg.NOT_FOUND_ERROR, "product %d not found", data.ID)
 		}
+		return p, generated.Movement{}, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to add stock: %s", err.Error())
+	}
 
-		// update stats
-		stats, err := q.GetStats(ctx)
+	if err := checkWitness(p, data); err != nil {
+		return p, generated.Movement{}, err
+	}
+
+	// stock received without a cost is costed like the last receipt
+	var unitCost pgtype.Numeric
+	if data.UnitCost != nil {
+		unitCost = pkg.Float64ToPgTypeNumeric(*data.UnitCost)
+	} else {
+		cost, ok, err := lastUnitCostTx(ctx, q, p)
 		if err != nil {
-			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get stats: %s", err.Error())
+			return p, generated.Movement{}, err
+		}
+		if !ok {
+			return p, generated.Movement{}, pkg.Errorf(pkg.INVALID_ERROR, "a unit cost is required, %s has no purchase cost yet", p.Name)
 		}
+		unitCost = cost
+	}
 
-		var (
-			newTotalLowStock         *int64
-			newTotalOutOfStock       *int64
-			newTotalStocksAdded      *int64
-			newTotalStocksAddedValue *float64
-			newTotalValue            *float64
-		)
+	// create movement
+	movementParam := generated.CreateMovementParams{
+		ProductID:   int64(data.ID),
+		Quantity:    int32(data.Quantity),
+		Price:       p.Price,
+		Type:        repository.MOVEMENT_ADD,
+		BatchNumber: pgtype.Text{Valid: false},
+		Note:        pgtype.Text{Valid: false},
+		PerformedBy: int64(data.PerformedBy),
+		UnitCost:    unitCost,
+		SaleID:      pgtype.Int8{Valid: false},
+		CustomerID:  pgtype.Int8{Valid: false},
+		WitnessedBy: pgtype.Int8{Valid: false},
+		Reason:      pgtype.Text{Valid: false},
+	}
+	if data.Note != nil {
+		movementParam.Note = pgtype.Text{String: *data.Note, Valid: true}
+	}
+	if data.BatchNumber != nil {
+		movementParam.BatchNumber = pgtype.Text{String: *data.BatchNumber, Valid: true}
+	}
+	if data.WitnessedBy != nil {
+		movementParam.WitnessedBy = pgtype.Int8{Int64: int64(*data.WitnessedBy), Valid: true}
+	}
+	if data.CustomerID != nil {
+		movementParam.CustomerID = pgtype.Int8{Int64: int64(*data.CustomerID), Valid: true}
+	}
+	if data.Reason != nil {
+		movementParam.Reason = pgtype.Text{String: *data.Reason, Valid: true}
+	}
 
-		if p.Stock-data.Quantity <= 0 && p.Stock > 0 {
-			val := stats.TotalOutOfStock - 1
-			newTotalOutOfStock = &val
-		}
-		if p.Stock-data.Quantity > 0 && p.Stock-data.Quantity <= int64(p.LowStockThreshold) && p.Stock > int64(p.LowStockThreshold) {
-			val := stats.TotalLowStock - 1
-			newTotalLowStock = &val
+	movement, err := q.CreateMovement(ctx, movementParam)
+	if err != nil {
+		return p, movement, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create movement: %s", err.Error())
+	}
+
+	if p.Controlled {
+		if err := recordControlledMovementTx(ctx, q, p, movement); err != nil {
+			return p, movement, err
 		}
+	}
 
-		val := stats.TotalStocksAdded + data.Quantity
-		newTotalStocksAdded = &val
+	// update stats
+	stats, err := q.GetStats(ctx)
+	if err != nil {
+		return p, movement, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get stats: %s", err.Error())
+	}
 
-		valFA := pkg.PgTypeNumericToFloat64(stats.TotalStocksAddedValue) + float64(data.Quantity)*pkg.PgTypeNumericToFloat64(p.Price)
-		newTotalStocksAddedValue = &valFA
+	var (
+		newTotalLowStock         *int64
+		newTotalOutOfStock       *int64
+		newTotalStocksAdded      *int64
+		newTotalStocksAddedValue *float64
+	)
 
-		valF := pkg.PgTypeNumericToFloat64(stats.TotalValue) + float64(data.Quantity)*pkg.PgTypeNumericToFloat64(p.Price)
-		newTotalValue = &valF
+	if p.Stock-data.Quantity <= 0 && p.Stock > 0 {
+		val := stats.TotalOutOfStock - 1
+		newTotalOutOfStock = &val
+	}
+	if p.Stock-data.Quantity > 0 && p.Stock-data.Quantity <= int64(p.LowStockThreshold) && p.Stock > int64(p.LowStockThreshold) {
+		val := stats.TotalLowStock - 1
+		newTotalLowStock = &val
+	}
 
-		err = updateStatsHelper(
-			ctx, q,
-			nil, // totalUsers
-			nil, // totalProducts
-			newTotalLowStock,
-			newTotalOutOfStock,
-			newTotalStocksAdded,
-			newTotalStocksAddedValue,
-			nil, // totalStocksRemoved
-			nil, // totalStocksRemovedValue
-			newTotalValue,
-		)
-		if err != nil {
-			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update stats: %s", err.Error())
-		}
+	val := stats.TotalStocksAdded + data.Quantity
+	newTotalStocksAdded = &val
+
+	valFA := pkg.PgTypeNumericToFloat64(stats.TotalStocksAddedValue) + float64(data.Quantity)*pkg.PgTypeNumericToFloat64(unitCost)
+	newTotalStocksAddedValue = &valFA
+
+	err = updateStatsHelper(
+		ctx, q,
+		nil, // totalUsers
+		nil, // totalProducts
+		newTotalLowStock,
+		newTotalOutOfStock,
+		newTotalStocksAdded,
+		newTotalStocksAddedValue,
+		nil, // totalStocksRemoved
+		nil, // totalStocksRemovedValue
+		nil, // totalValue
+	)
+	if err != nil {
+		return p, movement, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update stats: %s", err.Error())
+	}
 
-		return nil
-	})
-	return product, err
+	return p, movement, nil
 }
 
 func (pr *ProductRepository) RemoveStock(ctx context.Context, data *repository.ProductStockUpdate) (*repository.Product, error) {
 	var product *repository.Product
 	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
-		// remove stock
-		p, err := q.RemoveStock(ctx, generated.RemoveStockParams{
-			ID:       int64(data.ID),
-			Quantity: data.Quantity,
-		})
+		p, _, err := removeStockTx(ctx, q, data)
 		if err != nil {
-			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to remove stock: %s", err.Error())
+			return err
 		}
 		product = pgProductToRepoProduct(p)
 
-		if p.Stock < 0 {
-			return pkg.Errorf(pkg.INVALID_ERROR, "not enough stock to remove")
-		}
+		return nil
+	})
+	return product, err
+}
 
-		// create movement
-		movementParam := generated.CreateMovementParams{
-			ProductID:   int64(data.ID),
-			Quantity:    int32(data.Quantity),
-			Price:       p.Price,
-			Type:        repository.MOVEMENT_REMOVE,
-			BatchNumber: pgtype.Text{Valid: false},
-			Note:        pgtype.Text{Valid: false},
-			PerformedBy: int64(data.PerformedBy),
-		}
-		if data.Note != nil {
-			movementParam.Note = pgtype.Text{String: *data.Note, Valid: true}
+// checkWitness makes sure movements of controlled products are confirmed by a
+// second user.
+func checkWitness(p generated.Product, data *repository.ProductStockUpdate) error {
+	if !p.Controlled {
+		return nil
+	}
+	if data.WitnessedBy == nil {
+		return pkg.Errorf(pkg.INVALID_ERROR, "%s is a controlled drug and requires a witness", p.Name)
+	}
+	if *data.WitnessedBy == data.PerformedBy {
+		return pkg.Errorf(pkg.INVALID_ERROR, "the witness must be a different user")
+	}
+
+	return nil
+}
+
+// removeStockTx removes stock, records the REMOVE movement and updates stats using
+// the queries of an already open transaction.
+func removeStockTx(ctx context.Context, q *generated.Queries, data *repository.ProductStockUpdate) (generated.Product, generated.Movement, error) {
+	// remove stock
+	p, err := q.RemoveStock(ctx, generated.RemoveStockParams{
+		ID:       int64(data.ID),
+		Quantity: data.Quantity,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return p, generated.Movement{}, pkg.Errorf(pkg.NOT_FOUND_ERROR, "product %d not found", data.ID)
 		}
+		return p, generated.Movement{}, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to remove stock: %s", err.Error())
+	}
 
-		_, err = q.CreateMovement(ctx, movementParam)
+	// stock held by reservations can only leave through the reservation
+	if p.Stock < p.ReservedStock {
+		substitutes, err := listSubstitutesTx(ctx, q, int64(data.ID), substituteSuggestionLimit)
 		if err != nil {
-			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create movement: %s", err.Error())
+			return p, generated.Movement{}, err
 		}
 
-		// update stats
-		stats, err := q.GetStats(ctx)
+		return p, generated.Movement{}, pkg.Errorf(pkg.INVALID_ERROR, "not enough stock to remove for %s", p.Name).WithDetails(&repository.InsufficientStock{
+			ProductID:      uint32(p.ID),
+			AvailableStock: p.Stock + data.Quantity - p.ReservedStock,
+			Substitutes:    substitutes,
+		})
+	}
+
+	if err := checkWitness(p, data); err != nil {
+		return p, generated.Movement{}, err
+	}
+
+	// issues record the last receipt cost so returned stock goes back in at cost
+	unitCost, _, err := lastUnitCostTx(ctx, q, p)
+	if err != nil {
+		return p, generated.Movement{}, err
+	}
+
+	// create movement
+	movementParam := generated.CreateMovementParams{
+		ProductID:      int64(data.ID),
+		Quantity:       int32(data.Quantity),
+		Price:          p.Price,
+		Type:           repository.MOVEMENT_REMOVE,
+		BatchNumber:    pgtype.Text{Valid: false},
+		Note:           pgtype.Text{Valid: false},
+		PerformedBy:    int64(data.PerformedBy),
+		UnitCost:       unitCost,
+		SaleID:         pgtype.Int8{Valid: false},
+		CustomerID:     pgtype.Int8{Valid: false},
+		PrescriptionID: pgtype.Int8{Valid: false},
+		WitnessedBy:    pgtype.Int8{Valid: false},
+		Reason:         pgtype.Text{Valid: false},
+	}
+	if data.Note != nil {
+		movementParam.Note = pgtype.Text{String: *data.Note, Valid: true}
+	}
+	if data.BatchNumber != nil {
+		movementParam.BatchNumber = pgtype.Text{String: *data.BatchNumber, Valid: true}
+	}
+	if data.SaleID != nil {
+		movementParam.SaleID = pgtype.Int8{Int64: int64(*data.SaleID), Valid: true}
+	}
+	if data.CustomerID != nil {
+		movementParam.CustomerID = pgtype.Int8{Int64: int64(*data.CustomerID), Valid: true}
+	}
+	if data.WitnessedBy != nil {
+		movementParam.WitnessedBy = pgtype.Int8{Int64: int64(*data.WitnessedBy), Valid: true}
+	}
+	if data.Reason != nil {
+		movementParam.Reason = pgtype.Text{String: *data.Reason, Valid: true}
+	}
+
+	// prescription-only medicines can only be dispensed against a valid prescription;
+	// stock going back to the supplier, written off or quarantined is not dispensed
+	notDispensed := data.Reason != nil && (*data.Reason == repository.MOVEMENT_REASON_SUPPLIER_RETURN ||
+		*data.Reason == repository.MOVEMENT_REASON_WRITE_OFF ||
+		*data.Reason == repository.MOVEMENT_REASON_QUARANTINE)
+	if p.PrescriptionOnly && data.PrescriptionID == nil && !notDispensed {
+		return p, generated.Movement{}, pkg.Errorf(pkg.INVALID_ERROR, "%s is prescription-only and requires a prescription", p.Name)
+	}
+	if data.PrescriptionID != nil {
+		item, err := q.DispensePrescriptionItem(ctx, generated.DispensePrescriptionItemParams{
+			Quantity:       int32(data.Quantity),
+			PrescriptionID: int64(*data.PrescriptionID),
+			ProductID:      int64(data.ID),
+		})
 		if err != nil {
-			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get stats: %s", err.Error())
+			if errors.Is(err, sql.ErrNoRows) {
+				return p, generated.Movement{}, pkg.Errorf(pkg.INVALID_ERROR, "prescription %d is not valid for %d of %s", *data.PrescriptionID, data.Quantity, p.Name)
+			}
+			return p, generated.Movement{}, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to dispense prescription item: %s", err.Error())
 		}
 
-		var (
-			newTotalLowStock           *int64
-			newTotalOutOfStock         *int64
-			newTotalStocksRemoved      *int64
-			newTotalStocksRemovedValue *float64
-			newTotalValue              *float64
-		)
+		if data.CustomerID != nil && int64(*data.CustomerID) != item.CustomerID {
+			return p, generated.Movement{}, pkg.Errorf(pkg.INVALID_ERROR, "prescription %d was issued to a different customer", *data.PrescriptionID)
+		}
 
-		if p.Stock+data.Quantity > 0 && p.Stock <= 0 {
-			val := stats.TotalOutOfStock + 1
-			newTotalOutOfStock = &val
+		movementParam.PrescriptionID = pgtype.Int8{Int64: int64(*data.PrescriptionID), Valid: true}
+		movementParam.CustomerID = pgtype.Int8{Int64: item.CustomerID, Valid: true}
+	}
+
+	movement, err := q.CreateMovement(ctx, movementParam)
+	if err != nil {
+		if pkg.PgxErrorCode(err) == pkg.FOREIGN_KEY_VIOLATION && data.CustomerID != nil {
+			return p, movement, pkg.Errorf(pkg.NOT_FOUND_ERROR, "customer %d not found", *data.CustomerID)
 		}
-		if p.Stock+data.Quantity > int64(p.LowStockThreshold) && p.Stock <= int64(p.LowStockThreshold) && p.Stock > 0 {
-			val := stats.TotalLowStock + 1
-			newTotalLowStock = &val
+		return p, movement, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create movement: %s", err.Error())
+	}
+
+	if p.Controlled {
+		if err := recordControlledMovementTx(ctx, q, p, movement); err != nil {
+			return p, movement, err
 		}
+	}
 
-		val := stats.TotalStocksRemoved + data.Quantity
-		newTotalStocksRemoved = &val
+	// update stats
+	stats, err := q.GetStats(ctx)
+	if err != nil {
+		return p, movement, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get stats: %s", err.Error())
+	}
 
-		valFA := pkg.PgTypeNumericToFloat64(stats.TotalStocksRemovedValue) + float64(data.Quantity)*pkg.PgTypeNumericToFloat64(p.Price)
-		newTotalStocksRemovedValue = &valFA
+	var (
+		newTotalLowStock           *int64
+		newTotalOutOfStock         *int64
+		newTotalStocksRemoved      *int64
+		newTotalStocksRemovedValue *float64
+	)
 
-		valF := pkg.PgTypeNumericToFloat64(stats.TotalValue) - float64(data.Quantity)*pkg.PgTypeNumericToFloat64(p.Price)
-		newTotalValue = &valF
+	if p.Stock+data.Quantity > 0 && p.Stock <= 0 {
+		val := stats.TotalOutOfStock + 1
+		newTotalOutOfStock = &val
+	}
+	if p.Stock+data.Quantity > int64(p.LowStockThreshold) && p.Stock <= int64(p.LowStockThreshold) && p.Stock > 0 {
+		val := stats.TotalLowStock + 1
+		newTotalLowStock = &val
+	}
 
-		err = updateStatsHelper(
-			ctx, q,
-			nil, // totalUsers
-			nil, // totalProducts
-			newTotalLowStock,
-			newTotalOutOfStock,
-			nil, // totalStocksAdded
-			nil, // totalStocksAddedValue
-			newTotalStocksRemoved,
-			newTotalStocksRemovedValue,
-			newTotalValue,
-		)
-		if err != nil {
-			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update stats: %s", err.Error())
-		}
+	val := stats.TotalStocksRemoved + data.Quantity
+	newTotalStocksRemoved = &val
+
+	valFA := pkg.PgTypeNumericToFloat64(stats.TotalStocksRemovedValue) + float64(data.Quantity)*pkg.PgTypeNumericToFloat64(p.Price)
+	newTotalStocksRemovedValue = &valFA
+
+	err = updateStatsHelper(
+		ctx, q,
+		nil, // totalUsers
+		nil, // totalProducts
+		newTotalLowStock,
+		newTotalOutOfStock,
+		nil, // totalStocksAdded
+		nil, // totalStocksAddedValue
+		newTotalStocksRemoved,
+		newTotalStocksRemovedValue,
+		nil, // totalValue
+	)
+	if err != nil {
+		return p, movement, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update stats: %s", err.Error())
+	}
 
-		return nil
-	})
-	return product, err
+	return p, movement, nil
 }
 
 func (pr *ProductRepository) ListMovements(ctx context.Context, filter *repository.MovementFilter) ([]*repository.Movement, *pkg.Pagination, error) {
@@ -444,6 +834,10 @@ func (pr *ProductRepository) ListMovements(ctx context.Context, filter *reposito
 		listParams.BatchNumber = pgtype.Text{String: *filter.BatchNumber, Valid: true}
 		countParams.BatchNumber = pgtype.Text{String: *filter.BatchNumber, Valid: true}
 	}
+	if filter.CustomerID != nil {
+		listParams.CustomerID = pgtype.Int8{Int64: int64(*filter.CustomerID), Valid: true}
+		countParams.CustomerID = pgtype.Int8{Int64: int64(*filter.CustomerID), Valid: true}
+	}
 	if filter.StartDate != nil && filter.EndDate != nil {
 		listParams.StartDate = pgtype.Timestamptz{Time: *filter.StartDate, Valid: true}
 		countParams.StartDate = pgtype.Timestamptz{Time: *filter.StartDate, Valid: true}
@@ -470,14 +864,20 @@ func (pr *ProductRepository) ListMovements(ctx context.Context, filter *reposito
 		}
 
 		repoMovements[i] = &repository.Movement{
-			ID:          uint32(m.ID),
-			ProductID:   uint32(m.ProductID),
-			Quantity:    m.Quantity,
-			Price:       pkg.PgTypeNumericToFloat64(m.Price),
-			Type:        m.Type,
-			Note:        note,
-			PerformedBy: uint32(m.PerformedBy),
-			CreatedAt:   m.CreatedAt,
+			ID:             uint32(m.ID),
+			ProductID:      uint32(m.ProductID),
+			Quantity:       m.Quantity,
+			Price:          pkg.PgTypeNumericToFloat64(m.Price),
+			UnitCost:       pkg.PgTypeNumericToFloat64(m.UnitCost),
+			Type:           m.Type,
+			Note:           note,
+			PerformedBy:    uint32(m.PerformedBy),
+			SaleID:         pgInt8ToUint32(m.SaleID),
+			CustomerID:     pgInt8ToUint32(m.CustomerID),
+			PrescriptionID: pgInt8ToUint32(m.PrescriptionID),
+			WitnessedBy:    pgInt8ToUint32(m.WitnessedBy),
+			Reason:         pgTextToString(m.Reason),
+			CreatedAt:      m.CreatedAt,
 
 			ProductName: m.ProductName,
 			UserName:    m.UserName,
@@ -493,6 +893,16 @@ func (pr *ProductRepository) GetStats(ctx context.Context) (*repository.Stats, e
 		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get stats: %s", err.Error())
 	}
 
+	valuation, err := pr.cachedValuation(ctx, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	retailValue, err := pr.queries.GetRetailValue(ctx)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get retail value: %s", err.Error())
+	}
+
 	return &repository.Stats{
 		TotalUsers:             stats.TotalUsers,
 		TotalProducts:          stats.TotalProducts,
@@ -502,7 +912,9 @@ func (pr *ProductRepository) GetStats(ctx context.Context) (*repository.Stats, e
 		TotalStockAddedValue:   pkg.PgTypeNumericToFloat64(stats.TotalStocksAddedValue),
 		TotalStockRemoved:      stats.TotalStocksRemoved,
 		TotalStockRemovedValue: pkg.PgTypeNumericToFloat64(stats.TotalStocksRemovedValue),
-		TotalValue:             pkg.PgTypeNumericToFloat64(stats.TotalValue),
+		TotalValue:             valuation.TotalClosingValue,
+		TotalRetailValue:       pkg.PgTypeNumericToFloat64(retailValue),
+		ValuationMethod:        valuation.Method,
 	}, nil
 }
 
@@ -542,8 +954,24 @@ func (pr *ProductRepository) GetDashboardData(ctx context.Context) (map[string]a
 		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get stats: %s", err.Error())
 	}
 
+	// the week starts at midnight so the valuation can be reused through the day
+	now := time.Now()
+	weekStart := time.Date(now.Year(), now.Month(), now.Day()-7, 0, 0, 0, 0, now.Location())
+	valuation, err := pr.cachedValuation(ctx, weekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	retailValue, err := pr.queries.GetRetailValue(ctx)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get retail value: %s", err.Error())
+	}
+
 	result := map[string]any{
-		"stock_value":        stats.TotalValue,
+		"stock_value":        valuation.TotalClosingValue,
+		"retail_value":       pkg.PgTypeNumericToFloat64(retailValue),
+		"weekly_cogs":        valuation.TotalCostOfGoodsSold,
+		"valuation_method":   valuation.Method,
 		"total_products":     stats.TotalProducts,
 		"total_low_stock":    stats.TotalLowStock,
 		"total_out_of_stock": stats.TotalOutOfStock,
@@ -603,6 +1031,21 @@ func updateStatsHelper(
 	return err
 }
 
+// lastUnitCostTx returns the cost of the product's latest receipt, or the cost of
+// its opening stock when it has not been received since. ok is false when neither
+// is known.
+func lastUnitCostTx(ctx context.Context, q *generated.Queries, p generated.Product) (pgtype.Numeric, bool, error) {
+	cost, err := q.GetLastUnitCost(ctx, p.ID)
+	if err == nil {
+		return cost, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return pgtype.Numeric{}, false, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get unit cost: %s", err.Error())
+	}
+
+	return p.OpeningUnitCost, pkg.PgTypeNumericToFloat64(p.OpeningUnitCost) > 0, nil
+}
+
 func pgProductToRepoProduct(p generated.Product) *repository.Product {
 	return &repository.Product{
 		ID:                uint32(p.ID),
@@ -610,10 +1053,34 @@ func pgProductToRepoProduct(p generated.Product) *repository.Product {
 		Description:       p.Description.String,
 		Price:             pkg.PgTypeNumericToFloat64(p.Price),
 		Stock:             p.Stock,
+		OpeningUnitCost:   pkg.PgTypeNumericToFloat64(p.OpeningUnitCost),
+		ReservedStock:     p.ReservedStock,
+		AvailableStock:    p.Stock - p.ReservedStock,
+		QuarantinedStock:  p.QuarantinedStock,
 		Category:          p.Category,
+		CategoryID:        pgInt8ToUint32(p.CategoryID),
+		TaxClassID:        uint32(p.TaxClassID),
 		Unit:              p.Unit,
 		LowStockThreshold: p.LowStockThreshold,
+		PrescriptionOnly:  p.PrescriptionOnly,
+		Controlled:        p.Controlled,
 		Deleted:           p.Deleted,
 		CreatedAt:         p.CreatedAt,
+		IsKit:             p.IsKit,
+		GenericName:       pgTextToString(p.GenericName),
+		Strength:          pgTextToString(p.Strength),
+		DosageForm:        pgTextToString(p.DosageForm),
+		Route:             pgTextToString(p.Route),
+		Manufacturer:      pgTextToString(p.Manufacturer),
+		PackSize:          pgInt4ToInt32(p.PackSize),
+		TherapeuticClass:  pgTextToString(p.TherapeuticClass),
+
+		ReorderPoint:        pgInt4ToInt32(p.ReorderPoint),
+		ReorderQuantity:     pgInt4ToInt32(p.ReorderQuantity),
+		PreferredSupplierID: pgInt8ToUint32(p.PreferredSupplierID),
+
+		ABCClass:     pgTextToString(p.AbcClass),
+		XYZClass:     pgTextToString(p.XyzClass),
+		ClassifiedAt: pgTimestamptzToTime(p.ClassifiedAt),
 	}
 }
@@ -0,0 +1,327 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.PromotionRepository = (*PromotionRepository)(nil)
+
+type PromotionRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewPromotionRepository(db *Store) *PromotionRepository {
+	return &PromotionRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (pr *PromotionRepository) Create(ctx context.Context, promotion *repository.Promotion) (*repository.Promotion, error) {
+	if (promotion.ProductID == nil) == (promotion.CategoryID == nil) {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "a promotion applies to either a product or a category")
+	}
+	if err := validatePromotion(promotion); err != nil {
+		return nil, err
+	}
+
+	params := generated.CreatePromotionParams{
+		Name:         promotion.Name,
+		Type:         promotion.Type,
+		Value:        pkg.Float64ToPgTypeNumeric(promotion.Value),
+		BuyQuantity:  int32ToPgInt4(promotion.BuyQuantity),
+		FreeQuantity: int32ToPgInt4(promotion.FreeQuantity),
+		ProductID:    pgtype.Int8{Valid: false},
+		CategoryID:   pgtype.Int8{Valid: false},
+		StartsAt:     promotion.StartsAt,
+		EndsAt:       promotion.EndsAt,
+		CreatedBy:    int64(promotion.CreatedBy),
+	}
+	if promotion.ProductID != nil {
+		params.ProductID = pgtype.Int8{Int64: int64(*promotion.ProductID), Valid: true}
+	}
+	if promotion.CategoryID != nil {
+		params.CategoryID = pgtype.Int8{Int64: int64(*promotion.CategoryID), Valid: true}
+	}
+
+	p, err := pr.queries.CreatePromotion(ctx, params)
+	if err != nil {
+		if pkg.PgxErrorCode(err) == pkg.FOREIGN_KEY_VIOLATION {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "promotion product or category not found")
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create promotion: %s", err.Error())
+	}
+
+	return pr.GetByID(ctx, p.ID)
+}
+
+func (pr *PromotionRepository) GetByID(ctx context.Context, id int64) (*repository.Promotion, error) {
+	p, err := pr.queries.GetPromotionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "promotion with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get promotion: %s", err.Error())
+	}
+
+	promotion := pgPromotionToRepoPromotion(generated.Promotion{
+		ID:           p.ID,
+		Name:         p.Name,
+		Type:         p.Type,
+		Value:        p.Value,
+		BuyQuantity:  p.BuyQuantity,
+		FreeQuantity: p.FreeQuantity,
+		ProductID:    p.ProductID,
+		CategoryID:   p.CategoryID,
+		StartsAt:     p.StartsAt,
+		EndsAt:       p.EndsAt,
+		Active:       p.Active,
+		CreatedBy:    p.CreatedBy,
+		CreatedAt:    p.CreatedAt,
+	})
+	promotion.ProductName = pgTextToString(p.ProductName)
+	promotion.CategoryName = pgTextToString(p.CategoryName)
+
+	return promotion, nil
+}
+
+func (pr *PromotionRepository) Update(ctx context.Context, id int64, promotionUpdate *repository.PromotionUpdate) (*repository.Promotion, error) {
+	params := generated.UpdatePromotionParams{
+		ID:           id,
+		Name:         stringToPgText(promotionUpdate.Name),
+		Value:        pgtype.Numeric{Valid: false},
+		BuyQuantity:  int32ToPgInt4(promotionUpdate.BuyQuantity),
+		FreeQuantity: int32ToPgInt4(promotionUpdate.FreeQuantity),
+		StartsAt:     pgtype.Timestamptz{Valid: false},
+		EndsAt:       pgtype.Timestamptz{Valid: false},
+		Active:       pgtype.Bool{Valid: false},
+	}
+	if promotionUpdate.Value != nil {
+		params.Value = pkg.Float64ToPgTypeNumeric(*promotionUpdate.Value)
+	}
+	if promotionUpdate.StartsAt != nil {
+		params.StartsAt = pgtype.Timestamptz{Time: *promotionUpdate.StartsAt, Valid: true}
+	}
+	if promotionUpdate.EndsAt != nil {
+		params.EndsAt = pgtype.Timestamptz{Time: *promotionUpdate.EndsAt, Valid: true}
+	}
+	if promotionUpdate.Active != nil {
+		params.Active = pgtype.Bool{Bool: *promotionUpdate.Active, Valid: true}
+	}
+
+	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		p, err := q.UpdatePromotion(ctx, params)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "promotion with id %d not found", id)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update promotion: %s", err.Error())
+		}
+
+		// validated after merging so partial updates are checked against the stored values
+		return validatePromotion(pgPromotionToRepoPromotion(p))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pr.GetByID(ctx, id)
+}
+
+func (pr *PromotionRepository) List(ctx context.Context, filter *repository.PromotionFilter) ([]*repository.Promotion, *pkg.Pagination, error) {
+	listParams := generated.ListPromotionsParams{
+		Limit:     int32(filter.Pagination.PageSize),
+		Offset:    pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+		Current:   pgtype.Bool{Valid: false},
+		ProductID: pgtype.Int8{Valid: false},
+	}
+	countParams := generated.ListPromotionsCountParams{
+		Current:   pgtype.Bool{Valid: false},
+		ProductID: pgtype.Int8{Valid: false},
+	}
+
+	if filter.Current != nil {
+		listParams.Current = pgtype.Bool{Bool: *filter.Current, Valid: true}
+		countParams.Current = pgtype.Bool{Bool: *filter.Current, Valid: true}
+	}
+	if filter.ProductID != nil {
+		listParams.ProductID = pgtype.Int8{Int64: int64(*filter.ProductID), Valid: true}
+		countParams.ProductID = pgtype.Int8{Int64: int64(*filter.ProductID), Valid: true}
+	}
+
+	rows, err := pr.queries.ListPromotions(ctx, listParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list promotions: %s", err.Error())
+	}
+
+	totalCount, err := pr.queries.ListPromotionsCount(ctx, countParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count promotions: %s", err.Error())
+	}
+
+	promotions := make([]*repository.Promotion, len(rows))
+	for i, row := range rows {
+		promotions[i] = pgPromotionToRepoPromotion(generated.Promotion{
+			ID:           row.ID,
+			Name:         row.Name,
+			Type:         row.Type,
+			Value:        row.Value,
+			BuyQuantity:  row.BuyQuantity,
+			FreeQuantity: row.FreeQuantity,
+			ProductID:    row.ProductID,
+			CategoryID:   row.CategoryID,
+			StartsAt:     row.StartsAt,
+			EndsAt:       row.EndsAt,
+			Active:       row.Active,
+			CreatedBy:    row.CreatedBy,
+			CreatedAt:    row.CreatedAt,
+		})
+		promotions[i].ProductName = pgTextToString(row.ProductName)
+		promotions[i].CategoryName = pgTextToString(row.CategoryName)
+	}
+
+	return promotions, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
+}
+
+func (pr *PromotionRepository) GetDiscountReport(ctx context.Context, startDate, endDate time.Time) (*repository.DiscountReport, error) {
+	totals, err := pr.queries.GetSalesTotals(ctx, generated.GetSalesTotalsParams{
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get sales totals: %s", err.Error())
+	}
+
+	rows, err := pr.queries.GetDiscountReport(ctx, generated.GetDiscountReportParams{
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get discount report: %s", err.Error())
+	}
+
+	report := &repository.DiscountReport{
+		StartDate:      startDate,
+		EndDate:        endDate,
+		GrossAmount:    pkg.PgTypeNumericToFloat64(totals.GrossAmount),
+		DiscountAmount: pkg.PgTypeNumericToFloat64(totals.DiscountAmount),
+		NetAmount:      pkg.PgTypeNumericToFloat64(totals.NetAmount),
+		Promotions:     make([]*repository.DiscountReportLine, len(rows)),
+	}
+	for i, row := range rows {
+		report.Promotions[i] = &repository.DiscountReportLine{
+			PromotionID:    uint32(row.PromotionID),
+			PromotionName:  row.PromotionName,
+			PromotionType:  row.PromotionType,
+			TotalLines:     row.TotalLines,
+			TotalQuantity:  row.TotalQuantity,
+			GrossAmount:    pkg.PgTypeNumericToFloat64(row.GrossAmount),
+			DiscountAmount: pkg.PgTypeNumericToFloat64(row.DiscountAmount),
+		}
+	}
+
+	return report, nil
+}
+
+func validatePromotion(promotion *repository.Promotion) error {
+	if !promotion.EndsAt.After(promotion.StartsAt) {
+		return pkg.Errorf(pkg.INVALID_ERROR, "a promotion must end after it starts")
+	}
+
+	switch promotion.Type {
+	case repository.PROMOTION_PERCENTAGE:
+		if promotion.Value <= 0 || promotion.Value > 100 {
+			return pkg.Errorf(pkg.INVALID_ERROR, "a percentage discount must be greater than 0 and at most 100")
+		}
+	case repository.PROMOTION_FIXED:
+		if promotion.Value <= 0 {
+			return pkg.Errorf(pkg.INVALID_ERROR, "a fixed discount must be greater than 0")
+		}
+	case repository.PROMOTION_BUY_X_GET_Y:
+		if promotion.BuyQuantity == nil || *promotion.BuyQuantity <= 0 || promotion.FreeQuantity == nil || *promotion.FreeQuantity <= 0 {
+			return pkg.Errorf(pkg.INVALID_ERROR, "buy x get y promotions need a buy quantity and a free quantity greater than 0")
+		}
+	default:
+		return pkg.Errorf(pkg.INVALID_ERROR, "invalid promotion type: %s", promotion.Type)
+	}
+
+	return nil
+}
+
+// bestPromotionTx finds the promotions running at the given time for the product or
+// any category above it and returns the one giving the largest discount on the line.
+// Promotions do not stack.
+func bestPromotionTx(ctx context.Context, q *generated.Queries, productID int64, unitPrice float64, quantity int64, at time.Time) (*generated.Promotion, float64, error) {
+	promotions, err := q.ListApplicablePromotions(ctx, generated.ListApplicablePromotionsParams{
+		ProductID: productID,
+		At:        at,
+	})
+	if err != nil {
+		return nil, 0, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list applicable promotions: %s", err.Error())
+	}
+
+	var (
+		best         *generated.Promotion
+		bestDiscount float64
+	)
+	for i := range promotions {
+		discount := promotionDiscount(promotions[i], unitPrice, quantity)
+		if discount > bestDiscount {
+			best = &promotions[i]
+			bestDiscount = discount
+		}
+	}
+
+	return best, bestDiscount, nil
+}
+
+// promotionDiscount returns the discount a promotion gives on a line, rounded to cents
+// and never more than the line itself.
+func promotionDiscount(p generated.Promotion, unitPrice float64, quantity int64) float64 {
+	lineTotal := unitPrice * float64(quantity)
+	value := pkg.PgTypeNumericToFloat64(p.Value)
+
+	var discount float64
+	switch p.Type {
+	case repository.PROMOTION_PERCENTAGE:
+		discount = lineTotal * value / 100
+	case repository.PROMOTION_FIXED:
+		discount = value * float64(quantity)
+	case repository.PROMOTION_BUY_X_GET_Y:
+		// every complete group of buy + free units gets the free units at no charge
+		group := int64(p.BuyQuantity.Int32 + p.FreeQuantity.Int32)
+		if group > 0 {
+			discount = float64(quantity/group*int64(p.FreeQuantity.Int32)) * unitPrice
+		}
+	}
+
+	return math.Min(math.Round(discount*100)/100, lineTotal)
+}
+
+func pgPromotionToRepoPromotion(p generated.Promotion) *repository.Promotion {
+	return &repository.Promotion{
+		ID:           uint32(p.ID),
+		Name:         p.Name,
+		Type:         p.Type,
+		Value:        pkg.PgTypeNumericToFloat64(p.Value),
+		BuyQuantity:  pgInt4ToInt32(p.BuyQuantity),
+		FreeQuantity: pgInt4ToInt32(p.FreeQuantity),
+		ProductID:    pgInt8ToUint32(p.ProductID),
+		CategoryID:   pgInt8ToUint32(p.CategoryID),
+		StartsAt:     p.StartsAt,
+		EndsAt:       p.EndsAt,
+		Active:       p.Active,
+		CreatedBy:    uint32(p.CreatedBy),
+		CreatedAt:    p.CreatedAt,
+	}
+}
@@ -0,0 +1,331 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.PurchaseOrderRepository = (*PurchaseOrderRepository)(nil)
+
+type PurchaseOrderRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewPurchaseOrderRepository(db *Store) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (pr *PurchaseOrderRepository) GenerateDrafts(ctx context.Context) ([]*repository.PurchaseOrder, error) {
+	var orderIDs []int64
+	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		// runs are serialised so the same shortfall is never ordered twice
+		if err := q.LockReorderRun(ctx); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to lock reorder run: %s", err.Error())
+		}
+
+		suggestions, err := q.ListReorderSuggestions(ctx)
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list reorder suggestions: %s", err.Error())
+		}
+
+		forecastQuantities, err := forecastOrderQuantitiesTx(ctx, q, pr.db.config, suggestions)
+		if err != nil {
+			return err
+		}
+
+		// suggestions are ordered by supplier, so each supplier's lines are together
+		var order generated.PurchaseOrder
+		for _, suggestion := range suggestions {
+			if order.ID == 0 || order.SupplierID != suggestion.SupplierID {
+				order, err = q.GetDraftPurchaseOrderForSupplier(ctx, suggestion.SupplierID)
+				if err != nil && !errors.Is(err, sql.ErrNoRows) {
+					return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get draft purchase order: %s", err.Error())
+				}
+				if errors.Is(err, sql.ErrNoRows) {
+					order, err = q.CreatePurchaseOrder(ctx, generated.CreatePurchaseOrderParams{
+						SupplierID: suggestion.SupplierID,
+						Note:       pgtype.Text{String: "Generated from reorder points", Valid: true},
+						CreatedBy:  pgtype.Int8{Valid: false},
+					})
+					if err != nil {
+						return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create purchase order: %s", err.Error())
+					}
+				}
+				orderIDs = append(orderIDs, order.ID)
+			}
+
+			// a product already on the draft is topped up, since its shortfall counts
+			// what is on order
+			if _, err := q.CreatePurchaseOrderItem(ctx, generated.CreatePurchaseOrderItemParams{
+				PurchaseOrderID: order.ID,
+				ProductID:       suggestion.ProductID,
+				Quantity:        max(suggestion.Quantity, forecastQuantities[suggestion.ProductID]),
+				UnitCost:        suggestion.UnitCost,
+			}); err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to add purchase order item: %s", err.Error())
+			}
+		}
+
+		for _, id := range orderIDs {
+			if err := q.UpdatePurchaseOrderTotals(ctx, id); err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update purchase order totals: %s", err.Error())
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]*repository.PurchaseOrder, len(orderIDs))
+	for i, id := range orderIDs {
+		order, err := pr.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		orders[i] = order
+	}
+
+	return orders, nil
+}
+
+// forecastOrderQuantitiesTx returns the order quantity each suggested product's
+// demand forecast calls for, so a product selling faster than its reorder
+// quantity allows is ordered in line with its forecast.
+func forecastOrderQuantitiesTx(ctx context.Context, q *generated.Queries, config pkg.Config, suggestions []generated.ListReorderSuggestionsRow) (map[int64]int32, error) {
+	quantities := make(map[int64]int32, len(suggestions))
+	if len(suggestions) == 0 {
+		return quantities, nil
+	}
+
+	settings, err := newForecastSettings(config, &repository.ForecastFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(suggestions))
+	for i, suggestion := range suggestions {
+		ids[i] = suggestion.ProductID
+	}
+
+	products, err := q.ListForecastProducts(ctx, generated.ListForecastProductsParams{
+		ProductIds: ids,
+		Offset:     0,
+		Limit:      int32(len(ids)),
+	})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list products: %s", err.Error())
+	}
+
+	forecasts, err := forecastProductsTx(ctx, q, settings, products)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range forecasts {
+		quantities[int64(f.ProductID)] = int32(min(f.SuggestedOrderQuantity, math.MaxInt32))
+	}
+
+	return quantities, nil
+}
+
+func (pr *PurchaseOrderRepository) SetItemQuantity(ctx context.Context, id int64, itemID int64, quantity int32) (*repository.PurchaseOrder, error) {
+	if quantity < 0 {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "quantity cannot be negative")
+	}
+
+	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		order, err := getPurchaseOrderForUpdateTx(ctx, q, id)
+		if err != nil {
+			return err
+		}
+		if order.Status != repository.PURCHASE_ORDER_DRAFT {
+			return pkg.Errorf(pkg.INVALID_ERROR, "purchase order %s has already been %s", order.OrderNumber, order.Status)
+		}
+
+		if quantity == 0 {
+			_, err = q.DeletePurchaseOrderItem(ctx, generated.DeletePurchaseOrderItemParams{
+				ID:              itemID,
+				PurchaseOrderID: id,
+			})
+		} else {
+			_, err = q.UpdatePurchaseOrderItemQuantity(ctx, generated.UpdatePurchaseOrderItemQuantityParams{
+				ID:              itemID,
+				PurchaseOrderID: id,
+				Quantity:        quantity,
+			})
+		}
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "item %d is not on purchase order %s", itemID, order.OrderNumber)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update purchase order item: %s", err.Error())
+		}
+
+		if err := q.UpdatePurchaseOrderTotals(ctx, id); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update purchase order totals: %s", err.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pr.GetByID(ctx, id)
+}
+
+func (pr *PurchaseOrderRepository) UpdateStatus(ctx context.Context, id int64, status string, userID uint32) (*repository.PurchaseOrder, error) {
+	err := pr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		order, err := getPurchaseOrderForUpdateTx(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		params := generated.SetPurchaseOrderStatusParams{
+			ID:        id,
+			Status:    status,
+			OrderedBy: pgtype.Int8{Valid: false},
+		}
+		switch status {
+		case repository.PURCHASE_ORDER_ORDERED:
+			if order.Status != repository.PURCHASE_ORDER_DRAFT {
+				return pkg.Errorf(pkg.INVALID_ERROR, "only draft purchase orders can be ordered")
+			}
+			if order.TotalQuantity == 0 {
+				return pkg.Errorf(pkg.INVALID_ERROR, "purchase order %s has no items", order.OrderNumber)
+			}
+			params.OrderedBy = pgtype.Int8{Int64: int64(userID), Valid: true}
+		case repository.PURCHASE_ORDER_RECEIVED:
+			if order.Status != repository.PURCHASE_ORDER_ORDERED {
+				return pkg.Errorf(pkg.INVALID_ERROR, "only ordered purchase orders can be received")
+			}
+		case repository.PURCHASE_ORDER_CANCELLED:
+			if order.Status != repository.PURCHASE_ORDER_DRAFT && order.Status != repository.PURCHASE_ORDER_ORDERED {
+				return pkg.Errorf(pkg.INVALID_ERROR, "purchase order %s has already been %s", order.OrderNumber, order.Status)
+			}
+		default:
+			return pkg.Errorf(pkg.INVALID_ERROR, "invalid purchase order status: %s", status)
+		}
+
+		if err := q.SetPurchaseOrderStatus(ctx, params); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update purchase order status: %s", err.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pr.GetByID(ctx, id)
+}
+
+func getPurchaseOrderForUpdateTx(ctx context.Context, q *generated.Queries, id int64) (generated.PurchaseOrder, error) {
+	order, err := q.GetPurchaseOrderForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return order, pkg.Errorf(pkg.NOT_FOUND_ERROR, "purchase order with id %d not found", id)
+		}
+		return order, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get purchase order: %s", err.Error())
+	}
+
+	return order, nil
+}
+
+func (pr *PurchaseOrderRepository) GetByID(ctx context.Context, id int64) (*repository.PurchaseOrder, error) {
+	po, err := pr.queries.GetPurchaseOrderByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "purchase order with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get purchase order: %s", err.Error())
+	}
+
+	items, err := pr.queries.ListPurchaseOrderItems(ctx, id)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list purchase order items: %s", err.Error())
+	}
+
+	order := pgPurchaseOrderToRepoPurchaseOrder(generated.ListPurchaseOrdersRow(po))
+	order.Items = make([]*repository.PurchaseOrderItem, len(items))
+	for i, item := range items {
+		order.Items[i] = &repository.PurchaseOrderItem{
+			ID:              uint32(item.ID),
+			PurchaseOrderID: uint32(item.PurchaseOrderID),
+			ProductID:       uint32(item.ProductID),
+			Quantity:        item.Quantity,
+			UnitCost:        pkg.PgTypeNumericToFloat64(item.UnitCost),
+			LineTotal:       pkg.PgTypeNumericToFloat64(item.LineTotal),
+
+			ProductName:    item.ProductName,
+			AvailableStock: item.AvailableStock,
+			ReorderPoint:   pgInt4ToInt32(item.ReorderPoint),
+		}
+	}
+
+	return order, nil
+}
+
+func (pr *PurchaseOrderRepository) List(ctx context.Context, filter *repository.PurchaseOrderFilter) ([]*repository.PurchaseOrder, *pkg.Pagination, error) {
+	listParams := generated.ListPurchaseOrdersParams{
+		Limit:      int32(filter.Pagination.PageSize),
+		Offset:     pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+		SupplierID: pgtype.Int8{Valid: false},
+		Status:     stringToPgText(filter.Status),
+	}
+	if filter.SupplierID != nil {
+		listParams.SupplierID = pgtype.Int8{Int64: int64(*filter.SupplierID), Valid: true}
+	}
+
+	orders, err := pr.queries.ListPurchaseOrders(ctx, listParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list purchase orders: %s", err.Error())
+	}
+
+	totalCount, err := pr.queries.ListPurchaseOrdersCount(ctx, generated.ListPurchaseOrdersCountParams{
+		SupplierID: listParams.SupplierID,
+		Status:     listParams.Status,
+	})
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count purchase orders: %s", err.Error())
+	}
+
+	repoOrders := make([]*repository.PurchaseOrder, len(orders))
+	for i, po := range orders {
+		repoOrders[i] = pgPurchaseOrderToRepoPurchaseOrder(po)
+	}
+
+	return repoOrders, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
+}
+
+func pgPurchaseOrderToRepoPurchaseOrder(po generated.ListPurchaseOrdersRow) *repository.PurchaseOrder {
+	return &repository.PurchaseOrder{
+		ID:            uint32(po.ID),
+		OrderNumber:   po.OrderNumber,
+		SupplierID:    uint32(po.SupplierID),
+		Status:        po.Status,
+		TotalQuantity: po.TotalQuantity,
+		TotalAmount:   pkg.PgTypeNumericToFloat64(po.TotalAmount),
+		Note:          pgTextToString(po.Note),
+		CreatedBy:     pgInt8ToUint32(po.CreatedBy),
+		OrderedBy:     pgInt8ToUint32(po.OrderedBy),
+		OrderedAt:     pgTimestamptzToTime(po.OrderedAt),
+		ClosedAt:      pgTimestamptzToTime(po.ClosedAt),
+		CreatedAt:     po.CreatedAt,
+
+		SupplierName:  po.SupplierName,
+		CreatedByName: pgTextToString(po.CreatedByName),
+	}
+}
@@ -0,0 +1,92 @@
+-- name: GetCustomerCreditForUpdate :one
+SELECT credit_limit, payment_terms_days FROM customers
+WHERE id = $1 AND deleted = false
+FOR UPDATE;
+
+-- name: ListOpenInvoices :many
+SELECT * FROM (
+    SELECT 
+        s.id,
+        s.receipt_number,
+        s.total_amount,
+        COALESCE((
+            SELECT SUM(p.amount) FROM payments p 
+            WHERE p.sale_id = s.id AND p.status = 'COMPLETED'
+        ), 0)::numeric AS amount_paid,
+        s.created_at,
+        s.due_date
+    FROM sales s
+    WHERE s.customer_id = $1 AND s.on_account
+) AS invoices
+WHERE total_amount > amount_paid
+ORDER BY created_at ASC, id ASC;
+
+-- name: GetAgeingReport :many
+WITH outstanding AS (
+    SELECT 
+        s.customer_id,
+        s.total_amount - COALESCE((
+            SELECT SUM(p.amount) FROM payments p 
+            WHERE p.sale_id = s.id AND p.status = 'COMPLETED' AND p.completed_at <= sqlc.arg('as_of')::timestamptz
+        ), 0) AS balance,
+        sqlc.arg('as_of')::timestamptz - s.created_at AS age
+    FROM sales s
+    WHERE s.on_account AND s.created_at <= sqlc.arg('as_of')::timestamptz
+)
+SELECT 
+    c.id AS customer_id,
+    c.name AS customer_name,
+    c.credit_limit,
+    COALESCE(SUM(o.balance) FILTER (WHERE o.age <= INTERVAL '30 days'), 0)::numeric AS current_amount,
+    COALESCE(SUM(o.balance) FILTER (WHERE o.age > INTERVAL '30 days' AND o.age <= INTERVAL '60 days'), 0)::numeric AS days_31_60,
+    COALESCE(SUM(o.balance) FILTER (WHERE o.age > INTERVAL '60 days' AND o.age <= INTERVAL '90 days'), 0)::numeric AS days_61_90,
+    COALESCE(SUM(o.balance) FILTER (WHERE o.age > INTERVAL '90 days'), 0)::numeric AS over_90,
+    SUM(o.balance)::numeric AS total_outstanding
+FROM outstanding o
+JOIN customers c ON c.id = o.customer_id
+WHERE o.balance > 0
+    AND (
+        sqlc.narg('customer_id')::bigint IS NULL 
+        OR c.id = sqlc.narg('customer_id')
+    )
+GROUP BY c.id, c.name, c.credit_limit
+ORDER BY total_outstanding DESC, c.name ASC;
+
+-- name: GetStatementOpeningBalance :one
+SELECT (
+    COALESCE((
+        SELECT SUM(s.total_amount) FROM sales s
+        WHERE s.customer_id = sqlc.arg('customer_id') AND s.on_account AND s.created_at < sqlc.arg('start_date')
+    ), 0)
+    - COALESCE((
+        SELECT SUM(p.amount) FROM payments p
+        JOIN sales s ON s.id = p.sale_id
+        WHERE s.customer_id = sqlc.arg('customer_id') AND s.on_account 
+            AND p.status = 'COMPLETED' AND p.completed_at < sqlc.arg('start_date')
+    ), 0)
+)::numeric AS opening_balance;
+
+-- name: ListStatementEntries :many
+SELECT * FROM (
+    SELECT 
+        'INVOICE'::text AS entry_type,
+        s.id AS sale_id,
+        s.receipt_number AS reference,
+        s.created_at AS entry_date,
+        s.total_amount AS amount
+    FROM sales s
+    WHERE s.customer_id = sqlc.arg('customer_id') AND s.on_account
+        AND s.created_at >= sqlc.arg('start_date') AND s.created_at < sqlc.arg('end_date')
+    UNION ALL
+    SELECT 
+        'PAYMENT'::text AS entry_type,
+        s.id AS sale_id,
+        s.receipt_number || ' ' || p.method || COALESCE(' ' || p.reference, '') AS reference,
+        p.completed_at AS entry_date,
+        p.amount
+    FROM payments p
+    JOIN sales s ON s.id = p.sale_id
+    WHERE s.customer_id = sqlc.arg('customer_id') AND s.on_account AND p.status = 'COMPLETED'
+        AND p.completed_at >= sqlc.arg('start_date') AND p.completed_at < sqlc.arg('end_date')
+) AS entries
+ORDER BY entry_date ASC, entry_type ASC;
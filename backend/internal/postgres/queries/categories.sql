@@ -0,0 +1,58 @@
+-- name: CreateCategory :one
+INSERT INTO categories (name, parent_id)
+VALUES (sqlc.arg('name'), sqlc.narg('parent_id'))
+RETURNING *;
+
+-- name: GetCategoryByID :one
+SELECT * FROM categories WHERE id = $1;
+
+-- name: GetCategoryByName :one
+SELECT * FROM categories WHERE LOWER(name) = LOWER(TRIM(sqlc.arg('name')::text));
+
+-- name: UpdateCategory :one
+UPDATE categories
+SET name = coalesce(sqlc.narg('name'), name),
+    parent_id = CASE WHEN sqlc.arg('set_parent')::boolean THEN sqlc.narg('parent_id') ELSE parent_id END
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: DeleteCategory :exec
+DELETE FROM categories WHERE id = $1;
+
+-- name: ListCategories :many
+SELECT c.*, COUNT(p.id) AS product_count
+FROM categories c
+LEFT JOIN products p ON p.category_id = c.id AND p.deleted = false
+GROUP BY c.id
+ORDER BY c.name ASC;
+
+-- name: ListCategorySubtreeIDs :many
+WITH RECURSIVE subtree AS (
+    SELECT id FROM categories WHERE id = $1
+    UNION ALL
+    SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+)
+SELECT id FROM subtree;
+
+-- name: CountCategoryProducts :one
+SELECT COUNT(*) AS total_products
+FROM products
+WHERE category_id = $1;
+
+-- name: SyncProductCategoryName :exec
+UPDATE products p
+SET category = c.name
+FROM categories c
+WHERE c.id = sqlc.arg('category_id') AND p.category_id = c.id;
+
+-- name: MoveCategoryProducts :exec
+UPDATE products p
+SET category_id = c.id,
+    category = c.name
+FROM categories c
+WHERE c.id = sqlc.arg('to_category_id') AND p.category_id = sqlc.arg('from_category_id');
+
+-- name: MoveCategoryChildren :exec
+UPDATE categories
+SET parent_id = sqlc.narg('to_parent_id')
+WHERE parent_id = sqlc.arg('from_parent_id');
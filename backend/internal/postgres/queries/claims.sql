@@ -0,0 +1,133 @@
+-- name: CreateClaimBatch :one
+INSERT INTO claim_batches (insurer_id, period_start, period_end, created_by)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: CreateBatchClaims :exec
+INSERT INTO claims (batch_id, sale_id, scheme_id, member_number, amount)
+SELECT 
+    sqlc.arg('batch_id')::bigint,
+    s.id,
+    s.scheme_id,
+    s.member_number,
+    SUM(p.amount)
+FROM sales AS s
+JOIN insurance_schemes AS sc ON sc.id = s.scheme_id
+JOIN payments AS p ON p.sale_id = s.id AND p.method = 'INSURANCE' AND p.status = 'COMPLETED'
+WHERE 
+    sc.insurer_id = sqlc.arg('insurer_id')
+    AND s.created_at >= sqlc.arg('period_start')
+    AND s.created_at < sqlc.arg('period_end')
+    AND NOT EXISTS (SELECT 1 FROM claims AS c WHERE c.sale_id = s.id)
+GROUP BY s.id, s.scheme_id, s.member_number;
+
+-- name: UpdateClaimBatchTotals :exec
+UPDATE claim_batches AS cb
+SET total_claims = t.total_claims,
+    total_amount = t.total_amount,
+    approved_amount = t.approved_amount,
+    -- a submitted batch is complete once the insurer has decided every claim
+    status = CASE 
+        WHEN cb.status = 'SUBMITTED' AND t.undecided = 0 THEN 'COMPLETED'
+        ELSE cb.status
+    END
+FROM (
+    SELECT 
+        COUNT(*) AS total_claims,
+        COALESCE(SUM(amount), 0) AS total_amount,
+        COALESCE(SUM(approved_amount), 0) AS approved_amount,
+        COUNT(*) FILTER (WHERE status IN ('PENDING', 'SUBMITTED')) AS undecided
+    FROM claims
+    WHERE batch_id = $1
+) AS t
+WHERE cb.id = $1;
+
+-- name: GetClaimBatchForUpdate :one
+SELECT * FROM claim_batches WHERE id = $1 FOR UPDATE;
+
+-- name: SubmitClaimBatch :exec
+UPDATE claim_batches
+SET status = 'SUBMITTED',
+    external_reference = sqlc.narg('external_reference'),
+    submitted_by = sqlc.arg('submitted_by'),
+    submitted_at = now()
+WHERE id = sqlc.arg('id');
+
+-- name: MarkBatchClaimsSubmitted :exec
+UPDATE claims
+SET status = 'SUBMITTED'
+WHERE batch_id = $1 AND status = 'PENDING';
+
+-- name: GetClaimForUpdate :one
+SELECT * FROM claims WHERE id = $1 FOR UPDATE;
+
+-- name: ResolveClaim :exec
+UPDATE claims
+SET status = sqlc.arg('status'),
+    approved_amount = sqlc.arg('approved_amount'),
+    rejection_reason = sqlc.narg('rejection_reason'),
+    external_reference = coalesce(sqlc.narg('external_reference'), external_reference),
+    resolved_at = now()
+WHERE id = sqlc.arg('id');
+
+-- name: GetClaimBatchByID :one
+SELECT 
+    cb.*,
+    i.name AS insurer_name,
+    i.code AS insurer_code,
+    i.client AS insurer_client,
+    u.name AS user_name
+FROM claim_batches AS cb
+JOIN insurers AS i ON i.id = cb.insurer_id
+JOIN users AS u ON u.id = cb.created_by
+WHERE cb.id = $1;
+
+-- name: ListBatchClaims :many
+SELECT 
+    c.*,
+    s.receipt_number,
+    s.created_at AS sale_date,
+    s.total_amount AS sale_amount,
+    sc.name AS scheme_name,
+    cu.name AS customer_name
+FROM claims AS c
+JOIN sales AS s ON s.id = c.sale_id
+JOIN insurance_schemes AS sc ON sc.id = c.scheme_id
+LEFT JOIN customers AS cu ON cu.id = s.customer_id
+WHERE c.batch_id = $1
+ORDER BY s.created_at, c.id;
+
+-- name: ListClaimBatches :many
+SELECT 
+    cb.*,
+    i.name AS insurer_name,
+    i.code AS insurer_code,
+    i.client AS insurer_client,
+    u.name AS user_name
+FROM claim_batches AS cb
+JOIN insurers AS i ON i.id = cb.insurer_id
+JOIN users AS u ON u.id = cb.created_by
+WHERE 
+    (
+        sqlc.narg('insurer_id')::bigint IS NULL 
+        OR cb.insurer_id = sqlc.narg('insurer_id')
+    )
+    AND (
+        sqlc.narg('status')::text IS NULL 
+        OR cb.status = sqlc.narg('status')
+    )
+ORDER BY cb.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListClaimBatchesCount :one
+SELECT COUNT(*) AS total_batches
+FROM claim_batches
+WHERE 
+    (
+        sqlc.narg('insurer_id')::bigint IS NULL 
+        OR insurer_id = sqlc.narg('insurer_id')
+    )
+    AND (
+        sqlc.narg('status')::text IS NULL 
+        OR status = sqlc.narg('status')
+    );
@@ -0,0 +1,36 @@
+-- name: ListProductConsumption :many
+SELECT 
+    p.id,
+    p.name,
+    COALESCE(SUM(m.quantity), 0)::bigint AS consumption_quantity,
+    COALESCE(SUM(m.quantity * m.price), 0)::numeric AS consumption_value
+FROM products AS p
+LEFT JOIN movements AS m ON m.product_id = p.id
+    AND m.type = 'REMOVE'
+    AND (m.reason IS NULL OR m.reason NOT IN ('SUPPLIER_RETURN', 'WRITE_OFF', 'QUARANTINE'))
+    AND m.created_at >= sqlc.arg('start_date')
+    AND m.created_at < sqlc.arg('end_date')
+WHERE p.deleted = false AND p.is_kit = false
+GROUP BY p.id, p.name
+ORDER BY p.id;
+
+-- name: ListWeeklyDemand :many
+SELECT 
+    product_id,
+    FLOOR(EXTRACT(EPOCH FROM sqlc.arg('end_date')::timestamptz - created_at) / 604800)::integer AS weeks_ago,
+    SUM(quantity)::bigint AS quantity
+FROM movements
+WHERE 
+    type = 'REMOVE'
+    AND (reason IS NULL OR reason NOT IN ('SUPPLIER_RETURN', 'WRITE_OFF', 'QUARANTINE'))
+    AND created_at >= sqlc.arg('start_date')
+    AND created_at < sqlc.arg('end_date')
+GROUP BY product_id, weeks_ago
+ORDER BY product_id, weeks_ago;
+
+-- name: SetProductClasses :exec
+UPDATE products
+SET abc_class = sqlc.narg('abc_class'),
+    xyz_class = sqlc.narg('xyz_class'),
+    classified_at = sqlc.arg('classified_at')
+WHERE id = sqlc.arg('id');
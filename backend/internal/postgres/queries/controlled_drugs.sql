@@ -0,0 +1,41 @@
+-- name: GetLastRegisterEntry :one
+SELECT * FROM controlled_drug_register
+WHERE product_id = $1
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: CreateRegisterEntry :one
+INSERT INTO controlled_drug_register (
+    product_id, movement_id, entry_type, quantity, balance, reference, customer_id, 
+    prescription_id, performed_by, witnessed_by, previous_hash, hash, created_at
+)
+VALUES (
+    sqlc.arg('product_id'), sqlc.arg('movement_id'), sqlc.arg('entry_type'), sqlc.arg('quantity'), sqlc.arg('balance'), 
+    sqlc.narg('reference'), sqlc.narg('customer_id'), sqlc.narg('prescription_id'), sqlc.arg('performed_by'), 
+    sqlc.arg('witnessed_by'), sqlc.arg('previous_hash'), sqlc.arg('hash'), sqlc.arg('created_at')
+)
+RETURNING *;
+
+-- name: ListRegisterEntries :many
+SELECT 
+    r.*,
+    p.name AS product_name,
+    p.unit AS product_unit,
+    u.name AS performed_by_name,
+    w.name AS witnessed_by_name,
+    c.name AS customer_name,
+    pr.prescriber_name,
+    pr.prescriber_licence_number
+FROM controlled_drug_register AS r
+JOIN products AS p ON p.id = r.product_id
+JOIN users AS u ON u.id = r.performed_by
+JOIN users AS w ON w.id = r.witnessed_by
+LEFT JOIN customers AS c ON c.id = r.customer_id
+LEFT JOIN prescriptions AS pr ON pr.id = r.prescription_id
+WHERE 
+    (
+        sqlc.narg('product_id')::bigint IS NULL 
+        OR r.product_id = sqlc.narg('product_id')
+    )
+    AND r.created_at <= sqlc.arg('end_date')
+ORDER BY r.product_id, r.id;
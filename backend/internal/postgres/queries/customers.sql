@@ -0,0 +1,50 @@
+-- name: CreateCustomer :one
+INSERT INTO customers (name, phone_number, date_of_birth, allergies, notes, credit_limit, payment_terms_days)
+VALUES (
+    sqlc.arg('name'), sqlc.narg('phone_number'), sqlc.narg('date_of_birth'), sqlc.narg('allergies'), sqlc.narg('notes'),
+    sqlc.arg('credit_limit'), sqlc.arg('payment_terms_days')
+)
+RETURNING *;
+
+-- name: GetCustomerByID :one
+SELECT * FROM customers WHERE id = $1 AND deleted = false;
+
+-- name: UpdateCustomer :one
+UPDATE customers
+SET name = coalesce(sqlc.narg('name'), name),
+    phone_number = coalesce(sqlc.narg('phone_number'), phone_number),
+    date_of_birth = coalesce(sqlc.narg('date_of_birth'), date_of_birth),
+    allergies = coalesce(sqlc.narg('allergies'), allergies),
+    notes = coalesce(sqlc.narg('notes'), notes),
+    credit_limit = coalesce(sqlc.narg('credit_limit'), credit_limit),
+    payment_terms_days = coalesce(sqlc.narg('payment_terms_days'), payment_terms_days)
+WHERE id = sqlc.arg('id') AND deleted = false
+RETURNING *;
+
+-- name: DeleteCustomer :exec
+UPDATE customers
+SET deleted = true
+WHERE id = $1;
+
+-- name: ListCustomers :many
+SELECT * FROM customers
+WHERE 
+    (
+        COALESCE(sqlc.narg('search'), '') = '' 
+        OR LOWER(name) LIKE sqlc.narg('search')
+        OR LOWER(phone_number) LIKE sqlc.narg('search')
+    )
+    AND deleted = false
+ORDER BY name ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListCustomersCount :one
+SELECT COUNT(*) AS total_customers
+FROM customers
+WHERE 
+    (
+        COALESCE(sqlc.narg('search'), '') = '' 
+        OR LOWER(name) LIKE sqlc.narg('search')
+        OR LOWER(phone_number) LIKE sqlc.narg('search')
+    )
+    AND deleted = false;
@@ -0,0 +1,44 @@
+-- name: ListForecastProducts :many
+SELECT 
+    p.id,
+    p.name,
+    p.created_at,
+    p.stock - p.reserved_stock AS available_stock,
+    p.reorder_point,
+    o.on_order
+FROM products AS p
+CROSS JOIN LATERAL (
+    SELECT COALESCE(SUM(poi.quantity), 0)::bigint AS on_order
+    FROM purchase_order_items AS poi
+    JOIN purchase_orders AS po ON po.id = poi.purchase_order_id
+    WHERE poi.product_id = p.id AND po.status IN ('DRAFT', 'ORDERED')
+) AS o
+WHERE 
+    p.deleted = false 
+    AND p.is_kit = false
+    AND (
+        sqlc.narg('product_ids')::bigint[] IS NULL 
+        OR p.id = ANY(sqlc.narg('product_ids')::bigint[])
+    )
+ORDER BY p.name
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListForecastProductsCount :one
+SELECT COUNT(*) AS total_products
+FROM products
+WHERE deleted = false AND is_kit = false;
+
+-- name: ListDailyDemand :many
+SELECT 
+    product_id,
+    created_at::date AS day,
+    SUM(quantity)::bigint AS quantity
+FROM movements
+WHERE 
+    type = 'REMOVE'
+    AND (reason IS NULL OR reason NOT IN ('SUPPLIER_RETURN', 'WRITE_OFF', 'QUARANTINE'))
+    AND product_id = ANY(sqlc.arg('product_ids')::bigint[])
+    AND created_at >= sqlc.arg('start_date')
+    AND created_at < sqlc.arg('end_date')
+GROUP BY product_id, day
+ORDER BY product_id, day;
@@ -43,17 +43,16 @@ sales_data AS (
     created_at::date AS day,
     SUM(quantity) AS sales,
     COUNT(*) AS total_transacted,
-    SUM(price * quantity) AS total_amount
-  FROM movements
-  WHERE type = 'REMOVE'
-    AND created_at >= NOW() - INTERVAL '7 days'
+    SUM(line_total) AS total_amount
+  FROM sale_items
+  WHERE created_at >= NOW() - INTERVAL '7 days'
   GROUP BY created_at::date
 )
 SELECT
   to_char(ds.day, 'Dy') AS day,
   COALESCE(sd.sales, 0) AS sales,
   COALESCE(sd.total_transacted, 0) AS total_transacted,
-  COALESCE(sd.total_amount, 0) AS total_amount
+  COALESCE(sd.total_amount, 0)::numeric AS total_amount
 FROM date_series ds
 LEFT JOIN sales_data sd ON ds.day = sd.day
 ORDER BY ds.day;
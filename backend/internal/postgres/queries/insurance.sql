@@ -0,0 +1,60 @@
+-- name: CreateInsurer :one
+INSERT INTO insurers (name, code, client, email, phone_number)
+VALUES (sqlc.arg('name'), sqlc.arg('code'), sqlc.arg('client'), sqlc.narg('email'), sqlc.narg('phone_number'))
+RETURNING *;
+
+-- name: GetInsurerByID :one
+SELECT * FROM insurers WHERE id = $1 AND deleted = false;
+
+-- name: UpdateInsurer :one
+UPDATE insurers
+SET name = coalesce(sqlc.narg('name'), name),
+    client = coalesce(sqlc.narg('client'), client),
+    email = coalesce(sqlc.narg('email'), email),
+    phone_number = coalesce(sqlc.narg('phone_number'), phone_number)
+WHERE id = sqlc.arg('id') AND deleted = false
+RETURNING *;
+
+-- name: DeleteInsurer :exec
+UPDATE insurers
+SET deleted = true
+WHERE id = $1;
+
+-- name: ListInsurers :many
+SELECT * FROM insurers
+WHERE deleted = false
+ORDER BY name ASC;
+
+-- name: CreateInsuranceScheme :one
+INSERT INTO insurance_schemes (insurer_id, name, co_payment_rate)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetInsuranceSchemeByID :one
+SELECT 
+    sc.*,
+    i.name AS insurer_name
+FROM insurance_schemes AS sc
+JOIN insurers AS i ON i.id = sc.insurer_id
+WHERE sc.id = $1 AND i.deleted = false;
+
+-- name: UpdateInsuranceScheme :exec
+UPDATE insurance_schemes
+SET name = coalesce(sqlc.narg('name'), name),
+    co_payment_rate = coalesce(sqlc.narg('co_payment_rate'), co_payment_rate),
+    active = coalesce(sqlc.narg('active'), active)
+WHERE id = sqlc.arg('id');
+
+-- name: ListInsuranceSchemes :many
+SELECT 
+    sc.*,
+    i.name AS insurer_name
+FROM insurance_schemes AS sc
+JOIN insurers AS i ON i.id = sc.insurer_id
+WHERE 
+    i.deleted = false
+    AND (
+        sqlc.narg('insurer_id')::bigint IS NULL 
+        OR sc.insurer_id = sqlc.narg('insurer_id')
+    )
+ORDER BY i.name ASC, sc.name ASC;
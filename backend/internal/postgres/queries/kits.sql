@@ -0,0 +1,42 @@
+-- name: SetProductKit :one
+UPDATE products
+SET is_kit = sqlc.arg('is_kit')
+WHERE id = sqlc.arg('id') AND deleted = false
+RETURNING *;
+
+-- name: DeleteKitComponents :exec
+DELETE FROM kit_components WHERE kit_id = $1;
+
+-- name: CreateKitComponent :one
+INSERT INTO kit_components (kit_id, component_id, quantity)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListKitComponents :many
+SELECT 
+    kc.*,
+    p.name AS product_name,
+    p.unit,
+    (p.stock - p.reserved_stock)::bigint AS available_stock
+FROM kit_components AS kc
+JOIN products AS p ON p.id = kc.component_id
+WHERE kc.kit_id = $1
+ORDER BY p.name;
+
+-- name: ListKitBuildableQuantities :many
+SELECT 
+    kc.kit_id,
+    MIN(GREATEST(p.stock - p.reserved_stock, 0) / kc.quantity)::bigint AS buildable
+FROM kit_components AS kc
+JOIN products AS p ON p.id = kc.component_id
+WHERE kc.kit_id = ANY(sqlc.arg('kit_ids')::bigint[])
+GROUP BY kc.kit_id;
+
+-- name: GetLastUnitCost :one
+SELECT unit_cost FROM movements
+WHERE product_id = $1 AND type = 'ADD' AND unit_cost > 0
+ORDER BY created_at DESC, id DESC
+LIMIT 1;
+
+-- name: CountKitsUsingComponent :one
+SELECT COUNT(*) AS total_kits FROM kit_components WHERE component_id = $1;
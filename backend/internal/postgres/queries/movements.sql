@@ -1,8 +1,14 @@
 -- name: CreateMovement :one
-INSERT INTO movements (product_id, quantity, price, type, note, batch_number, performed_by)
-VALUES (sqlc.arg('product_id'), sqlc.arg('quantity'), sqlc.arg('price'), sqlc.arg('type'), sqlc.arg('note'), sqlc.narg('batch_number'), sqlc.arg('performed_by'))
+INSERT INTO movements (product_id, quantity, price, type, note, batch_number, performed_by, unit_cost, sale_id, customer_id, prescription_id, witnessed_by, reason)
+VALUES (sqlc.arg('product_id'), sqlc.arg('quantity'), sqlc.arg('price'), sqlc.arg('type'), sqlc.arg('note'), sqlc.narg('batch_number'), sqlc.arg('performed_by'), sqlc.arg('unit_cost'), sqlc.narg('sale_id'), sqlc.narg('customer_id'), sqlc.narg('prescription_id'), sqlc.narg('witnessed_by'), sqlc.narg('reason'))
 RETURNING *;
 
+-- name: ListValuationMovements :many
+SELECT product_id, type, quantity, unit_cost, created_at
+FROM movements
+WHERE created_at <= sqlc.arg('end_date')
+ORDER BY created_at ASC, id ASC;
+
 -- name: GetMovementByID :one
 SELECT * FROM movements WHERE id = $1;
 
@@ -27,6 +33,10 @@ WHERE
         sqlc.narg('batch_number')::text IS NULL 
         OR m.batch_number = sqlc.narg('batch_number')
     )
+    AND (
+        sqlc.narg('customer_id')::bigint IS NULL 
+        OR m.customer_id = sqlc.narg('customer_id')
+    )
     AND (
         sqlc.narg('start_date')::timestamptz IS NULL
         OR m.created_at BETWEEN sqlc.narg('start_date')::timestamptz 
@@ -51,6 +61,10 @@ WHERE
         sqlc.narg('batch_number')::text IS NULL 
         OR batch_number = sqlc.narg('batch_number')
     )
+    AND (
+        sqlc.narg('customer_id')::bigint IS NULL 
+        OR customer_id = sqlc.narg('customer_id')
+    )
     AND (
         sqlc.narg('start_date')::timestamptz IS NULL
         OR created_at BETWEEN sqlc.narg('start_date')::timestamptz AND COALESCE(sqlc.narg('end_date')::timestamptz, now())
@@ -0,0 +1,79 @@
+-- name: CreatePayment :one
+INSERT INTO payments (sale_id, method, amount, status, reference, phone_number, received_by, completed_at, shift_id)
+VALUES (
+    sqlc.arg('sale_id'), sqlc.arg('method'), sqlc.arg('amount'), sqlc.arg('status'), sqlc.narg('reference'),
+    sqlc.narg('phone_number'), sqlc.arg('received_by'), sqlc.narg('completed_at'), sqlc.narg('shift_id')
+)
+RETURNING *;
+
+-- name: GetPaymentByID :one
+SELECT * FROM payments WHERE id = $1;
+
+-- name: GetSaleBalanceForUpdate :one
+SELECT 
+    s.total_amount,
+    COALESCE((
+        SELECT SUM(p.amount) FROM payments p 
+        WHERE p.sale_id = s.id AND p.status IN ('PENDING', 'COMPLETED')
+    ), 0)::numeric AS committed_amount,
+    s.scheme_id,
+    (SELECT sc.co_payment_rate FROM insurance_schemes sc WHERE sc.id = s.scheme_id) AS co_payment_rate,
+    COALESCE((
+        SELECT SUM(p.amount) FROM payments p 
+        WHERE p.sale_id = s.id AND p.method = 'INSURANCE' AND p.status = 'COMPLETED'
+    ), 0)::numeric AS insured_amount,
+    EXISTS (SELECT 1 FROM claims c WHERE c.sale_id = s.id) AS claimed
+FROM sales s
+WHERE s.id = $1
+FOR UPDATE;
+
+-- name: SetPaymentCheckoutRequestID :exec
+UPDATE payments
+SET checkout_request_id = sqlc.arg('checkout_request_id')
+WHERE id = sqlc.arg('id');
+
+-- name: GetPaymentByCheckoutRequestID :one
+SELECT * FROM payments WHERE checkout_request_id = $1 FOR UPDATE;
+
+-- name: CompletePayment :one
+UPDATE payments
+SET status = 'COMPLETED',
+    reference = coalesce(sqlc.narg('reference'), reference),
+    completed_at = now()
+WHERE id = sqlc.arg('id') AND status = 'PENDING'
+RETURNING *;
+
+-- name: FailPayment :one
+UPDATE payments
+SET status = 'FAILED',
+    failure_reason = sqlc.narg('failure_reason')
+WHERE id = sqlc.arg('id') AND status = 'PENDING'
+RETURNING *;
+
+-- name: ListSalePayments :many
+SELECT * FROM payments
+WHERE sale_id = $1
+ORDER BY id;
+
+-- name: ListStalePendingPaymentIDs :many
+SELECT id FROM payments
+WHERE method = 'MPESA' AND status = 'PENDING' AND created_at <= sqlc.arg('created_before')
+ORDER BY id
+FOR UPDATE SKIP LOCKED;
+
+-- name: ListSaleInsurancePaymentsForUpdate :many
+SELECT * FROM payments
+WHERE sale_id = $1 AND method = 'INSURANCE' AND status = 'COMPLETED'
+ORDER BY id DESC
+FOR UPDATE;
+
+-- name: ReducePayment :exec
+UPDATE payments
+SET amount = amount - sqlc.arg('reduction')
+WHERE id = sqlc.arg('id');
+
+-- name: ReversePayment :exec
+UPDATE payments
+SET status = 'FAILED',
+    failure_reason = sqlc.narg('failure_reason')
+WHERE id = sqlc.arg('id');
@@ -0,0 +1,88 @@
+-- name: CreatePrescription :one
+INSERT INTO prescriptions (customer_id, prescriber_name, prescriber_licence_number, prescribed_at, valid_until, notes, created_by)
+VALUES (sqlc.arg('customer_id'), sqlc.arg('prescriber_name'), sqlc.arg('prescriber_licence_number'), sqlc.arg('prescribed_at'), sqlc.arg('valid_until'), sqlc.narg('notes'), sqlc.arg('created_by'))
+RETURNING *;
+
+-- name: CreatePrescriptionItem :one
+INSERT INTO prescription_items (prescription_id, product_id, quantity, dosage)
+VALUES (sqlc.arg('prescription_id'), sqlc.arg('product_id'), sqlc.arg('quantity'), sqlc.narg('dosage'))
+RETURNING *;
+
+-- name: GetPrescriptionByID :one
+SELECT 
+    pr.*,
+    c.name AS customer_name
+FROM prescriptions AS pr
+JOIN customers AS c ON c.id = pr.customer_id
+WHERE pr.id = $1;
+
+-- name: ListPrescriptionItems :many
+SELECT 
+    pi.*,
+    p.name AS product_name
+FROM prescription_items AS pi
+JOIN products AS p ON p.id = pi.product_id
+WHERE pi.prescription_id = $1
+ORDER BY pi.id;
+
+-- name: DispensePrescriptionItem :one
+UPDATE prescription_items AS pi
+SET dispensed_quantity = pi.dispensed_quantity + sqlc.arg('quantity')
+FROM prescriptions AS pr
+WHERE pr.id = pi.prescription_id
+    AND pi.prescription_id = sqlc.arg('prescription_id')
+    AND pi.product_id = sqlc.arg('product_id')
+    AND pi.quantity - pi.dispensed_quantity >= sqlc.arg('quantity')
+    AND pr.valid_until >= CURRENT_DATE
+RETURNING pi.*, pr.customer_id;
+
+-- name: ListPrescriptions :many
+SELECT 
+    pr.*,
+    c.name AS customer_name
+FROM prescriptions AS pr
+JOIN customers AS c ON c.id = pr.customer_id
+WHERE 
+    (
+        sqlc.narg('customer_id')::bigint IS NULL 
+        OR pr.customer_id = sqlc.narg('customer_id')
+    )
+    AND (
+        COALESCE(sqlc.narg('search'), '') = '' 
+        OR LOWER(pr.prescriber_name) LIKE sqlc.narg('search')
+        OR LOWER(pr.prescriber_licence_number) LIKE sqlc.narg('search')
+        OR LOWER(c.name) LIKE sqlc.narg('search')
+    )
+ORDER BY pr.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListPrescriptionsCount :one
+SELECT COUNT(*) AS total_prescriptions
+FROM prescriptions AS pr
+JOIN customers AS c ON c.id = pr.customer_id
+WHERE 
+    (
+        sqlc.narg('customer_id')::bigint IS NULL 
+        OR pr.customer_id = sqlc.narg('customer_id')
+    )
+    AND (
+        COALESCE(sqlc.narg('search'), '') = '' 
+        OR LOWER(pr.prescriber_name) LIKE sqlc.narg('search')
+        OR LOWER(pr.prescriber_licence_number) LIKE sqlc.narg('search')
+        OR LOWER(c.name) LIKE sqlc.narg('search')
+    );
+
+-- name: CreatePrescriptionScan :one
+INSERT INTO prescription_scans (prescription_id, file_name, content_type, data, uploaded_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, prescription_id, file_name, content_type, uploaded_by, created_at;
+
+-- name: ListPrescriptionScans :many
+SELECT id, prescription_id, file_name, content_type, uploaded_by, created_at
+FROM prescription_scans
+WHERE prescription_id = $1
+ORDER BY id;
+
+-- name: GetPrescriptionScan :one
+SELECT * FROM prescription_scans
+WHERE id = $1 AND prescription_id = $2;
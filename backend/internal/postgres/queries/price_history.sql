@@ -0,0 +1,32 @@
+-- name: CreatePriceChange :one
+INSERT INTO price_history (product_id, old_price, new_price, effective_at, applied, applied_at, note, changed_by)
+VALUES (sqlc.arg('product_id'), sqlc.arg('old_price'), sqlc.arg('new_price'), sqlc.arg('effective_at'), sqlc.arg('applied'), sqlc.narg('applied_at'), sqlc.narg('note'), sqlc.arg('changed_by'))
+RETURNING *;
+
+-- name: ListPriceHistory :many
+SELECT 
+    ph.*,
+    u.name AS user_name
+FROM price_history AS ph
+JOIN users AS u ON u.id = ph.changed_by
+WHERE ph.product_id = sqlc.arg('product_id')
+ORDER BY ph.effective_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListPriceHistoryCount :one
+SELECT COUNT(*) AS total_changes
+FROM price_history
+WHERE product_id = sqlc.arg('product_id');
+
+-- name: ListDuePriceChanges :many
+SELECT * FROM price_history
+WHERE applied = false AND effective_at <= now()
+ORDER BY effective_at ASC
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkPriceChangeApplied :exec
+UPDATE price_history
+SET applied = true,
+    applied_at = now(),
+    old_price = sqlc.arg('old_price')
+WHERE id = sqlc.arg('id');
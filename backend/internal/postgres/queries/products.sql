@@ -1,6 +1,9 @@
 -- name: CreateProduct :one
-INSERT INTO products (name, description, price, stock, category, unit, low_stock_threshold)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO products (
+    name, description, price, stock, category, unit, low_stock_threshold, prescription_only, controlled, category_id,
+    generic_name, strength, dosage_form, route, manufacturer, pack_size, therapeutic_class, tax_class_id, opening_unit_cost
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 RETURNING *;
 
 -- name: GetProductByID :one
@@ -12,8 +15,19 @@ SET name = coalesce(sqlc.narg('name'), name),
     description = coalesce(sqlc.narg('description'), description),
     price = coalesce(sqlc.narg('price'), price),
     category = coalesce(sqlc.narg('category'), category),
+    category_id = coalesce(sqlc.narg('category_id'), category_id),
     unit = coalesce(sqlc.narg('unit'), unit),
-    low_stock_threshold = coalesce(sqlc.narg('low_stock_threshold'), low_stock_threshold)
+    low_stock_threshold = coalesce(sqlc.narg('low_stock_threshold'), low_stock_threshold),
+    prescription_only = coalesce(sqlc.narg('prescription_only'), prescription_only),
+    controlled = coalesce(sqlc.narg('controlled'), controlled),
+    generic_name = coalesce(sqlc.narg('generic_name'), generic_name),
+    strength = coalesce(sqlc.narg('strength'), strength),
+    dosage_form = coalesce(sqlc.narg('dosage_form'), dosage_form),
+    route = coalesce(sqlc.narg('route'), route),
+    manufacturer = coalesce(sqlc.narg('manufacturer'), manufacturer),
+    pack_size = coalesce(sqlc.narg('pack_size'), pack_size),
+    therapeutic_class = coalesce(sqlc.narg('therapeutic_class'), therapeutic_class),
+    tax_class_id = coalesce(sqlc.narg('tax_class_id'), tax_class_id)
 WHERE id = sqlc.arg('id')
 RETURNING *;
 
@@ -29,11 +43,39 @@ SET stock = stock - sqlc.arg('quantity')
 WHERE id = sqlc.arg('id')
 RETURNING *;
 
+-- name: QuarantineStock :one
+UPDATE products
+SET quarantined_stock = quarantined_stock + sqlc.arg('quantity')
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
 -- name: DeleteProduct :exec
 UPDATE products
 SET deleted = true
 WHERE id = $1;
 
+-- name: ListProductOpeningStock :many
+SELECT
+    p.id,
+    p.name,
+    p.opening_unit_cost,
+    p.created_at,
+    (p.stock - COALESCE(SUM(CASE WHEN m.type = 'ADD' THEN m.quantity ELSE -m.quantity END), 0))::bigint AS opening_stock
+FROM products AS p
+LEFT JOIN movements AS m ON m.product_id = p.id
+WHERE p.deleted = false
+GROUP BY p.id
+ORDER BY p.id;
+
+-- name: GetValuationVersion :one
+SELECT
+    (SELECT COALESCE(MAX(id), 0) FROM movements)::bigint AS last_movement_id,
+    COUNT(*)::bigint AS total_products,
+    COALESCE(MAX(id), 0)::bigint AS last_product_id,
+    COALESCE(SUM(stock), 0)::bigint AS total_stock
+FROM products
+WHERE deleted = false;
+
 -- name: ProductHelpers :many
 SELECT id, name FROM products
 WHERE deleted = false
@@ -47,6 +89,9 @@ WHERE
         OR LOWER(name) LIKE sqlc.narg('search')
         OR LOWER(description) LIKE sqlc.narg('search')
         OR LOWER(category) LIKE sqlc.narg('search')
+        OR LOWER(generic_name) LIKE sqlc.narg('search')
+        OR LOWER(manufacturer) LIKE sqlc.narg('search')
+        OR LOWER(therapeutic_class) LIKE sqlc.narg('search')
     )
     AND (
         sqlc.narg('in_stock')::boolean IS NULL
@@ -58,6 +103,26 @@ WHERE
             END
         )
     )
+    AND (
+        sqlc.narg('category_id')::bigint IS NULL
+        OR category_id IN (
+            WITH RECURSIVE subtree AS (
+                SELECT id FROM categories WHERE id = sqlc.narg('category_id')
+                UNION ALL
+                SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+            )
+            SELECT id FROM subtree
+        )
+    )
+    AND (sqlc.narg('generic_name')::text IS NULL OR LOWER(generic_name) = LOWER(sqlc.narg('generic_name')))
+    -- strengths match regardless of spacing, so "500 mg" finds "500mg"
+    AND (sqlc.narg('strength')::text IS NULL OR REPLACE(LOWER(strength), ' ', '') = REPLACE(LOWER(sqlc.narg('strength')), ' ', ''))
+    AND (sqlc.narg('dosage_form')::text IS NULL OR LOWER(dosage_form) = LOWER(sqlc.narg('dosage_form')))
+    AND (sqlc.narg('route')::text IS NULL OR LOWER(route) = LOWER(sqlc.narg('route')))
+    AND (sqlc.narg('manufacturer')::text IS NULL OR LOWER(manufacturer) = LOWER(sqlc.narg('manufacturer')))
+    AND (sqlc.narg('therapeutic_class')::text IS NULL OR LOWER(therapeutic_class) = LOWER(sqlc.narg('therapeutic_class')))
+    AND (sqlc.narg('abc_class')::text IS NULL OR abc_class = sqlc.narg('abc_class'))
+    AND (sqlc.narg('xyz_class')::text IS NULL OR xyz_class = sqlc.narg('xyz_class'))
     AND deleted = false
 ORDER BY created_at DESC
 LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
@@ -71,6 +136,9 @@ WHERE
         OR LOWER(name) LIKE sqlc.narg('search')
         OR LOWER(description) LIKE sqlc.narg('search')
         OR LOWER(category) LIKE sqlc.narg('search')
+        OR LOWER(generic_name) LIKE sqlc.narg('search')
+        OR LOWER(manufacturer) LIKE sqlc.narg('search')
+        OR LOWER(therapeutic_class) LIKE sqlc.narg('search')
     )
     AND (
         sqlc.narg('in_stock')::boolean IS NULL
@@ -82,4 +150,51 @@ WHERE
             END
         )
     )
-    AND deleted = false;
\ No newline at end of file
+    AND (
+        sqlc.narg('category_id')::bigint IS NULL
+        OR category_id IN (
+            WITH RECURSIVE subtree AS (
+                SELECT id FROM categories WHERE id = sqlc.narg('category_id')
+                UNION ALL
+                SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+            )
+            SELECT id FROM subtree
+        )
+    )
+    AND (sqlc.narg('generic_name')::text IS NULL OR LOWER(generic_name) = LOWER(sqlc.narg('generic_name')))
+    -- strengths match regardless of spacing, so "500 mg" finds "500mg"
+    AND (sqlc.narg('strength')::text IS NULL OR REPLACE(LOWER(strength), ' ', '') = REPLACE(LOWER(sqlc.narg('strength')), ' ', ''))
+    AND (sqlc.narg('dosage_form')::text IS NULL OR LOWER(dosage_form) = LOWER(sqlc.narg('dosage_form')))
+    AND (sqlc.narg('route')::text IS NULL OR LOWER(route) = LOWER(sqlc.narg('route')))
+    AND (sqlc.narg('manufacturer')::text IS NULL OR LOWER(manufacturer) = LOWER(sqlc.narg('manufacturer')))
+    AND (sqlc.narg('therapeutic_class')::text IS NULL OR LOWER(therapeutic_class) = LOWER(sqlc.narg('therapeutic_class')))
+    AND (sqlc.narg('abc_class')::text IS NULL OR abc_class = sqlc.narg('abc_class'))
+    AND (sqlc.narg('xyz_class')::text IS NULL OR xyz_class = sqlc.narg('xyz_class'))
+    AND deleted = false;
+
+-- name: ReserveStock :one
+UPDATE products
+SET reserved_stock = reserved_stock + sqlc.arg('quantity')
+WHERE id = sqlc.arg('id') AND deleted = false AND stock - reserved_stock >= sqlc.arg('quantity')
+RETURNING *;
+
+-- name: ListProductSubstitutes :many
+SELECT s.*
+FROM products p
+JOIN products s ON s.id <> p.id
+    AND LOWER(s.generic_name) = LOWER(p.generic_name)
+    AND REPLACE(LOWER(s.strength), ' ', '') IS NOT DISTINCT FROM REPLACE(LOWER(p.strength), ' ', '')
+    AND LOWER(s.dosage_form) IS NOT DISTINCT FROM LOWER(p.dosage_form)
+WHERE p.id = sqlc.arg('product_id')
+    AND s.deleted = false
+    AND s.stock - s.reserved_stock > 0
+ORDER BY s.price ASC, s.stock - s.reserved_stock DESC
+LIMIT sqlc.arg('limit');
+
+-- name: SetProductReorderPolicy :one
+UPDATE products
+SET reorder_point = sqlc.narg('reorder_point'),
+    reorder_quantity = sqlc.narg('reorder_quantity'),
+    preferred_supplier_id = sqlc.narg('preferred_supplier_id')
+WHERE id = sqlc.arg('id') AND deleted = false
+RETURNING *;
@@ -0,0 +1,106 @@
+-- name: CreatePromotion :one
+INSERT INTO promotions (name, type, value, buy_quantity, free_quantity, product_id, category_id, starts_at, ends_at, created_by)
+VALUES (
+    sqlc.arg('name'), sqlc.arg('type'), sqlc.arg('value'), sqlc.narg('buy_quantity'), sqlc.narg('free_quantity'),
+    sqlc.narg('product_id'), sqlc.narg('category_id'), sqlc.arg('starts_at'), sqlc.arg('ends_at'), sqlc.arg('created_by')
+)
+RETURNING *;
+
+-- name: GetPromotionByID :one
+SELECT 
+    pr.*,
+    p.name AS product_name,
+    c.name AS category_name
+FROM promotions pr
+LEFT JOIN products p ON p.id = pr.product_id
+LEFT JOIN categories c ON c.id = pr.category_id
+WHERE pr.id = $1;
+
+-- name: UpdatePromotion :one
+UPDATE promotions
+SET name = coalesce(sqlc.narg('name'), name),
+    value = coalesce(sqlc.narg('value'), value),
+    buy_quantity = coalesce(sqlc.narg('buy_quantity'), buy_quantity),
+    free_quantity = coalesce(sqlc.narg('free_quantity'), free_quantity),
+    starts_at = coalesce(sqlc.narg('starts_at'), starts_at),
+    ends_at = coalesce(sqlc.narg('ends_at'), ends_at),
+    active = coalesce(sqlc.narg('active'), active)
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: ListPromotions :many
+SELECT 
+    pr.*,
+    p.name AS product_name,
+    c.name AS category_name
+FROM promotions pr
+LEFT JOIN products p ON p.id = pr.product_id
+LEFT JOIN categories c ON c.id = pr.category_id
+WHERE 
+    (
+        sqlc.narg('current')::boolean IS NULL
+        OR (pr.active = true AND pr.starts_at <= now() AND pr.ends_at > now()) = sqlc.narg('current')
+    )
+    AND (
+        sqlc.narg('product_id')::bigint IS NULL 
+        OR pr.product_id = sqlc.narg('product_id')
+    )
+ORDER BY pr.starts_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListPromotionsCount :one
+SELECT COUNT(*) AS total_promotions
+FROM promotions pr
+WHERE 
+    (
+        sqlc.narg('current')::boolean IS NULL
+        OR (pr.active = true AND pr.starts_at <= now() AND pr.ends_at > now()) = sqlc.narg('current')
+    )
+    AND (
+        sqlc.narg('product_id')::bigint IS NULL 
+        OR pr.product_id = sqlc.narg('product_id')
+    );
+
+-- name: ListApplicablePromotions :many
+WITH RECURSIVE ancestors AS (
+    SELECT c.id, c.parent_id
+    FROM categories c
+    JOIN products p ON p.category_id = c.id
+    WHERE p.id = sqlc.arg('product_id')
+    UNION ALL
+    SELECT c.id, c.parent_id
+    FROM categories c
+    JOIN ancestors a ON c.id = a.parent_id
+)
+SELECT * FROM promotions
+WHERE active = true
+    AND starts_at <= sqlc.arg('at') AND ends_at > sqlc.arg('at')
+    AND (
+        product_id = sqlc.arg('product_id')
+        OR category_id IN (SELECT id FROM ancestors)
+    )
+ORDER BY id;
+
+-- name: GetDiscountReport :many
+SELECT 
+    pr.id AS promotion_id,
+    pr.name AS promotion_name,
+    pr.type AS promotion_type,
+    COUNT(si.id) AS total_lines,
+    COALESCE(SUM(si.quantity), 0)::bigint AS total_quantity,
+    COALESCE(SUM(si.line_total + si.discount_amount), 0)::numeric AS gross_amount,
+    COALESCE(SUM(si.discount_amount), 0)::numeric AS discount_amount
+FROM sale_items si
+JOIN sales s ON s.id = si.sale_id
+JOIN promotions pr ON pr.id = si.promotion_id
+WHERE s.created_at >= sqlc.arg('start_date') AND s.created_at < sqlc.arg('end_date')
+GROUP BY pr.id, pr.name, pr.type
+ORDER BY discount_amount DESC;
+
+-- name: GetSalesTotals :one
+SELECT 
+    COALESCE(SUM(total_amount + total_discount), 0)::numeric AS gross_amount,
+    COALESCE(SUM(total_discount), 0)::numeric AS discount_amount,
+    COALESCE(SUM(total_amount), 0)::numeric AS net_amount
+FROM sales
+WHERE created_at >= sqlc.arg('start_date') AND created_at < sqlc.arg('end_date');
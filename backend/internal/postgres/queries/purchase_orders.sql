@@ -0,0 +1,139 @@
+-- name: ListReorderSuggestions :many
+SELECT 
+    p.id AS product_id,
+    p.preferred_supplier_id::bigint AS supplier_id,
+    GREATEST(p.reorder_quantity, p.reorder_point - (p.stock - p.reserved_stock + o.on_order))::integer AS quantity,
+    COALESCE((
+        SELECT m.unit_cost 
+        FROM movements AS m
+        WHERE m.product_id = p.id AND m.type = 'ADD' AND m.unit_cost > 0
+        ORDER BY m.created_at DESC
+        LIMIT 1
+    ), 0)::numeric AS unit_cost
+FROM products AS p
+JOIN suppliers AS s ON s.id = p.preferred_supplier_id AND s.deleted = false
+CROSS JOIN LATERAL (
+    SELECT COALESCE(SUM(poi.quantity), 0)::bigint AS on_order
+    FROM purchase_order_items AS poi
+    JOIN purchase_orders AS po ON po.id = poi.purchase_order_id
+    WHERE poi.product_id = p.id AND po.status IN ('DRAFT', 'ORDERED')
+) AS o
+WHERE 
+    p.deleted = false 
+    AND p.is_kit = false
+    AND p.reorder_point IS NOT NULL
+    AND p.stock - p.reserved_stock + o.on_order < p.reorder_point
+ORDER BY p.preferred_supplier_id, p.name;
+
+-- name: LockReorderRun :exec
+SELECT pg_advisory_xact_lock(hashtext('purchase_orders_reorder'));
+
+-- name: GetDraftPurchaseOrderForSupplier :one
+SELECT * FROM purchase_orders 
+WHERE supplier_id = $1 AND status = 'DRAFT'
+ORDER BY created_at DESC
+LIMIT 1
+FOR UPDATE;
+
+-- name: CreatePurchaseOrder :one
+INSERT INTO purchase_orders (supplier_id, note, created_by)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: CreatePurchaseOrderItem :one
+INSERT INTO purchase_order_items (purchase_order_id, product_id, quantity, unit_cost, line_total)
+VALUES ($1, $2, $3, $4, $3 * $4)
+ON CONFLICT (purchase_order_id, product_id) DO UPDATE
+SET quantity = purchase_order_items.quantity + EXCLUDED.quantity,
+    line_total = purchase_order_items.unit_cost * (purchase_order_items.quantity + EXCLUDED.quantity)
+RETURNING *;
+
+-- name: UpdatePurchaseOrderItemQuantity :one
+UPDATE purchase_order_items
+SET quantity = sqlc.arg('quantity'),
+    line_total = unit_cost * sqlc.arg('quantity')
+WHERE id = sqlc.arg('id') AND purchase_order_id = sqlc.arg('purchase_order_id')
+RETURNING *;
+
+-- name: DeletePurchaseOrderItem :one
+DELETE FROM purchase_order_items
+WHERE id = $1 AND purchase_order_id = $2
+RETURNING *;
+
+-- name: UpdatePurchaseOrderTotals :exec
+UPDATE purchase_orders AS po
+SET total_quantity = t.total_quantity,
+    total_amount = t.total_amount
+FROM (
+    SELECT 
+        COALESCE(SUM(quantity), 0)::bigint AS total_quantity,
+        COALESCE(SUM(line_total), 0) AS total_amount
+    FROM purchase_order_items
+    WHERE purchase_order_id = $1
+) AS t
+WHERE po.id = $1;
+
+-- name: GetPurchaseOrderForUpdate :one
+SELECT * FROM purchase_orders WHERE id = $1 FOR UPDATE;
+
+-- name: SetPurchaseOrderStatus :exec
+UPDATE purchase_orders
+SET status = sqlc.arg('status'),
+    ordered_by = coalesce(sqlc.narg('ordered_by'), ordered_by),
+    ordered_at = CASE WHEN sqlc.arg('status') = 'ORDERED' THEN now() ELSE ordered_at END,
+    closed_at = CASE WHEN sqlc.arg('status') IN ('RECEIVED', 'CANCELLED') THEN now() ELSE closed_at END
+WHERE id = sqlc.arg('id');
+
+-- name: GetPurchaseOrderByID :one
+SELECT 
+    po.*,
+    s.name AS supplier_name,
+    u.name AS created_by_name
+FROM purchase_orders AS po
+JOIN suppliers AS s ON s.id = po.supplier_id
+LEFT JOIN users AS u ON u.id = po.created_by
+WHERE po.id = $1;
+
+-- name: ListPurchaseOrderItems :many
+SELECT 
+    poi.*,
+    p.name AS product_name,
+    p.stock - p.reserved_stock AS available_stock,
+    p.reorder_point
+FROM purchase_order_items AS poi
+JOIN products AS p ON p.id = poi.product_id
+WHERE poi.purchase_order_id = $1
+ORDER BY p.name;
+
+-- name: ListPurchaseOrders :many
+SELECT 
+    po.*,
+    s.name AS supplier_name,
+    u.name AS created_by_name
+FROM purchase_orders AS po
+JOIN suppliers AS s ON s.id = po.supplier_id
+LEFT JOIN users AS u ON u.id = po.created_by
+WHERE 
+    (
+        sqlc.narg('supplier_id')::bigint IS NULL 
+        OR po.supplier_id = sqlc.narg('supplier_id')
+    )
+    AND (
+        sqlc.narg('status')::text IS NULL 
+        OR po.status = sqlc.narg('status')
+    )
+ORDER BY po.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListPurchaseOrdersCount :one
+SELECT COUNT(*) AS total_orders
+FROM purchase_orders AS po
+WHERE 
+    (
+        sqlc.narg('supplier_id')::bigint IS NULL 
+        OR po.supplier_id = sqlc.narg('supplier_id')
+    )
+    AND (
+        sqlc.narg('status')::text IS NULL 
+        OR po.status = sqlc.narg('status')
+    );
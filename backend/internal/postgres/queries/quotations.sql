@@ -0,0 +1,82 @@
+-- name: CreateQuotation :one
+INSERT INTO quotations (customer_id, note, valid_until, created_by)
+VALUES (sqlc.narg('customer_id'), sqlc.narg('note'), sqlc.arg('valid_until'), sqlc.arg('created_by'))
+RETURNING *;
+
+-- name: CreateQuotationItem :one
+INSERT INTO quotation_items (quotation_id, product_id, quantity, unit_price, discount_amount, line_total, promotion_id, tax_code, tax_rate, tax_amount)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: UpdateQuotationTotals :exec
+UPDATE quotations
+SET total_quantity = sqlc.arg('total_quantity'),
+    total_amount = sqlc.arg('total_amount'),
+    total_tax = sqlc.arg('total_tax'),
+    total_discount = sqlc.arg('total_discount')
+WHERE id = sqlc.arg('id');
+
+-- name: GetQuotationForUpdate :one
+SELECT * FROM quotations WHERE id = $1 FOR UPDATE;
+
+-- name: ResolveQuotation :exec
+UPDATE quotations
+SET status = sqlc.arg('status'),
+    sale_id = sqlc.narg('sale_id'),
+    resolved_by = sqlc.arg('resolved_by'),
+    resolved_at = now()
+WHERE id = sqlc.arg('id');
+
+-- name: GetQuotationByID :one
+SELECT 
+    qt.*,
+    u.name AS user_name,
+    c.name AS customer_name
+FROM quotations AS qt
+JOIN users AS u ON u.id = qt.created_by
+LEFT JOIN customers AS c ON c.id = qt.customer_id
+WHERE qt.id = $1;
+
+-- name: ListQuotationItems :many
+SELECT 
+    qi.*,
+    p.name AS product_name,
+    pr.name AS promotion_name
+FROM quotation_items AS qi
+JOIN products AS p ON p.id = qi.product_id
+LEFT JOIN promotions AS pr ON pr.id = qi.promotion_id
+WHERE qi.quotation_id = $1
+ORDER BY qi.id;
+
+-- name: ListQuotations :many
+SELECT 
+    qt.*,
+    u.name AS user_name,
+    c.name AS customer_name
+FROM quotations AS qt
+JOIN users AS u ON u.id = qt.created_by
+LEFT JOIN customers AS c ON c.id = qt.customer_id
+WHERE 
+    (
+        sqlc.narg('status')::text IS NULL 
+        OR qt.status = sqlc.narg('status')
+    )
+    AND (
+        sqlc.narg('customer_id')::bigint IS NULL 
+        OR qt.customer_id = sqlc.narg('customer_id')
+    )
+ORDER BY qt.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListQuotationsCount :one
+SELECT COUNT(*) AS total_quotations
+FROM quotations
+WHERE 
+    (
+        sqlc.narg('status')::text IS NULL 
+        OR status = sqlc.narg('status')
+    )
+    AND (
+        sqlc.narg('customer_id')::bigint IS NULL 
+        OR customer_id = sqlc.narg('customer_id')
+    );
@@ -0,0 +1,90 @@
+-- name: CreateReservation :one
+INSERT INTO reservations (customer_id, note, expires_at, created_by)
+VALUES (sqlc.narg('customer_id'), sqlc.narg('note'), sqlc.arg('expires_at'), sqlc.arg('created_by'))
+RETURNING *;
+
+-- name: CreateReservationItem :one
+INSERT INTO reservation_items (reservation_id, product_id, quantity)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetReservationForUpdate :one
+SELECT * FROM reservations WHERE id = $1 FOR UPDATE;
+
+-- name: ReleaseReservedStock :exec
+UPDATE products AS p
+SET reserved_stock = p.reserved_stock - ri.quantity
+FROM (
+    SELECT product_id, SUM(quantity)::bigint AS quantity
+    FROM reservation_items
+    WHERE reservation_id = $1
+    GROUP BY product_id
+) AS ri
+WHERE p.id = ri.product_id;
+
+-- name: ResolveReservation :one
+UPDATE reservations
+SET status = sqlc.arg('status'),
+    sale_id = sqlc.narg('sale_id'),
+    resolved_by = sqlc.narg('resolved_by'),
+    resolved_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: ListExpiredReservationIDs :many
+SELECT id FROM reservations
+WHERE status = 'ACTIVE' AND expires_at <= now()
+ORDER BY expires_at
+FOR UPDATE SKIP LOCKED;
+
+-- name: GetReservationByID :one
+SELECT 
+    r.*,
+    u.name AS user_name,
+    c.name AS customer_name
+FROM reservations AS r
+JOIN users AS u ON u.id = r.created_by
+LEFT JOIN customers AS c ON c.id = r.customer_id
+WHERE r.id = $1;
+
+-- name: ListReservationItems :many
+SELECT 
+    ri.*,
+    p.name AS product_name
+FROM reservation_items AS ri
+JOIN products AS p ON p.id = ri.product_id
+WHERE ri.reservation_id = $1
+ORDER BY ri.id;
+
+-- name: ListReservations :many
+SELECT 
+    r.*,
+    u.name AS user_name,
+    c.name AS customer_name
+FROM reservations AS r
+JOIN users AS u ON u.id = r.created_by
+LEFT JOIN customers AS c ON c.id = r.customer_id
+WHERE 
+    (
+        sqlc.narg('status')::text IS NULL 
+        OR r.status = sqlc.narg('status')
+    )
+    AND (
+        sqlc.narg('customer_id')::bigint IS NULL 
+        OR r.customer_id = sqlc.narg('customer_id')
+    )
+ORDER BY r.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListReservationsCount :one
+SELECT COUNT(*) AS total_reservations
+FROM reservations
+WHERE 
+    (
+        sqlc.narg('status')::text IS NULL 
+        OR status = sqlc.narg('status')
+    )
+    AND (
+        sqlc.narg('customer_id')::bigint IS NULL 
+        OR customer_id = sqlc.narg('customer_id')
+    );
@@ -0,0 +1,81 @@
+-- name: GetMovementForUpdate :one
+SELECT * FROM movements WHERE id = $1 FOR UPDATE;
+
+-- name: GetSaleItemMovementID :one
+SELECT movement_id FROM sale_items WHERE id = $1;
+
+-- name: GetReturnedQuantity :one
+SELECT COALESCE(SUM(quantity), 0)::bigint AS returned_quantity
+FROM returns
+WHERE movement_id = $1;
+
+-- name: CreateReturn :one
+INSERT INTO returns (movement_id, sale_id, product_id, customer_id, quantity, status, reason, batch_number, restock_movement_id, performed_by)
+VALUES (
+    sqlc.arg('movement_id'), sqlc.narg('sale_id'), sqlc.arg('product_id'), sqlc.narg('customer_id'), sqlc.arg('quantity'), 
+    sqlc.arg('status'), sqlc.narg('reason'), sqlc.narg('batch_number'), sqlc.narg('restock_movement_id'), sqlc.arg('performed_by')
+)
+RETURNING *;
+
+-- name: GetReturnForUpdate :one
+SELECT * FROM returns WHERE id = $1 FOR UPDATE;
+
+-- name: ResolveReturn :one
+UPDATE returns
+SET status = sqlc.arg('status'),
+    restock_movement_id = coalesce(sqlc.narg('restock_movement_id'), restock_movement_id),
+    resolved_by = sqlc.arg('resolved_by'),
+    resolved_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: GetReturnByID :one
+SELECT 
+    r.*,
+    p.name AS product_name,
+    u.name AS user_name
+FROM returns AS r
+JOIN products AS p ON p.id = r.product_id
+JOIN users AS u ON u.id = r.performed_by
+WHERE r.id = $1;
+
+-- name: ListReturns :many
+SELECT 
+    r.*,
+    p.name AS product_name,
+    u.name AS user_name
+FROM returns AS r
+JOIN products AS p ON p.id = r.product_id
+JOIN users AS u ON u.id = r.performed_by
+WHERE 
+    (
+        sqlc.narg('status')::text IS NULL 
+        OR r.status = sqlc.narg('status')
+    )
+    AND (
+        sqlc.narg('product_id')::bigint IS NULL 
+        OR r.product_id = sqlc.narg('product_id')
+    )
+    AND (
+        sqlc.narg('sale_id')::bigint IS NULL 
+        OR r.sale_id = sqlc.narg('sale_id')
+    )
+ORDER BY r.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListReturnsCount :one
+SELECT COUNT(*) AS total_returns
+FROM returns
+WHERE 
+    (
+        sqlc.narg('status')::text IS NULL 
+        OR status = sqlc.narg('status')
+    )
+    AND (
+        sqlc.narg('product_id')::bigint IS NULL 
+        OR product_id = sqlc.narg('product_id')
+    )
+    AND (
+        sqlc.narg('sale_id')::bigint IS NULL 
+        OR sale_id = sqlc.narg('sale_id')
+    );
@@ -0,0 +1,109 @@
+-- name: CreateSale :one
+INSERT INTO sales (note, performed_by, location, receipt_number, customer_id, on_account, due_date, shift_id, scheme_id, member_number)
+VALUES (
+    sqlc.narg('note'), sqlc.arg('performed_by'), sqlc.arg('location'), sqlc.arg('receipt_number'), sqlc.narg('customer_id'),
+    sqlc.arg('on_account'), sqlc.narg('due_date'), sqlc.narg('shift_id'), sqlc.narg('scheme_id'), sqlc.narg('member_number')
+)
+RETURNING *;
+
+-- name: NextReceiptNumber :one
+INSERT INTO receipt_sequences (location, last_number)
+VALUES ($1, 1)
+ON CONFLICT (location) DO UPDATE
+SET last_number = receipt_sequences.last_number + 1
+RETURNING last_number;
+
+-- name: IncrementReceiptPrintCount :one
+UPDATE sales
+SET receipt_print_count = receipt_print_count + 1
+WHERE id = $1
+RETURNING receipt_print_count;
+
+-- name: UpdateSaleTotals :one
+UPDATE sales
+SET total_quantity = sqlc.arg('total_quantity'),
+    total_amount = sqlc.arg('total_amount'),
+    total_tax = sqlc.arg('total_tax'),
+    total_discount = sqlc.arg('total_discount')
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: CreateSaleItem :one
+INSERT INTO sale_items (sale_id, product_id, movement_id, quantity, unit_price, line_total, tax_class_id, tax_code, tax_rate, tax_amount, promotion_id, discount_amount)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING *;
+
+-- name: GetSaleByID :one
+SELECT 
+    s.*,
+    u.name AS user_name,
+    c.name AS customer_name,
+    sc.name AS scheme_name
+FROM sales AS s
+JOIN users AS u ON u.id = s.performed_by
+LEFT JOIN customers AS c ON c.id = s.customer_id
+LEFT JOIN insurance_schemes AS sc ON sc.id = s.scheme_id
+WHERE s.id = $1;
+
+-- name: ListSaleItems :many
+SELECT 
+    si.*,
+    p.name AS product_name,
+    m.prescription_id,
+    pr.name AS promotion_name
+FROM sale_items AS si
+JOIN products AS p ON p.id = si.product_id
+JOIN movements AS m ON m.id = si.movement_id
+LEFT JOIN promotions AS pr ON pr.id = si.promotion_id
+WHERE si.sale_id = $1
+ORDER BY si.id;
+
+-- name: ListSales :many
+SELECT 
+    s.*,
+    u.name AS user_name,
+    c.name AS customer_name
+FROM sales AS s
+JOIN users AS u ON u.id = s.performed_by
+LEFT JOIN customers AS c ON c.id = s.customer_id
+WHERE 
+    (
+        sqlc.narg('performed_by')::bigint IS NULL 
+        OR s.performed_by = sqlc.narg('performed_by')
+    )
+    AND (
+        sqlc.narg('location')::text IS NULL 
+        OR s.location = sqlc.narg('location')
+    )
+    AND (
+        sqlc.narg('customer_id')::bigint IS NULL 
+        OR s.customer_id = sqlc.narg('customer_id')
+    )
+    AND (
+        sqlc.narg('start_date')::timestamptz IS NULL
+        OR s.created_at BETWEEN sqlc.narg('start_date')::timestamptz 
+            AND COALESCE(sqlc.narg('end_date')::timestamptz, now())
+    )
+ORDER BY s.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListSalesCount :one
+SELECT COUNT(*) AS total_sales
+FROM sales
+WHERE 
+    (
+        sqlc.narg('performed_by')::bigint IS NULL 
+        OR performed_by = sqlc.narg('performed_by')
+    )
+    AND (
+        sqlc.narg('location')::text IS NULL 
+        OR location = sqlc.narg('location')
+    )
+    AND (
+        sqlc.narg('customer_id')::bigint IS NULL 
+        OR customer_id = sqlc.narg('customer_id')
+    )
+    AND (
+        sqlc.narg('start_date')::timestamptz IS NULL
+        OR created_at BETWEEN sqlc.narg('start_date')::timestamptz AND COALESCE(sqlc.narg('end_date')::timestamptz, now())
+    );
@@ -0,0 +1,145 @@
+-- name: CreateShift :one
+INSERT INTO shifts (user_id, location, opening_float, opening_note)
+VALUES (sqlc.arg('user_id'), sqlc.arg('location'), sqlc.arg('opening_float'), sqlc.narg('opening_note'))
+RETURNING *;
+
+-- name: GetShiftByID :one
+SELECT 
+    sh.*,
+    u.name AS user_name
+FROM shifts AS sh
+JOIN users AS u ON u.id = sh.user_id
+WHERE sh.id = $1;
+
+-- name: GetShiftForUpdate :one
+SELECT * FROM shifts WHERE id = $1 FOR UPDATE;
+
+-- name: GetOpenShiftByUser :one
+SELECT * FROM shifts WHERE user_id = $1 AND status = 'OPEN';
+
+-- name: CloseShift :one
+UPDATE shifts
+SET status = 'CLOSED',
+    closing_note = sqlc.narg('closing_note'),
+    closed_at = now()
+WHERE id = sqlc.arg('id') AND status = 'OPEN'
+RETURNING *;
+
+-- name: GetShiftPaymentTotals :many
+SELECT 
+    method,
+    COUNT(*) AS total_payments,
+    SUM(amount)::numeric AS total_amount
+FROM payments
+WHERE shift_id = $1 AND status = 'COMPLETED'
+GROUP BY method
+ORDER BY method;
+
+-- name: GetShiftSalesTotals :one
+SELECT 
+    COUNT(*) AS total_sales,
+    COALESCE(SUM(total_amount), 0)::numeric AS total_amount,
+    COALESCE(SUM(total_tax), 0)::numeric AS total_tax,
+    COALESCE(SUM(total_discount), 0)::numeric AS total_discount
+FROM sales
+WHERE shift_id = $1;
+
+-- name: CreateShiftTender :one
+INSERT INTO shift_tenders (shift_id, method, expected_amount, counted_amount, variance)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListShiftTenders :many
+SELECT * FROM shift_tenders
+WHERE shift_id = $1
+ORDER BY method;
+
+-- name: ListShifts :many
+SELECT 
+    sh.*,
+    u.name AS user_name
+FROM shifts AS sh
+JOIN users AS u ON u.id = sh.user_id
+WHERE 
+    (
+        sqlc.narg('user_id')::bigint IS NULL 
+        OR sh.user_id = sqlc.narg('user_id')
+    )
+    AND (
+        sqlc.narg('location')::text IS NULL 
+        OR sh.location = sqlc.narg('location')
+    )
+    AND (
+        sqlc.narg('status')::text IS NULL 
+        OR sh.status = sqlc.narg('status')
+    )
+    AND (
+        sqlc.narg('start_date')::timestamptz IS NULL 
+        OR sh.opened_at >= sqlc.narg('start_date')
+    )
+    AND (
+        sqlc.narg('end_date')::timestamptz IS NULL 
+        OR sh.opened_at < sqlc.narg('end_date')
+    )
+ORDER BY sh.opened_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListShiftsCount :one
+SELECT COUNT(*) AS total_shifts
+FROM shifts AS sh
+WHERE 
+    (
+        sqlc.narg('user_id')::bigint IS NULL 
+        OR sh.user_id = sqlc.narg('user_id')
+    )
+    AND (
+        sqlc.narg('location')::text IS NULL 
+        OR sh.location = sqlc.narg('location')
+    )
+    AND (
+        sqlc.narg('status')::text IS NULL 
+        OR sh.status = sqlc.narg('status')
+    )
+    AND (
+        sqlc.narg('start_date')::timestamptz IS NULL 
+        OR sh.opened_at >= sqlc.narg('start_date')
+    )
+    AND (
+        sqlc.narg('end_date')::timestamptz IS NULL 
+        OR sh.opened_at < sqlc.narg('end_date')
+    );
+
+-- name: ListLocationShifts :many
+SELECT 
+    sh.*,
+    u.name AS user_name
+FROM shifts AS sh
+JOIN users AS u ON u.id = sh.user_id
+WHERE sh.location = sqlc.arg('location')
+    AND sh.opened_at < sqlc.arg('end_date')
+    AND (sh.closed_at IS NULL OR sh.closed_at >= sqlc.arg('start_date')::timestamptz)
+ORDER BY sh.opened_at ASC;
+
+-- name: GetLocationSalesTotals :one
+SELECT 
+    COUNT(*) AS total_sales,
+    COALESCE(SUM(total_quantity), 0)::bigint AS total_quantity,
+    COALESCE(SUM(total_amount), 0)::numeric AS total_amount,
+    COALESCE(SUM(total_tax), 0)::numeric AS total_tax,
+    COALESCE(SUM(total_discount), 0)::numeric AS total_discount,
+    COALESCE(SUM(total_amount) FILTER (WHERE on_account), 0)::numeric AS on_account_amount
+FROM sales
+WHERE location = sqlc.arg('location')
+    AND created_at >= sqlc.arg('start_date') AND created_at < sqlc.arg('end_date');
+
+-- name: GetLocationPaymentTotals :many
+SELECT 
+    p.method,
+    COUNT(*) AS total_payments,
+    SUM(p.amount)::numeric AS total_amount
+FROM payments p
+JOIN sales s ON s.id = p.sale_id
+WHERE s.location = sqlc.arg('location') AND p.status = 'COMPLETED'
+    AND p.completed_at >= sqlc.arg('start_date')::timestamptz AND p.completed_at < sqlc.arg('end_date')::timestamptz
+GROUP BY p.method
+ORDER BY p.method;
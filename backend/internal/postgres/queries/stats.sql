@@ -27,4 +27,8 @@ SET total_users = coalesce(sqlc.narg('total_users'), total_users),
     total_stocks_removed_value = coalesce(sqlc.narg('total_stocks_removed_value'), total_stocks_removed_value),
     total_value = coalesce(sqlc.narg('total_value'), total_value)
 WHERE id = 1
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+-- name: GetRetailValue :one
+SELECT COALESCE(SUM(price * stock), 0)::numeric AS retail_value
+FROM products
+WHERE deleted = false;
@@ -0,0 +1,101 @@
+-- name: CreateSupplierReturn :one
+INSERT INTO supplier_returns (supplier_id, note, performed_by)
+VALUES (sqlc.arg('supplier_id'), sqlc.narg('note'), sqlc.arg('performed_by'))
+RETURNING *;
+
+-- name: UpdateSupplierReturnTotals :one
+UPDATE supplier_returns
+SET total_quantity = sqlc.arg('total_quantity'),
+    total_amount = sqlc.arg('total_amount')
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: CreateSupplierReturnItem :one
+INSERT INTO supplier_return_items (supplier_return_id, product_id, batch_number, quantity, unit_cost, line_total, reason, movement_id, return_id)
+VALUES (
+    sqlc.arg('supplier_return_id'), sqlc.arg('product_id'), sqlc.narg('batch_number'), sqlc.arg('quantity'), sqlc.arg('unit_cost'), 
+    sqlc.arg('line_total'), sqlc.narg('reason'), sqlc.narg('movement_id'), sqlc.narg('return_id')
+)
+RETURNING *;
+
+-- name: GetBatchUnitCost :one
+SELECT unit_cost FROM movements
+WHERE product_id = $1 AND batch_number = $2 AND type = 'ADD' AND reason IS NULL
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: GetSupplierReturnByID :one
+SELECT 
+    sr.*,
+    s.name AS supplier_name,
+    u.name AS user_name
+FROM supplier_returns AS sr
+JOIN suppliers AS s ON s.id = sr.supplier_id
+JOIN users AS u ON u.id = sr.performed_by
+WHERE sr.id = $1;
+
+-- name: ListSupplierReturnItems :many
+SELECT 
+    sri.*,
+    p.name AS product_name
+FROM supplier_return_items AS sri
+JOIN products AS p ON p.id = sri.product_id
+WHERE sri.supplier_return_id = $1
+ORDER BY sri.id;
+
+-- name: ListSupplierReturns :many
+SELECT 
+    sr.*,
+    s.name AS supplier_name,
+    u.name AS user_name
+FROM supplier_returns AS sr
+JOIN suppliers AS s ON s.id = sr.supplier_id
+JOIN users AS u ON u.id = sr.performed_by
+WHERE 
+    (
+        sqlc.narg('supplier_id')::bigint IS NULL 
+        OR sr.supplier_id = sqlc.narg('supplier_id')
+    )
+    AND (
+        sqlc.narg('credit_status')::text IS NULL 
+        OR sr.credit_status = sqlc.narg('credit_status')
+    )
+ORDER BY sr.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListSupplierReturnsCount :one
+SELECT COUNT(*) AS total_supplier_returns
+FROM supplier_returns
+WHERE 
+    (
+        sqlc.narg('supplier_id')::bigint IS NULL 
+        OR supplier_id = sqlc.narg('supplier_id')
+    )
+    AND (
+        sqlc.narg('credit_status')::text IS NULL 
+        OR credit_status = sqlc.narg('credit_status')
+    );
+
+-- name: ApplySupplierCredit :one
+UPDATE supplier_returns
+SET credit_received = credit_received + sqlc.arg('amount'),
+    credit_status = CASE 
+        WHEN credit_received + sqlc.arg('amount') >= total_amount THEN 'RECEIVED' 
+        ELSE 'PARTIAL' 
+    END
+WHERE id = sqlc.arg('id') AND credit_received + sqlc.arg('amount') <= total_amount
+RETURNING *;
+
+-- name: CreateSupplierCredit :one
+INSERT INTO supplier_credits (supplier_return_id, amount, reference, received_at, recorded_by)
+VALUES (sqlc.arg('supplier_return_id'), sqlc.arg('amount'), sqlc.narg('reference'), sqlc.arg('received_at'), sqlc.arg('recorded_by'))
+RETURNING *;
+
+-- name: ListSupplierCredits :many
+SELECT 
+    sc.*,
+    u.name AS user_name
+FROM supplier_credits AS sc
+JOIN users AS u ON u.id = sc.recorded_by
+WHERE sc.supplier_return_id = $1
+ORDER BY sc.received_at, sc.id;
@@ -0,0 +1,52 @@
+-- name: CreateSupplier :one
+INSERT INTO suppliers (name, contact_person, phone_number, email, address)
+VALUES (sqlc.arg('name'), sqlc.narg('contact_person'), sqlc.narg('phone_number'), sqlc.narg('email'), sqlc.narg('address'))
+RETURNING *;
+
+-- name: GetSupplierByID :one
+SELECT * FROM suppliers WHERE id = $1 AND deleted = false;
+
+-- name: UpdateSupplier :one
+UPDATE suppliers
+SET name = coalesce(sqlc.narg('name'), name),
+    contact_person = coalesce(sqlc.narg('contact_person'), contact_person),
+    phone_number = coalesce(sqlc.narg('phone_number'), phone_number),
+    email = coalesce(sqlc.narg('email'), email),
+    address = coalesce(sqlc.narg('address'), address)
+WHERE id = sqlc.arg('id') AND deleted = false
+RETURNING *;
+
+-- name: DeleteSupplier :exec
+UPDATE suppliers
+SET deleted = true
+WHERE id = $1;
+
+-- name: ListSuppliers :many
+SELECT * FROM suppliers
+WHERE 
+    (
+        COALESCE(sqlc.narg('search'), '') = '' 
+        OR LOWER(name) LIKE sqlc.narg('search')
+        OR LOWER(contact_person) LIKE sqlc.narg('search')
+    )
+    AND deleted = false
+ORDER BY name ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListSuppliersCount :one
+SELECT COUNT(*) AS total_suppliers
+FROM suppliers
+WHERE 
+    (
+        COALESCE(sqlc.narg('search'), '') = '' 
+        OR LOWER(name) LIKE sqlc.narg('search')
+        OR LOWER(contact_person) LIKE sqlc.narg('search')
+    )
+    AND deleted = false;
+
+-- name: GetSupplierCreditSummary :one
+SELECT 
+    COALESCE(SUM(total_amount), 0)::numeric AS credit_expected,
+    COALESCE(SUM(credit_received), 0)::numeric AS credit_received
+FROM supplier_returns
+WHERE supplier_id = $1;
@@ -0,0 +1,77 @@
+-- name: CreateTaxInvoice :exec
+INSERT INTO tax_invoices (sale_id)
+VALUES ($1);
+
+-- name: GetTaxInvoiceBySale :one
+SELECT * FROM tax_invoices WHERE sale_id = $1;
+
+-- name: LeaseTaxInvoice :one
+UPDATE tax_invoices
+SET attempts = attempts + 1,
+    next_attempt_at = sqlc.arg('lease_until')
+WHERE sale_id = sqlc.arg('sale_id') AND status = 'PENDING' AND next_attempt_at <= now()
+RETURNING *;
+
+-- name: LeaseDueTaxInvoices :many
+UPDATE tax_invoices
+SET attempts = attempts + 1,
+    next_attempt_at = sqlc.arg('lease_until')
+WHERE id IN (
+    SELECT ti.id FROM tax_invoices AS ti
+    WHERE ti.status = 'PENDING' AND ti.next_attempt_at <= now()
+    ORDER BY ti.next_attempt_at
+    LIMIT sqlc.arg('limit')
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: SignTaxInvoice :one
+UPDATE tax_invoices
+SET status = 'SIGNED',
+    last_error = NULL,
+    control_number = sqlc.arg('control_number'),
+    internal_data = sqlc.narg('internal_data'),
+    receipt_signature = sqlc.narg('receipt_signature'),
+    qr_code = sqlc.narg('qr_code'),
+    signed_at = sqlc.arg('signed_at')
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: FailTaxInvoice :one
+UPDATE tax_invoices
+SET status = sqlc.arg('status'),
+    last_error = sqlc.arg('last_error'),
+    next_attempt_at = sqlc.arg('next_attempt_at')
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: RequeueTaxInvoice :one
+UPDATE tax_invoices
+SET status = 'PENDING',
+    next_attempt_at = now()
+WHERE sale_id = $1 AND status <> 'SIGNED'
+RETURNING *;
+
+-- name: ListTaxInvoices :many
+SELECT 
+    ti.*,
+    s.receipt_number,
+    s.total_amount
+FROM tax_invoices AS ti
+JOIN sales AS s ON s.id = ti.sale_id
+WHERE 
+    (
+        sqlc.narg('status')::text IS NULL 
+        OR ti.status = sqlc.narg('status')
+    )
+ORDER BY ti.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListTaxInvoicesCount :one
+SELECT COUNT(*) AS total_invoices
+FROM tax_invoices
+WHERE 
+    (
+        sqlc.narg('status')::text IS NULL 
+        OR status = sqlc.narg('status')
+    );
@@ -0,0 +1,39 @@
+-- name: CreateTaxClass :one
+INSERT INTO tax_classes (code, name, type, rate)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetTaxClassByID :one
+SELECT * FROM tax_classes WHERE id = $1;
+
+-- name: GetDefaultTaxClass :one
+SELECT * FROM tax_classes
+WHERE type = 'EXEMPT'
+ORDER BY id
+LIMIT 1;
+
+-- name: UpdateTaxClass :one
+UPDATE tax_classes
+SET name = coalesce(sqlc.narg('name'), name),
+    rate = coalesce(sqlc.narg('rate'), rate)
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: ListTaxClasses :many
+SELECT * FROM tax_classes ORDER BY code;
+
+-- name: GetTaxSummary :many
+SELECT 
+    si.tax_code,
+    tc.name AS tax_name,
+    tc.type AS tax_type,
+    si.tax_rate,
+    COUNT(DISTINCT si.sale_id) AS total_sales,
+    COALESCE(SUM(si.line_total), 0)::numeric AS gross_amount,
+    COALESCE(SUM(si.tax_amount), 0)::numeric AS tax_amount
+FROM sale_items si
+JOIN sales s ON s.id = si.sale_id
+JOIN tax_classes tc ON tc.id = si.tax_class_id
+WHERE s.created_at >= sqlc.arg('start_date') AND s.created_at < sqlc.arg('end_date')
+GROUP BY si.tax_code, tc.name, tc.type, si.tax_rate
+ORDER BY si.tax_code, si.tax_rate;
@@ -0,0 +1,375 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.QuotationRepository = (*QuotationRepository)(nil)
+
+type QuotationRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewQuotationRepository(db *Store) *QuotationRepository {
+	return &QuotationRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (qr *QuotationRepository) Create(ctx context.Context, quotation *repository.Quotation) (*repository.Quotation, error) {
+	if len(quotation.Items) == 0 {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "a quotation must have at least one line")
+	}
+	if !quotation.ValidUntil.After(time.Now()) {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "a quotation must be valid until a future date")
+	}
+
+	err := qr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		createParams := generated.CreateQuotationParams{
+			CustomerID: pgtype.Int8{Valid: false},
+			Note:       stringToPgText(quotation.Note),
+			ValidUntil: quotation.ValidUntil,
+			CreatedBy:  int64(quotation.CreatedBy),
+		}
+		if quotation.CustomerID != nil {
+			createParams.CustomerID = pgtype.Int8{Int64: int64(*quotation.CustomerID), Valid: true}
+		}
+
+		qt, err := q.CreateQuotation(ctx, createParams)
+		if err != nil {
+			if pkg.PgxErrorCode(err) == pkg.FOREIGN_KEY_VIOLATION && quotation.CustomerID != nil {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "customer %d not found", *quotation.CustomerID)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create quotation: %s", err.Error())
+		}
+
+		var (
+			totalQuantity int64
+			totalAmount   int64
+			totalTax      int64
+			totalDiscount int64
+			taxClasses    = map[int64]generated.TaxClass{}
+		)
+		for _, item := range quotation.Items {
+			p, err := q.GetProductByID(ctx, int64(item.ProductID))
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return pkg.Errorf(pkg.NOT_FOUND_ERROR, "product %d not found", item.ProductID)
+				}
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get product: %s", err.Error())
+			}
+
+			taxClass, ok := taxClasses[p.TaxClassID]
+			if !ok {
+				taxClass, err = getTaxClassTx(ctx, q, p.TaxClassID)
+				if err != nil {
+					return err
+				}
+				taxClasses[p.TaxClassID] = taxClass
+			}
+
+			// priced as a sale would be now, including any running promotion
+			unitPrice := pkg.PgTypeNumericToFloat64(p.Price)
+			promotion, discount, err := bestPromotionTx(ctx, q, p.ID, unitPrice, item.Quantity, time.Now())
+			if err != nil {
+				return err
+			}
+			promotionID := pgtype.Int8{Valid: false}
+			if promotion != nil {
+				promotionID = pgtype.Int8{Int64: promotion.ID, Valid: true}
+			}
+
+			lineTotal := unitPrice*float64(item.Quantity) - discount
+			taxAmount := taxIncluded(lineTotal, pkg.PgTypeNumericToFloat64(taxClass.Rate))
+
+			if _, err := q.CreateQuotationItem(ctx, generated.CreateQuotationItemParams{
+				QuotationID:    qt.ID,
+				ProductID:      p.ID,
+				Quantity:       int32(item.Quantity),
+				UnitPrice:      p.Price,
+				DiscountAmount: pkg.Float64ToPgTypeNumeric(discount),
+				LineTotal:      pkg.Float64ToPgTypeNumeric(lineTotal),
+				PromotionID:    promotionID,
+				TaxCode:        taxClass.Code,
+				TaxRate:        taxClass.Rate,
+				TaxAmount:      pkg.Float64ToPgTypeNumeric(taxAmount),
+			}); err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create quotation item: %s", err.Error())
+			}
+
+			totalQuantity += item.Quantity
+			totalAmount += toCents(lineTotal)
+			totalTax += toCents(taxAmount)
+			totalDiscount += toCents(discount)
+		}
+
+		if err := q.UpdateQuotationTotals(ctx, generated.UpdateQuotationTotalsParams{
+			ID:            qt.ID,
+			TotalQuantity: totalQuantity,
+			TotalAmount:   pkg.Float64ToPgTypeNumeric(float64(totalAmount) / 100),
+			TotalTax:      pkg.Float64ToPgTypeNumeric(float64(totalTax) / 100),
+			TotalDiscount: pkg.Float64ToPgTypeNumeric(float64(totalDiscount) / 100),
+		}); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update quotation totals: %s", err.Error())
+		}
+
+		quotation.ID = uint32(qt.ID)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return qr.GetByID(ctx, int64(quotation.ID))
+}
+
+func (qr *QuotationRepository) Convert(ctx context.Context, id int64, sale *repository.Sale, acceptPriceChanges bool) (*repository.Sale, error) {
+	err := qr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		qt, err := lockOpenQuotationTx(ctx, q, id)
+		if err != nil {
+			return err
+		}
+		if !qt.ValidUntil.After(time.Now()) {
+			return pkg.Errorf(pkg.INVALID_ERROR, "quotation %s expired on %s", qt.QuotationNumber.String, qt.ValidUntil.Format("02/01/2006"))
+		}
+
+		items, err := q.ListQuotationItems(ctx, qt.ID)
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list quotation items: %s", err.Error())
+		}
+
+		// check every line first so the customer can be told about all of them at once
+		changes := []*repository.QuotationChange{}
+		for _, item := range items {
+			p, err := q.GetProductByID(ctx, item.ProductID)
+			if err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get product: %s", err.Error())
+			}
+
+			quotedPrice := pkg.PgTypeNumericToFloat64(item.UnitPrice)
+			currentPrice := pkg.PgTypeNumericToFloat64(p.Price)
+			available := p.Stock - p.ReservedStock
+			if p.Deleted {
+				available = 0
+			}
+
+			// the sale prices the line again, so a promotion that has ended or started
+			// changes what the customer pays even when the price has not
+			_, currentDiscount, err := bestPromotionTx(ctx, q, p.ID, currentPrice, int64(item.Quantity), time.Now())
+			if err != nil {
+				return err
+			}
+			quotedDiscount := pkg.PgTypeNumericToFloat64(item.DiscountAmount)
+			quotedLineTotal := pkg.PgTypeNumericToFloat64(item.LineTotal)
+			currentLineTotal := currentPrice*float64(item.Quantity) - currentDiscount
+
+			priceChanged := (toCents(quotedPrice) != toCents(currentPrice) ||
+				toCents(quotedDiscount) != toCents(currentDiscount) ||
+				toCents(quotedLineTotal) != toCents(currentLineTotal)) && !acceptPriceChanges
+			// kits are assembled from their components when sold
+			shortOfStock := available < int64(item.Quantity) && (!p.IsKit || p.Deleted)
+			if priceChanged || shortOfStock {
+				changes = append(changes, &repository.QuotationChange{
+					ProductID:      uint32(p.ID),
+					ProductName:    p.Name,
+					Quantity:       int64(item.Quantity),
+					QuotedPrice:    quotedPrice,
+					CurrentPrice:   currentPrice,
+					AvailableStock: available,
+
+					QuotedDiscount:   quotedDiscount,
+					CurrentDiscount:  currentDiscount,
+					QuotedLineTotal:  quotedLineTotal,
+					CurrentLineTotal: currentLineTotal,
+				})
+			}
+		}
+		if len(changes) > 0 {
+			return pkg.Errorf(pkg.INVALID_ERROR, "quotation %s can no longer be sold as quoted", qt.QuotationNumber.String).WithDetails(changes)
+		}
+
+		// prescriptions are given per product when the quotation is accepted
+		prescriptions := make(map[uint32]*uint32, len(sale.Items))
+		for _, item := range sale.Items {
+			prescriptions[item.ProductID] = item.PrescriptionID
+		}
+
+		sale.Items = make([]*repository.SaleItem, len(items))
+		for i, item := range items {
+			sale.Items[i] = &repository.SaleItem{
+				ProductID:      uint32(item.ProductID),
+				Quantity:       int64(item.Quantity),
+				PrescriptionID: prescriptions[uint32(item.ProductID)],
+			}
+		}
+		if sale.CustomerID == nil {
+			sale.CustomerID = pgInt8ToUint32(qt.CustomerID)
+		}
+
+		if err := createSaleTx(ctx, q, sale); err != nil {
+			return err
+		}
+
+		if err := q.ResolveQuotation(ctx, generated.ResolveQuotationParams{
+			ID:         qt.ID,
+			Status:     repository.QUOTATION_CONVERTED,
+			SaleID:     pgtype.Int8{Int64: int64(sale.ID), Valid: true},
+			ResolvedBy: pgtype.Int8{Int64: int64(sale.PerformedBy), Valid: true},
+		}); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to resolve quotation: %s", err.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+func (qr *QuotationRepository) Cancel(ctx context.Context, id int64, cancelledBy uint32) (*repository.Quotation, error) {
+	err := qr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		qt, err := lockOpenQuotationTx(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		if err := q.ResolveQuotation(ctx, generated.ResolveQuotationParams{
+			ID:         qt.ID,
+			Status:     repository.QUOTATION_CANCELLED,
+			SaleID:     pgtype.Int8{Valid: false},
+			ResolvedBy: pgtype.Int8{Int64: int64(cancelledBy), Valid: true},
+		}); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to cancel quotation: %s", err.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return qr.GetByID(ctx, id)
+}
+
+// lockOpenQuotationTx locks the quotation and checks it has not been converted or cancelled.
+func lockOpenQuotationTx(ctx context.Context, q *generated.Queries, id int64) (generated.Quotation, error) {
+	qt, err := q.GetQuotationForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return qt, pkg.Errorf(pkg.NOT_FOUND_ERROR, "quotation with id %d not found", id)
+		}
+		return qt, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get quotation: %s", err.Error())
+	}
+
+	if qt.Status != repository.QUOTATION_OPEN {
+		return qt, pkg.Errorf(pkg.INVALID_ERROR, "quotation %s is already %s", qt.QuotationNumber.String, qt.Status)
+	}
+
+	return qt, nil
+}
+
+func (qr *QuotationRepository) GetByID(ctx context.Context, id int64) (*repository.Quotation, error) {
+	qt, err := qr.queries.GetQuotationByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "quotation with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get quotation: %s", err.Error())
+	}
+
+	items, err := qr.queries.ListQuotationItems(ctx, id)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list quotation items: %s", err.Error())
+	}
+
+	quotation := pgQuotationToRepoQuotation(generated.ListQuotationsRow(qt))
+	quotation.Items = make([]*repository.QuotationItem, len(items))
+	for i, item := range items {
+		quotation.Items[i] = &repository.QuotationItem{
+			ID:             uint32(item.ID),
+			QuotationID:    uint32(item.QuotationID),
+			ProductID:      uint32(item.ProductID),
+			Quantity:       int64(item.Quantity),
+			UnitPrice:      pkg.PgTypeNumericToFloat64(item.UnitPrice),
+			DiscountAmount: pkg.PgTypeNumericToFloat64(item.DiscountAmount),
+			LineTotal:      pkg.PgTypeNumericToFloat64(item.LineTotal),
+			PromotionID:    pgInt8ToUint32(item.PromotionID),
+			TaxCode:        item.TaxCode,
+			TaxRate:        pkg.PgTypeNumericToFloat64(item.TaxRate),
+			TaxAmount:      pkg.PgTypeNumericToFloat64(item.TaxAmount),
+
+			ProductName:   item.ProductName,
+			PromotionName: pgTextToString(item.PromotionName),
+		}
+	}
+
+	return quotation, nil
+}
+
+func (qr *QuotationRepository) List(ctx context.Context, filter *repository.QuotationFilter) ([]*repository.Quotation, *pkg.Pagination, error) {
+	listParams := generated.ListQuotationsParams{
+		Limit:      int32(filter.Pagination.PageSize),
+		Offset:     pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+		Status:     stringToPgText(filter.Status),
+		CustomerID: pgtype.Int8{Valid: false},
+	}
+	if filter.CustomerID != nil {
+		listParams.CustomerID = pgtype.Int8{Int64: int64(*filter.CustomerID), Valid: true}
+	}
+
+	quotations, err := qr.queries.ListQuotations(ctx, listParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list quotations: %s", err.Error())
+	}
+
+	totalCount, err := qr.queries.ListQuotationsCount(ctx, generated.ListQuotationsCountParams{
+		Status:     listParams.Status,
+		CustomerID: listParams.CustomerID,
+	})
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count quotations: %s", err.Error())
+	}
+
+	repoQuotations := make([]*repository.Quotation, len(quotations))
+	for i, qt := range quotations {
+		repoQuotations[i] = pgQuotationToRepoQuotation(qt)
+	}
+
+	return repoQuotations, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
+}
+
+func pgQuotationToRepoQuotation(qt generated.ListQuotationsRow) *repository.Quotation {
+	return &repository.Quotation{
+		ID:              uint32(qt.ID),
+		QuotationNumber: qt.QuotationNumber.String,
+		CustomerID:      pgInt8ToUint32(qt.CustomerID),
+		Status:          qt.Status,
+		Note:            pgTextToString(qt.Note),
+		ValidUntil:      qt.ValidUntil,
+		TotalQuantity:   qt.TotalQuantity,
+		TotalAmount:     pkg.PgTypeNumericToFloat64(qt.TotalAmount),
+		TotalTax:        pkg.PgTypeNumericToFloat64(qt.TotalTax),
+		TotalDiscount:   pkg.PgTypeNumericToFloat64(qt.TotalDiscount),
+		SaleID:          pgInt8ToUint32(qt.SaleID),
+		CreatedBy:       uint32(qt.CreatedBy),
+		ResolvedBy:      pgInt8ToUint32(qt.ResolvedBy),
+		ResolvedAt:      pgTimestamptzToTime(qt.ResolvedAt),
+		CreatedAt:       qt.CreatedAt,
+
+		UserName:     qt.UserName,
+		CustomerName: pgTextToString(qt.CustomerName),
+	}
+}
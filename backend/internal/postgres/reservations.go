@@ -0,0 +1,322 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.ReservationRepository = (*ReservationRepository)(nil)
+
+type ReservationRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewReservationRepository(db *Store) *ReservationRepository {
+	return &ReservationRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (rr *ReservationRepository) Create(ctx context.Context, reservation *repository.Reservation) (*repository.Reservation, error) {
+	if len(reservation.Items) == 0 {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "a reservation must have at least one line")
+	}
+	if !reservation.ExpiresAt.After(time.Now()) {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "a reservation must expire in the future")
+	}
+
+	err := rr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		createParams := generated.CreateReservationParams{
+			CustomerID: pgtype.Int8{Valid: false},
+			Note:       pgtype.Text{Valid: false},
+			ExpiresAt:  reservation.ExpiresAt,
+			CreatedBy:  int64(reservation.CreatedBy),
+		}
+		if reservation.CustomerID != nil {
+			createParams.CustomerID = pgtype.Int8{Int64: int64(*reservation.CustomerID), Valid: true}
+		}
+		if reservation.Note != nil {
+			createParams.Note = pgtype.Text{String: *reservation.Note, Valid: true}
+		}
+
+		r, err := q.CreateReservation(ctx, createParams)
+		if err != nil {
+			if pkg.PgxErrorCode(err) == pkg.FOREIGN_KEY_VIOLATION && reservation.CustomerID != nil {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "customer %d not found", *reservation.CustomerID)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create reservation: %s", err.Error())
+		}
+
+		for _, item := range reservation.Items {
+			if _, err := q.ReserveStock(ctx, generated.ReserveStockParams{
+				ID:       int64(item.ProductID),
+				Quantity: item.Quantity,
+			}); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return pkg.Errorf(pkg.INVALID_ERROR, "product %d not found or not enough available stock to reserve %d", item.ProductID, item.Quantity)
+				}
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to reserve stock: %s", err.Error())
+			}
+
+			if _, err := q.CreateReservationItem(ctx, generated.CreateReservationItemParams{
+				ReservationID: r.ID,
+				ProductID:     int64(item.ProductID),
+				Quantity:      int32(item.Quantity),
+			}); err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create reservation item: %s", err.Error())
+			}
+		}
+
+		reservation.ID = uint32(r.ID)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rr.GetByID(ctx, int64(reservation.ID))
+}
+
+func (rr *ReservationRepository) Convert(ctx context.Context, id int64, sale *repository.Sale) (*repository.Sale, error) {
+	err := rr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		r, err := lockActiveReservationTx(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		items, err := q.ListReservationItems(ctx, r.ID)
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list reservation items: %s", err.Error())
+		}
+
+		// prescriptions are given per product when the reservation is collected
+		prescriptions := make(map[uint32]*uint32, len(sale.Items))
+		for _, item := range sale.Items {
+			prescriptions[item.ProductID] = item.PrescriptionID
+		}
+
+		sale.Items = make([]*repository.SaleItem, len(items))
+		for i, item := range items {
+			sale.Items[i] = &repository.SaleItem{
+				ProductID:      uint32(item.ProductID),
+				Quantity:       int64(item.Quantity),
+				PrescriptionID: prescriptions[uint32(item.ProductID)],
+			}
+		}
+		if sale.CustomerID == nil {
+			sale.CustomerID = pgInt8ToUint32(r.CustomerID)
+		}
+
+		if err := q.ReleaseReservedStock(ctx, r.ID); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to release reserved stock: %s", err.Error())
+		}
+
+		if err := createSaleTx(ctx, q, sale); err != nil {
+			return err
+		}
+
+		if _, err := q.ResolveReservation(ctx, generated.ResolveReservationParams{
+			ID:         r.ID,
+			Status:     repository.RESERVATION_CONVERTED,
+			SaleID:     pgtype.Int8{Int64: int64(sale.ID), Valid: true},
+			ResolvedBy: pgtype.Int8{Int64: int64(sale.PerformedBy), Valid: true},
+		}); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to resolve reservation: %s", err.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+func (rr *ReservationRepository) Release(ctx context.Context, id int64, releasedBy uint32) (*repository.Reservation, error) {
+	err := rr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		r, err := lockActiveReservationTx(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		if err := q.ReleaseReservedStock(ctx, r.ID); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to release reserved stock: %s", err.Error())
+		}
+
+		if _, err := q.ResolveReservation(ctx, generated.ResolveReservationParams{
+			ID:         r.ID,
+			Status:     repository.RESERVATION_RELEASED,
+			SaleID:     pgtype.Int8{Valid: false},
+			ResolvedBy: pgtype.Int8{Int64: int64(releasedBy), Valid: true},
+		}); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to resolve reservation: %s", err.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rr.GetByID(ctx, id)
+}
+
+func (rr *ReservationRepository) ExpireDue(ctx context.Context) (int64, error) {
+	var expired int64
+	err := rr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		ids, err := q.ListExpiredReservationIDs(ctx)
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list expired reservations: %s", err.Error())
+		}
+
+		for _, id := range ids {
+			if err := q.ReleaseReservedStock(ctx, id); err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to release reserved stock: %s", err.Error())
+			}
+
+			if _, err := q.ResolveReservation(ctx, generated.ResolveReservationParams{
+				ID:         id,
+				Status:     repository.RESERVATION_EXPIRED,
+				SaleID:     pgtype.Int8{Valid: false},
+				ResolvedBy: pgtype.Int8{Valid: false},
+			}); err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to expire reservation: %s", err.Error())
+			}
+		}
+		expired = int64(len(ids))
+
+		return nil
+	})
+
+	return expired, err
+}
+
+// lockActiveReservationTx locks the reservation and checks it still holds stock.
+func lockActiveReservationTx(ctx context.Context, q *generated.Queries, id int64) (generated.Reservation, error) {
+	r, err := q.GetReservationForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return r, pkg.Errorf(pkg.NOT_FOUND_ERROR, "reservation with id %d not found", id)
+		}
+		return r, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get reservation: %s", err.Error())
+	}
+
+	// the expiry job may not have run yet
+	if r.Status == repository.RESERVATION_ACTIVE && !r.ExpiresAt.After(time.Now()) {
+		return r, pkg.Errorf(pkg.INVALID_ERROR, "reservation %d has expired", id)
+	}
+	if r.Status != repository.RESERVATION_ACTIVE {
+		return r, pkg.Errorf(pkg.INVALID_ERROR, "reservation %d is already %s", id, r.Status)
+	}
+
+	return r, nil
+}
+
+func (rr *ReservationRepository) GetByID(ctx context.Context, id int64) (*repository.Reservation, error) {
+	r, err := rr.queries.GetReservationByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "reservation with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get reservation: %s", err.Error())
+	}
+
+	items, err := rr.queries.ListReservationItems(ctx, id)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list reservation items: %s", err.Error())
+	}
+
+	reservation := &repository.Reservation{
+		ID:         uint32(r.ID),
+		CustomerID: pgInt8ToUint32(r.CustomerID),
+		Status:     r.Status,
+		Note:       pgTextToString(r.Note),
+		ExpiresAt:  r.ExpiresAt,
+		SaleID:     pgInt8ToUint32(r.SaleID),
+		CreatedBy:  uint32(r.CreatedBy),
+		ResolvedBy: pgInt8ToUint32(r.ResolvedBy),
+		ResolvedAt: pgTimestamptzToTime(r.ResolvedAt),
+		CreatedAt:  r.CreatedAt,
+		Items:      make([]*repository.ReservationItem, len(items)),
+
+		UserName:     r.UserName,
+		CustomerName: pgTextToString(r.CustomerName),
+	}
+	for i, item := range items {
+		reservation.Items[i] = &repository.ReservationItem{
+			ID:            uint32(item.ID),
+			ReservationID: uint32(item.ReservationID),
+			ProductID:     uint32(item.ProductID),
+			Quantity:      int64(item.Quantity),
+
+			ProductName: item.ProductName,
+		}
+	}
+
+	return reservation, nil
+}
+
+func (rr *ReservationRepository) List(ctx context.Context, filter *repository.ReservationFilter) ([]*repository.Reservation, *pkg.Pagination, error) {
+	listParams := generated.ListReservationsParams{
+		Limit:      int32(filter.Pagination.PageSize),
+		Offset:     pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+		Status:     pgtype.Text{Valid: false},
+		CustomerID: pgtype.Int8{Valid: false},
+	}
+
+	countParams := generated.ListReservationsCountParams{
+		Status:     pgtype.Text{Valid: false},
+		CustomerID: pgtype.Int8{Valid: false},
+	}
+
+	if filter.Status != nil {
+		listParams.Status = pgtype.Text{String: *filter.Status, Valid: true}
+		countParams.Status = pgtype.Text{String: *filter.Status, Valid: true}
+	}
+	if filter.CustomerID != nil {
+		listParams.CustomerID = pgtype.Int8{Int64: int64(*filter.CustomerID), Valid: true}
+		countParams.CustomerID = pgtype.Int8{Int64: int64(*filter.CustomerID), Valid: true}
+	}
+
+	reservations, err := rr.queries.ListReservations(ctx, listParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list reservations: %s", err.Error())
+	}
+
+	totalCount, err := rr.queries.ListReservationsCount(ctx, countParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count reservations: %s", err.Error())
+	}
+
+	repoReservations := make([]*repository.Reservation, len(reservations))
+	for i, r := range reservations {
+		repoReservations[i] = &repository.Reservation{
+			ID:         uint32(r.ID),
+			CustomerID: pgInt8ToUint32(r.CustomerID),
+			Status:     r.Status,
+			Note:       pgTextToString(r.Note),
+			ExpiresAt:  r.ExpiresAt,
+			SaleID:     pgInt8ToUint32(r.SaleID),
+			CreatedBy:  uint32(r.CreatedBy),
+			ResolvedBy: pgInt8ToUint32(r.ResolvedBy),
+			ResolvedAt: pgTimestamptzToTime(r.ResolvedAt),
+			CreatedAt:  r.CreatedAt,
+
+			UserName:     r.UserName,
+			CustomerName: pgTextToString(r.CustomerName),
+		}
+	}
+
+	return repoReservations, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
+}
@@ -0,0 +1,356 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.ReturnRepository = (*ReturnRepository)(nil)
+
+type ReturnRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewReturnRepository(db *Store) *ReturnRepository {
+	return &ReturnRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (rr *ReturnRepository) Create(ctx context.Context, ret *repository.Return) (*repository.Return, error) {
+	if ret.Status != repository.RETURN_RESTOCKED && ret.Status != repository.RETURN_QUARANTINED {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "a return is either restocked or quarantined")
+	}
+
+	err := rr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		movementID := int64(ret.MovementID)
+		if movementID == 0 {
+			if ret.SaleItemID == nil {
+				return pkg.Errorf(pkg.INVALID_ERROR, "a return must reference a movement or a sale item")
+			}
+
+			id, err := q.GetSaleItemMovementID(ctx, int64(*ret.SaleItemID))
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return pkg.Errorf(pkg.NOT_FOUND_ERROR, "sale item %d not found", *ret.SaleItemID)
+				}
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get sale line: %s", err.Error())
+			}
+			movementID = id
+		}
+
+		// lock the original movement so concurrent returns cannot exceed its quantity
+		original, err := q.GetMovementForUpdate(ctx, movementID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "movement %d not found", movementID)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get movement: %s", err.Error())
+		}
+		if original.Type != repository.MOVEMENT_REMOVE || original.Reason.Valid {
+			return pkg.Errorf(pkg.INVALID_ERROR, "only stock issued to customers can be returned")
+		}
+
+		returned, err := q.GetReturnedQuantity(ctx, original.ID)
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get returned quantity: %s", err.Error())
+		}
+		if returnable := int64(original.Quantity) - returned; int64(ret.Quantity) > returnable {
+			return pkg.Errorf(pkg.INVALID_ERROR, "only %d of movement %d can still be returned", returnable, original.ID)
+		}
+
+		// goods go back into the batch they were issued from when it is known
+		batchNumber := original.BatchNumber
+		if !batchNumber.Valid && ret.BatchNumber != nil {
+			batchNumber = pgtype.Text{String: *ret.BatchNumber, Valid: true}
+		}
+
+		createParams := generated.CreateReturnParams{
+			MovementID:        original.ID,
+			SaleID:            original.SaleID,
+			ProductID:         original.ProductID,
+			CustomerID:        original.CustomerID,
+			Quantity:          ret.Quantity,
+			Status:            ret.Status,
+			Reason:            pgtype.Text{Valid: false},
+			BatchNumber:       batchNumber,
+			RestockMovementID: pgtype.Int8{Valid: false},
+			PerformedBy:       int64(ret.PerformedBy),
+		}
+		if ret.Reason != nil {
+			createParams.Reason = pgtype.Text{String: *ret.Reason, Valid: true}
+		}
+
+		if ret.Status == repository.RETURN_RESTOCKED {
+			_, movement, err := restockReturnTx(ctx, q, original, ret.Quantity, batchNumber, ret.PerformedBy, ret.WitnessedBy)
+			if err != nil {
+				return err
+			}
+			createParams.RestockMovementID = pgtype.Int8{Int64: movement.ID, Valid: true}
+		} else {
+			if err := quarantineReturnTx(ctx, q, original, ret.Quantity, batchNumber, ret.PerformedBy, ret.WitnessedBy); err != nil {
+				return err
+			}
+		}
+
+		r, err := q.CreateReturn(ctx, createParams)
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create return: %s", err.Error())
+		}
+		ret.ID = uint32(r.ID)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rr.GetByID(ctx, int64(ret.ID))
+}
+
+func (rr *ReturnRepository) Resolve(ctx context.Context, id int64, status string, resolvedBy uint32, witnessedBy *uint32) (*repository.Return, error) {
+	if status != repository.RETURN_RESTOCKED && status != repository.RETURN_DISPOSED {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "quarantined goods are either restocked or disposed")
+	}
+
+	err := rr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		r, err := q.GetReturnForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "return with id %d not found", id)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get return: %s", err.Error())
+		}
+		if r.Status != repository.RETURN_QUARANTINED {
+			return pkg.Errorf(pkg.INVALID_ERROR, "only quarantined returns can be resolved")
+		}
+
+		_, movement, err := releaseQuarantineTx(ctx, q, r, r.BatchNumber, resolvedBy, witnessedBy)
+		if err != nil {
+			return err
+		}
+
+		resolveParams := generated.ResolveReturnParams{
+			ID:                id,
+			Status:            status,
+			RestockMovementID: pgtype.Int8{Valid: false},
+			ResolvedBy:        pgtype.Int8{Int64: int64(resolvedBy), Valid: true},
+		}
+
+		if status == repository.RETURN_RESTOCKED {
+			resolveParams.RestockMovementID = pgtype.Int8{Int64: movement.ID, Valid: true}
+		} else {
+			// disposal is written off like any other destroyed stock, witnessed and
+			// in the register for controlled products
+			reason := repository.MOVEMENT_REASON_WRITE_OFF
+			note := fmt.Sprintf("disposal of return #%d", r.ID)
+			if _, _, err := removeStockTx(ctx, q, &repository.ProductStockUpdate{
+				ID:          uint32(r.ProductID),
+				PerformedBy: resolvedBy,
+				Quantity:    int64(r.Quantity),
+				Note:        &note,
+				BatchNumber: pgTextToString(r.BatchNumber),
+				WitnessedBy: witnessedBy,
+				Reason:      &reason,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if _, err := q.ResolveReturn(ctx, resolveParams); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to resolve return: %s", err.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rr.GetByID(ctx, id)
+}
+
+// restockReturnTx puts returned goods back into stock at the cost they were issued at.
+func restockReturnTx(ctx context.Context, q *generated.Queries, original generated.Movement, quantity int32, batchNumber pgtype.Text, performedBy uint32, witnessedBy *uint32) (generated.Product, generated.Movement, error) {
+	unitCost := pkg.PgTypeNumericToFloat64(original.UnitCost)
+	note := fmt.Sprintf("return of movement #%d", original.ID)
+	reason := repository.MOVEMENT_REASON_CUSTOMER_RETURN
+
+	return addStockTx(ctx, q, &repository.ProductStockUpdate{
+		ID:          uint32(original.ProductID),
+		PerformedBy: performedBy,
+		Quantity:    int64(quantity),
+		UnitCost:    &unitCost,
+		Note:        &note,
+		BatchNumber: pgTextToString(batchNumber),
+		CustomerID:  pgInt8ToUint32(original.CustomerID),
+		WitnessedBy: witnessedBy,
+		Reason:      &reason,
+	})
+}
+
+// quarantineReturnTx books returned goods in as a customer return and straight
+// back out into quarantine, so the goods held outside stock have movements and,
+// for controlled products, witnessed register entries.
+func quarantineReturnTx(ctx context.Context, q *generated.Queries, original generated.Movement, quantity int32, batchNumber pgtype.Text, performedBy uint32, witnessedBy *uint32) error {
+	if _, _, err := restockReturnTx(ctx, q, original, quantity, batchNumber, performedBy, witnessedBy); err != nil {
+		return err
+	}
+
+	note := fmt.Sprintf("quarantine of movement #%d", original.ID)
+	reason := repository.MOVEMENT_REASON_QUARANTINE
+	if _, _, err := removeStockTx(ctx, q, &repository.ProductStockUpdate{
+		ID:          uint32(original.ProductID),
+		PerformedBy: performedBy,
+		Quantity:    int64(quantity),
+		Note:        &note,
+		BatchNumber: pgTextToString(batchNumber),
+		WitnessedBy: witnessedBy,
+		Reason:      &reason,
+	}); err != nil {
+		return err
+	}
+
+	if _, err := q.QuarantineStock(ctx, generated.QuarantineStockParams{
+		ID:       original.ProductID,
+		Quantity: int64(quantity),
+	}); err != nil {
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to quarantine stock: %s", err.Error())
+	}
+
+	return nil
+}
+
+// releaseQuarantineTx books the goods of a quarantined return back into stock at
+// the cost they were issued at.
+func releaseQuarantineTx(ctx context.Context, q *generated.Queries, r generated.Return, batchNumber pgtype.Text, performedBy uint32, witnessedBy *uint32) (generated.Product, generated.Movement, error) {
+	if _, err := q.QuarantineStock(ctx, generated.QuarantineStockParams{
+		ID:       r.ProductID,
+		Quantity: -int64(r.Quantity),
+	}); err != nil {
+		return generated.Product{}, generated.Movement{}, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to release quarantined stock: %s", err.Error())
+	}
+
+	original, err := q.GetMovementByID(ctx, r.MovementID)
+	if err != nil {
+		return generated.Product{}, generated.Movement{}, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get movement: %s", err.Error())
+	}
+
+	unitCost := pkg.PgTypeNumericToFloat64(original.UnitCost)
+	note := fmt.Sprintf("release of return #%d from quarantine", r.ID)
+	reason := repository.MOVEMENT_REASON_QUARANTINE
+
+	return addStockTx(ctx, q, &repository.ProductStockUpdate{
+		ID:          uint32(r.ProductID),
+		PerformedBy: performedBy,
+		Quantity:    int64(r.Quantity),
+		UnitCost:    &unitCost,
+		Note:        &note,
+		BatchNumber: pgTextToString(batchNumber),
+		WitnessedBy: witnessedBy,
+		Reason:      &reason,
+	})
+}
+
+func (rr *ReturnRepository) GetByID(ctx context.Context, id int64) (*repository.Return, error) {
+	r, err := rr.queries.GetReturnByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "return with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get return: %s", err.Error())
+	}
+
+	return &repository.Return{
+		ID:                uint32(r.ID),
+		MovementID:        uint32(r.MovementID),
+		SaleID:            pgInt8ToUint32(r.SaleID),
+		ProductID:         uint32(r.ProductID),
+		CustomerID:        pgInt8ToUint32(r.CustomerID),
+		Quantity:          r.Quantity,
+		Status:            r.Status,
+		Reason:            pgTextToString(r.Reason),
+		BatchNumber:       pgTextToString(r.BatchNumber),
+		RestockMovementID: pgInt8ToUint32(r.RestockMovementID),
+		PerformedBy:       uint32(r.PerformedBy),
+		ResolvedBy:        pgInt8ToUint32(r.ResolvedBy),
+		ResolvedAt:        pgTimestamptzToTime(r.ResolvedAt),
+		CreatedAt:         r.CreatedAt,
+
+		ProductName: r.ProductName,
+		UserName:    r.UserName,
+	}, nil
+}
+
+func (rr *ReturnRepository) List(ctx context.Context, filter *repository.ReturnFilter) ([]*repository.Return, *pkg.Pagination, error) {
+	listParams := generated.ListReturnsParams{
+		Limit:     int32(filter.Pagination.PageSize),
+		Offset:    pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+		Status:    pgtype.Text{Valid: false},
+		ProductID: pgtype.Int8{Valid: false},
+		SaleID:    pgtype.Int8{Valid: false},
+	}
+
+	countParams := generated.ListReturnsCountParams{
+		Status:    pgtype.Text{Valid: false},
+		ProductID: pgtype.Int8{Valid: false},
+		SaleID:    pgtype.Int8{Valid: false},
+	}
+
+	if filter.Status != nil {
+		listParams.Status = pgtype.Text{String: *filter.Status, Valid: true}
+		countParams.Status = pgtype.Text{String: *filter.Status, Valid: true}
+	}
+	if filter.ProductID != nil {
+		listParams.ProductID = pgtype.Int8{Int64: int64(*filter.ProductID), Valid: true}
+		countParams.ProductID = pgtype.Int8{Int64: int64(*filter.ProductID), Valid: true}
+	}
+	if filter.SaleID != nil {
+		listParams.SaleID = pgtype.Int8{Int64: int64(*filter.SaleID), Valid: true}
+		countParams.SaleID = pgtype.Int8{Int64: int64(*filter.SaleID), Valid: true}
+	}
+
+	returns, err := rr.queries.ListReturns(ctx, listParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list returns: %s", err.Error())
+	}
+
+	totalCount, err := rr.queries.ListReturnsCount(ctx, countParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count returns: %s", err.Error())
+	}
+
+	repoReturns := make([]*repository.Return, len(returns))
+	for i, r := range returns {
+		repoReturns[i] = &repository.Return{
+			ID:                uint32(r.ID),
+			MovementID:        uint32(r.MovementID),
+			SaleID:            pgInt8ToUint32(r.SaleID),
+			ProductID:         uint32(r.ProductID),
+			CustomerID:        pgInt8ToUint32(r.CustomerID),
+			Quantity:          r.Quantity,
+			Status:            r.Status,
+			Reason:            pgTextToString(r.Reason),
+			BatchNumber:       pgTextToString(r.BatchNumber),
+			RestockMovementID: pgInt8ToUint32(r.RestockMovementID),
+			PerformedBy:       uint32(r.PerformedBy),
+			ResolvedBy:        pgInt8ToUint32(r.ResolvedBy),
+			ResolvedAt:        pgTimestamptzToTime(r.ResolvedAt),
+			CreatedAt:         r.CreatedAt,
+
+			ProductName: r.ProductName,
+			UserName:    r.UserName,
+		}
+	}
+
+	return repoReturns, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
+}
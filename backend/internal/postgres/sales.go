@@ -0,0 +1,443 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.SaleRepository = (*SaleRepository)(nil)
+
+type SaleRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewSaleRepository(db *Store) *SaleRepository {
+	return &SaleRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (sr *SaleRepository) Create(ctx context.Context, sale *repository.Sale) (*repository.Sale, error) {
+	if len(sale.Items) == 0 {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "a sale must have at least one line")
+	}
+
+	err := sr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		return createSaleTx(ctx, q, sale)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+// createSaleTx numbers the sale, removes stock for every line and records the sale
+// using the queries of an already open transaction.
+func createSaleTx(ctx context.Context, q *generated.Queries, sale *repository.Sale) error {
+	// the sequence row stays locked until the transaction ends so receipt numbers
+	// are handed out in order and a rolled back sale does not leave a gap
+	number, err := q.NextReceiptNumber(ctx, sale.Location)
+	if err != nil {
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get receipt number: %s", err.Error())
+	}
+
+	createParams := generated.CreateSaleParams{
+		Note:          pgtype.Text{Valid: false},
+		PerformedBy:   int64(sale.PerformedBy),
+		Location:      sale.Location,
+		ReceiptNumber: fmt.Sprintf("%s-%06d", sale.Location, number),
+		CustomerID:    pgtype.Int8{Valid: false},
+		OnAccount:     sale.OnAccount,
+		DueDate:       pgtype.Timestamptz{Valid: false},
+	}
+
+	shiftID, shiftLocation, err := openShiftIDTx(ctx, q, sale.PerformedBy)
+	if err != nil {
+		return err
+	}
+	if shiftID.Valid && shiftLocation != sale.Location {
+		return pkg.Errorf(pkg.INVALID_ERROR, "sale location %s does not match the open shift at %s", sale.Location, shiftLocation)
+	}
+	createParams.ShiftID = shiftID
+
+	var creditLimit float64
+	if sale.OnAccount {
+		if sale.CustomerID == nil {
+			return pkg.Errorf(pkg.INVALID_ERROR, "a sale on account must have a customer")
+		}
+
+		// the customer row stays locked so concurrent sales cannot both use the same credit
+		credit, err := q.GetCustomerCreditForUpdate(ctx, int64(*sale.CustomerID))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "customer %d not found", *sale.CustomerID)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get customer credit: %s", err.Error())
+		}
+
+		creditLimit = pkg.PgTypeNumericToFloat64(credit.CreditLimit)
+		if creditLimit <= 0 {
+			return pkg.Errorf(pkg.INVALID_ERROR, "customer %d does not have a credit account", *sale.CustomerID)
+		}
+		createParams.DueDate = pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, int(credit.PaymentTermsDays)), Valid: true}
+	}
+	if sale.SchemeID != nil {
+		if sale.MemberNumber == nil || *sale.MemberNumber == "" {
+			return pkg.Errorf(pkg.INVALID_ERROR, "an insured sale must have the member number")
+		}
+
+		scheme, err := q.GetInsuranceSchemeByID(ctx, int64(*sale.SchemeID))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "insurance scheme %d not found", *sale.SchemeID)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get insurance scheme: %s", err.Error())
+		}
+		if !scheme.Active {
+			return pkg.Errorf(pkg.INVALID_ERROR, "insurance scheme %s is no longer active", scheme.Name)
+		}
+
+		createParams.SchemeID = pgtype.Int8{Int64: scheme.ID, Valid: true}
+		createParams.MemberNumber = stringToPgText(sale.MemberNumber)
+		sale.SchemeName = &scheme.Name
+	}
+	if sale.Note != nil {
+		createParams.Note = pgtype.Text{String: *sale.Note, Valid: true}
+	}
+	if sale.CustomerID != nil {
+		createParams.CustomerID = pgtype.Int8{Int64: int64(*sale.CustomerID), Valid: true}
+	}
+
+	s, err := q.CreateSale(ctx, createParams)
+	if err != nil {
+		if pkg.PgxErrorCode(err) == pkg.FOREIGN_KEY_VIOLATION && sale.CustomerID != nil {
+			return pkg.Errorf(pkg.NOT_FOUND_ERROR, "customer %d not found", *sale.CustomerID)
+		}
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create sale: %s", err.Error())
+	}
+	saleID := uint32(s.ID)
+
+	if err := q.CreateTaxInvoice(ctx, s.ID); err != nil {
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to queue tax invoice: %s", err.Error())
+	}
+
+	var (
+		totalQuantity int64
+		totalAmount   float64
+		totalTax      float64
+		totalDiscount float64
+		taxClasses    = map[int64]generated.TaxClass{}
+	)
+	for _, item := range sale.Items {
+		stockUpdate := &repository.ProductStockUpdate{
+			ID:             item.ProductID,
+			PerformedBy:    sale.PerformedBy,
+			Quantity:       item.Quantity,
+			Note:           sale.Note,
+			SaleID:         &saleID,
+			CustomerID:     sale.CustomerID,
+			PrescriptionID: item.PrescriptionID,
+			WitnessedBy:    sale.WitnessedBy,
+		}
+
+		// kits that are not assembled yet are built from their components first
+		if err := ensureKitStockTx(ctx, q, stockUpdate); err != nil {
+			return err
+		}
+
+		p, movement, err := removeStockTx(ctx, q, stockUpdate)
+		if err != nil {
+			return err
+		}
+
+		taxClass, ok := taxClasses[p.TaxClassID]
+		if !ok {
+			taxClass, err = getTaxClassTx(ctx, q, p.TaxClassID)
+			if err != nil {
+				return err
+			}
+			taxClasses[p.TaxClassID] = taxClass
+		}
+
+		unitPrice := pkg.PgTypeNumericToFloat64(p.Price)
+		promotion, discount, err := bestPromotionTx(ctx, q, p.ID, unitPrice, item.Quantity, s.CreatedAt)
+		if err != nil {
+			return err
+		}
+		promotionID := pgtype.Int8{Valid: false}
+		if promotion != nil {
+			promotionID = pgtype.Int8{Int64: promotion.ID, Valid: true}
+			item.PromotionName = &promotion.Name
+		}
+
+		lineTotal := unitPrice*float64(item.Quantity) - discount
+		taxRate := pkg.PgTypeNumericToFloat64(taxClass.Rate)
+		taxAmount := taxIncluded(lineTotal, taxRate)
+
+		si, err := q.CreateSaleItem(ctx, generated.CreateSaleItemParams{
+			SaleID:         s.ID,
+			ProductID:      p.ID,
+			MovementID:     movement.ID,
+			Quantity:       int32(item.Quantity),
+			UnitPrice:      p.Price,
+			LineTotal:      pkg.Float64ToPgTypeNumeric(lineTotal),
+			TaxClassID:     taxClass.ID,
+			TaxCode:        taxClass.Code,
+			TaxRate:        taxClass.Rate,
+			TaxAmount:      pkg.Float64ToPgTypeNumeric(taxAmount),
+			PromotionID:    promotionID,
+			DiscountAmount: pkg.Float64ToPgTypeNumeric(discount),
+		})
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create sale item: %s", err.Error())
+		}
+
+		item.ID = uint32(si.ID)
+		item.SaleID = saleID
+		item.MovementID = uint32(movement.ID)
+		item.UnitPrice = unitPrice
+		item.LineTotal = lineTotal
+		item.TaxCode = taxClass.Code
+		item.TaxRate = taxRate
+		item.TaxAmount = taxAmount
+		item.PromotionID = pgInt8ToUint32(promotionID)
+		item.DiscountAmount = discount
+		item.CreatedAt = si.CreatedAt
+		item.ProductName = p.Name
+
+		totalQuantity += item.Quantity
+		totalAmount += lineTotal
+		totalTax += taxAmount
+		totalDiscount += discount
+	}
+
+	s, err = q.UpdateSaleTotals(ctx, generated.UpdateSaleTotalsParams{
+		ID:            s.ID,
+		TotalQuantity: totalQuantity,
+		TotalAmount:   pkg.Float64ToPgTypeNumeric(totalAmount),
+		TotalTax:      pkg.Float64ToPgTypeNumeric(totalTax),
+		TotalDiscount: pkg.Float64ToPgTypeNumeric(totalDiscount),
+	})
+	if err != nil {
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update sale totals: %s", err.Error())
+	}
+
+	sale.ID = saleID
+	sale.ReceiptNumber = s.ReceiptNumber
+	sale.TotalQuantity = s.TotalQuantity
+	sale.TotalAmount = pkg.PgTypeNumericToFloat64(s.TotalAmount)
+	sale.TotalTax = pkg.PgTypeNumericToFloat64(s.TotalTax)
+	sale.TotalDiscount = pkg.PgTypeNumericToFloat64(s.TotalDiscount)
+	sale.ShiftID = pgInt8ToUint32(s.ShiftID)
+	sale.CreatedAt = s.CreatedAt
+
+	// tenders given at the till are settled with the sale; M-Pesa is collected
+	// afterwards through an STK push against the sale
+	for i, payment := range sale.Payments {
+		if payment.Method == repository.PAYMENT_MPESA {
+			return pkg.Errorf(pkg.INVALID_ERROR, "M-Pesa payments must be requested after the sale is created")
+		}
+		payment.SaleID = saleID
+		payment.ReceivedBy = sale.PerformedBy
+
+		pgPayment, err := createPaymentTx(ctx, q, payment)
+		if err != nil {
+			return err
+		}
+		sale.Payments[i] = pgPaymentToRepoPayment(pgPayment)
+	}
+	sale.AmountPaid, sale.Balance = settlePayments(sale.TotalAmount, sale.Payments)
+
+	if sale.OnAccount {
+		// the new sale is already one of the open invoices
+		_, outstanding, err := openInvoicesTx(ctx, q, int64(*sale.CustomerID))
+		if err != nil {
+			return err
+		}
+		if toCents(outstanding) > toCents(creditLimit) {
+			available := max(creditLimit-(outstanding-sale.Balance), 0)
+			return pkg.Errorf(pkg.INVALID_ERROR, "sale exceeds the customer's available credit of %.2f", available)
+		}
+
+		sale.DueDate = pgTimestamptzToTime(s.DueDate)
+	}
+
+	return nil
+}
+
+func (sr *SaleRepository) GetByID(ctx context.Context, id int64) (*repository.Sale, error) {
+	s, err := sr.queries.GetSaleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "sale not found")
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get sale: %s", err.Error())
+	}
+
+	items, err := sr.queries.ListSaleItems(ctx, id)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list sale items: %s", err.Error())
+	}
+
+	payments, err := sr.queries.ListSalePayments(ctx, id)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list sale payments: %s", err.Error())
+	}
+
+	sale := &repository.Sale{
+		ID:                uint32(s.ID),
+		Location:          s.Location,
+		ReceiptNumber:     s.ReceiptNumber,
+		ReceiptPrintCount: s.ReceiptPrintCount,
+		TotalQuantity:     s.TotalQuantity,
+		TotalAmount:       pkg.PgTypeNumericToFloat64(s.TotalAmount),
+		TotalTax:          pkg.PgTypeNumericToFloat64(s.TotalTax),
+		TotalDiscount:     pkg.PgTypeNumericToFloat64(s.TotalDiscount),
+		Note:              pgTextToString(s.Note),
+		PerformedBy:       uint32(s.PerformedBy),
+		CustomerID:        pgInt8ToUint32(s.CustomerID),
+		OnAccount:         s.OnAccount,
+		DueDate:           pgTimestamptzToTime(s.DueDate),
+		ShiftID:           pgInt8ToUint32(s.ShiftID),
+		SchemeID:          pgInt8ToUint32(s.SchemeID),
+		MemberNumber:      pgTextToString(s.MemberNumber),
+		CreatedAt:         s.CreatedAt,
+		Items:             make([]*repository.SaleItem, len(items)),
+
+		UserName:     s.UserName,
+		CustomerName: pgTextToString(s.CustomerName),
+		SchemeName:   pgTextToString(s.SchemeName),
+	}
+	for i, item := range items {
+		sale.Items[i] = &repository.SaleItem{
+			ID:             uint32(item.ID),
+			SaleID:         uint32(item.SaleID),
+			ProductID:      uint32(item.ProductID),
+			MovementID:     uint32(item.MovementID),
+			PrescriptionID: pgInt8ToUint32(item.PrescriptionID),
+			Quantity:       int64(item.Quantity),
+			UnitPrice:      pkg.PgTypeNumericToFloat64(item.UnitPrice),
+			LineTotal:      pkg.PgTypeNumericToFloat64(item.LineTotal),
+			CreatedAt:      item.CreatedAt,
+			TaxCode:        item.TaxCode,
+			TaxRate:        pkg.PgTypeNumericToFloat64(item.TaxRate),
+			TaxAmount:      pkg.PgTypeNumericToFloat64(item.TaxAmount),
+			PromotionID:    pgInt8ToUint32(item.PromotionID),
+			DiscountAmount: pkg.PgTypeNumericToFloat64(item.DiscountAmount),
+
+			ProductName:   item.ProductName,
+			PromotionName: pgTextToString(item.PromotionName),
+		}
+	}
+	sale.Payments = make([]*repository.Payment, len(payments))
+	for i, p := range payments {
+		sale.Payments[i] = pgPaymentToRepoPayment(p)
+	}
+	sale.AmountPaid, sale.Balance = settlePayments(sale.TotalAmount, sale.Payments)
+
+	taxInvoice, err := sr.queries.GetTaxInvoiceBySale(ctx, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get sale tax invoice: %s", err.Error())
+	}
+	if err == nil {
+		sale.TaxInvoice = pgTaxInvoiceToRepoTaxInvoice(taxInvoice)
+	}
+
+	return sale, nil
+}
+
+func (sr *SaleRepository) List(ctx context.Context, filter *repository.SaleFilter) ([]*repository.Sale, *pkg.Pagination, error) {
+	listParams := generated.ListSalesParams{
+		Limit:       int32(filter.Pagination.PageSize),
+		Offset:      pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+		PerformedBy: pgtype.Int8{Valid: false},
+		StartDate:   pgtype.Timestamptz{Valid: false},
+		EndDate:     pgtype.Timestamptz{Valid: false},
+	}
+	countParams := generated.ListSalesCountParams{
+		PerformedBy: pgtype.Int8{Valid: false},
+		StartDate:   pgtype.Timestamptz{Valid: false},
+		EndDate:     pgtype.Timestamptz{Valid: false},
+	}
+
+	if filter.PerformedBy != nil {
+		listParams.PerformedBy = pgtype.Int8{Int64: int64(*filter.PerformedBy), Valid: true}
+		countParams.PerformedBy = pgtype.Int8{Int64: int64(*filter.PerformedBy), Valid: true}
+	}
+	if filter.Location != nil {
+		listParams.Location = pgtype.Text{String: *filter.Location, Valid: true}
+		countParams.Location = pgtype.Text{String: *filter.Location, Valid: true}
+	}
+	if filter.CustomerID != nil {
+		listParams.CustomerID = pgtype.Int8{Int64: int64(*filter.CustomerID), Valid: true}
+		countParams.CustomerID = pgtype.Int8{Int64: int64(*filter.CustomerID), Valid: true}
+	}
+	if filter.StartDate != nil && filter.EndDate != nil {
+		listParams.StartDate = pgtype.Timestamptz{Time: *filter.StartDate, Valid: true}
+		countParams.StartDate = pgtype.Timestamptz{Time: *filter.StartDate, Valid: true}
+
+		listParams.EndDate = pgtype.Timestamptz{Time: *filter.EndDate, Valid: true}
+		countParams.EndDate = pgtype.Timestamptz{Time: *filter.EndDate, Valid: true}
+	}
+
+	sales, err := sr.queries.ListSales(ctx, listParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list sales: %s", err.Error())
+	}
+
+	totalCount, err := sr.queries.ListSalesCount(ctx, countParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count sales: %s", err.Error())
+	}
+
+	repoSales := make([]*repository.Sale, len(sales))
+	for i, s := range sales {
+		repoSales[i] = &repository.Sale{
+			ID:                uint32(s.ID),
+			Location:          s.Location,
+			ReceiptNumber:     s.ReceiptNumber,
+			ReceiptPrintCount: s.ReceiptPrintCount,
+			TotalQuantity:     s.TotalQuantity,
+			TotalAmount:       pkg.PgTypeNumericToFloat64(s.TotalAmount),
+			TotalTax:          pkg.PgTypeNumericToFloat64(s.TotalTax),
+			TotalDiscount:     pkg.PgTypeNumericToFloat64(s.TotalDiscount),
+			Note:              pgTextToString(s.Note),
+			PerformedBy:       uint32(s.PerformedBy),
+			CustomerID:        pgInt8ToUint32(s.CustomerID),
+			OnAccount:         s.OnAccount,
+			DueDate:           pgTimestamptzToTime(s.DueDate),
+			ShiftID:           pgInt8ToUint32(s.ShiftID),
+			SchemeID:          pgInt8ToUint32(s.SchemeID),
+			MemberNumber:      pgTextToString(s.MemberNumber),
+			CreatedAt:         s.CreatedAt,
+
+			UserName:     s.UserName,
+			CustomerName: pgTextToString(s.CustomerName),
+		}
+	}
+
+	return repoSales, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
+}
+
+func (sr *SaleRepository) RecordReceiptPrint(ctx context.Context, id int64) (int32, error) {
+	count, err := sr.queries.IncrementReceiptPrintCount(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, pkg.Errorf(pkg.NOT_FOUND_ERROR, "sale not found")
+		}
+		return 0, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to record receipt print: %s", err.Error())
+	}
+
+	return count, nil
+}
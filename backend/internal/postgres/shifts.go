@@ -0,0 +1,405 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.ShiftRepository = (*ShiftRepository)(nil)
+
+type ShiftRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewShiftRepository(db *Store) *ShiftRepository {
+	return &ShiftRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (sr *ShiftRepository) Open(ctx context.Context, shift *repository.Shift) (*repository.Shift, error) {
+	if shift.OpeningFloat < 0 {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "opening float cannot be negative")
+	}
+
+	pgShift, err := sr.queries.CreateShift(ctx, generated.CreateShiftParams{
+		UserID:       int64(shift.UserID),
+		Location:     shift.Location,
+		OpeningFloat: pkg.Float64ToPgTypeNumeric(shift.OpeningFloat),
+		OpeningNote:  stringToPgText(shift.OpeningNote),
+	})
+	if err != nil {
+		if pkg.PgxErrorCode(err) == pkg.UNIQUE_VIOLATION {
+			return nil, pkg.Errorf(pkg.ALREADY_EXISTS_ERROR, "user already has an open shift")
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to open shift: %s", err.Error())
+	}
+
+	return getShiftTx(ctx, sr.queries, pgShift.ID)
+}
+
+func (sr *ShiftRepository) GetByID(ctx context.Context, id int64) (*repository.Shift, error) {
+	return getShiftTx(ctx, sr.queries, id)
+}
+
+func (sr *ShiftRepository) GetOpen(ctx context.Context, userID uint32) (*repository.Shift, error) {
+	pgShift, err := sr.queries.GetOpenShiftByUser(ctx, int64(userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "no open shift")
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get open shift: %s", err.Error())
+	}
+
+	return getShiftTx(ctx, sr.queries, pgShift.ID)
+}
+
+func (sr *ShiftRepository) Close(ctx context.Context, id int64, shiftClose *repository.ShiftClose) (*repository.Shift, error) {
+	for method, amount := range shiftClose.Counted {
+		if !isPaymentMethod(method) {
+			return nil, pkg.Errorf(pkg.INVALID_ERROR, "invalid payment method: %s", method)
+		}
+		if amount < 0 {
+			return nil, pkg.Errorf(pkg.INVALID_ERROR, "counted %s cannot be negative", method)
+		}
+	}
+
+	var shift *repository.Shift
+	err := sr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		pgShift, err := q.GetShiftForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "shift not found")
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get shift: %s", err.Error())
+		}
+		if pgShift.Status != repository.SHIFT_OPEN {
+			return pkg.Errorf(pkg.INVALID_ERROR, "shift is already closed")
+		}
+
+		expected, err := expectedTendersTx(ctx, q, pgShift.ID, pkg.PgTypeNumericToFloat64(pgShift.OpeningFloat))
+		if err != nil {
+			return err
+		}
+
+		// every method that was expected or counted gets a line, so a tender that
+		// was not counted shows as short
+		byMethod := map[string]*repository.ShiftTender{}
+		for _, tender := range expected {
+			byMethod[tender.Method] = tender
+		}
+		for method := range shiftClose.Counted {
+			if _, ok := byMethod[method]; !ok {
+				tender := &repository.ShiftTender{Method: method}
+				byMethod[method] = tender
+				expected = append(expected, tender)
+			}
+		}
+
+		for _, tender := range expected {
+			counted := toCents(shiftClose.Counted[tender.Method])
+			variance := counted - toCents(tender.ExpectedAmount)
+
+			_, err := q.CreateShiftTender(ctx, generated.CreateShiftTenderParams{
+				ShiftID:        pgShift.ID,
+				Method:         tender.Method,
+				ExpectedAmount: pkg.Float64ToPgTypeNumeric(tender.ExpectedAmount),
+				CountedAmount:  pkg.Float64ToPgTypeNumeric(float64(counted) / 100),
+				Variance:       pkg.Float64ToPgTypeNumeric(float64(variance) / 100),
+			})
+			if err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to record shift tender: %s", err.Error())
+			}
+		}
+
+		if _, err := q.CloseShift(ctx, generated.CloseShiftParams{
+			ID:          pgShift.ID,
+			ClosingNote: stringToPgText(shiftClose.Note),
+		}); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to close shift: %s", err.Error())
+		}
+
+		shift, err = getShiftTx(ctx, q, pgShift.ID)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+func (sr *ShiftRepository) List(ctx context.Context, filter *repository.ShiftFilter) ([]*repository.Shift, *pkg.Pagination, error) {
+	listParams := generated.ListShiftsParams{
+		Limit:     int32(filter.Pagination.PageSize),
+		Offset:    pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+		UserID:    pgtype.Int8{Valid: false},
+		Location:  stringToPgText(filter.Location),
+		Status:    stringToPgText(filter.Status),
+		StartDate: pgtype.Timestamptz{Valid: false},
+		EndDate:   pgtype.Timestamptz{Valid: false},
+	}
+
+	if filter.UserID != nil {
+		listParams.UserID = pgtype.Int8{Int64: int64(*filter.UserID), Valid: true}
+	}
+	if filter.StartDate != nil && filter.EndDate != nil {
+		listParams.StartDate = pgtype.Timestamptz{Time: *filter.StartDate, Valid: true}
+		listParams.EndDate = pgtype.Timestamptz{Time: *filter.EndDate, Valid: true}
+	}
+
+	shifts, err := sr.queries.ListShifts(ctx, listParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list shifts: %s", err.Error())
+	}
+
+	totalCount, err := sr.queries.ListShiftsCount(ctx, generated.ListShiftsCountParams{
+		UserID:    listParams.UserID,
+		Location:  listParams.Location,
+		Status:    listParams.Status,
+		StartDate: listParams.StartDate,
+		EndDate:   listParams.EndDate,
+	})
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count shifts: %s", err.Error())
+	}
+
+	repoShifts := make([]*repository.Shift, len(shifts))
+	for i, shift := range shifts {
+		repoShifts[i] = pgShiftToRepoShift(generated.GetShiftByIDRow(shift))
+	}
+
+	return repoShifts, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
+}
+
+func (sr *ShiftRepository) GetZReport(ctx context.Context, location string, startDate, endDate time.Time) (*repository.ZReport, error) {
+	report := &repository.ZReport{
+		Location:  location,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+
+	sales, err := sr.queries.GetLocationSalesTotals(ctx, generated.GetLocationSalesTotalsParams{
+		Location:  location,
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get sales totals: %s", err.Error())
+	}
+	report.Sales = repository.SalesTotals{
+		TotalSales:      sales.TotalSales,
+		TotalQuantity:   sales.TotalQuantity,
+		TotalAmount:     pkg.PgTypeNumericToFloat64(sales.TotalAmount),
+		TotalTax:        pkg.PgTypeNumericToFloat64(sales.TotalTax),
+		TotalDiscount:   pkg.PgTypeNumericToFloat64(sales.TotalDiscount),
+		OnAccountAmount: pkg.PgTypeNumericToFloat64(sales.OnAccountAmount),
+	}
+
+	payments, err := sr.queries.GetLocationPaymentTotals(ctx, generated.GetLocationPaymentTotalsParams{
+		Location:  location,
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get payment totals: %s", err.Error())
+	}
+	report.Payments = make([]*repository.TenderTotal, len(payments))
+	for i, p := range payments {
+		report.Payments[i] = &repository.TenderTotal{
+			Method:        p.Method,
+			TotalPayments: p.TotalPayments,
+			TotalAmount:   pkg.PgTypeNumericToFloat64(p.TotalAmount),
+		}
+	}
+
+	shifts, err := sr.queries.ListLocationShifts(ctx, generated.ListLocationShiftsParams{
+		Location:  location,
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list shifts: %s", err.Error())
+	}
+
+	report.Shifts = make([]*repository.Shift, len(shifts))
+	byMethod := map[string]*repository.ShiftTender{}
+	for i, pgShift := range shifts {
+		shift := pgShiftToRepoShift(generated.GetShiftByIDRow(pgShift))
+		if err := loadShiftTotalsTx(ctx, sr.queries, shift); err != nil {
+			return nil, err
+		}
+		report.Shifts[i] = shift
+
+		if shift.Status == repository.SHIFT_OPEN {
+			report.OpenShifts++
+			continue
+		}
+
+		for _, tender := range shift.Tenders {
+			total, ok := byMethod[tender.Method]
+			if !ok {
+				counted, variance := 0.0, 0.0
+				total = &repository.ShiftTender{Method: tender.Method, CountedAmount: &counted, Variance: &variance}
+				byMethod[tender.Method] = total
+				report.Tenders = append(report.Tenders, total)
+			}
+			total.TotalPayments += tender.TotalPayments
+			total.ExpectedAmount += tender.ExpectedAmount
+			*total.CountedAmount += *tender.CountedAmount
+			*total.Variance += *tender.Variance
+		}
+		report.TotalVariance += shift.TotalVariance
+	}
+
+	return report, nil
+}
+
+func getShiftTx(ctx context.Context, q *generated.Queries, id int64) (*repository.Shift, error) {
+	pgShift, err := q.GetShiftByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "shift not found")
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get shift: %s", err.Error())
+	}
+
+	shift := pgShiftToRepoShift(pgShift)
+	if err := loadShiftTotalsTx(ctx, q, shift); err != nil {
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+// loadShiftTotalsTx fills in the shift's sales and tenders. Closed shifts show the
+// recorded count; open shifts show what is expected so far.
+func loadShiftTotalsTx(ctx context.Context, q *generated.Queries, shift *repository.Shift) error {
+	shiftID := pgtype.Int8{Int64: int64(shift.ID), Valid: true}
+	sales, err := q.GetShiftSalesTotals(ctx, shiftID)
+	if err != nil {
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get shift sales: %s", err.Error())
+	}
+	shift.Sales = &repository.SalesTotals{
+		TotalSales:    sales.TotalSales,
+		TotalAmount:   pkg.PgTypeNumericToFloat64(sales.TotalAmount),
+		TotalTax:      pkg.PgTypeNumericToFloat64(sales.TotalTax),
+		TotalDiscount: pkg.PgTypeNumericToFloat64(sales.TotalDiscount),
+	}
+
+	expected, err := expectedTendersTx(ctx, q, int64(shift.ID), shift.OpeningFloat)
+	if err != nil {
+		return err
+	}
+	shift.Tenders = expected
+	if shift.Status == repository.SHIFT_OPEN {
+		return nil
+	}
+
+	// the recorded expectation is kept, since a pending M-Pesa payment that completes
+	// after the close would otherwise change a closed till
+	recorded, err := q.ListShiftTenders(ctx, int64(shift.ID))
+	if err != nil {
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list shift tenders: %s", err.Error())
+	}
+
+	payments := map[string]int64{}
+	for _, tender := range expected {
+		payments[tender.Method] = tender.TotalPayments
+	}
+
+	var totalVariance int64
+	shift.Tenders = make([]*repository.ShiftTender, len(recorded))
+	for i, tender := range recorded {
+		counted := pkg.PgTypeNumericToFloat64(tender.CountedAmount)
+		variance := pkg.PgTypeNumericToFloat64(tender.Variance)
+		shift.Tenders[i] = &repository.ShiftTender{
+			Method:         tender.Method,
+			TotalPayments:  payments[tender.Method],
+			ExpectedAmount: pkg.PgTypeNumericToFloat64(tender.ExpectedAmount),
+			CountedAmount:  &counted,
+			Variance:       &variance,
+		}
+		totalVariance += toCents(variance)
+	}
+	shift.TotalVariance = float64(totalVariance) / 100
+
+	return nil
+}
+
+// expectedTendersTx totals the shift's completed payments per method. The opening
+// float is expected in the cash drawer on top of cash takings.
+func expectedTendersTx(ctx context.Context, q *generated.Queries, shiftID int64, openingFloat float64) ([]*repository.ShiftTender, error) {
+	totals, err := q.GetShiftPaymentTotals(ctx, pgtype.Int8{Int64: shiftID, Valid: true})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get shift payments: %s", err.Error())
+	}
+
+	tenders := make([]*repository.ShiftTender, 0, len(totals)+1)
+	hasCash := false
+	for _, total := range totals {
+		tender := &repository.ShiftTender{
+			Method:         total.Method,
+			TotalPayments:  total.TotalPayments,
+			ExpectedAmount: pkg.PgTypeNumericToFloat64(total.TotalAmount),
+		}
+		if total.Method == repository.PAYMENT_CASH {
+			tender.ExpectedAmount = float64(toCents(tender.ExpectedAmount)+toCents(openingFloat)) / 100
+			hasCash = true
+		}
+		tenders = append(tenders, tender)
+	}
+	if !hasCash {
+		tenders = append(tenders, &repository.ShiftTender{Method: repository.PAYMENT_CASH, ExpectedAmount: openingFloat})
+	}
+
+	return tenders, nil
+}
+
+// openShiftIDTx returns the user's open shift, if any, for linking sales and payments.
+func openShiftIDTx(ctx context.Context, q *generated.Queries, userID uint32) (pgtype.Int8, string, error) {
+	shift, err := q.GetOpenShiftByUser(ctx, int64(userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return pgtype.Int8{Valid: false}, "", nil
+		}
+		return pgtype.Int8{}, "", pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get open shift: %s", err.Error())
+	}
+
+	return pgtype.Int8{Int64: shift.ID, Valid: true}, shift.Location, nil
+}
+
+func isPaymentMethod(method string) bool {
+	switch method {
+	case repository.PAYMENT_CASH, repository.PAYMENT_CARD, repository.PAYMENT_MPESA, repository.PAYMENT_INSURANCE:
+		return true
+	default:
+		return false
+	}
+}
+
+func pgShiftToRepoShift(s generated.GetShiftByIDRow) *repository.Shift {
+	return &repository.Shift{
+		ID:           uint32(s.ID),
+		UserID:       uint32(s.UserID),
+		Location:     s.Location,
+		Status:       s.Status,
+		OpeningFloat: pkg.PgTypeNumericToFloat64(s.OpeningFloat),
+		OpeningNote:  pgTextToString(s.OpeningNote),
+		ClosingNote:  pgTextToString(s.ClosingNote),
+		OpenedAt:     s.OpenedAt,
+		ClosedAt:     pgTimestamptzToTime(s.ClosedAt),
+
+		UserName: s.UserName,
+	}
+}
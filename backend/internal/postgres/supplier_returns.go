@@ -0,0 +1,367 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.SupplierReturnRepository = (*SupplierReturnRepository)(nil)
+
+type SupplierReturnRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewSupplierReturnRepository(db *Store) *SupplierReturnRepository {
+	return &SupplierReturnRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (sr *SupplierReturnRepository) Create(ctx context.Context, supplierReturn *repository.SupplierReturn) (*repository.SupplierReturn, error) {
+	if len(supplierReturn.Items) == 0 {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "a supplier return must have at least one line")
+	}
+
+	err := sr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		if _, err := q.GetSupplierByID(ctx, int64(supplierReturn.SupplierID)); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pkg.Errorf(pkg.NOT_FOUND_ERROR, "supplier %d not found", supplierReturn.SupplierID)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get supplier: %s", err.Error())
+		}
+
+		createParams := generated.CreateSupplierReturnParams{
+			SupplierID:  int64(supplierReturn.SupplierID),
+			Note:        pgtype.Text{Valid: false},
+			PerformedBy: int64(supplierReturn.PerformedBy),
+		}
+		if supplierReturn.Note != nil {
+			createParams.Note = pgtype.Text{String: *supplierReturn.Note, Valid: true}
+		}
+
+		s, err := q.CreateSupplierReturn(ctx, createParams)
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create supplier return: %s", err.Error())
+		}
+
+		var (
+			totalQuantity int64
+			totalAmount   float64
+		)
+		for _, item := range supplierReturn.Items {
+			itemParams := generated.CreateSupplierReturnItemParams{
+				SupplierReturnID: s.ID,
+				ProductID:        int64(item.ProductID),
+				BatchNumber:      pgtype.Text{Valid: false},
+				Reason:           pgtype.Text{Valid: false},
+				MovementID:       pgtype.Int8{Valid: false},
+				ReturnID:         pgtype.Int8{Valid: false},
+			}
+			if item.BatchNumber != nil {
+				itemParams.BatchNumber = pgtype.Text{String: *item.BatchNumber, Valid: true}
+			}
+			if item.Reason != nil {
+				itemParams.Reason = pgtype.Text{String: *item.Reason, Valid: true}
+			}
+
+			if item.ReturnID != nil {
+				// quarantined goods are outside stock, so they are released back in and
+				// then removed to the supplier; both movements are witnessed and written
+				// to the register like any other
+				r, err := q.GetReturnForUpdate(ctx, int64(*item.ReturnID))
+				if err != nil {
+					if errors.Is(err, sql.ErrNoRows) {
+						return pkg.Errorf(pkg.NOT_FOUND_ERROR, "return with id %d not found", *item.ReturnID)
+					}
+					return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get return: %s", err.Error())
+				}
+				if r.Status != repository.RETURN_QUARANTINED {
+					return pkg.Errorf(pkg.INVALID_ERROR, "return %d is not in quarantine", r.ID)
+				}
+				if r.ProductID != int64(item.ProductID) {
+					return pkg.Errorf(pkg.INVALID_ERROR, "return %d is not for product %d", r.ID, item.ProductID)
+				}
+				if item.Quantity != 0 && item.Quantity != int64(r.Quantity) {
+					return pkg.Errorf(pkg.INVALID_ERROR, "return %d must be sent back in full (%d)", r.ID, r.Quantity)
+				}
+				item.Quantity = int64(r.Quantity)
+				if item.BatchNumber == nil {
+					itemParams.BatchNumber = r.BatchNumber
+				}
+
+				_, restock, err := releaseQuarantineTx(ctx, q, r, itemParams.BatchNumber, supplierReturn.PerformedBy, supplierReturn.WitnessedBy)
+				if err != nil {
+					return err
+				}
+
+				reason := repository.MOVEMENT_REASON_SUPPLIER_RETURN
+				_, movement, err := removeStockTx(ctx, q, &repository.ProductStockUpdate{
+					ID:          item.ProductID,
+					PerformedBy: supplierReturn.PerformedBy,
+					Quantity:    item.Quantity,
+					Note:        &s.DebitNoteNumber,
+					BatchNumber: pgTextToString(itemParams.BatchNumber),
+					WitnessedBy: supplierReturn.WitnessedBy,
+					Reason:      &reason,
+				})
+				if err != nil {
+					return err
+				}
+				itemParams.MovementID = pgtype.Int8{Int64: movement.ID, Valid: true}
+
+				if _, err := q.ResolveReturn(ctx, generated.ResolveReturnParams{
+					ID:                r.ID,
+					Status:            repository.RETURN_SUPPLIER_RETURNED,
+					RestockMovementID: pgtype.Int8{Int64: restock.ID, Valid: true},
+					ResolvedBy:        pgtype.Int8{Int64: int64(supplierReturn.PerformedBy), Valid: true},
+				}); err != nil {
+					return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to resolve return: %s", err.Error())
+				}
+
+				itemParams.ReturnID = pgtype.Int8{Int64: r.ID, Valid: true}
+			} else {
+				if item.BatchNumber == nil {
+					return pkg.Errorf(pkg.INVALID_ERROR, "a batch number is required for product %d", item.ProductID)
+				}
+
+				reason := repository.MOVEMENT_REASON_SUPPLIER_RETURN
+				_, movement, err := removeStockTx(ctx, q, &repository.ProductStockUpdate{
+					ID:          item.ProductID,
+					PerformedBy: supplierReturn.PerformedBy,
+					Quantity:    item.Quantity,
+					Note:        &s.DebitNoteNumber,
+					BatchNumber: item.BatchNumber,
+					WitnessedBy: supplierReturn.WitnessedBy,
+					Reason:      &reason,
+				})
+				if err != nil {
+					return err
+				}
+
+				itemParams.MovementID = pgtype.Int8{Int64: movement.ID, Valid: true}
+			}
+
+			if item.UnitCost == 0 {
+				if !itemParams.BatchNumber.Valid {
+					return pkg.Errorf(pkg.INVALID_ERROR, "a unit cost is required for product %d", item.ProductID)
+				}
+
+				unitCost, err := q.GetBatchUnitCost(ctx, generated.GetBatchUnitCostParams{
+					ProductID:   int64(item.ProductID),
+					BatchNumber: itemParams.BatchNumber,
+				})
+				if err != nil {
+					if errors.Is(err, sql.ErrNoRows) {
+						return pkg.Errorf(pkg.INVALID_ERROR, "no purchase of batch %s found for product %d, a unit cost is required", itemParams.BatchNumber.String, item.ProductID)
+					}
+					return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get batch unit cost: %s", err.Error())
+				}
+				item.UnitCost = pkg.PgTypeNumericToFloat64(unitCost)
+			}
+			lineTotal := item.UnitCost * float64(item.Quantity)
+
+			itemParams.Quantity = int32(item.Quantity)
+			itemParams.UnitCost = pkg.Float64ToPgTypeNumeric(item.UnitCost)
+			itemParams.LineTotal = pkg.Float64ToPgTypeNumeric(lineTotal)
+
+			if _, err := q.CreateSupplierReturnItem(ctx, itemParams); err != nil {
+				return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create supplier return item: %s", err.Error())
+			}
+
+			totalQuantity += item.Quantity
+			totalAmount += lineTotal
+		}
+
+		if _, err := q.UpdateSupplierReturnTotals(ctx, generated.UpdateSupplierReturnTotalsParams{
+			ID:            s.ID,
+			TotalQuantity: totalQuantity,
+			TotalAmount:   pkg.Float64ToPgTypeNumeric(totalAmount),
+		}); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update supplier return totals: %s", err.Error())
+		}
+
+		supplierReturn.ID = uint32(s.ID)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sr.GetByID(ctx, int64(supplierReturn.ID))
+}
+
+func (sr *SupplierReturnRepository) GetByID(ctx context.Context, id int64) (*repository.SupplierReturn, error) {
+	s, err := sr.queries.GetSupplierReturnByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "supplier return with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get supplier return: %s", err.Error())
+	}
+
+	items, err := sr.queries.ListSupplierReturnItems(ctx, id)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list supplier return items: %s", err.Error())
+	}
+
+	credits, err := sr.queries.ListSupplierCredits(ctx, id)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list supplier credits: %s", err.Error())
+	}
+
+	totalAmount := pkg.PgTypeNumericToFloat64(s.TotalAmount)
+	creditReceived := pkg.PgTypeNumericToFloat64(s.CreditReceived)
+	supplierReturn := &repository.SupplierReturn{
+		ID:              uint32(s.ID),
+		SupplierID:      uint32(s.SupplierID),
+		DebitNoteNumber: s.DebitNoteNumber,
+		TotalQuantity:   s.TotalQuantity,
+		TotalAmount:     totalAmount,
+		CreditReceived:  creditReceived,
+		CreditPending:   totalAmount - creditReceived,
+		CreditStatus:    s.CreditStatus,
+		Note:            pgTextToString(s.Note),
+		PerformedBy:     uint32(s.PerformedBy),
+		CreatedAt:       s.CreatedAt,
+		Items:           make([]*repository.SupplierReturnItem, len(items)),
+		Credits:         make([]*repository.SupplierCredit, len(credits)),
+
+		SupplierName: s.SupplierName,
+		UserName:     s.UserName,
+	}
+	for i, item := range items {
+		supplierReturn.Items[i] = &repository.SupplierReturnItem{
+			ID:               uint32(item.ID),
+			SupplierReturnID: uint32(item.SupplierReturnID),
+			ProductID:        uint32(item.ProductID),
+			BatchNumber:      pgTextToString(item.BatchNumber),
+			Quantity:         int64(item.Quantity),
+			UnitCost:         pkg.PgTypeNumericToFloat64(item.UnitCost),
+			LineTotal:        pkg.PgTypeNumericToFloat64(item.LineTotal),
+			Reason:           pgTextToString(item.Reason),
+			MovementID:       pgInt8ToUint32(item.MovementID),
+			ReturnID:         pgInt8ToUint32(item.ReturnID),
+
+			ProductName: item.ProductName,
+		}
+	}
+	for i, credit := range credits {
+		supplierReturn.Credits[i] = &repository.SupplierCredit{
+			ID:               uint32(credit.ID),
+			SupplierReturnID: uint32(credit.SupplierReturnID),
+			Amount:           pkg.PgTypeNumericToFloat64(credit.Amount),
+			Reference:        pgTextToString(credit.Reference),
+			ReceivedAt:       credit.ReceivedAt.Time,
+			RecordedBy:       uint32(credit.RecordedBy),
+			CreatedAt:        credit.CreatedAt,
+
+			UserName: credit.UserName,
+		}
+	}
+
+	return supplierReturn, nil
+}
+
+func (sr *SupplierReturnRepository) List(ctx context.Context, filter *repository.SupplierReturnFilter) ([]*repository.SupplierReturn, *pkg.Pagination, error) {
+	listParams := generated.ListSupplierReturnsParams{
+		Limit:        int32(filter.Pagination.PageSize),
+		Offset:       pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+		SupplierID:   pgtype.Int8{Valid: false},
+		CreditStatus: pgtype.Text{Valid: false},
+	}
+
+	countParams := generated.ListSupplierReturnsCountParams{
+		SupplierID:   pgtype.Int8{Valid: false},
+		CreditStatus: pgtype.Text{Valid: false},
+	}
+
+	if filter.SupplierID != nil {
+		listParams.SupplierID = pgtype.Int8{Int64: int64(*filter.SupplierID), Valid: true}
+		countParams.SupplierID = pgtype.Int8{Int64: int64(*filter.SupplierID), Valid: true}
+	}
+	if filter.CreditStatus != nil {
+		listParams.CreditStatus = pgtype.Text{String: *filter.CreditStatus, Valid: true}
+		countParams.CreditStatus = pgtype.Text{String: *filter.CreditStatus, Valid: true}
+	}
+
+	supplierReturns, err := sr.queries.ListSupplierReturns(ctx, listParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list supplier returns: %s", err.Error())
+	}
+
+	totalCount, err := sr.queries.ListSupplierReturnsCount(ctx, countParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count supplier returns: %s", err.Error())
+	}
+
+	repoSupplierReturns := make([]*repository.SupplierReturn, len(supplierReturns))
+	for i, s := range supplierReturns {
+		totalAmount := pkg.PgTypeNumericToFloat64(s.TotalAmount)
+		creditReceived := pkg.PgTypeNumericToFloat64(s.CreditReceived)
+		repoSupplierReturns[i] = &repository.SupplierReturn{
+			ID:              uint32(s.ID),
+			SupplierID:      uint32(s.SupplierID),
+			DebitNoteNumber: s.DebitNoteNumber,
+			TotalQuantity:   s.TotalQuantity,
+			TotalAmount:     totalAmount,
+			CreditReceived:  creditReceived,
+			CreditPending:   totalAmount - creditReceived,
+			CreditStatus:    s.CreditStatus,
+			Note:            pgTextToString(s.Note),
+			PerformedBy:     uint32(s.PerformedBy),
+			CreatedAt:       s.CreatedAt,
+
+			SupplierName: s.SupplierName,
+			UserName:     s.UserName,
+		}
+	}
+
+	return repoSupplierReturns, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
+}
+
+func (sr *SupplierReturnRepository) RecordCredit(ctx context.Context, credit *repository.SupplierCredit) (*repository.SupplierReturn, error) {
+	err := sr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		amount := pkg.Float64ToPgTypeNumeric(credit.Amount)
+
+		if _, err := q.ApplySupplierCredit(ctx, generated.ApplySupplierCreditParams{
+			ID:     int64(credit.SupplierReturnID),
+			Amount: amount,
+		}); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pkg.Errorf(pkg.INVALID_ERROR, "supplier return %d does not exist or the credit exceeds the amount pending", credit.SupplierReturnID)
+			}
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to apply supplier credit: %s", err.Error())
+		}
+
+		params := generated.CreateSupplierCreditParams{
+			SupplierReturnID: int64(credit.SupplierReturnID),
+			Amount:           amount,
+			Reference:        pgtype.Text{Valid: false},
+			ReceivedAt:       pgtype.Date{Time: credit.ReceivedAt, Valid: true},
+			RecordedBy:       int64(credit.RecordedBy),
+		}
+		if credit.Reference != nil {
+			params.Reference = pgtype.Text{String: *credit.Reference, Valid: true}
+		}
+
+		if _, err := q.CreateSupplierCredit(ctx, params); err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create supplier credit: %s", err.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sr.GetByID(ctx, int64(credit.SupplierReturnID))
+}
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.SupplierRepository = (*SupplierRepository)(nil)
+
+type SupplierRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewSupplierRepository(db *Store) *SupplierRepository {
+	return &SupplierRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (sr *SupplierRepository) Create(ctx context.Context, supplier *repository.Supplier) (*repository.Supplier, error) {
+	params := generated.CreateSupplierParams{
+		Name:          supplier.Name,
+		ContactPerson: pgtype.Text{Valid: false},
+		PhoneNumber:   pgtype.Text{Valid: false},
+		Email:         pgtype.Text{Valid: false},
+		Address:       pgtype.Text{Valid: false},
+	}
+	if supplier.ContactPerson != nil {
+		params.ContactPerson = pgtype.Text{String: *supplier.ContactPerson, Valid: true}
+	}
+	if supplier.PhoneNumber != nil {
+		params.PhoneNumber = pgtype.Text{String: *supplier.PhoneNumber, Valid: true}
+	}
+	if supplier.Email != nil {
+		params.Email = pgtype.Text{String: *supplier.Email, Valid: true}
+	}
+	if supplier.Address != nil {
+		params.Address = pgtype.Text{String: *supplier.Address, Valid: true}
+	}
+
+	pgSupplier, err := sr.queries.CreateSupplier(ctx, params)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create supplier: %s", err.Error())
+	}
+
+	return pgSupplierToRepoSupplier(pgSupplier), nil
+}
+
+func (sr *SupplierRepository) GetByID(ctx context.Context, id int64) (*repository.Supplier, error) {
+	pgSupplier, err := sr.queries.GetSupplierByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "supplier with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get supplier by id: %s", err.Error())
+	}
+
+	summary, err := sr.queries.GetSupplierCreditSummary(ctx, id)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get supplier credit summary: %s", err.Error())
+	}
+
+	supplier := pgSupplierToRepoSupplier(pgSupplier)
+	supplier.CreditSummary = &repository.SupplierCreditSummary{
+		CreditExpected: pkg.PgTypeNumericToFloat64(summary.CreditExpected),
+		CreditReceived: pkg.PgTypeNumericToFloat64(summary.CreditReceived),
+	}
+	supplier.CreditSummary.CreditPending = supplier.CreditSummary.CreditExpected - supplier.CreditSummary.CreditReceived
+
+	return supplier, nil
+}
+
+func (sr *SupplierRepository) Update(ctx context.Context, id int64, supplierUpdate *repository.SupplierUpdate) (*repository.Supplier, error) {
+	params := generated.UpdateSupplierParams{
+		ID:            id,
+		Name:          pgtype.Text{Valid: false},
+		ContactPerson: pgtype.Text{Valid: false},
+		PhoneNumber:   pgtype.Text{Valid: false},
+		Email:         pgtype.Text{Valid: false},
+		Address:       pgtype.Text{Valid: false},
+	}
+
+	if supplierUpdate.Name != nil {
+		params.Name = pgtype.Text{String: *supplierUpdate.Name, Valid: true}
+	}
+	if supplierUpdate.ContactPerson != nil {
+		params.ContactPerson = pgtype.Text{String: *supplierUpdate.ContactPerson, Valid: true}
+	}
+	if supplierUpdate.PhoneNumber != nil {
+		params.PhoneNumber = pgtype.Text{String: *supplierUpdate.PhoneNumber, Valid: true}
+	}
+	if supplierUpdate.Email != nil {
+		params.Email = pgtype.Text{String: *supplierUpdate.Email, Valid: true}
+	}
+	if supplierUpdate.Address != nil {
+		params.Address = pgtype.Text{String: *supplierUpdate.Address, Valid: true}
+	}
+
+	pgSupplier, err := sr.queries.UpdateSupplier(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "supplier with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update supplier: %s", err.Error())
+	}
+
+	return pgSupplierToRepoSupplier(pgSupplier), nil
+}
+
+func (sr *SupplierRepository) Delete(ctx context.Context, id int64) error {
+	if err := sr.queries.DeleteSupplier(ctx, id); err != nil {
+		return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to delete supplier: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (sr *SupplierRepository) List(ctx context.Context, filter *repository.SupplierFilter) ([]*repository.Supplier, *pkg.Pagination, error) {
+	listParams := generated.ListSuppliersParams{
+		Limit:  int32(filter.Pagination.PageSize),
+		Offset: pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+		Search: pgtype.Text{Valid: false},
+	}
+
+	countSearch := pgtype.Text{Valid: false}
+
+	if filter.Search != nil {
+		s := strings.ToLower(*filter.Search)
+		listParams.Search = pgtype.Text{String: "%" + s + "%", Valid: true}
+		countSearch = pgtype.Text{String: "%" + s + "%", Valid: true}
+	}
+
+	pgSuppliers, err := sr.queries.ListSuppliers(ctx, listParams)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list suppliers: %s", err.Error())
+	}
+
+	totalCount, err := sr.queries.ListSuppliersCount(ctx, countSearch)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count suppliers: %s", err.Error())
+	}
+
+	suppliers := make([]*repository.Supplier, 0, len(pgSuppliers))
+	for _, pgSupplier := range pgSuppliers {
+		suppliers = append(suppliers, pgSupplierToRepoSupplier(pgSupplier))
+	}
+
+	return suppliers, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
+}
+
+func pgSupplierToRepoSupplier(pgSupplier generated.Supplier) *repository.Supplier {
+	return &repository.Supplier{
+		ID:            uint32(pgSupplier.ID),
+		Name:          pgSupplier.Name,
+		ContactPerson: pgTextToString(pgSupplier.ContactPerson),
+		PhoneNumber:   pgTextToString(pgSupplier.PhoneNumber),
+		Email:         pgTextToString(pgSupplier.Email),
+		Address:       pgTextToString(pgSupplier.Address),
+		Deleted:       pgSupplier.Deleted,
+		CreatedAt:     pgSupplier.CreatedAt,
+	}
+}
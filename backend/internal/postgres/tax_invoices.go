@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.TaxInvoiceRepository = (*TaxInvoiceRepository)(nil)
+
+type TaxInvoiceRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewTaxInvoiceRepository(db *Store) *TaxInvoiceRepository {
+	return &TaxInvoiceRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (tr *TaxInvoiceRepository) GetBySale(ctx context.Context, saleID int64) (*repository.TaxInvoice, error) {
+	invoice, err := tr.queries.GetTaxInvoiceBySale(ctx, saleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "no tax invoice for sale %d", saleID)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get tax invoice: %s", err.Error())
+	}
+
+	return pgTaxInvoiceToRepoTaxInvoice(invoice), nil
+}
+
+func (tr *TaxInvoiceRepository) List(ctx context.Context, filter *repository.TaxInvoiceFilter) ([]*repository.TaxInvoice, *pkg.Pagination, error) {
+	status := stringToPgText(filter.Status)
+
+	invoices, err := tr.queries.ListTaxInvoices(ctx, generated.ListTaxInvoicesParams{
+		Limit:  int32(filter.Pagination.PageSize),
+		Offset: pkg.Offset(filter.Pagination.Page, filter.Pagination.PageSize),
+		Status: status,
+	})
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list tax invoices: %s", err.Error())
+	}
+
+	totalCount, err := tr.queries.ListTaxInvoicesCount(ctx, status)
+	if err != nil {
+		return nil, nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to count tax invoices: %s", err.Error())
+	}
+
+	repoInvoices := make([]*repository.TaxInvoice, len(invoices))
+	for i, invoice := range invoices {
+		repoInvoices[i] = pgTaxInvoiceToRepoTaxInvoice(generated.TaxInvoice{
+			ID:               invoice.ID,
+			SaleID:           invoice.SaleID,
+			Status:           invoice.Status,
+			Attempts:         invoice.Attempts,
+			LastError:        invoice.LastError,
+			NextAttemptAt:    invoice.NextAttemptAt,
+			ControlNumber:    invoice.ControlNumber,
+			InternalData:     invoice.InternalData,
+			ReceiptSignature: invoice.ReceiptSignature,
+			QrCode:           invoice.QrCode,
+			SignedAt:         invoice.SignedAt,
+			CreatedAt:        invoice.CreatedAt,
+		})
+		repoInvoices[i].ReceiptNumber = invoice.ReceiptNumber
+		repoInvoices[i].TotalAmount = pkg.PgTypeNumericToFloat64(invoice.TotalAmount)
+	}
+
+	return repoInvoices, pkg.CalculatePagination(uint32(totalCount), filter.Pagination.PageSize, filter.Pagination.Page), nil
+}
+
+func (tr *TaxInvoiceRepository) Lease(ctx context.Context, saleID int64, leaseFor time.Duration) (*repository.TaxInvoice, error) {
+	invoice, err := tr.queries.LeaseTaxInvoice(ctx, generated.LeaseTaxInvoiceParams{
+		SaleID:     saleID,
+		LeaseUntil: time.Now().Add(leaseFor),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to lease tax invoice: %s", err.Error())
+	}
+
+	return pgTaxInvoiceToRepoTaxInvoice(invoice), nil
+}
+
+func (tr *TaxInvoiceRepository) LeaseDue(ctx context.Context, limit int32, leaseFor time.Duration) ([]*repository.TaxInvoice, error) {
+	invoices, err := tr.queries.LeaseDueTaxInvoices(ctx, generated.LeaseDueTaxInvoicesParams{
+		Limit:      limit,
+		LeaseUntil: time.Now().Add(leaseFor),
+	})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to lease due tax invoices: %s", err.Error())
+	}
+
+	repoInvoices := make([]*repository.TaxInvoice, len(invoices))
+	for i, invoice := range invoices {
+		repoInvoices[i] = pgTaxInvoiceToRepoTaxInvoice(invoice)
+	}
+
+	return repoInvoices, nil
+}
+
+func (tr *TaxInvoiceRepository) Sign(ctx context.Context, id int64, signature *repository.TaxInvoiceSignature) (*repository.TaxInvoice, error) {
+	invoice, err := tr.queries.SignTaxInvoice(ctx, generated.SignTaxInvoiceParams{
+		ID:               id,
+		ControlNumber:    pgtype.Text{String: signature.ControlNumber, Valid: true},
+		InternalData:     pgtype.Text{String: signature.InternalData, Valid: signature.InternalData != ""},
+		ReceiptSignature: pgtype.Text{String: signature.ReceiptSignature, Valid: signature.ReceiptSignature != ""},
+		QrCode:           pgtype.Text{String: signature.QRCode, Valid: signature.QRCode != ""},
+		SignedAt:         pgtype.Timestamptz{Time: signature.SignedAt, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "tax invoice with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to sign tax invoice: %s", err.Error())
+	}
+
+	return pgTaxInvoiceToRepoTaxInvoice(invoice), nil
+}
+
+func (tr *TaxInvoiceRepository) Fail(ctx context.Context, id int64, reason string, rejected bool, retryAt time.Time) (*repository.TaxInvoice, error) {
+	status := repository.TAX_INVOICE_PENDING
+	if rejected {
+		status = repository.TAX_INVOICE_REJECTED
+	}
+
+	invoice, err := tr.queries.FailTaxInvoice(ctx, generated.FailTaxInvoiceParams{
+		ID:            id,
+		Status:        status,
+		LastError:     pgtype.Text{String: reason, Valid: true},
+		NextAttemptAt: retryAt,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.NOT_FOUND_ERROR, "tax invoice with id %d not found", id)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to record tax invoice failure: %s", err.Error())
+	}
+
+	return pgTaxInvoiceToRepoTaxInvoice(invoice), nil
+}
+
+func (tr *TaxInvoiceRepository) Requeue(ctx context.Context, saleID int64) (*repository.TaxInvoice, error) {
+	invoice, err := tr.queries.RequeueTaxInvoice(ctx, saleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkg.Errorf(pkg.INVALID_ERROR, "tax invoice for sale %d is already signed", saleID)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to requeue tax invoice: %s", err.Error())
+	}
+
+	return pgTaxInvoiceToRepoTaxInvoice(invoice), nil
+}
+
+func pgTaxInvoiceToRepoTaxInvoice(invoice generated.TaxInvoice) *repository.TaxInvoice {
+	return &repository.TaxInvoice{
+		ID:               uint32(invoice.ID),
+		SaleID:           uint32(invoice.SaleID),
+		Status:           invoice.Status,
+		Attempts:         invoice.Attempts,
+		LastError:        pgTextToString(invoice.LastError),
+		NextAttemptAt:    invoice.NextAttemptAt,
+		CreatedAt:        invoice.CreatedAt,
+		ControlNumber:    pgTextToString(invoice.ControlNumber),
+		InternalData:     pgTextToString(invoice.InternalData),
+		ReceiptSignature: pgTextToString(invoice.ReceiptSignature),
+		QRCode:           pgTextToString(invoice.QrCode),
+		SignedAt:         pgTimestamptzToTime(invoice.SignedAt),
+	}
+}
@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/pkg"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var _ repository.TaxClassRepository = (*TaxClassRepository)(nil)
+
+type TaxClassRepository struct {
+	queries *generated.Queries
+	db      *Store
+}
+
+func NewTaxClassRepository(db *Store) *TaxClassRepository {
+	return &TaxClassRepository{
+		db:      db,
+		queries: generated.New(db.pool),
+	}
+}
+
+func (tr *TaxClassRepository) Create(ctx context.Context, taxClass *repository.TaxClass) (*repository.TaxClass, error) {
+	if err := validateTaxRate(taxClass.Type, taxClass.Rate); err != nil {
+		return nil, err
+	}
+
+	pgTaxClass, err := tr.queries.CreateTaxClass(ctx, generated.CreateTaxClassParams{
+		Code: taxClass.Code,
+		Name: taxClass.Name,
+		Type: taxClass.Type,
+		Rate: pkg.Float64ToPgTypeNumeric(taxClass.Rate),
+	})
+	if err != nil {
+		if pkg.PgxErrorCode(err) == pkg.UNIQUE_VIOLATION {
+			return nil, pkg.Errorf(pkg.ALREADY_EXISTS_ERROR, "tax class with code %s already exists", taxClass.Code)
+		}
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to create tax class: %s", err.Error())
+	}
+
+	return pgTaxClassToRepoTaxClass(pgTaxClass), nil
+}
+
+func (tr *TaxClassRepository) GetByID(ctx context.Context, id int64) (*repository.TaxClass, error) {
+	pgTaxClass, err := getTaxClassTx(ctx, tr.queries, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgTaxClassToRepoTaxClass(pgTaxClass), nil
+}
+
+func (tr *TaxClassRepository) Update(ctx context.Context, id int64, taxClassUpdate *repository.TaxClassUpdate) (*repository.TaxClass, error) {
+	params := generated.UpdateTaxClassParams{
+		ID:   id,
+		Name: pgtype.Text{Valid: false},
+		Rate: pgtype.Numeric{Valid: false},
+	}
+	if taxClassUpdate.Name != nil {
+		params.Name = pgtype.Text{String: *taxClassUpdate.Name, Valid: true}
+	}
+
+	var taxClass *repository.TaxClass
+	err := tr.db.ExecTx(ctx, func(q *generated.Queries) error {
+		current, err := getTaxClassTx(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		// the new rate only applies to sales made after the change
+		if taxClassUpdate.Rate != nil {
+			if err := validateTaxRate(current.Type, *taxClassUpdate.Rate); err != nil {
+				return err
+			}
+			params.Rate = pkg.Float64ToPgTypeNumeric(*taxClassUpdate.Rate)
+		}
+
+		pgTaxClass, err := q.UpdateTaxClass(ctx, params)
+		if err != nil {
+			return pkg.Errorf(pkg.INTERNAL_ERROR, "failed to update tax class: %s", err.Error())
+		}
+		taxClass = pgTaxClassToRepoTaxClass(pgTaxClass)
+
+		return nil
+	})
+
+	return taxClass, err
+}
+
+func (tr *TaxClassRepository) List(ctx context.Context) ([]*repository.TaxClass, error) {
+	pgTaxClasses, err := tr.queries.ListTaxClasses(ctx)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list tax classes: %s", err.Error())
+	}
+
+	taxClasses := make([]*repository.TaxClass, len(pgTaxClasses))
+	for i, tc := range pgTaxClasses {
+		taxClasses[i] = pgTaxClassToRepoTaxClass(tc)
+	}
+
+	return taxClasses, nil
+}
+
+func (tr *TaxClassRepository) GetTaxSummary(ctx context.Context, startDate, endDate time.Time) (*repository.TaxSummary, error) {
+	rows, err := tr.queries.GetTaxSummary(ctx, generated.GetTaxSummaryParams{
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get tax summary: %s", err.Error())
+	}
+
+	summary := &repository.TaxSummary{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Lines:     make([]*repository.TaxSummaryLine, len(rows)),
+	}
+	for i, row := range rows {
+		line := &repository.TaxSummaryLine{
+			TaxCode:     row.TaxCode,
+			TaxName:     row.TaxName,
+			TaxType:     row.TaxType,
+			TaxRate:     pkg.PgTypeNumericToFloat64(row.TaxRate),
+			TotalSales:  row.TotalSales,
+			GrossAmount: pkg.PgTypeNumericToFloat64(row.GrossAmount),
+			TaxAmount:   pkg.PgTypeNumericToFloat64(row.TaxAmount),
+		}
+		line.NetAmount = line.GrossAmount - line.TaxAmount
+		summary.Lines[i] = line
+
+		summary.GrossAmount += line.GrossAmount
+		summary.NetAmount += line.NetAmount
+		summary.TaxAmount += line.TaxAmount
+	}
+
+	return summary, nil
+}
+
+func validateTaxRate(taxType string, rate float64) error {
+	if rate < 0 {
+		return pkg.Errorf(pkg.INVALID_ERROR, "tax rate cannot be negative")
+	}
+	if taxType != repository.TAX_STANDARD && rate != 0 {
+		return pkg.Errorf(pkg.INVALID_ERROR, "only standard rated tax classes can have a rate")
+	}
+
+	return nil
+}
+
+func getTaxClassTx(ctx context.Context, q *generated.Queries, id int64) (generated.TaxClass, error) {
+	taxClass, err := q.GetTaxClassByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return generated.TaxClass{}, pkg.Errorf(pkg.NOT_FOUND_ERROR, "tax class with id %d not found", id)
+		}
+		return generated.TaxClass{}, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get tax class: %s", err.Error())
+	}
+
+	return taxClass, nil
+}
+
+// productTaxClassTx returns the tax class for a new product. Products created without
+// one are exempt.
+func productTaxClassTx(ctx context.Context, q *generated.Queries, id uint32) (generated.TaxClass, error) {
+	if id != 0 {
+		return getTaxClassTx(ctx, q, int64(id))
+	}
+
+	taxClass, err := q.GetDefaultTaxClass(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return generated.TaxClass{}, pkg.Errorf(pkg.NOT_FOUND_ERROR, "no exempt tax class configured")
+		}
+		return generated.TaxClass{}, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get default tax class: %s", err.Error())
+	}
+
+	return taxClass, nil
+}
+
+// taxIncluded returns the tax contained in a tax-inclusive amount, rounded to cents.
+func taxIncluded(amount, rate float64) float64 {
+	if rate <= 0 {
+		return 0
+	}
+
+	return math.Round(amount*rate/(100+rate)*100) / 100
+}
+
+func pgTaxClassToRepoTaxClass(tc generated.TaxClass) *repository.TaxClass {
+	return &repository.TaxClass{
+		ID:        uint32(tc.ID),
+		Code:      tc.Code,
+		Name:      tc.Name,
+		Type:      tc.Type,
+		Rate:      pkg.PgTypeNumericToFloat64(tc.Rate),
+		CreatedAt: tc.CreatedAt,
+	}
+}
@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/postgres/generated"
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/valuation"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+// GetValuation replays stock movements through the valuation engine using the
+// configured method unless the filter overrides it. Stock that is not explained
+// by movements (e.g. the initial stock set when a product is created) is treated
+// as an opening layer valued at the product's opening unit cost.
+func (pr *ProductRepository) GetValuation(ctx context.Context, filter *repository.ValuationFilter) (*repository.Valuation, error) {
+	method := pr.db.config.VALUATION_METHOD
+	if filter.Method != nil {
+		method = *filter.Method
+	}
+
+	engine, err := valuation.NewEngine(method)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate := time.Now()
+	if filter.EndDate != nil {
+		endDate = *filter.EndDate
+	}
+	startDate := time.Time{}
+	if filter.StartDate != nil {
+		startDate = *filter.StartDate
+	}
+
+	products, err := pr.queries.ListProductOpeningStock(ctx)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list opening stock: %s", err.Error())
+	}
+
+	movements, err := pr.queries.ListValuationMovements(ctx, endDate)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to list movements: %s", err.Error())
+	}
+
+	productNames := make(map[uint32]string, len(products))
+	entries := make([]valuation.Entry, 0, len(products)+len(movements))
+	for _, p := range products {
+		productNames[uint32(p.ID)] = p.Name
+		if p.OpeningStock > 0 {
+			entries = append(entries, valuation.Entry{
+				ProductID: uint32(p.ID),
+				Type:      valuation.ENTRY_IN,
+				Quantity:  p.OpeningStock,
+				UnitCost:  pkg.PgTypeNumericToFloat64(p.OpeningUnitCost),
+				At:        p.CreatedAt,
+			})
+		}
+	}
+	for _, m := range movements {
+		// skip deleted products
+		if _, ok := productNames[uint32(m.ProductID)]; !ok {
+			continue
+		}
+
+		entries = append(entries, valuation.Entry{
+			ProductID: uint32(m.ProductID),
+			Type:      m.Type,
+			Quantity:  int64(m.Quantity),
+			UnitCost:  pkg.PgTypeNumericToFloat64(m.UnitCost),
+			At:        m.CreatedAt,
+		})
+	}
+
+	// opening layers are dated at product creation so they must sort before the movements
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].At.Before(entries[j].At)
+	})
+
+	result := &repository.Valuation{
+		Method:    engine.Method(),
+		StartDate: startDate,
+		EndDate:   endDate,
+		Products:  []*repository.ProductValuation{},
+	}
+	for _, r := range engine.Value(entries, startDate, endDate) {
+		result.TotalClosingValue += r.ClosingValue
+		result.TotalCostOfGoodsSold += r.CostOfGoodsSold
+		result.Products = append(result.Products, &repository.ProductValuation{
+			ProductID:       r.ProductID,
+			ProductName:     productNames[r.ProductID],
+			QuantityOnHand:  r.QuantityOnHand,
+			UnitCost:        r.UnitCost,
+			ClosingValue:    r.ClosingValue,
+			QuantitySold:    r.QuantitySold,
+			CostOfGoodsSold: r.CostOfGoodsSold,
+		})
+	}
+
+	return result, nil
+}
+
+// valuationCache keeps the valuations behind the stats and the dashboard so they
+// do not replay the movement history on every request. They are dropped once a
+// movement or a change to the products makes them stale.
+type valuationCache struct {
+	mu         sync.Mutex
+	version    generated.GetValuationVersionRow
+	valuations map[int64]*repository.Valuation // by start date
+}
+
+// cachedValuation returns the valuation with the configured method from startDate
+// up to now, reusing the last one while nothing it was built from has changed.
+func (pr *ProductRepository) cachedValuation(ctx context.Context, startDate time.Time) (*repository.Valuation, error) {
+	version, err := pr.queries.GetValuationVersion(ctx)
+	if err != nil {
+		return nil, pkg.Errorf(pkg.INTERNAL_ERROR, "failed to get valuation version: %s", err.Error())
+	}
+
+	cache := pr.valuations
+	cache.mu.Lock()
+	if cache.version != version {
+		cache.version = version
+		cache.valuations = make(map[int64]*repository.Valuation)
+	}
+	cached, ok := cache.valuations[startDate.Unix()]
+	cache.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	result, err := pr.GetValuation(ctx, &repository.ValuationFilter{StartDate: &startDate})
+	if err != nil {
+		return nil, err
+	}
+
+	cache.mu.Lock()
+	if cache.version == version {
+		cache.valuations[startDate.Unix()] = result
+	}
+	cache.mu.Unlock()
+
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package reports
+
+import (
+	"fmt"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+// registerColumns follow the columns of the statutory dangerous drugs register.
+var registerColumns = []int{-16, -8, -14, -22, -26, 8, 8, 9, -15, -15}
+
+func (r *ReportServiceImpl) ControlledDrugRegister(report *repository.ControlledDrugReport, format string) ([]byte, error) {
+	w := newLineWriter(registerWidth)
+
+	w.center(r.config.BUSINESS_NAME)
+	w.center("CONTROLLED DRUGS REGISTER")
+	period := "Up to " + report.EndDate.Format("02/01/2006")
+	if !report.StartDate.IsZero() {
+		period = fmt.Sprintf("%s to %s", report.StartDate.Format("02/01/2006"), report.EndDate.Format("02/01/2006"))
+	}
+	w.center(period)
+	w.blank()
+
+	for _, register := range report.Registers {
+		w.divider()
+		w.pair(fmt.Sprintf("Drug: %s (%s)", register.ProductName, register.Unit), fmt.Sprintf("Opening balance: %d", register.OpeningBalance))
+		if register.Verified {
+			w.left("Register integrity: verified")
+		} else {
+			w.left(fmt.Sprintf("Register integrity: FAILED - entries from #%d have been altered", *register.TamperedEntryID))
+		}
+		w.divider()
+		w.row(registerColumns, "Date", "Entry", "Reference", "Patient", "Prescriber (licence)", "Received", "Issued", "Balance", "Performed by", "Witness")
+		w.divider()
+
+		for _, e := range register.Entries {
+			received, issued, entry := "", "", "ISSUED"
+			if e.EntryType == repository.MOVEMENT_ADD {
+				received, entry = fmt.Sprintf("%d", e.Quantity), "RECEIVED"
+			} else {
+				issued = fmt.Sprintf("%d", e.Quantity)
+			}
+
+			prescriber := "-"
+			if e.PrescriberName != nil {
+				prescriber = fmt.Sprintf("%s (%s)", *e.PrescriberName, stringOrDash(e.PrescriberLicenceNumber))
+			}
+
+			w.row(registerColumns,
+				e.CreatedAt.Format("02/01/2006 15:04"),
+				entry,
+				stringOrDash(e.Reference),
+				stringOrDash(e.CustomerName),
+				prescriber,
+				received,
+				issued,
+				fmt.Sprintf("%d", e.Balance),
+				e.PerformedByName,
+				e.WitnessedByName,
+			)
+		}
+
+		w.divider()
+		w.pair(fmt.Sprintf("Received: %d   Issued: %d", register.TotalReceived, register.TotalIssued), fmt.Sprintf("Closing balance: %d", register.ClosingBalance))
+		w.blank()
+	}
+
+	if len(report.Registers) == 0 {
+		w.center("No controlled drug movements in this period")
+	}
+
+	switch format {
+	case services.REPORT_FORMAT_TEXT:
+		return []byte(w.String()), nil
+	case services.REPORT_FORMAT_PDF:
+		return w.pdf(), nil
+	default:
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "unsupported report format: %s", format)
+	}
+}
+
+func stringOrDash(s *string) string {
+	if s == nil || *s == "" {
+		return "-"
+	}
+
+	return *s
+}
@@ -0,0 +1,75 @@
+package reports
+
+import (
+	"fmt"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+var debitNoteColumns = []int{-28, -12, 6, 10, 11, -8}
+
+func (r *ReportServiceImpl) DebitNote(supplierReturn *repository.SupplierReturn, supplier *repository.Supplier, format string) ([]byte, error) {
+	w := newLineWriter(documentWidth)
+
+	w.center(r.config.BUSINESS_NAME)
+	if r.config.BUSINESS_ADDRESS != "" {
+		w.center(r.config.BUSINESS_ADDRESS)
+	}
+	if r.config.BUSINESS_PHONE != "" {
+		w.center("Tel: " + r.config.BUSINESS_PHONE)
+	}
+	w.blank()
+	w.center("DEBIT NOTE")
+	w.blank()
+
+	w.pair("To: "+supplier.Name, "Debit Note No: "+supplierReturn.DebitNoteNumber)
+	w.pair("Attn: "+stringOrDash(supplier.ContactPerson), "Date: "+supplierReturn.CreatedAt.Format("02/01/2006"))
+	if supplier.Address != nil && *supplier.Address != "" {
+		w.left(*supplier.Address)
+	}
+	if supplier.PhoneNumber != nil && *supplier.PhoneNumber != "" {
+		w.left("Tel: " + *supplier.PhoneNumber)
+	}
+	w.blank()
+	w.left("We have debited your account for the following goods returned to you:")
+	w.divider()
+	w.row(debitNoteColumns, "Item", "Batch", "Qty", "Unit Cost", "Total", "Reason")
+	w.divider()
+
+	for _, item := range supplierReturn.Items {
+		w.row(debitNoteColumns,
+			item.ProductName,
+			stringOrDash(item.BatchNumber),
+			fmt.Sprintf("%d", item.Quantity),
+			money(item.UnitCost),
+			money(item.LineTotal),
+			stringOrDash(item.Reason),
+		)
+	}
+	w.divider()
+
+	w.pair("Items:", fmt.Sprintf("%d", supplierReturn.TotalQuantity))
+	w.pair("TOTAL CREDIT DUE:", money(supplierReturn.TotalAmount))
+	if supplierReturn.CreditReceived > 0 {
+		w.pair("Credit received:", money(supplierReturn.CreditReceived))
+		w.pair("Balance pending:", money(supplierReturn.CreditPending))
+	}
+	w.divider()
+
+	if supplierReturn.Note != nil && *supplierReturn.Note != "" {
+		w.left(*supplierReturn.Note)
+		w.blank()
+	}
+	w.pair("Prepared by: "+supplierReturn.UserName, "Received by: ____________________")
+
+	switch format {
+	case services.REPORT_FORMAT_TEXT:
+		return []byte(w.String()), nil
+	case services.REPORT_FORMAT_PDF:
+		return w.pdf(), nil
+	default:
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "unsupported debit note format: %s", format)
+	}
+}
@@ -0,0 +1,136 @@
+package reports
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	receiptWidth  = 42  // characters per line on an 80mm thermal printer
+	documentWidth = 80  // characters per line on an A4 page in 10pt Courier
+	registerWidth = 150 // characters per line on a landscape A4 page
+)
+
+// lineWriter builds fixed width text that is printed as-is on receipt printers
+// and drawn line by line in monospaced PDF documents.
+type lineWriter struct {
+	width int
+	lines []string
+}
+
+func newLineWriter(width int) *lineWriter {
+	return &lineWriter{width: width}
+}
+
+func (w *lineWriter) left(s string) {
+	w.lines = append(w.lines, truncate(s, w.width))
+}
+
+func (w *lineWriter) center(s string) {
+	s = truncate(s, w.width)
+	pad := (w.width - len(s)) / 2
+	w.lines = append(w.lines, strings.Repeat(" ", pad)+s)
+}
+
+// pair writes left and right aligned text on the same line.
+func (w *lineWriter) pair(left, right string) {
+	space := w.width - len(right) - 1
+	if space < 1 {
+		w.lines = append(w.lines, truncate(left, w.width), fmt.Sprintf("%*s", w.width, truncate(right, w.width)))
+		return
+	}
+	w.lines = append(w.lines, fmt.Sprintf("%-*s %s", space, truncate(left, space), right))
+}
+
+// columns writes a row where the first column takes the remaining width and the
+// others are right aligned to the given widths.
+func (w *lineWriter) columns(first string, widths []int, others ...string) {
+	firstWidth := w.width
+	for _, cw := range widths {
+		firstWidth -= cw
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-*s", firstWidth, truncate(first, firstWidth))
+	for i, cw := range widths {
+		fmt.Fprintf(&b, "%*s", cw, truncate(others[i], cw-1))
+	}
+	w.lines = append(w.lines, b.String())
+}
+
+// row writes cells padded to the given widths. As with fmt, a negative width left
+// aligns the cell and a positive one right aligns it. Cells are separated by a space.
+func (w *lineWriter) row(widths []int, cells ...string) {
+	var b strings.Builder
+	for i, cw := range widths {
+		n := cw
+		if n < 0 {
+			n = -n
+		}
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		fmt.Fprintf(&b, "%*s", cw, truncate(cells[i], n))
+	}
+	w.lines = append(w.lines, truncate(strings.TrimRight(b.String(), " "), w.width))
+}
+
+// wrap writes s over as many lines as it needs. It is for text without natural
+// breaks, such as signatures and urls.
+func (w *lineWriter) wrap(s string) {
+	for len(s) > w.width {
+		w.lines = append(w.lines, s[:w.width])
+		s = s[w.width:]
+	}
+	w.lines = append(w.lines, s)
+}
+
+func (w *lineWriter) divider() {
+	w.lines = append(w.lines, strings.Repeat("-", w.width))
+}
+
+func (w *lineWriter) blank() {
+	w.lines = append(w.lines, "")
+}
+
+func (w *lineWriter) String() string {
+	return strings.Join(w.lines, "\n") + "\n"
+}
+
+// pdf renders the lines on A4 pages, using the bold font for the first line. Lines
+// wider than documentWidth are printed landscape with the font shrunk to fit.
+func (w *lineWriter) pdf() []byte {
+	width, height, margin, size := a4Width, a4Height, 50.0, 10.0
+	if w.width > documentWidth {
+		width, height, margin = a4Height, a4Width, 30.0
+		size = math.Min(size, (width-2*margin)/textWidth(fontMono, 1, strings.Repeat(" ", w.width)))
+	}
+
+	doc := newPDFDocument(width, height, margin)
+	for i, line := range w.lines {
+		if i == 0 {
+			doc.writeCentered(fontBold, 14, strings.TrimSpace(line))
+			doc.space(4)
+			continue
+		}
+		doc.writeln(fontMono, size, line)
+	}
+
+	return doc.bytes()
+}
+
+func truncate(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if len(s) <= n {
+		return s
+	}
+
+	return s[:n]
+}
+
+func money(f float64) string {
+	return fmt.Sprintf("%.2f", f)
+}
@@ -0,0 +1,140 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	a4Width  = 595.28
+	a4Height = 841.89
+
+	fontRegular = "F1"
+	fontBold    = "F2"
+	fontMono    = "F3"
+)
+
+// pdfDocument is a minimal PDF writer for text based documents. It only uses the
+// standard Type1 fonts so no font files need to be embedded.
+type pdfDocument struct {
+	width   float64
+	height  float64
+	margin  float64
+	y       float64
+	pages   []*bytes.Buffer
+	current *bytes.Buffer
+}
+
+func newPDFDocument(width, height, margin float64) *pdfDocument {
+	d := &pdfDocument{
+		width:  width,
+		height: height,
+		margin: margin,
+	}
+	d.addPage()
+
+	return d
+}
+
+func (d *pdfDocument) addPage() {
+	d.current = &bytes.Buffer{}
+	d.pages = append(d.pages, d.current)
+	d.y = d.margin
+}
+
+// text writes s with its top-left corner at x and the current cursor position.
+func (d *pdfDocument) text(x float64, font string, size float64, s string) {
+	fmt.Fprintf(d.current, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, d.height-d.y-size, escapePDFText(s))
+}
+
+// writeln writes a line of text at the left margin and moves the cursor down,
+// starting a new page when the current one is full.
+func (d *pdfDocument) writeln(font string, size float64, s string) {
+	if d.y+size*1.4 > d.height-d.margin {
+		d.addPage()
+	}
+	d.text(d.margin, font, size, s)
+	d.y += size * 1.4
+}
+
+// writeCentered writes a line of text centred on the page.
+func (d *pdfDocument) writeCentered(font string, size float64, s string) {
+	if d.y+size*1.4 > d.height-d.margin {
+		d.addPage()
+	}
+	x := (d.width - textWidth(font, size, s)) / 2
+	d.text(x, font, size, s)
+	d.y += size * 1.4
+}
+
+func (d *pdfDocument) space(points float64) {
+	d.y += points
+}
+
+func (d *pdfDocument) bytes() []byte {
+	var (
+		buf     bytes.Buffer
+		offsets []int
+	)
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// 1: catalog, 2: pages, 3-5: fonts, then a page and content object per page
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+i*2)
+	}
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		obj(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /%s 3 0 R /%s 4 0 R /%s 5 0 R >> >> /Contents %d 0 R >>",
+			d.width, d.height, fontRegular, fontBold, fontMono, 7+i*2,
+		))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+// textWidth is exact for the monospaced font and an approximation otherwise.
+func textWidth(font string, size float64, s string) float64 {
+	if font == fontMono {
+		return float64(len(s)) * size * 0.6
+	}
+
+	return float64(len(s)) * size * 0.5
+}
+
+func escapePDFText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteRune('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
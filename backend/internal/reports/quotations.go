@@ -0,0 +1,67 @@
+package reports
+
+import (
+	"fmt"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+)
+
+var quotationColumns = []int{-30, 5, 11, 11, 12, -5}
+
+func (r *ReportServiceImpl) Quotation(quotation *repository.Quotation, format string) ([]byte, error) {
+	w := newLineWriter(documentWidth)
+
+	w.center(r.config.BUSINESS_NAME)
+	if r.config.BUSINESS_ADDRESS != "" {
+		w.center(r.config.BUSINESS_ADDRESS)
+	}
+	if r.config.BUSINESS_PHONE != "" {
+		w.center("Tel: " + r.config.BUSINESS_PHONE)
+	}
+	w.blank()
+	w.center("QUOTATION / PROFORMA INVOICE")
+	w.blank()
+
+	customer := "Cash customer"
+	if quotation.CustomerName != nil {
+		customer = *quotation.CustomerName
+	}
+	w.pair("To: "+customer, "Quotation No: "+quotation.QuotationNumber)
+	w.pair("Prepared by: "+quotation.UserName, "Date: "+quotation.CreatedAt.Format("02/01/2006"))
+	w.pair("", "Valid until: "+quotation.ValidUntil.Format("02/01/2006"))
+	w.blank()
+	w.row(quotationColumns, "Item", "Qty", "Unit Price", "Discount", "Total", "Tax")
+	w.divider()
+
+	for _, item := range quotation.Items {
+		w.row(quotationColumns,
+			item.ProductName,
+			fmt.Sprintf("%d", item.Quantity),
+			money(item.UnitPrice),
+			money(item.DiscountAmount),
+			money(item.LineTotal),
+			item.TaxCode,
+		)
+	}
+	w.divider()
+
+	w.pair("Items:", fmt.Sprintf("%d", quotation.TotalQuantity))
+	if quotation.TotalDiscount > 0 {
+		w.pair("Discount:", money(quotation.TotalDiscount))
+	}
+	w.pair("Tax included:", money(quotation.TotalTax))
+	w.pair("TOTAL:", money(quotation.TotalAmount))
+	w.divider()
+
+	if quotation.Note != nil && *quotation.Note != "" {
+		w.left(*quotation.Note)
+		w.blank()
+	}
+	w.left("Prices are inclusive of tax and subject to stock availability at the time of sale.")
+	if quotation.Status != repository.QUOTATION_OPEN {
+		w.blank()
+		w.center("*** " + quotation.Status + " ***")
+	}
+
+	return renderDocument(w, format)
+}
@@ -0,0 +1,161 @@
+package reports
+
+import (
+	"fmt"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+func (r *ReportServiceImpl) SaleReceipt(sale *repository.Sale, format string) ([]byte, error) {
+	switch format {
+	case services.REPORT_FORMAT_TEXT:
+		return []byte(r.receiptLines(sale, receiptWidth).String()), nil
+	case services.REPORT_FORMAT_PDF:
+		return r.receiptLines(sale, documentWidth).pdf(), nil
+	default:
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "unsupported receipt format: %s", format)
+	}
+}
+
+func (r *ReportServiceImpl) receiptLines(sale *repository.Sale, width int) *lineWriter {
+	w := newLineWriter(width)
+
+	w.center(r.config.BUSINESS_NAME)
+	if r.config.BUSINESS_ADDRESS != "" {
+		w.center(r.config.BUSINESS_ADDRESS)
+	}
+	if r.config.BUSINESS_PHONE != "" {
+		w.center("Tel: " + r.config.BUSINESS_PHONE)
+	}
+	w.blank()
+
+	// the first print is the original, anything after that is a copy
+	if sale.ReceiptPrintCount > 1 {
+		w.center(fmt.Sprintf("*** REPRINT #%d ***", sale.ReceiptPrintCount-1))
+		w.blank()
+	}
+
+	w.pair("Receipt No:", sale.ReceiptNumber)
+	w.pair("Date:", sale.CreatedAt.Format("02/01/2006 15:04"))
+	w.pair("Served by:", sale.UserName)
+	if sale.CustomerName != nil {
+		w.pair("Customer:", *sale.CustomerName)
+	}
+	if sale.SchemeName != nil && sale.MemberNumber != nil {
+		w.pair("Insurance:", *sale.SchemeName)
+		w.pair("Member No:", *sale.MemberNumber)
+	}
+	w.divider()
+
+	// the last column is the tax code explained in the tax summary below
+	widths := []int{6, 10, 11, 3}
+	w.columns("Item", widths, "Qty", "Price", "Total", "")
+	w.divider()
+	nameWidth := width - widths[0] - widths[1] - widths[2] - widths[3]
+	for _, item := range sale.Items {
+		// long names get a line of their own so they are not cut off on narrow receipts
+		name := item.ProductName
+		if len(name) > nameWidth {
+			w.left(name)
+			name = ""
+		}
+		// the line total is after the discount, so show the list price total above it
+		if item.DiscountAmount > 0 {
+			w.columns(name, widths, fmt.Sprintf("%d", item.Quantity), money(item.UnitPrice), money(item.LineTotal+item.DiscountAmount), "")
+			w.columns("  "+stringOrDash(item.PromotionName), widths, "", "", "-"+money(item.DiscountAmount), "")
+			w.columns("", widths, "", "", money(item.LineTotal), item.TaxCode)
+			continue
+		}
+		w.columns(name, widths, fmt.Sprintf("%d", item.Quantity), money(item.UnitPrice), money(item.LineTotal), item.TaxCode)
+	}
+	w.divider()
+
+	w.pair("Items:", fmt.Sprintf("%d", sale.TotalQuantity))
+	if sale.TotalDiscount > 0 {
+		w.pair("You saved:", money(sale.TotalDiscount))
+	}
+	w.pair("TOTAL:", money(sale.TotalAmount))
+	for _, payment := range sale.Payments {
+		if payment.Status != repository.PAYMENT_COMPLETED {
+			continue
+		}
+		label := payment.Method
+		if payment.Reference != nil {
+			label += " " + *payment.Reference
+		}
+		w.pair(label, money(payment.Amount))
+	}
+	if len(sale.Payments) > 0 {
+		w.pair("Balance:", money(sale.Balance))
+	}
+	if sale.OnAccount && sale.DueDate != nil {
+		w.pair("Charged to account, due:", sale.DueDate.Format("02/01/2006"))
+	}
+	w.divider()
+
+	taxWidths := []int{8, 12, 12}
+	w.columns("Tax", taxWidths, "Rate", "Net", "Tax")
+	for _, line := range receiptTaxLines(sale.Items) {
+		w.columns(line.TaxCode, taxWidths, fmt.Sprintf("%.0f%%", line.TaxRate), money(line.NetAmount), money(line.TaxAmount))
+	}
+	w.pair("Total tax:", money(sale.TotalTax))
+	w.left("Prices include tax")
+	w.divider()
+
+	// signed receipts carry the tax authority's control number and a link to
+	// verify them
+	if invoice := sale.TaxInvoice; invoice != nil {
+		if invoice.Status == repository.TAX_INVOICE_SIGNED && invoice.ControlNumber != nil {
+			w.pair("CU Invoice No:", *invoice.ControlNumber)
+			if invoice.SignedAt != nil {
+				w.pair("CU Date:", invoice.SignedAt.Format("02/01/2006 15:04:05"))
+			}
+			if invoice.InternalData != nil {
+				w.left("Internal Data:")
+				w.wrap(*invoice.InternalData)
+			}
+			if invoice.ReceiptSignature != nil {
+				w.left("Receipt Signature:")
+				w.wrap(*invoice.ReceiptSignature)
+			}
+			if invoice.QRCode != nil {
+				w.left("Verify at:")
+				w.wrap(*invoice.QRCode)
+			}
+		} else {
+			w.center("Tax invoice pending")
+		}
+		w.divider()
+	}
+
+	if sale.Note != nil && *sale.Note != "" {
+		w.left(*sale.Note)
+		w.blank()
+	}
+	w.center("Thank you for your purchase")
+
+	return w
+}
+
+// receiptTaxLines totals the sale's lines per tax code and rate in the order the
+// codes first appear.
+func receiptTaxLines(items []*repository.SaleItem) []*repository.TaxSummaryLine {
+	lines := []*repository.TaxSummaryLine{}
+	byKey := map[string]*repository.TaxSummaryLine{}
+	for _, item := range items {
+		key := fmt.Sprintf("%s/%.2f", item.TaxCode, item.TaxRate)
+		line, ok := byKey[key]
+		if !ok {
+			line = &repository.TaxSummaryLine{TaxCode: item.TaxCode, TaxRate: item.TaxRate}
+			byKey[key] = line
+			lines = append(lines, line)
+		}
+		line.GrossAmount += item.LineTotal
+		line.TaxAmount += item.TaxAmount
+		line.NetAmount = line.GrossAmount - line.TaxAmount
+	}
+
+	return lines
+}
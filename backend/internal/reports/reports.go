@@ -3,16 +3,19 @@ package reports
 import (
 	"github.com/EmilioCliff/jonche-med/internal/postgres"
 	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
 )
 
 var _ services.ReportService = (*ReportServiceImpl)(nil)
 
-func NewReportService(store *postgres.PostgresRepo) services.ReportService {
+func NewReportService(config pkg.Config, store *postgres.PostgresRepo) services.ReportService {
 	return &ReportServiceImpl{
-		store: store,
+		config: config,
+		store:  store,
 	}
 }
 
 type ReportServiceImpl struct {
-	store *postgres.PostgresRepo
+	config pkg.Config
+	store  *postgres.PostgresRepo
 }
@@ -0,0 +1,91 @@
+package reports
+
+import (
+	"fmt"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+)
+
+var (
+	zReportPaymentColumns = []int{-12, 8, 14}
+	zReportTenderColumns  = []int{-12, 8, 14, 14, 14}
+	zReportShiftColumns   = []int{-6, -18, -11, -11, 14, 14}
+)
+
+func (r *ReportServiceImpl) ZReport(report *repository.ZReport, format string) ([]byte, error) {
+	w := newLineWriter(documentWidth)
+
+	w.center(r.config.BUSINESS_NAME)
+	w.center("END OF DAY (Z) REPORT")
+	// the end date is exclusive, so the last day shown is the day before it
+	period := report.StartDate.Format("02/01/2006")
+	if last := report.EndDate.AddDate(0, 0, -1).Format("02/01/2006"); last != period {
+		period += " to " + last
+	}
+	w.center(fmt.Sprintf("%s - %s", report.Location, period))
+	w.blank()
+
+	w.left("SALES")
+	w.divider()
+	w.pair("Number of sales:", fmt.Sprintf("%d", report.Sales.TotalSales))
+	w.pair("Items sold:", fmt.Sprintf("%d", report.Sales.TotalQuantity))
+	w.pair("Discounts given:", money(report.Sales.TotalDiscount))
+	w.pair("Tax included:", money(report.Sales.TotalTax))
+	w.pair("Charged to accounts:", money(report.Sales.OnAccountAmount))
+	w.pair("GROSS SALES:", money(report.Sales.TotalAmount))
+	w.blank()
+
+	w.left("PAYMENTS RECEIVED")
+	w.divider()
+	w.row(zReportPaymentColumns, "Method", "Count", "Amount")
+	var received float64
+	for _, payment := range report.Payments {
+		w.row(zReportPaymentColumns, payment.Method, fmt.Sprintf("%d", payment.TotalPayments), money(payment.TotalAmount))
+		received += payment.TotalAmount
+	}
+	w.pair("Total received:", money(received))
+	w.blank()
+
+	w.left("TILL RECONCILIATION")
+	w.divider()
+	w.row(zReportTenderColumns, "Method", "Count", "Expected", "Counted", "Over/Short")
+	for _, tender := range report.Tenders {
+		w.row(zReportTenderColumns,
+			tender.Method,
+			fmt.Sprintf("%d", tender.TotalPayments),
+			money(tender.ExpectedAmount),
+			money(*tender.CountedAmount),
+			money(*tender.Variance),
+		)
+	}
+	if len(report.Tenders) == 0 {
+		w.center("No closed shifts")
+	}
+	w.pair("TOTAL OVER/SHORT:", money(report.TotalVariance))
+	w.blank()
+
+	w.left("SHIFTS")
+	w.divider()
+	w.row(zReportShiftColumns, "Shift", "Cashier", "Opened", "Closed", "Float", "Over/Short")
+	for _, shift := range report.Shifts {
+		closed, variance := "OPEN", "-"
+		if shift.ClosedAt != nil {
+			closed = shift.ClosedAt.Format("02/01 15:04")
+			variance = money(shift.TotalVariance)
+		}
+		w.row(zReportShiftColumns,
+			fmt.Sprintf("%d", shift.ID),
+			shift.UserName,
+			shift.OpenedAt.Format("02/01 15:04"),
+			closed,
+			money(shift.OpeningFloat),
+			variance,
+		)
+	}
+	if report.OpenShifts > 0 {
+		w.blank()
+		w.left(fmt.Sprintf("WARNING: %d shift(s) still open, their takings are not reconciled", report.OpenShifts))
+	}
+
+	return renderDocument(w, format)
+}
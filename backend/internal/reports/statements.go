@@ -0,0 +1,119 @@
+package reports
+
+import (
+	"fmt"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+var (
+	statementColumns = []int{-10, -7, -25, 11, 11, 11}
+	ageingColumns    = []int{-19, 9, 9, 9, 9, 9, 10}
+)
+
+func (r *ReportServiceImpl) CustomerStatement(statement *repository.CustomerStatement, format string) ([]byte, error) {
+	w := newLineWriter(documentWidth)
+
+	w.center(r.config.BUSINESS_NAME)
+	if r.config.BUSINESS_ADDRESS != "" {
+		w.center(r.config.BUSINESS_ADDRESS)
+	}
+	if r.config.BUSINESS_PHONE != "" {
+		w.center("Tel: " + r.config.BUSINESS_PHONE)
+	}
+	w.blank()
+	w.center("STATEMENT OF ACCOUNT")
+	w.blank()
+
+	customer := statement.Customer
+	// the end date is exclusive, so the last day shown is the day before it
+	w.pair("To: "+customer.Name, "From: "+statement.StartDate.Format("02/01/2006"))
+	w.pair("Tel: "+stringOrDash(customer.PhoneNumber), "To: "+statement.EndDate.AddDate(0, 0, -1).Format("02/01/2006"))
+	w.pair(fmt.Sprintf("Credit limit: %s", money(customer.CreditLimit)), fmt.Sprintf("Terms: %d days", customer.PaymentTermsDays))
+	w.divider()
+	w.row(statementColumns, "Date", "Type", "Reference", "Debit", "Credit", "Balance")
+	w.divider()
+
+	w.row(statementColumns, statement.StartDate.Format("02/01/2006"), "", "Balance brought forward", "", "", money(statement.OpeningBalance))
+	for _, entry := range statement.Entries {
+		debit, credit := "", ""
+		if entry.Debit > 0 {
+			debit = money(entry.Debit)
+		}
+		if entry.Credit > 0 {
+			credit = money(entry.Credit)
+		}
+		w.row(statementColumns, entry.Date.Format("02/01/2006"), entry.Type, entry.Reference, debit, credit, money(entry.Balance))
+	}
+	w.divider()
+	w.pair("AMOUNT DUE:", money(statement.ClosingBalance))
+	w.divider()
+
+	if statement.Ageing != nil {
+		w.row(ageingColumns, "", "Current", "31-60", "61-90", "Over 90", "", "Total")
+		w.row(ageingColumns, "Ageing",
+			money(statement.Ageing.Current),
+			money(statement.Ageing.Days31To60),
+			money(statement.Ageing.Days61To90),
+			money(statement.Ageing.Over90),
+			"",
+			money(statement.Ageing.Total),
+		)
+		w.divider()
+	}
+	w.left("Please quote the reference numbers with your payment.")
+
+	return renderDocument(w, format)
+}
+
+func (r *ReportServiceImpl) AgeingReport(report *repository.AgeingReport, format string) ([]byte, error) {
+	w := newLineWriter(documentWidth)
+
+	w.center(r.config.BUSINESS_NAME)
+	w.center("RECEIVABLES AGEING")
+	w.center("As of " + report.AsOf.Format("02/01/2006 15:04"))
+	w.blank()
+
+	w.divider()
+	w.row(ageingColumns, "Customer", "Limit", "Current", "31-60", "61-90", "Over 90", "Total")
+	w.divider()
+	for _, line := range report.Lines {
+		w.row(ageingColumns,
+			line.CustomerName,
+			money(line.CreditLimit),
+			money(line.Current),
+			money(line.Days31To60),
+			money(line.Days61To90),
+			money(line.Over90),
+			money(line.Total),
+		)
+	}
+	if len(report.Lines) == 0 {
+		w.center("No outstanding balances")
+	}
+	w.divider()
+	w.row(ageingColumns,
+		"TOTAL",
+		"",
+		money(report.Totals.Current),
+		money(report.Totals.Days31To60),
+		money(report.Totals.Days61To90),
+		money(report.Totals.Over90),
+		money(report.Totals.Total),
+	)
+
+	return renderDocument(w, format)
+}
+
+func renderDocument(w *lineWriter, format string) ([]byte, error) {
+	switch format {
+	case services.REPORT_FORMAT_TEXT:
+		return []byte(w.String()), nil
+	case services.REPORT_FORMAT_PDF:
+		return w.pdf(), nil
+	default:
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "unsupported report format: %s", format)
+	}
+}
@@ -0,0 +1,53 @@
+package reports
+
+import (
+	"fmt"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+	"github.com/EmilioCliff/jonche-med/internal/services"
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+var taxSummaryColumns = []int{-5, -18, -11, 7, 6, 13, 13}
+
+func (r *ReportServiceImpl) TaxSummary(summary *repository.TaxSummary, format string) ([]byte, error) {
+	w := newLineWriter(documentWidth)
+
+	w.center(r.config.BUSINESS_NAME)
+	w.center("TAX SUMMARY")
+	// the end date is exclusive, so the last day shown is the day before it
+	w.center(fmt.Sprintf("%s to %s", summary.StartDate.Format("02/01/2006"), summary.EndDate.AddDate(0, 0, -1).Format("02/01/2006")))
+	w.blank()
+
+	w.divider()
+	w.row(taxSummaryColumns, "Code", "Tax class", "Type", "Rate", "Sales", "Net", "Tax")
+	w.divider()
+	for _, line := range summary.Lines {
+		w.row(taxSummaryColumns,
+			line.TaxCode,
+			line.TaxName,
+			line.TaxType,
+			fmt.Sprintf("%.2f%%", line.TaxRate),
+			fmt.Sprintf("%d", line.TotalSales),
+			money(line.NetAmount),
+			money(line.TaxAmount),
+		)
+	}
+	if len(summary.Lines) == 0 {
+		w.center("No sales in this period")
+	}
+	w.divider()
+
+	w.pair("Net sales:", money(summary.NetAmount))
+	w.pair("Tax:", money(summary.TaxAmount))
+	w.pair("GROSS SALES:", money(summary.GrossAmount))
+
+	switch format {
+	case services.REPORT_FORMAT_TEXT:
+		return []byte(w.String()), nil
+	case services.REPORT_FORMAT_PDF:
+		return w.pdf(), nil
+	default:
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "unsupported report format: %s", format)
+	}
+}
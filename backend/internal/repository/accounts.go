@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	STATEMENT_INVOICE = "INVOICE"
+	STATEMENT_PAYMENT = "PAYMENT"
+)
+
+// Invoice is a sale on account with a balance still owed.
+type Invoice struct {
+	SaleID        uint32     `json:"sale_id"`
+	ReceiptNumber string     `json:"receipt_number"`
+	TotalAmount   float64    `json:"total_amount"`
+	AmountPaid    float64    `json:"amount_paid"`
+	Balance       float64    `json:"balance"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DueDate       *time.Time `json:"due_date"`
+	DaysOverdue   int64      `json:"days_overdue"`
+}
+
+type CustomerAccount struct {
+	CustomerID       uint32     `json:"customer_id"`
+	CustomerName     string     `json:"customer_name"`
+	CreditLimit      float64    `json:"credit_limit"`
+	PaymentTermsDays int32      `json:"payment_terms_days"`
+	Outstanding      float64    `json:"outstanding"`
+	AvailableCredit  float64    `json:"available_credit"`
+	OpenInvoices     []*Invoice `json:"open_invoices"`
+}
+
+// AccountPayment is money received from a customer against their account. It is
+// allocated to the oldest open invoices first.
+type AccountPayment struct {
+	CustomerID uint32  `json:"customer_id"`
+	Method     string  `json:"method"`
+	Amount     float64 `json:"amount"`
+	Reference  *string `json:"reference"`
+	ReceivedBy uint32  `json:"received_by"`
+}
+
+// AgeingLine splits a customer's outstanding balance by the age of the invoices.
+type AgeingLine struct {
+	CustomerID   uint32  `json:"customer_id"`
+	CustomerName string  `json:"customer_name"`
+	CreditLimit  float64 `json:"credit_limit"`
+	Current      float64 `json:"current"`
+	Days31To60   float64 `json:"days_31_60"`
+	Days61To90   float64 `json:"days_61_90"`
+	Over90       float64 `json:"over_90"`
+	Total        float64 `json:"total"`
+}
+
+type AgeingReport struct {
+	AsOf   time.Time     `json:"as_of"`
+	Lines  []*AgeingLine `json:"lines"`
+	Totals AgeingLine    `json:"totals"`
+}
+
+type StatementEntry struct {
+	Date      time.Time `json:"date"`
+	Type      string    `json:"type"`
+	SaleID    uint32    `json:"sale_id"`
+	Reference string    `json:"reference"`
+	Debit     float64   `json:"debit"`
+	Credit    float64   `json:"credit"`
+	Balance   float64   `json:"balance"`
+}
+
+type CustomerStatement struct {
+	Customer       *Customer         `json:"customer"`
+	StartDate      time.Time         `json:"start_date"`
+	EndDate        time.Time         `json:"end_date"`
+	OpeningBalance float64           `json:"opening_balance"`
+	Entries        []*StatementEntry `json:"entries"`
+	ClosingBalance float64           `json:"closing_balance"`
+	Ageing         *AgeingLine       `json:"ageing"`
+}
+
+type CustomerAccountRepository interface {
+	GetAccount(ctx context.Context, customerID int64) (*CustomerAccount, error)
+	// RecordPayment allocates the payment to the customer's open invoices, oldest
+	// first, and returns the payments recorded against each invoice.
+	RecordPayment(ctx context.Context, payment *AccountPayment) ([]*Payment, error)
+	GetAgeingReport(ctx context.Context, asOf time.Time, customerID *uint32) (*AgeingReport, error)
+	// GetStatement lists invoices and payments between startDate and endDate with running balances.
+	GetStatement(ctx context.Context, customerID int64, startDate, endDate time.Time) (*CustomerStatement, error)
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// Category groups products. Categories form a tree through ParentID.
+type Category struct {
+	ID           uint32      `json:"id"`
+	Name         string      `json:"name"`
+	ParentID     *uint32     `json:"parent_id"`
+	ProductCount int64       `json:"product_count"`
+	CreatedAt    time.Time   `json:"created_at"`
+	Children     []*Category `json:"children,omitempty"`
+}
+
+type CategoryUpdate struct {
+	Name     *string `json:"name"`
+	ParentID *uint32 `json:"parent_id"`
+
+	// MakeRoot detaches the category from its parent.
+	MakeRoot bool `json:"make_root"`
+}
+
+type CategoryRepository interface {
+	Create(ctx context.Context, category *Category) (*Category, error)
+	GetByID(ctx context.Context, id int64) (*Category, error)
+	Update(ctx context.Context, id int64, categoryUpdate *CategoryUpdate) (*Category, error)
+	// Delete moves the category's products to moveTo and its subcategories to its parent.
+	// A category that still has products cannot be deleted without moveTo.
+	Delete(ctx context.Context, id int64, moveTo *uint32) error
+	// List returns the root categories with their subcategories nested under them.
+	List(ctx context.Context) ([]*Category, error)
+}
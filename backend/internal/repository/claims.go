@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+const (
+	CLAIM_BATCH_DRAFT     = "DRAFT"
+	CLAIM_BATCH_SUBMITTED = "SUBMITTED"
+	CLAIM_BATCH_COMPLETED = "COMPLETED"
+)
+
+const (
+	CLAIM_PENDING   = "PENDING"
+	CLAIM_SUBMITTED = "SUBMITTED"
+	CLAIM_APPROVED  = "APPROVED"
+	CLAIM_REJECTED  = "REJECTED"
+)
+
+// ClaimBatch groups the insured sales of one insurer over a period so they can be
+// submitted together. A batch is completed once every claim in it is decided.
+type ClaimBatch struct {
+	ID                uint32     `json:"id"`
+	BatchNumber       string     `json:"batch_number"`
+	InsurerID         uint32     `json:"insurer_id"`
+	PeriodStart       time.Time  `json:"period_start"`
+	PeriodEnd         time.Time  `json:"period_end"`
+	Status            string     `json:"status"`
+	TotalClaims       int64      `json:"total_claims"`
+	TotalAmount       float64    `json:"total_amount"`
+	ApprovedAmount    float64    `json:"approved_amount"`
+	ExternalReference *string    `json:"external_reference"`
+	SubmittedBy       *uint32    `json:"submitted_by"`
+	SubmittedAt       *time.Time `json:"submitted_at"`
+	CreatedBy         uint32     `json:"created_by"`
+	CreatedAt         time.Time  `json:"created_at"`
+	Claims            []*Claim   `json:"claims,omitempty"`
+
+	// Related fields
+	InsurerName   string `json:"insurer_name"`
+	InsurerCode   string `json:"insurer_code"`
+	InsurerClient string `json:"insurer_client"`
+	UserName      string `json:"user_name"`
+}
+
+// Claim is the amount of a sale's insurance tenders claimed from the insurer.
+type Claim struct {
+	ID                uint32     `json:"id"`
+	BatchID           uint32     `json:"batch_id"`
+	SaleID            uint32     `json:"sale_id"`
+	SchemeID          uint32     `json:"scheme_id"`
+	MemberNumber      string     `json:"member_number"`
+	Amount            float64    `json:"amount"`
+	Status            string     `json:"status"`
+	ApprovedAmount    float64    `json:"approved_amount"`
+	RejectionReason   *string    `json:"rejection_reason"`
+	ExternalReference *string    `json:"external_reference"`
+	ResolvedAt        *time.Time `json:"resolved_at"`
+
+	// Related fields
+	ReceiptNumber string    `json:"receipt_number"`
+	SaleDate      time.Time `json:"sale_date"`
+	SaleAmount    float64   `json:"sale_amount"`
+	SchemeName    string    `json:"scheme_name"`
+	CustomerName  *string   `json:"customer_name"`
+}
+
+// ClaimDecision is the insurer's decision on a submitted claim. ApprovedAmount
+// defaults to the full claim when an approval does not give one.
+type ClaimDecision struct {
+	ClaimID           uint32
+	Status            string
+	ApprovedAmount    float64
+	RejectionReason   *string
+	ExternalReference *string
+}
+
+type ClaimBatchFilter struct {
+	Pagination *pkg.Pagination
+	InsurerID  *uint32
+	Status     *string
+}
+
+type ClaimRepository interface {
+	// CreateBatch claims every insured sale of the insurer in the period that has not
+	// been claimed yet. It fails when there is nothing to claim.
+	CreateBatch(ctx context.Context, batch *ClaimBatch) (*ClaimBatch, error)
+	GetBatch(ctx context.Context, id int64) (*ClaimBatch, error)
+	ListBatches(ctx context.Context, filter *ClaimBatchFilter) ([]*ClaimBatch, *pkg.Pagination, error)
+
+	// MarkSubmitted records that a draft batch was accepted by the insurer.
+	MarkSubmitted(ctx context.Context, id int64, reference *string, submittedBy uint32) (*ClaimBatch, error)
+
+	// Decide applies the insurer's decisions to claims of a submitted batch. Claims
+	// that are already decided are left as they are. Whatever a rejected or partly
+	// approved claim leaves unpaid is taken off the sale's insurance payments, reopening
+	// the sale balance for the customer to settle.
+	Decide(ctx context.Context, batchID int64, decisions []*ClaimDecision) (*ClaimBatch, error)
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// ClassificationFilter is the period products are classified over. It defaults
+// to the year up to now.
+type ClassificationFilter struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// ProductClassification is a product's ABC class, from its share of the
+// consumption value, and XYZ class, from how much its weekly demand varies.
+// Consumption is the stock removed from the product other than returns to
+// suppliers and write-offs, valued at the price it was removed at.
+type ProductClassification struct {
+	ProductID           uint32  `json:"product_id"`
+	ProductName         string  `json:"product_name"`
+	ConsumptionQuantity int64   `json:"consumption_quantity"`
+	ConsumptionValue    float64 `json:"consumption_value"`
+	ValueShare          float64 `json:"value_share"`
+	CumulativeShare     float64 `json:"cumulative_share"`
+	ABCClass            string  `json:"abc_class"`
+
+	MeanWeeklyDemand float64 `json:"mean_weekly_demand"`
+	// CoefficientOfVariation and XYZClass are nil for products without demand in
+	// the period.
+	CoefficientOfVariation *float64 `json:"coefficient_of_variation"`
+	XYZClass               *string  `json:"xyz_class"`
+}
+
+type ClassificationReport struct {
+	StartDate    time.Time `json:"start_date"`
+	EndDate      time.Time `json:"end_date"`
+	Weeks        int       `json:"weeks"`
+	ClassifiedAt time.Time `json:"classified_at"`
+	TotalValue   float64   `json:"total_value"`
+	// Counts holds the number of products in each ABC and XYZ class pair, such
+	// as "AX"; products without an XYZ class are counted under their ABC class.
+	Counts   map[string]int           `json:"counts"`
+	Products []*ProductClassification `json:"products"`
+}
+
+type ClassificationRepository interface {
+	// Classify classes every product other than kits, which are assembled from
+	// their components, and stores the classes on the products. It returns the
+	// products ranked by consumption value.
+	Classify(ctx context.Context, filter *ClassificationFilter) (*ClassificationReport, error)
+}
@@ -0,0 +1,55 @@
+package repository
+
+import "time"
+
+type ControlledDrugFilter struct {
+	ProductID *uint32
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+type ControlledDrugEntry struct {
+	ID             uint32    `json:"id"`
+	ProductID      uint32    `json:"product_id"`
+	MovementID     uint32    `json:"movement_id"`
+	EntryType      string    `json:"entry_type"`
+	Quantity       int32     `json:"quantity"`
+	Balance        int64     `json:"balance"`
+	Reference      *string   `json:"reference"`
+	CustomerID     *uint32   `json:"customer_id"`
+	PrescriptionID *uint32   `json:"prescription_id"`
+	PerformedBy    uint32    `json:"performed_by"`
+	WitnessedBy    uint32    `json:"witnessed_by"`
+	PreviousHash   string    `json:"previous_hash"`
+	Hash           string    `json:"hash"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Related fields
+	PerformedByName         string  `json:"performed_by_name"`
+	WitnessedByName         string  `json:"witnessed_by_name"`
+	CustomerName            *string `json:"customer_name"`
+	PrescriberName          *string `json:"prescriber_name"`
+	PrescriberLicenceNumber *string `json:"prescriber_licence_number"`
+}
+
+// ControlledDrugRegister is the running register of a single controlled product.
+// Verified is false when the hash chain does not match the stored entries, in which
+// case TamperedEntryID is the first entry that failed verification.
+type ControlledDrugRegister struct {
+	ProductID       uint32                 `json:"product_id"`
+	ProductName     string                 `json:"product_name"`
+	Unit            string                 `json:"unit"`
+	OpeningBalance  int64                  `json:"opening_balance"`
+	ClosingBalance  int64                  `json:"closing_balance"`
+	TotalReceived   int64                  `json:"total_received"`
+	TotalIssued     int64                  `json:"total_issued"`
+	Verified        bool                   `json:"verified"`
+	TamperedEntryID *uint32                `json:"tampered_entry_id"`
+	Entries         []*ControlledDrugEntry `json:"entries"`
+}
+
+type ControlledDrugReport struct {
+	StartDate time.Time                 `json:"start_date"`
+	EndDate   time.Time                 `json:"end_date"`
+	Registers []*ControlledDrugRegister `json:"registers"`
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+type Customer struct {
+	ID          uint32     `json:"id"`
+	Name        string     `json:"name"`
+	PhoneNumber *string    `json:"phone_number"`
+	DateOfBirth *time.Time `json:"date_of_birth"`
+	Allergies   *string    `json:"allergies"`
+	Notes       *string    `json:"notes"`
+	Deleted     bool       `json:"deleted"`
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// Customers with a credit limit above zero may buy on account, paying within
+	// PaymentTermsDays of the sale.
+	CreditLimit      float64 `json:"credit_limit"`
+	PaymentTermsDays int32   `json:"payment_terms_days"`
+}
+
+type CustomerUpdate struct {
+	Name        *string    `json:"name"`
+	PhoneNumber *string    `json:"phone_number"`
+	DateOfBirth *time.Time `json:"date_of_birth"`
+	Allergies   *string    `json:"allergies"`
+	Notes       *string    `json:"notes"`
+
+	CreditLimit      *float64 `json:"credit_limit"`
+	PaymentTermsDays *int32   `json:"payment_terms_days"`
+}
+
+type CustomerFilter struct {
+	Pagination *pkg.Pagination
+	Search     *string
+}
+
+type CustomerRepository interface {
+	Create(ctx context.Context, customer *Customer) (*Customer, error)
+	GetByID(ctx context.Context, id int64) (*Customer, error)
+	Update(ctx context.Context, id int64, customerUpdate *CustomerUpdate) (*Customer, error)
+	Delete(ctx context.Context, id int64) error
+	List(ctx context.Context, filter *CustomerFilter) ([]*Customer, *pkg.Pagination, error)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+// ForecastFilter overrides the configured forecast settings. HistoryDays is how
+// many days of demand the forecast learns from and HorizonDays how many days
+// ahead it forecasts, which is also the period suggested orders cover.
+type ForecastFilter struct {
+	Pagination  *pkg.Pagination
+	Method      *string
+	HistoryDays *int
+	HorizonDays *int
+}
+
+type ForecastPoint struct {
+	Date     time.Time `json:"date"`
+	Quantity float64   `json:"quantity"`
+	Lower    float64   `json:"lower"`
+	Upper    float64   `json:"upper"`
+}
+
+// ProductForecast is a product's forecast daily demand with 95% confidence bands.
+// Demand is the stock removed from the product other than returns to suppliers
+// and write-offs.
+type ProductForecast struct {
+	ProductID   uint32           `json:"product_id"`
+	ProductName string           `json:"product_name"`
+	Method      string           `json:"method"`
+	HistoryDays int              `json:"history_days"`
+	HorizonDays int              `json:"horizon_days"`
+	Points      []*ForecastPoint `json:"points"`
+	TotalDemand float64          `json:"total_demand"`
+	TotalLower  float64          `json:"total_lower"`
+	TotalUpper  float64          `json:"total_upper"`
+
+	AvailableStock int64  `json:"available_stock"`
+	OnOrder        int64  `json:"on_order"`
+	ReorderPoint   *int32 `json:"reorder_point"`
+	// DaysOfCover is how many days the available stock lasts at the forecast
+	// demand. It is nil when no demand is forecast.
+	DaysOfCover *float64 `json:"days_of_cover"`
+	// SuggestedOrderQuantity tops available and on order stock up to the upper
+	// band of demand over the horizon.
+	SuggestedOrderQuantity int64 `json:"suggested_order_quantity"`
+}
+
+type ForecastRepository interface {
+	GetProductForecast(ctx context.Context, productID int64, filter *ForecastFilter) (*ProductForecast, error)
+	// List forecasts every product other than kits, which are assembled from
+	// their components rather than bought.
+	List(ctx context.Context, filter *ForecastFilter) ([]*ProductForecast, *pkg.Pagination, error)
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// Insurer is an insurance company whose members' sales are claimed from it. Client
+// names the insurance client implementation its claims are submitted through.
+type Insurer struct {
+	ID          uint32    `json:"id"`
+	Name        string    `json:"name"`
+	Code        string    `json:"code"`
+	Client      string    `json:"client"`
+	Email       *string   `json:"email"`
+	PhoneNumber *string   `json:"phone_number"`
+	Deleted     bool      `json:"deleted"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type InsurerUpdate struct {
+	Name        *string `json:"name"`
+	Client      *string `json:"client"`
+	Email       *string `json:"email"`
+	PhoneNumber *string `json:"phone_number"`
+}
+
+// InsuranceScheme is a cover plan offered by an insurer. CoPaymentRate is the
+// percentage of each sale the member pays themselves; the insurer covers the rest.
+type InsuranceScheme struct {
+	ID            uint32    `json:"id"`
+	InsurerID     uint32    `json:"insurer_id"`
+	Name          string    `json:"name"`
+	CoPaymentRate float64   `json:"co_payment_rate"`
+	Active        bool      `json:"active"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Related fields
+	InsurerName string `json:"insurer_name"`
+}
+
+type InsuranceSchemeUpdate struct {
+	Name          *string  `json:"name"`
+	CoPaymentRate *float64 `json:"co_payment_rate"`
+	Active        *bool    `json:"active"`
+}
+
+type InsuranceRepository interface {
+	CreateInsurer(ctx context.Context, insurer *Insurer) (*Insurer, error)
+	GetInsurer(ctx context.Context, id int64) (*Insurer, error)
+	UpdateInsurer(ctx context.Context, id int64, insurerUpdate *InsurerUpdate) (*Insurer, error)
+	DeleteInsurer(ctx context.Context, id int64) error
+	ListInsurers(ctx context.Context) ([]*Insurer, error)
+
+	CreateScheme(ctx context.Context, scheme *InsuranceScheme) (*InsuranceScheme, error)
+	GetScheme(ctx context.Context, id int64) (*InsuranceScheme, error)
+	UpdateScheme(ctx context.Context, id int64, schemeUpdate *InsuranceSchemeUpdate) (*InsuranceScheme, error)
+	ListSchemes(ctx context.Context, insurerID *uint32) ([]*InsuranceScheme, error)
+}
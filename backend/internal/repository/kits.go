@@ -0,0 +1,13 @@
+package repository
+
+// KitComponent is one line of a kit's bill of materials.
+type KitComponent struct {
+	KitID       uint32 `json:"kit_id"`
+	ComponentID uint32 `json:"component_id"`
+	Quantity    int32  `json:"quantity"`
+
+	// Related fields
+	ProductName    string `json:"product_name"`
+	Unit           string `json:"unit"`
+	AvailableStock int64  `json:"available_stock"`
+}
@@ -9,18 +9,31 @@ import (
 const (
 	MOVEMENT_ADD    = "ADD"
 	MOVEMENT_REMOVE = "REMOVE"
+
+	// reasons for movements that are not ordinary purchases or issues
+	MOVEMENT_REASON_CUSTOMER_RETURN = "CUSTOMER_RETURN"
+	MOVEMENT_REASON_SUPPLIER_RETURN = "SUPPLIER_RETURN"
+	MOVEMENT_REASON_KIT_ASSEMBLY    = "KIT_ASSEMBLY"
+	MOVEMENT_REASON_WRITE_OFF       = "WRITE_OFF"
+	MOVEMENT_REASON_QUARANTINE      = "QUARANTINE"
 )
 
 type Movement struct {
-	ID          uint32    `json:"id"`
-	ProductID   uint32    `json:"product_id"`
-	Quantity    int32     `json:"quantity"`
-	Price       float64   `json:"price"`
-	Type        string    `json:"type"`
-	BatchNumber *string   `json:"batch_number"`
-	Note        *string   `json:"note"`
-	PerformedBy uint32    `json:"performed_by"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID             uint32    `json:"id"`
+	ProductID      uint32    `json:"product_id"`
+	Quantity       int32     `json:"quantity"`
+	Price          float64   `json:"price"`
+	UnitCost       float64   `json:"unit_cost"`
+	Type           string    `json:"type"`
+	BatchNumber    *string   `json:"batch_number"`
+	Note           *string   `json:"note"`
+	PerformedBy    uint32    `json:"performed_by"`
+	SaleID         *uint32   `json:"sale_id"`
+	CustomerID     *uint32   `json:"customer_id"`
+	PrescriptionID *uint32   `json:"prescription_id"`
+	WitnessedBy    *uint32   `json:"witnessed_by"`
+	Reason         *string   `json:"reason"`
+	CreatedAt      time.Time `json:"created_at"`
 
 	// Related fields
 	ProductName string `json:"product_name"`
@@ -32,6 +45,7 @@ type MovementFilter struct {
 	ProductID   *uint32
 	Type        *string
 	BatchNumber *string
+	CustomerID  *uint32
 	StartDate   *time.Time
 	EndDate     *time.Time
 }
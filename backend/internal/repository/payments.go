@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	PAYMENT_CASH      = "CASH"
+	PAYMENT_CARD      = "CARD"
+	PAYMENT_MPESA     = "MPESA"
+	PAYMENT_INSURANCE = "INSURANCE"
+)
+
+const (
+	PAYMENT_PENDING   = "PENDING"
+	PAYMENT_COMPLETED = "COMPLETED"
+	PAYMENT_FAILED    = "FAILED"
+)
+
+// Payment is one tender towards a sale. A sale can be settled by several payments
+// of different methods. M-Pesa payments stay pending until the STK push callback
+// arrives; every other method is completed when it is recorded.
+type Payment struct {
+	ID                uint32     `json:"id"`
+	SaleID            uint32     `json:"sale_id"`
+	Method            string     `json:"method"`
+	Amount            float64    `json:"amount"`
+	Status            string     `json:"status"`
+	Reference         *string    `json:"reference"`
+	PhoneNumber       *string    `json:"phone_number"`
+	CheckoutRequestID *string    `json:"checkout_request_id"`
+	FailureReason     *string    `json:"failure_reason"`
+	ReceivedBy        uint32     `json:"received_by"`
+	CompletedAt       *time.Time `json:"completed_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	// ShiftID is the open shift of the user who received the payment.
+	ShiftID *uint32 `json:"shift_id"`
+}
+
+// PaymentResult is the outcome of a pending payment reported by the payment provider.
+type PaymentResult struct {
+	CheckoutRequestID string
+	Success           bool
+	Reference         *string
+	FailureReason     *string
+	// Amount is what the customer actually paid, checked against the pending payment.
+	Amount *float64
+}
+
+type PaymentRepository interface {
+	// Create records a payment against a sale. Pending and completed payments
+	// together may not exceed the sale total.
+	Create(ctx context.Context, payment *Payment) (*Payment, error)
+	GetByID(ctx context.Context, id int64) (*Payment, error)
+	ListBySale(ctx context.Context, saleID int64) ([]*Payment, error)
+	SetCheckoutRequestID(ctx context.Context, id int64, checkoutRequestID string) error
+	// Fail marks a pending payment as failed, e.g. when the provider rejects the request outright.
+	Fail(ctx context.Context, id int64, reason string) (*Payment, error)
+	// Resolve completes or fails the pending payment matching the result's checkout request id.
+	// A successful result whose amount differs from the payment fails it instead.
+	Resolve(ctx context.Context, result *PaymentResult) (*Payment, error)
+	// ExpirePending fails mobile money payments that have waited longer than the
+	// configured timeout for a callback, releasing their hold on the sale balance.
+	ExpirePending(ctx context.Context) (int64, error)
+}
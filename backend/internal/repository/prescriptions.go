@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+type Prescription struct {
+	ID                      uint32              `json:"id"`
+	CustomerID              uint32              `json:"customer_id"`
+	PrescriberName          string              `json:"prescriber_name"`
+	PrescriberLicenceNumber string              `json:"prescriber_licence_number"`
+	PrescribedAt            time.Time           `json:"prescribed_at"`
+	ValidUntil              time.Time           `json:"valid_until"`
+	Notes                   *string             `json:"notes"`
+	CreatedBy               uint32              `json:"created_by"`
+	CreatedAt               time.Time           `json:"created_at"`
+	Items                   []*PrescriptionItem `json:"items"`
+	Scans                   []*PrescriptionScan `json:"scans"`
+
+	// Related fields
+	CustomerName string `json:"customer_name"`
+}
+
+type PrescriptionItem struct {
+	ID                uint32  `json:"id"`
+	PrescriptionID    uint32  `json:"prescription_id"`
+	ProductID         uint32  `json:"product_id"`
+	Quantity          int32   `json:"quantity"`
+	DispensedQuantity int32   `json:"dispensed_quantity"`
+	Dosage            *string `json:"dosage"`
+
+	// Related fields
+	ProductName string `json:"product_name"`
+}
+
+type PrescriptionScan struct {
+	ID             uint32    `json:"id"`
+	PrescriptionID uint32    `json:"prescription_id"`
+	FileName       string    `json:"file_name"`
+	ContentType    string    `json:"content_type"`
+	Data           []byte    `json:"-"`
+	UploadedBy     uint32    `json:"uploaded_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type PrescriptionFilter struct {
+	Pagination *pkg.Pagination
+	CustomerID *uint32
+	Search     *string
+}
+
+type PrescriptionRepository interface {
+	Create(ctx context.Context, prescription *Prescription) (*Prescription, error)
+	GetByID(ctx context.Context, id int64) (*Prescription, error)
+	List(ctx context.Context, filter *PrescriptionFilter) ([]*Prescription, *pkg.Pagination, error)
+
+	// Scans of the paper prescription
+	AddScan(ctx context.Context, scan *PrescriptionScan) (*PrescriptionScan, error)
+	GetScan(ctx context.Context, prescriptionID, scanID int64) (*PrescriptionScan, error)
+}
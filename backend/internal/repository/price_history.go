@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+type PriceChange struct {
+	ID          uint32     `json:"id"`
+	ProductID   uint32     `json:"product_id"`
+	OldPrice    float64    `json:"old_price"`
+	NewPrice    float64    `json:"new_price"`
+	EffectiveAt time.Time  `json:"effective_at"`
+	Applied     bool       `json:"applied"`
+	AppliedAt   *time.Time `json:"applied_at"`
+	Note        *string    `json:"note"`
+	ChangedBy   uint32     `json:"changed_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// Related fields
+	UserName string `json:"user_name"`
+}
+
+type PriceHistoryFilter struct {
+	Pagination *pkg.Pagination
+	ProductID  uint32
+}
@@ -13,11 +13,44 @@ type Product struct {
 	Description       string    `json:"description"`
 	Price             float64   `json:"price"`
 	Stock             int64     `json:"stock"`
+	OpeningUnitCost   float64   `json:"opening_unit_cost"`
+	ReservedStock     int64     `json:"reserved_stock"`
+	AvailableStock    int64     `json:"available_stock"`
+	QuarantinedStock  int64     `json:"quarantined_stock"`
 	Category          string    `json:"category"`
+	CategoryID        *uint32   `json:"category_id"`
+	TaxClassID        uint32    `json:"tax_class_id"`
 	Unit              string    `json:"unit"`
 	LowStockThreshold int32     `json:"low_stock_threshold"`
+	PrescriptionOnly  bool      `json:"prescription_only"`
+	Controlled        bool      `json:"controlled"`
 	Deleted           bool      `json:"deleted"`
 	CreatedAt         time.Time `json:"created_at"`
+
+	// GenericName is the international non-proprietary name (INN), e.g. amoxicillin.
+	GenericName      *string `json:"generic_name"`
+	Strength         *string `json:"strength"`
+	DosageForm       *string `json:"dosage_form"`
+	Route            *string `json:"route"`
+	Manufacturer     *string `json:"manufacturer"`
+	PackSize         *int32  `json:"pack_size"`
+	TherapeuticClass *string `json:"therapeutic_class"`
+
+	// Products with a reorder point are ordered from their preferred supplier when
+	// their available stock, with what is already on order, falls below it.
+	ReorderPoint        *int32  `json:"reorder_point"`
+	ReorderQuantity     *int32  `json:"reorder_quantity"`
+	PreferredSupplierID *uint32 `json:"preferred_supplier_id"`
+
+	// ABCClass and XYZClass are set by the latest inventory classification.
+	ABCClass     *string    `json:"abc_class"`
+	XYZClass     *string    `json:"xyz_class"`
+	ClassifiedAt *time.Time `json:"classified_at"`
+
+	// Kits are sold from assembled stock first and then built from their components,
+	// so their available stock includes the kits the components can still make.
+	IsKit      bool            `json:"is_kit"`
+	Components []*KitComponent `json:"components,omitempty"`
 }
 
 type ProductUpdate struct {
@@ -25,22 +58,81 @@ type ProductUpdate struct {
 	Description       *string  `json:"description"`
 	Price             *float64 `json:"price"`
 	Category          *string  `json:"category"`
+	CategoryID        *uint32  `json:"category_id"`
+	TaxClassID        *uint32  `json:"tax_class_id"`
 	Unit              *string  `json:"unit"`
 	LowStockThreshold *int32   `json:"low_stock_threshold"`
+	PrescriptionOnly  *bool    `json:"prescription_only"`
+	Controlled        *bool    `json:"controlled"`
+	GenericName       *string  `json:"generic_name"`
+	Strength          *string  `json:"strength"`
+	DosageForm        *string  `json:"dosage_form"`
+	Route             *string  `json:"route"`
+	Manufacturer      *string  `json:"manufacturer"`
+	PackSize          *int32   `json:"pack_size"`
+	TherapeuticClass  *string  `json:"therapeutic_class"`
+
+	// A price change takes effect immediately unless PriceEffectiveAt is in the future,
+	// in which case it is recorded as a scheduled change.
+	PriceEffectiveAt *time.Time `json:"price_effective_at"`
+	PriceNote        *string    `json:"price_note"`
+	UpdatedBy        uint32     `json:"-"`
 }
 
 type ProductStockUpdate struct {
 	ID          uint32
 	PerformedBy uint32
 	Quantity    int64
+	UnitCost    *float64
 	Note        *string
 	BatchNumber *string
+	SaleID      *uint32
+	CustomerID  *uint32
+
+	// PrescriptionID is required when removing stock of a prescription-only product.
+	PrescriptionID *uint32
+
+	// WitnessedBy is the second user confirming a movement of a controlled product.
+	WitnessedBy *uint32
+
+	// Reason marks movements that are not ordinary stock additions or issues.
+	Reason *string
+}
+
+// ReorderPolicy replaces a product's reorder settings. A nil ReorderPoint stops the
+// product from being reordered.
+type ReorderPolicy struct {
+	ReorderPoint        *int32
+	ReorderQuantity     *int32
+	PreferredSupplierID *uint32
 }
 
 type ProductFilter struct {
 	Pagination *pkg.Pagination
 	Search     *string
 	Status     *string
+
+	// CategoryID matches products in the category or any of its subcategories.
+	CategoryID *uint32
+
+	// Attribute filters match case-insensitively; Strength also ignores spacing.
+	GenericName      *string
+	Strength         *string
+	DosageForm       *string
+	Route            *string
+	Manufacturer     *string
+	TherapeuticClass *string
+
+	ABCClass *string
+	XYZClass *string
+}
+
+// InsufficientStock is returned as error details when stock cannot be removed,
+// with in-stock equivalents the user can sell instead.
+type InsufficientStock struct {
+	ProductID      uint32     `json:"product_id"`
+	AvailableStock int64      `json:"available_stock"`
+	Substitutes    []*Product `json:"substitutes"`
 }
 
 type ProductRepository interface {
@@ -49,14 +141,29 @@ type ProductRepository interface {
 	Update(ctx context.Context, id int64, productUpdate *ProductUpdate) (*Product, error)
 	Delete(ctx context.Context, id int64) error
 	List(ctx context.Context, filter *ProductFilter) ([]*Product, *pkg.Pagination, error)
+	// ListSubstitutes returns in-stock products with the same generic name, strength and
+	// dosage form, cheapest first.
+	ListSubstitutes(ctx context.Context, id int64, limit int32) ([]*Product, error)
 
 	// For stock movements
 	AddStock(ctx context.Context, data *ProductStockUpdate) (*Product, error)
 	RemoveStock(ctx context.Context, data *ProductStockUpdate) (*Product, error)
 	ListMovements(ctx context.Context, filter *MovementFilter) ([]*Movement, *pkg.Pagination, error)
 
+	SetReorderPolicy(ctx context.Context, id int64, policy *ReorderPolicy) (*Product, error)
+
+	// Kits
+	SetKitComponents(ctx context.Context, kitID int64, components []*KitComponent) (*Product, error)
+	AssembleKit(ctx context.Context, data *ProductStockUpdate) (*Product, error)
+
+	// Price history
+	ListPriceHistory(ctx context.Context, filter *PriceHistoryFilter) ([]*PriceChange, *pkg.Pagination, error)
+	ApplyDuePriceChanges(ctx context.Context) (int, error)
+
 	// Stats
 	GetStats(ctx context.Context) (*Stats, error)
+	GetValuation(ctx context.Context, filter *ValuationFilter) (*Valuation, error)
+	GetControlledDrugRegister(ctx context.Context, filter *ControlledDrugFilter) (*ControlledDrugReport, error)
 	ProductFormHeper(ctx context.Context) (any, error)
 	GetDashboardData(ctx context.Context) (map[string]any, error)
 }
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+const (
+	PROMOTION_PERCENTAGE  = "PERCENTAGE"
+	PROMOTION_FIXED       = "FIXED"
+	PROMOTION_BUY_X_GET_Y = "BUY_X_GET_Y"
+)
+
+// Promotion discounts sale lines of a product, or of every product in a category and
+// its subcategories, between StartsAt and EndsAt. Value is a percentage for PERCENTAGE
+// promotions and an amount off each unit for FIXED ones. BUY_X_GET_Y promotions give
+// FreeQuantity units free for every BuyQuantity units paid for.
+type Promotion struct {
+	ID           uint32    `json:"id"`
+	Name         string    `json:"name"`
+	Type         string    `json:"type"`
+	Value        float64   `json:"value"`
+	BuyQuantity  *int32    `json:"buy_quantity"`
+	FreeQuantity *int32    `json:"free_quantity"`
+	ProductID    *uint32   `json:"product_id"`
+	CategoryID   *uint32   `json:"category_id"`
+	StartsAt     time.Time `json:"starts_at"`
+	EndsAt       time.Time `json:"ends_at"`
+	Active       bool      `json:"active"`
+	CreatedBy    uint32    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Related fields
+	ProductName  *string `json:"product_name,omitempty"`
+	CategoryName *string `json:"category_name,omitempty"`
+}
+
+type PromotionUpdate struct {
+	Name         *string    `json:"name"`
+	Value        *float64   `json:"value"`
+	BuyQuantity  *int32     `json:"buy_quantity"`
+	FreeQuantity *int32     `json:"free_quantity"`
+	StartsAt     *time.Time `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	Active       *bool      `json:"active"`
+}
+
+type PromotionFilter struct {
+	Pagination *pkg.Pagination
+	// Current limits the list to promotions running right now, or to those that are not.
+	Current   *bool
+	ProductID *uint32
+}
+
+type DiscountReportLine struct {
+	PromotionID    uint32  `json:"promotion_id"`
+	PromotionName  string  `json:"promotion_name"`
+	PromotionType  string  `json:"promotion_type"`
+	TotalLines     int64   `json:"total_lines"`
+	TotalQuantity  int64   `json:"total_quantity"`
+	GrossAmount    float64 `json:"gross_amount"`
+	DiscountAmount float64 `json:"discount_amount"`
+}
+
+// DiscountReport splits sales for a period into gross sales at list price, discounts
+// given and net revenue.
+type DiscountReport struct {
+	StartDate      time.Time             `json:"start_date"`
+	EndDate        time.Time             `json:"end_date"`
+	GrossAmount    float64               `json:"gross_amount"`
+	DiscountAmount float64               `json:"discount_amount"`
+	NetAmount      float64               `json:"net_amount"`
+	Promotions     []*DiscountReportLine `json:"promotions"`
+}
+
+type PromotionRepository interface {
+	Create(ctx context.Context, promotion *Promotion) (*Promotion, error)
+	GetByID(ctx context.Context, id int64) (*Promotion, error)
+	Update(ctx context.Context, id int64, promotionUpdate *PromotionUpdate) (*Promotion, error)
+	List(ctx context.Context, filter *PromotionFilter) ([]*Promotion, *pkg.Pagination, error)
+
+	// GetDiscountReport covers sales made from startDate up to but not including endDate.
+	GetDiscountReport(ctx context.Context, startDate, endDate time.Time) (*DiscountReport, error)
+}
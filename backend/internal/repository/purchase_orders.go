@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+const (
+	PURCHASE_ORDER_DRAFT     = "DRAFT"
+	PURCHASE_ORDER_ORDERED   = "ORDERED"
+	PURCHASE_ORDER_RECEIVED  = "RECEIVED"
+	PURCHASE_ORDER_CANCELLED = "CANCELLED"
+)
+
+// PurchaseOrder is an order of stock from a supplier. Drafts are generated from
+// product reorder points and can be edited until they are ordered. Stock on draft
+// and ordered purchase orders counts as on order, so it is not ordered again.
+type PurchaseOrder struct {
+	ID            uint32               `json:"id"`
+	OrderNumber   string               `json:"order_number"`
+	SupplierID    uint32               `json:"supplier_id"`
+	Status        string               `json:"status"`
+	TotalQuantity int64                `json:"total_quantity"`
+	TotalAmount   float64              `json:"total_amount"`
+	Note          *string              `json:"note"`
+	CreatedBy     *uint32              `json:"created_by"`
+	OrderedBy     *uint32              `json:"ordered_by"`
+	OrderedAt     *time.Time           `json:"ordered_at"`
+	ClosedAt      *time.Time           `json:"closed_at"`
+	CreatedAt     time.Time            `json:"created_at"`
+	Items         []*PurchaseOrderItem `json:"items"`
+
+	// Related fields
+	SupplierName  string  `json:"supplier_name"`
+	CreatedByName *string `json:"created_by_name"`
+}
+
+// PurchaseOrderItem is one product on a purchase order, costed at the product's
+// last purchase cost when it was added.
+type PurchaseOrderItem struct {
+	ID              uint32  `json:"id"`
+	PurchaseOrderID uint32  `json:"purchase_order_id"`
+	ProductID       uint32  `json:"product_id"`
+	Quantity        int32   `json:"quantity"`
+	UnitCost        float64 `json:"unit_cost"`
+	LineTotal       float64 `json:"line_total"`
+
+	// Related fields
+	ProductName    string `json:"product_name"`
+	AvailableStock int64  `json:"available_stock"`
+	ReorderPoint   *int32 `json:"reorder_point"`
+}
+
+type PurchaseOrderFilter struct {
+	Pagination *pkg.Pagination
+	SupplierID *uint32
+	Status     *string
+}
+
+type PurchaseOrderRepository interface {
+	GetByID(ctx context.Context, id int64) (*PurchaseOrder, error)
+	List(ctx context.Context, filter *PurchaseOrderFilter) ([]*PurchaseOrder, *pkg.Pagination, error)
+
+	// GenerateDrafts orders every product below its reorder point from its preferred
+	// supplier. Each line orders the reorder quantity, or more when that does not
+	// reach the reorder point or cover the product's demand forecast. Lines are added
+	// to the supplier's open draft, topping up the product's line when it is already
+	// on it, or a new draft when it has none. It returns the drafts that were created
+	// or added to.
+	GenerateDrafts(ctx context.Context) ([]*PurchaseOrder, error)
+
+	// SetItemQuantity changes a line on a draft. A zero quantity removes the line.
+	SetItemQuantity(ctx context.Context, id int64, itemID int64, quantity int32) (*PurchaseOrder, error)
+
+	// UpdateStatus moves an order from DRAFT to ORDERED, from ORDERED to RECEIVED,
+	// or from DRAFT or ORDERED to CANCELLED.
+	UpdateStatus(ctx context.Context, id int64, status string, userID uint32) (*PurchaseOrder, error)
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+const (
+	QUOTATION_OPEN      = "OPEN"
+	QUOTATION_CONVERTED = "CONVERTED"
+	QUOTATION_CANCELLED = "CANCELLED"
+)
+
+// Quotation is a priced offer to a customer. It does not hold or move stock; the
+// lines are priced, discounted and taxed the way a sale would have been when the
+// quotation was made.
+type Quotation struct {
+	ID              uint32           `json:"id"`
+	QuotationNumber string           `json:"quotation_number"`
+	CustomerID      *uint32          `json:"customer_id"`
+	Status          string           `json:"status"`
+	Note            *string          `json:"note"`
+	ValidUntil      time.Time        `json:"valid_until"`
+	TotalQuantity   int64            `json:"total_quantity"`
+	TotalAmount     float64          `json:"total_amount"`
+	TotalTax        float64          `json:"total_tax"`
+	TotalDiscount   float64          `json:"total_discount"`
+	SaleID          *uint32          `json:"sale_id"`
+	CreatedBy       uint32           `json:"created_by"`
+	ResolvedBy      *uint32          `json:"resolved_by"`
+	ResolvedAt      *time.Time       `json:"resolved_at"`
+	CreatedAt       time.Time        `json:"created_at"`
+	Items           []*QuotationItem `json:"items"`
+
+	// Related fields
+	UserName     string  `json:"user_name"`
+	CustomerName *string `json:"customer_name"`
+}
+
+type QuotationItem struct {
+	ID             uint32  `json:"id"`
+	QuotationID    uint32  `json:"quotation_id"`
+	ProductID      uint32  `json:"product_id"`
+	Quantity       int64   `json:"quantity"`
+	UnitPrice      float64 `json:"unit_price"`
+	DiscountAmount float64 `json:"discount_amount"`
+	LineTotal      float64 `json:"line_total"`
+	PromotionID    *uint32 `json:"promotion_id"`
+	TaxCode        string  `json:"tax_code"`
+	TaxRate        float64 `json:"tax_rate"`
+	TaxAmount      float64 `json:"tax_amount"`
+
+	// Related fields
+	ProductName   string  `json:"product_name"`
+	PromotionName *string `json:"promotion_name"`
+}
+
+type QuotationFilter struct {
+	Pagination *pkg.Pagination
+	Status     *string
+	CustomerID *uint32
+}
+
+// QuotationChange is a quoted line that can no longer be sold as quoted.
+type QuotationChange struct {
+	ProductID      uint32  `json:"product_id"`
+	ProductName    string  `json:"product_name"`
+	Quantity       int64   `json:"quantity"`
+	QuotedPrice    float64 `json:"quoted_price"`
+	CurrentPrice   float64 `json:"current_price"`
+	AvailableStock int64   `json:"available_stock"`
+
+	// the line as quoted and as it would be sold now, after promotions
+	QuotedDiscount   float64 `json:"quoted_discount"`
+	CurrentDiscount  float64 `json:"current_discount"`
+	QuotedLineTotal  float64 `json:"quoted_line_total"`
+	CurrentLineTotal float64 `json:"current_line_total"`
+}
+
+type QuotationRepository interface {
+	Create(ctx context.Context, quotation *Quotation) (*Quotation, error)
+	GetByID(ctx context.Context, id int64) (*Quotation, error)
+	List(ctx context.Context, filter *QuotationFilter) ([]*Quotation, *pkg.Pagination, error)
+
+	// Convert sells the quoted lines at current prices and promotions. It fails listing
+	// every changed line when a line would cost or be discounted differently than
+	// quoted, unless acceptPriceChanges is set, or when stock is no longer available.
+	Convert(ctx context.Context, id int64, sale *Sale, acceptPriceChanges bool) (*Sale, error)
+	Cancel(ctx context.Context, id int64, cancelledBy uint32) (*Quotation, error)
+}
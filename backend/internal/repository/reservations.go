@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+const (
+	RESERVATION_ACTIVE    = "ACTIVE"
+	RESERVATION_CONVERTED = "CONVERTED"
+	RESERVATION_RELEASED  = "RELEASED"
+	RESERVATION_EXPIRED   = "EXPIRED"
+)
+
+type Reservation struct {
+	ID         uint32             `json:"id"`
+	CustomerID *uint32            `json:"customer_id"`
+	Status     string             `json:"status"`
+	Note       *string            `json:"note"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	SaleID     *uint32            `json:"sale_id"`
+	CreatedBy  uint32             `json:"created_by"`
+	ResolvedBy *uint32            `json:"resolved_by"`
+	ResolvedAt *time.Time         `json:"resolved_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+	Items      []*ReservationItem `json:"items"`
+
+	// Related fields
+	UserName     string  `json:"user_name"`
+	CustomerName *string `json:"customer_name"`
+}
+
+type ReservationItem struct {
+	ID            uint32 `json:"id"`
+	ReservationID uint32 `json:"reservation_id"`
+	ProductID     uint32 `json:"product_id"`
+	Quantity      int64  `json:"quantity"`
+
+	// Related fields
+	ProductName string `json:"product_name"`
+}
+
+type ReservationFilter struct {
+	Pagination *pkg.Pagination
+	Status     *string
+	CustomerID *uint32
+}
+
+type ReservationRepository interface {
+	// Create holds stock for every line so it is no longer available to other sales.
+	// The physical stock is left untouched until the reservation is converted.
+	Create(ctx context.Context, reservation *Reservation) (*Reservation, error)
+	GetByID(ctx context.Context, id int64) (*Reservation, error)
+	List(ctx context.Context, filter *ReservationFilter) ([]*Reservation, *pkg.Pagination, error)
+
+	// Convert releases the held stock and sells it in a single transaction. The sale's
+	// items are taken from the reservation, with any prescriptions set on sale.Items.
+	Convert(ctx context.Context, id int64, sale *Sale) (*Sale, error)
+	Release(ctx context.Context, id int64, releasedBy uint32) (*Reservation, error)
+
+	// ExpireDue releases the stock of every active reservation past its expiry.
+	ExpireDue(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+const (
+	RETURN_RESTOCKED   = "RESTOCKED"
+	RETURN_QUARANTINED = "QUARANTINED"
+	RETURN_DISPOSED    = "DISPOSED"
+
+	// RETURN_SUPPLIER_RETURNED marks quarantined goods sent back to the supplier.
+	RETURN_SUPPLIER_RETURNED = "SUPPLIER_RETURNED"
+)
+
+type Return struct {
+	ID                uint32     `json:"id"`
+	MovementID        uint32     `json:"movement_id"`
+	SaleID            *uint32    `json:"sale_id"`
+	ProductID         uint32     `json:"product_id"`
+	CustomerID        *uint32    `json:"customer_id"`
+	Quantity          int32      `json:"quantity"`
+	Status            string     `json:"status"`
+	Reason            *string    `json:"reason"`
+	BatchNumber       *string    `json:"batch_number"`
+	RestockMovementID *uint32    `json:"restock_movement_id"`
+	PerformedBy       uint32     `json:"performed_by"`
+	ResolvedBy        *uint32    `json:"resolved_by"`
+	ResolvedAt        *time.Time `json:"resolved_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+
+	// SaleItemID is the sale line being returned when MovementID is not set.
+	SaleItemID *uint32 `json:"-"`
+
+	// WitnessedBy is needed when restocking a controlled product.
+	WitnessedBy *uint32 `json:"-"`
+
+	// Related fields
+	ProductName string `json:"product_name"`
+	UserName    string `json:"user_name"`
+}
+
+type ReturnFilter struct {
+	Pagination *pkg.Pagination
+	Status     *string
+	ProductID  *uint32
+	SaleID     *uint32
+}
+
+type ReturnRepository interface {
+	// Create checks the quantity still returnable on the original movement (found from
+	// the sale line when MovementID is not set) and either puts the goods back into
+	// stock or into quarantine, depending on the return status. Quarantined goods
+	// are booked in and out again so they have movements too.
+	Create(ctx context.Context, ret *Return) (*Return, error)
+	GetByID(ctx context.Context, id int64) (*Return, error)
+	List(ctx context.Context, filter *ReturnFilter) ([]*Return, *pkg.Pagination, error)
+
+	// Resolve releases the goods of a quarantined return and either keeps them in
+	// stock or writes them off.
+	Resolve(ctx context.Context, id int64, status string, resolvedBy uint32, witnessedBy *uint32) (*Return, error)
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+type Sale struct {
+	ID                uint32      `json:"id"`
+	Location          string      `json:"location"`
+	ReceiptNumber     string      `json:"receipt_number"`
+	ReceiptPrintCount int32       `json:"receipt_print_count"`
+	TotalQuantity     int64       `json:"total_quantity"`
+	TotalAmount       float64     `json:"total_amount"`
+	TotalTax          float64     `json:"total_tax"`
+	TotalDiscount     float64     `json:"total_discount"`
+	Note              *string     `json:"note"`
+	PerformedBy       uint32      `json:"performed_by"`
+	CustomerID        *uint32     `json:"customer_id"`
+	WitnessedBy       *uint32     `json:"-"`
+	CreatedAt         time.Time   `json:"created_at"`
+	Items             []*SaleItem `json:"items"`
+
+	// Payments are the tenders recorded against the sale. Only completed payments
+	// count towards AmountPaid.
+	Payments   []*Payment `json:"payments"`
+	AmountPaid float64    `json:"amount_paid"`
+	Balance    float64    `json:"balance"`
+
+	// A sale on account is invoiced to the customer and may be left unpaid up to
+	// their credit limit, due within their payment terms.
+	OnAccount bool       `json:"on_account"`
+	DueDate   *time.Time `json:"due_date"`
+
+	// ShiftID is the seller's open shift when the sale was made.
+	ShiftID *uint32 `json:"shift_id"`
+
+	// Insurance tenders are only accepted on sales to a scheme member.
+	SchemeID     *uint32 `json:"scheme_id"`
+	MemberNumber *string `json:"member_number"`
+
+	// TaxInvoice is the sale's transmission to the tax authority, queued when the
+	// sale is made.
+	TaxInvoice *TaxInvoice `json:"tax_invoice"`
+
+	// Related fields
+	UserName     string  `json:"user_name"`
+	CustomerName *string `json:"customer_name"`
+	SchemeName   *string `json:"scheme_name"`
+}
+
+type SaleItem struct {
+	ID             uint32    `json:"id"`
+	SaleID         uint32    `json:"sale_id"`
+	ProductID      uint32    `json:"product_id"`
+	MovementID     uint32    `json:"movement_id"`
+	PrescriptionID *uint32   `json:"prescription_id"`
+	Quantity       int64     `json:"quantity"`
+	UnitPrice      float64   `json:"unit_price"`
+	LineTotal      float64   `json:"line_total"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Prices include tax. TaxAmount is the tax contained in LineTotal at TaxRate.
+	TaxCode   string  `json:"tax_code"`
+	TaxRate   float64 `json:"tax_rate"`
+	TaxAmount float64 `json:"tax_amount"`
+
+	// LineTotal is after the discount of the promotion applied to the line, if any.
+	PromotionID    *uint32 `json:"promotion_id"`
+	DiscountAmount float64 `json:"discount_amount"`
+
+	// Related fields
+	ProductName   string  `json:"product_name"`
+	PromotionName *string `json:"promotion_name"`
+}
+
+type SaleFilter struct {
+	Pagination  *pkg.Pagination
+	Location    *string
+	PerformedBy *uint32
+	CustomerID  *uint32
+	StartDate   *time.Time
+	EndDate     *time.Time
+}
+
+type SaleRepository interface {
+	// Create removes stock for every line in a single transaction and links the
+	// resulting movements to the sale. Either all lines succeed or none do.
+	// Payments given with the sale are recorded in the same transaction.
+	Create(ctx context.Context, sale *Sale) (*Sale, error)
+	GetByID(ctx context.Context, id int64) (*Sale, error)
+	List(ctx context.Context, filter *SaleFilter) ([]*Sale, *pkg.Pagination, error)
+
+	// RecordReceiptPrint increments and returns the number of times the sale's receipt has been printed.
+	RecordReceiptPrint(ctx context.Context, id int64) (int32, error)
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+const (
+	SHIFT_OPEN   = "OPEN"
+	SHIFT_CLOSED = "CLOSED"
+)
+
+// Shift is a user's session on a till. Sales and payments the user takes while
+// the shift is open are linked to it so the till can be reconciled when it closes.
+type Shift struct {
+	ID           uint32     `json:"id"`
+	UserID       uint32     `json:"user_id"`
+	Location     string     `json:"location"`
+	Status       string     `json:"status"`
+	OpeningFloat float64    `json:"opening_float"`
+	OpeningNote  *string    `json:"opening_note"`
+	ClosingNote  *string    `json:"closing_note"`
+	OpenedAt     time.Time  `json:"opened_at"`
+	ClosedAt     *time.Time `json:"closed_at"`
+
+	// Tenders are the expected takings per payment method, and once the shift is
+	// closed the amounts counted and the over/short variance.
+	Tenders       []*ShiftTender `json:"tenders"`
+	TotalVariance float64        `json:"total_variance"`
+	Sales         *SalesTotals   `json:"sales"`
+
+	// Related fields
+	UserName string `json:"user_name"`
+}
+
+type ShiftTender struct {
+	Method         string  `json:"method"`
+	TotalPayments  int64   `json:"total_payments"`
+	ExpectedAmount float64 `json:"expected_amount"`
+	// CountedAmount and Variance are nil until the shift is closed.
+	CountedAmount *float64 `json:"counted_amount"`
+	Variance      *float64 `json:"variance"`
+}
+
+type SalesTotals struct {
+	TotalSales      int64   `json:"total_sales"`
+	TotalQuantity   int64   `json:"total_quantity"`
+	TotalAmount     float64 `json:"total_amount"`
+	TotalTax        float64 `json:"total_tax"`
+	TotalDiscount   float64 `json:"total_discount"`
+	OnAccountAmount float64 `json:"on_account_amount"`
+}
+
+// ShiftClose is the till count. Methods missing from Counted are counted as zero.
+type ShiftClose struct {
+	Counted map[string]float64 `json:"counted"`
+	Note    *string            `json:"note"`
+}
+
+type ShiftFilter struct {
+	Pagination *pkg.Pagination
+	UserID     *uint32
+	Location   *string
+	Status     *string
+	StartDate  *time.Time
+	EndDate    *time.Time
+}
+
+type TenderTotal struct {
+	Method        string  `json:"method"`
+	TotalPayments int64   `json:"total_payments"`
+	TotalAmount   float64 `json:"total_amount"`
+}
+
+// ZReport is the end of day summary for a location.
+type ZReport struct {
+	Location  string    `json:"location"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+
+	Sales    SalesTotals    `json:"sales"`
+	Payments []*TenderTotal `json:"payments"`
+
+	// Tenders add up the closed shifts' counts per payment method.
+	Shifts        []*Shift       `json:"shifts"`
+	Tenders       []*ShiftTender `json:"tenders"`
+	TotalVariance float64        `json:"total_variance"`
+	OpenShifts    int            `json:"open_shifts"`
+}
+
+type ShiftRepository interface {
+	Open(ctx context.Context, shift *Shift) (*Shift, error)
+	GetByID(ctx context.Context, id int64) (*Shift, error)
+	// GetOpen returns the user's open shift.
+	GetOpen(ctx context.Context, userID uint32) (*Shift, error)
+	// Close records the counted takings against the amounts expected from the shift's payments.
+	Close(ctx context.Context, id int64, shiftClose *ShiftClose) (*Shift, error)
+	List(ctx context.Context, filter *ShiftFilter) ([]*Shift, *pkg.Pagination, error)
+	GetZReport(ctx context.Context, location string, startDate, endDate time.Time) (*ZReport, error)
+}
@@ -10,4 +10,6 @@ type Stats struct {
 	TotalStockRemoved      int64   `json:"total_stocks_removed"`
 	TotalStockRemovedValue float64 `json:"total_stocks_removed_value"`
 	TotalValue             float64 `json:"total_value"`
+	TotalRetailValue       float64 `json:"total_retail_value"`
+	ValuationMethod        string  `json:"valuation_method"`
 }
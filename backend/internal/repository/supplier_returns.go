@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+const (
+	SUPPLIER_CREDIT_PENDING  = "PENDING"
+	SUPPLIER_CREDIT_PARTIAL  = "PARTIAL"
+	SUPPLIER_CREDIT_RECEIVED = "RECEIVED"
+)
+
+type SupplierReturn struct {
+	ID              uint32                `json:"id"`
+	SupplierID      uint32                `json:"supplier_id"`
+	DebitNoteNumber string                `json:"debit_note_number"`
+	TotalQuantity   int64                 `json:"total_quantity"`
+	TotalAmount     float64               `json:"total_amount"`
+	CreditReceived  float64               `json:"credit_received"`
+	CreditPending   float64               `json:"credit_pending"`
+	CreditStatus    string                `json:"credit_status"`
+	Note            *string               `json:"note"`
+	PerformedBy     uint32                `json:"performed_by"`
+	WitnessedBy     *uint32               `json:"-"`
+	CreatedAt       time.Time             `json:"created_at"`
+	Items           []*SupplierReturnItem `json:"items"`
+	Credits         []*SupplierCredit     `json:"credits"`
+
+	// Related fields
+	SupplierName string `json:"supplier_name"`
+	UserName     string `json:"user_name"`
+}
+
+// SupplierReturnItem is one batch of a product sent back to the supplier. Lines
+// with a ReturnID send back a quarantined customer return instead of sellable stock;
+// the return is released from quarantine and removed again so the removal has a
+// movement too.
+// A zero UnitCost is looked up from the purchase of the batch.
+type SupplierReturnItem struct {
+	ID               uint32  `json:"id"`
+	SupplierReturnID uint32  `json:"supplier_return_id"`
+	ProductID        uint32  `json:"product_id"`
+	BatchNumber      *string `json:"batch_number"`
+	Quantity         int64   `json:"quantity"`
+	UnitCost         float64 `json:"unit_cost"`
+	LineTotal        float64 `json:"line_total"`
+	Reason           *string `json:"reason"`
+	MovementID       *uint32 `json:"movement_id"`
+	ReturnID         *uint32 `json:"return_id"`
+
+	// Related fields
+	ProductName string `json:"product_name"`
+}
+
+type SupplierCredit struct {
+	ID               uint32    `json:"id"`
+	SupplierReturnID uint32    `json:"supplier_return_id"`
+	Amount           float64   `json:"amount"`
+	Reference        *string   `json:"reference"`
+	ReceivedAt       time.Time `json:"received_at"`
+	RecordedBy       uint32    `json:"recorded_by"`
+	CreatedAt        time.Time `json:"created_at"`
+
+	// Related fields
+	UserName string `json:"user_name"`
+}
+
+type SupplierReturnFilter struct {
+	Pagination   *pkg.Pagination
+	SupplierID   *uint32
+	CreditStatus *string
+}
+
+type SupplierReturnRepository interface {
+	// Create removes every line from stock (or from quarantine) in a single transaction
+	// and records the debit note raised against the supplier.
+	Create(ctx context.Context, supplierReturn *SupplierReturn) (*SupplierReturn, error)
+	GetByID(ctx context.Context, id int64) (*SupplierReturn, error)
+	List(ctx context.Context, filter *SupplierReturnFilter) ([]*SupplierReturn, *pkg.Pagination, error)
+
+	// RecordCredit records credit received from the supplier against a debit note.
+	// The credit received can never exceed the debit note total.
+	RecordCredit(ctx context.Context, credit *SupplierCredit) (*SupplierReturn, error)
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+type Supplier struct {
+	ID            uint32                 `json:"id"`
+	Name          string                 `json:"name"`
+	ContactPerson *string                `json:"contact_person"`
+	PhoneNumber   *string                `json:"phone_number"`
+	Email         *string                `json:"email"`
+	Address       *string                `json:"address"`
+	Deleted       bool                   `json:"deleted"`
+	CreatedAt     time.Time              `json:"created_at"`
+	CreditSummary *SupplierCreditSummary `json:"credit_summary,omitempty"`
+}
+
+// SupplierCreditSummary totals the credit owed by a supplier for goods returned to them.
+type SupplierCreditSummary struct {
+	CreditExpected float64 `json:"credit_expected"`
+	CreditReceived float64 `json:"credit_received"`
+	CreditPending  float64 `json:"credit_pending"`
+}
+
+type SupplierUpdate struct {
+	Name          *string `json:"name"`
+	ContactPerson *string `json:"contact_person"`
+	PhoneNumber   *string `json:"phone_number"`
+	Email         *string `json:"email"`
+	Address       *string `json:"address"`
+}
+
+type SupplierFilter struct {
+	Pagination *pkg.Pagination
+	Search     *string
+}
+
+type SupplierRepository interface {
+	Create(ctx context.Context, supplier *Supplier) (*Supplier, error)
+	// GetByID also returns the supplier's credit summary.
+	GetByID(ctx context.Context, id int64) (*Supplier, error)
+	Update(ctx context.Context, id int64, supplierUpdate *SupplierUpdate) (*Supplier, error)
+	Delete(ctx context.Context, id int64) error
+	List(ctx context.Context, filter *SupplierFilter) ([]*Supplier, *pkg.Pagination, error)
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+const (
+	TAX_INVOICE_PENDING  = "PENDING"
+	TAX_INVOICE_SIGNED   = "SIGNED"
+	TAX_INVOICE_REJECTED = "REJECTED"
+)
+
+// TaxInvoice tracks the transmission of a sale to the tax authority. Pending
+// invoices are retried until the authority signs them; a rejected invoice needs
+// attention before it is queued again.
+type TaxInvoice struct {
+	ID            uint32    `json:"id"`
+	SaleID        uint32    `json:"sale_id"`
+	Status        string    `json:"status"`
+	Attempts      int32     `json:"attempts"`
+	LastError     *string   `json:"last_error"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Set once signed. ControlNumber and QRCode are printed on the receipt.
+	ControlNumber    *string    `json:"control_number"`
+	InternalData     *string    `json:"internal_data"`
+	ReceiptSignature *string    `json:"receipt_signature"`
+	QRCode           *string    `json:"qr_code"`
+	SignedAt         *time.Time `json:"signed_at"`
+
+	// Related fields
+	ReceiptNumber string  `json:"receipt_number,omitempty"`
+	TotalAmount   float64 `json:"total_amount,omitempty"`
+}
+
+type TaxInvoiceSignature struct {
+	ControlNumber    string
+	InternalData     string
+	ReceiptSignature string
+	QRCode           string
+	SignedAt         time.Time
+}
+
+type TaxInvoiceFilter struct {
+	Pagination *pkg.Pagination
+	Status     *string
+}
+
+type TaxInvoiceRepository interface {
+	GetBySale(ctx context.Context, saleID int64) (*TaxInvoice, error)
+	List(ctx context.Context, filter *TaxInvoiceFilter) ([]*TaxInvoice, *pkg.Pagination, error)
+
+	// Lease takes a sale's pending invoice for leaseFor so no other worker sends it
+	// at the same time. It returns nil when the invoice is not due or not pending.
+	Lease(ctx context.Context, saleID int64, leaseFor time.Duration) (*TaxInvoice, error)
+	// LeaseDue takes up to limit pending invoices that are due for another attempt.
+	LeaseDue(ctx context.Context, limit int32, leaseFor time.Duration) ([]*TaxInvoice, error)
+
+	Sign(ctx context.Context, id int64, signature *TaxInvoiceSignature) (*TaxInvoice, error)
+	// Fail records a failed attempt. Rejected invoices are not retried; any other
+	// failure is retried from retryAt.
+	Fail(ctx context.Context, id int64, reason string, rejected bool, retryAt time.Time) (*TaxInvoice, error)
+	// Requeue makes an unsigned invoice due now, putting a rejected invoice back
+	// in the queue.
+	Requeue(ctx context.Context, saleID int64) (*TaxInvoice, error)
+}
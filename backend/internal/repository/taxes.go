@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	TAX_STANDARD   = "STANDARD"
+	TAX_ZERO_RATED = "ZERO_RATED"
+	TAX_EXEMPT     = "EXEMPT"
+)
+
+// TaxClass is the VAT treatment of a product. Only standard rated classes have a
+// non-zero rate; zero rated and exempt sales are reported separately.
+type TaxClass struct {
+	ID        uint32    `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Rate      float64   `json:"rate"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TaxClassUpdate struct {
+	Name *string  `json:"name"`
+	Rate *float64 `json:"rate"`
+}
+
+type TaxSummaryLine struct {
+	TaxCode     string  `json:"tax_code"`
+	TaxName     string  `json:"tax_name"`
+	TaxType     string  `json:"tax_type"`
+	TaxRate     float64 `json:"tax_rate"`
+	TotalSales  int64   `json:"total_sales"`
+	GrossAmount float64 `json:"gross_amount"`
+	NetAmount   float64 `json:"net_amount"`
+	TaxAmount   float64 `json:"tax_amount"`
+}
+
+type TaxSummary struct {
+	StartDate   time.Time         `json:"start_date"`
+	EndDate     time.Time         `json:"end_date"`
+	Lines       []*TaxSummaryLine `json:"lines"`
+	GrossAmount float64           `json:"gross_amount"`
+	NetAmount   float64           `json:"net_amount"`
+	TaxAmount   float64           `json:"tax_amount"`
+}
+
+type TaxClassRepository interface {
+	Create(ctx context.Context, taxClass *TaxClass) (*TaxClass, error)
+	GetByID(ctx context.Context, id int64) (*TaxClass, error)
+	Update(ctx context.Context, id int64, taxClassUpdate *TaxClassUpdate) (*TaxClass, error)
+	List(ctx context.Context) ([]*TaxClass, error)
+
+	// GetTaxSummary totals sales and the tax charged on them per tax class and rate
+	// for sales made from startDate up to but not including endDate.
+	GetTaxSummary(ctx context.Context, startDate, endDate time.Time) (*TaxSummary, error)
+}
@@ -8,8 +8,9 @@ import (
 )
 
 const (
-	ADMIN_ROLE = "admin"
-	STAFF_ROLE = "staff"
+	ADMIN_ROLE      = "admin"
+	PHARMACIST_ROLE = "pharmacist"
+	STAFF_ROLE      = "staff"
 )
 
 type User struct {
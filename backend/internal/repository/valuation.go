@@ -0,0 +1,28 @@
+package repository
+
+import "time"
+
+type ValuationFilter struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+	Method    *string
+}
+
+type ProductValuation struct {
+	ProductID       uint32  `json:"product_id"`
+	ProductName     string  `json:"product_name"`
+	QuantityOnHand  int64   `json:"quantity_on_hand"`
+	UnitCost        float64 `json:"unit_cost"`
+	ClosingValue    float64 `json:"closing_value"`
+	QuantitySold    int64   `json:"quantity_sold"`
+	CostOfGoodsSold float64 `json:"cost_of_goods_sold"`
+}
+
+type Valuation struct {
+	Method               string              `json:"method"`
+	StartDate            time.Time           `json:"start_date"`
+	EndDate              time.Time           `json:"end_date"`
+	TotalClosingValue    float64             `json:"total_closing_value"`
+	TotalCostOfGoodsSold float64             `json:"total_cost_of_goods_sold"`
+	Products             []*ProductValuation `json:"products"`
+}
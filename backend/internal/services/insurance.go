@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// ClaimSubmission is a batch of claims sent to an insurer in one submission.
+type ClaimSubmission struct {
+	InsurerCode string
+	BatchNumber string
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Claims      []*ClaimLine
+}
+
+type ClaimLine struct {
+	ClaimID       uint32
+	ReceiptNumber string
+	SaleDate      time.Time
+	SchemeName    string
+	MemberNumber  string
+	PatientName   string
+	Amount        float64
+}
+
+type ClaimSubmissionResult struct {
+	// Reference is the insurer's reference for the submission, used to check on it later.
+	Reference string
+	Message   string
+}
+
+// ClaimStatus is the insurer's decision on one claim. Status is SUBMITTED while
+// the claim is under review, then APPROVED or REJECTED.
+type ClaimStatus struct {
+	ClaimID         uint32
+	Status          string
+	ApprovedAmount  float64
+	RejectionReason string
+	Reference       string
+}
+
+// InsuranceClient submits claims to insurers and reports the insurers' decisions.
+// Each insurer is configured with the name of the client its claims go through.
+type InsuranceClient interface {
+	SubmitClaims(ctx context.Context, submission *ClaimSubmission) (*ClaimSubmissionResult, error)
+	GetClaimStatuses(ctx context.Context, insurerCode string, reference string) ([]*ClaimStatus, error)
+}
@@ -0,0 +1,37 @@
+package services
+
+import "context"
+
+// STKPushRequest asks the customer's phone to approve a payment.
+type STKPushRequest struct {
+	PhoneNumber string
+	Amount      float64
+	// AccountReference and Description are shown to the customer on the prompt.
+	AccountReference string
+	Description      string
+}
+
+type STKPushResponse struct {
+	CheckoutRequestID   string
+	MerchantRequestID   string
+	CustomerMessage     string
+	ResponseDescription string
+}
+
+// MobileMoneyCallback is the provider's result for an STK push.
+type MobileMoneyCallback struct {
+	CheckoutRequestID string
+	MerchantRequestID string
+	Success           bool
+	ResultDescription string
+	ReceiptNumber     string
+	Amount            float64
+	PhoneNumber       string
+}
+
+// MobileMoneyService initiates mobile money payments and interprets the results
+// the provider posts back to the callback url.
+type MobileMoneyService interface {
+	InitiateSTKPush(ctx context.Context, req *STKPushRequest) (*STKPushResponse, error)
+	ParseCallback(body []byte) (*MobileMoneyCallback, error)
+}
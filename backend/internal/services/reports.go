@@ -1,4 +1,34 @@
 package services
 
+import "github.com/EmilioCliff/jonche-med/internal/repository"
+
+const (
+	REPORT_FORMAT_PDF  = "pdf"
+	REPORT_FORMAT_TEXT = "text"
+)
+
 type ReportService interface {
+	// SaleReceipt renders the sale's receipt as a PDF document or as plain text laid out for 80mm receipt printers.
+	SaleReceipt(sale *repository.Sale, format string) ([]byte, error)
+
+	// ControlledDrugRegister renders the controlled drugs register in the regulator's column layout.
+	ControlledDrugRegister(report *repository.ControlledDrugReport, format string) ([]byte, error)
+
+	// DebitNote renders the debit note raised against a supplier for goods returned to them.
+	DebitNote(supplierReturn *repository.SupplierReturn, supplier *repository.Supplier, format string) ([]byte, error)
+
+	// TaxSummary renders the sales and tax charged per tax class for a period.
+	TaxSummary(summary *repository.TaxSummary, format string) ([]byte, error)
+
+	// CustomerStatement renders a customer's invoices and payments for a period with the ageing of the amount due.
+	CustomerStatement(statement *repository.CustomerStatement, format string) ([]byte, error)
+
+	// AgeingReport renders outstanding customer balances split into 30, 60 and 90 day buckets.
+	AgeingReport(report *repository.AgeingReport, format string) ([]byte, error)
+
+	// ZReport renders a location's end of day sales, payments and till reconciliation.
+	ZReport(report *repository.ZReport, format string) ([]byte, error)
+
+	// Quotation renders a quotation as a proforma invoice the customer can take away.
+	Quotation(quotation *repository.Quotation, format string) ([]byte, error)
 }
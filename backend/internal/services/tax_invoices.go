@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/internal/repository"
+)
+
+// TaxInvoiceRequest is a sale as transmitted to the tax authority. InvoiceNumber
+// is unique per sale so a repeated submission is recognised as the same invoice.
+type TaxInvoiceRequest struct {
+	InvoiceNumber uint32
+	ReceiptNumber string
+	SaleDate      time.Time
+	CustomerName  string
+	// PaymentMethod is the method of the sale's tenders, empty when it was paid
+	// by more than one method.
+	PaymentMethod string
+	OnAccount     bool
+	Lines         []*TaxInvoiceLine
+	TotalTaxable  float64
+	TotalTax      float64
+	TotalAmount   float64
+}
+
+// TaxInvoiceLine is one sale line. Amounts include tax; TaxableAmount is the line
+// total less the tax it contains. TaxType is the line's tax class type.
+type TaxInvoiceLine struct {
+	Sequence      int
+	ItemCode      string
+	ItemName      string
+	Quantity      int64
+	UnitPrice     float64
+	Discount      float64
+	TaxType       string
+	TaxRate       float64
+	TaxableAmount float64
+	TaxAmount     float64
+	TotalAmount   float64
+}
+
+// TaxInvoiceResponse is the tax authority's signature for an invoice.
+type TaxInvoiceResponse struct {
+	ControlNumber    string
+	InternalData     string
+	ReceiptSignature string
+	QRCode           string
+	SignedAt         time.Time
+}
+
+// TaxDeviceClient signs and submits invoices to the tax authority. An INVALID
+// error means the invoice was rejected and should not be resent as is; any other
+// error is treated as the endpoint being unavailable.
+type TaxDeviceClient interface {
+	SubmitInvoice(ctx context.Context, req *TaxInvoiceRequest) (*TaxInvoiceResponse, error)
+}
+
+// TaxInvoiceService transmits queued tax invoices through a TaxDeviceClient.
+type TaxInvoiceService interface {
+	// Transmit sends a sale's pending invoice now if it is due. The invoice is
+	// returned in whatever state the attempt left it.
+	Transmit(ctx context.Context, saleID int64) (*repository.TaxInvoice, error)
+	// RetryDue sends pending invoices that are due for another attempt and
+	// returns how many were signed.
+	RetryDue(ctx context.Context) (int, error)
+}
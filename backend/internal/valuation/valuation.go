@@ -0,0 +1,177 @@
+package valuation
+
+import (
+	"sort"
+	"time"
+
+	"github.com/EmilioCliff/jonche-med/pkg"
+)
+
+const (
+	METHOD_FIFO             = "FIFO"
+	METHOD_WEIGHTED_AVERAGE = "WEIGHTED_AVERAGE"
+
+	ENTRY_IN  = "ADD"
+	ENTRY_OUT = "REMOVE"
+)
+
+// Entry is a single stock movement fed to the engine. Entries must be passed in
+// the order they happened.
+type Entry struct {
+	ProductID uint32
+	Type      string
+	Quantity  int64
+	UnitCost  float64
+	At        time.Time
+}
+
+// Result is the valuation of a single product.
+type Result struct {
+	ProductID       uint32  `json:"product_id"`
+	QuantityOnHand  int64   `json:"quantity_on_hand"`
+	UnitCost        float64 `json:"unit_cost"`
+	ClosingValue    float64 `json:"closing_value"`
+	QuantitySold    int64   `json:"quantity_sold"`
+	CostOfGoodsSold float64 `json:"cost_of_goods_sold"`
+}
+
+type layer struct {
+	quantity int64
+	unitCost float64
+}
+
+type productState struct {
+	result   *Result
+	layers   []layer // FIFO receipt layers, oldest first
+	avgCost  float64 // moving weighted average cost
+	lastCost float64 // used when more stock is removed than was received
+}
+
+type Engine struct {
+	method string
+}
+
+func NewEngine(method string) (*Engine, error) {
+	if !IsValidMethod(method) {
+		return nil, pkg.Errorf(pkg.INVALID_ERROR, "unsupported valuation method: %s", method)
+	}
+
+	return &Engine{method: method}, nil
+}
+
+func IsValidMethod(method string) bool {
+	return method == METHOD_FIFO || method == METHOD_WEIGHTED_AVERAGE
+}
+
+func (e *Engine) Method() string {
+	return e.method
+}
+
+// Value replays entries up to end and returns the closing stock value per product
+// together with the cost of goods sold for removals made on or after start.
+// Results are sorted by product ID.
+func (e *Engine) Value(entries []Entry, start, end time.Time) []*Result {
+	states := make(map[uint32]*productState)
+
+	for _, entry := range entries {
+		if entry.At.After(end) {
+			continue
+		}
+
+		st, ok := states[entry.ProductID]
+		if !ok {
+			st = &productState{result: &Result{ProductID: entry.ProductID}}
+			states[entry.ProductID] = st
+		}
+
+		switch entry.Type {
+		case ENTRY_IN:
+			e.receive(st, entry)
+		case ENTRY_OUT:
+			cost := e.issue(st, entry)
+			if !entry.At.Before(start) {
+				st.result.QuantitySold += entry.Quantity
+				st.result.CostOfGoodsSold += cost
+			}
+		}
+	}
+
+	results := make([]*Result, 0, len(states))
+	for _, st := range states {
+		st.result.ClosingValue = e.closingValue(st)
+		if st.result.QuantityOnHand > 0 {
+			st.result.UnitCost = st.result.ClosingValue / float64(st.result.QuantityOnHand)
+		}
+		results = append(results, st.result)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].ProductID < results[j].ProductID })
+
+	return results
+}
+
+func (e *Engine) receive(st *productState, entry Entry) {
+	onHand := st.result.QuantityOnHand
+	st.result.QuantityOnHand += entry.Quantity
+	st.lastCost = entry.UnitCost
+
+	switch e.method {
+	case METHOD_FIFO:
+		// units already issued while stock was negative are not part of the new layer
+		quantity := entry.Quantity
+		if onHand < 0 {
+			quantity += onHand
+		}
+		if quantity > 0 {
+			st.layers = append(st.layers, layer{quantity: quantity, unitCost: entry.UnitCost})
+		}
+	case METHOD_WEIGHTED_AVERAGE:
+		if onHand <= 0 || st.result.QuantityOnHand <= 0 {
+			st.avgCost = entry.UnitCost
+			return
+		}
+		st.avgCost = (float64(onHand)*st.avgCost + float64(entry.Quantity)*entry.UnitCost) / float64(st.result.QuantityOnHand)
+	}
+}
+
+// issue removes stock and returns the cost of the removed quantity.
+func (e *Engine) issue(st *productState, entry Entry) float64 {
+	st.result.QuantityOnHand -= entry.Quantity
+
+	if e.method == METHOD_WEIGHTED_AVERAGE {
+		return float64(entry.Quantity) * st.avgCost
+	}
+
+	remaining := entry.Quantity
+	cost := 0.0
+	for remaining > 0 && len(st.layers) > 0 {
+		l := &st.layers[0]
+		take := min(remaining, l.quantity)
+		cost += float64(take) * l.unitCost
+		l.quantity -= take
+		remaining -= take
+		if l.quantity == 0 {
+			st.layers = st.layers[1:]
+		}
+	}
+	// stock removed beyond what was received is costed at the last receipt cost
+	cost += float64(remaining) * st.lastCost
+
+	return cost
+}
+
+func (e *Engine) closingValue(st *productState) float64 {
+	if st.result.QuantityOnHand <= 0 {
+		return 0
+	}
+
+	if e.method == METHOD_WEIGHTED_AVERAGE {
+		return float64(st.result.QuantityOnHand) * st.avgCost
+	}
+
+	value := 0.0
+	for _, l := range st.layers {
+		value += float64(l.quantity) * l.unitCost
+	}
+
+	return value
+}
@@ -22,6 +22,34 @@ type Config struct {
 	TOKEN_SYMMETRIC_KEY     string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	TOKEN_ISSUER            string        `mapstructure:"TOKEN_ISSUER"`
 	DEFAULT_USER_PASSWORD   string        `mapstructure:"DEFAULT_USER_PASSWORD"`
+	JOBS_INTERVAL           time.Duration `mapstructure:"JOBS_INTERVAL"`
+	VALUATION_METHOD        string        `mapstructure:"VALUATION_METHOD"`
+	BUSINESS_NAME           string        `mapstructure:"BUSINESS_NAME"`
+	BUSINESS_ADDRESS        string        `mapstructure:"BUSINESS_ADDRESS"`
+	BUSINESS_PHONE          string        `mapstructure:"BUSINESS_PHONE"`
+	DEFAULT_LOCATION        string        `mapstructure:"DEFAULT_LOCATION"`
+	LOCATIONS               []string      `mapstructure:"LOCATIONS"`
+	PRESCRIPTION_VALIDITY   time.Duration `mapstructure:"PRESCRIPTION_VALIDITY"`
+	RESERVATION_DURATION    time.Duration `mapstructure:"RESERVATION_DURATION"`
+	QUOTATION_VALIDITY      time.Duration `mapstructure:"QUOTATION_VALIDITY"`
+	MPESA_BASE_URL          string        `mapstructure:"MPESA_BASE_URL"`
+	MPESA_CONSUMER_KEY      string        `mapstructure:"MPESA_CONSUMER_KEY"`
+	MPESA_CONSUMER_SECRET   string        `mapstructure:"MPESA_CONSUMER_SECRET"`
+	MPESA_SHORTCODE         string        `mapstructure:"MPESA_SHORTCODE"`
+	MPESA_PASSKEY           string        `mapstructure:"MPESA_PASSKEY"`
+	MPESA_CALLBACK_URL      string        `mapstructure:"MPESA_CALLBACK_URL"`
+	MPESA_CALLBACK_TOKEN    string        `mapstructure:"MPESA_CALLBACK_TOKEN"`
+	MPESA_PAYMENT_TIMEOUT   time.Duration `mapstructure:"MPESA_PAYMENT_TIMEOUT"`
+	ETIMS_BASE_URL          string        `mapstructure:"ETIMS_BASE_URL"`
+	ETIMS_PIN               string        `mapstructure:"ETIMS_PIN"`
+	ETIMS_BRANCH_ID         string        `mapstructure:"ETIMS_BRANCH_ID"`
+	ETIMS_CMC_KEY           string        `mapstructure:"ETIMS_CMC_KEY"`
+	ETIMS_SDC_ID            string        `mapstructure:"ETIMS_SDC_ID"`
+	ETIMS_RECEIPT_URL       string        `mapstructure:"ETIMS_RECEIPT_URL"`
+	ETIMS_RETRY_INTERVAL    time.Duration `mapstructure:"ETIMS_RETRY_INTERVAL"`
+	FORECAST_METHOD         string        `mapstructure:"FORECAST_METHOD"`
+	FORECAST_HISTORY_DAYS   int           `mapstructure:"FORECAST_HISTORY_DAYS"`
+	FORECAST_HORIZON_DAYS   int           `mapstructure:"FORECAST_HORIZON_DAYS"`
 }
 
 func LoadConfig(path string) (Config, error) {
@@ -60,4 +88,32 @@ func setDefaults() {
 	viper.SetDefault("TOKEN_SYMMETRIC_KEY", "")
 	viper.SetDefault("TOKEN_ISSUER", "")
 	viper.SetDefault("DEFAULT_USER_PASSWORD", "")
+	viper.SetDefault("JOBS_INTERVAL", time.Minute)
+	viper.SetDefault("VALUATION_METHOD", "FIFO")
+	viper.SetDefault("BUSINESS_NAME", "Jonche Med")
+	viper.SetDefault("BUSINESS_ADDRESS", "")
+	viper.SetDefault("BUSINESS_PHONE", "")
+	viper.SetDefault("DEFAULT_LOCATION", "MAIN")
+	viper.SetDefault("LOCATIONS", []string{"MAIN"})
+	viper.SetDefault("PRESCRIPTION_VALIDITY", 30*24*time.Hour)
+	viper.SetDefault("RESERVATION_DURATION", 24*time.Hour)
+	viper.SetDefault("QUOTATION_VALIDITY", 14*24*time.Hour)
+	viper.SetDefault("MPESA_BASE_URL", "https://sandbox.safaricom.co.ke")
+	viper.SetDefault("MPESA_CONSUMER_KEY", "")
+	viper.SetDefault("MPESA_CONSUMER_SECRET", "")
+	viper.SetDefault("MPESA_SHORTCODE", "")
+	viper.SetDefault("MPESA_PASSKEY", "")
+	viper.SetDefault("MPESA_CALLBACK_URL", "")
+	viper.SetDefault("MPESA_CALLBACK_TOKEN", "")
+	viper.SetDefault("MPESA_PAYMENT_TIMEOUT", 10*time.Minute)
+	viper.SetDefault("ETIMS_BASE_URL", "https://etims-api-sbx.kra.go.ke/etims-api")
+	viper.SetDefault("ETIMS_PIN", "")
+	viper.SetDefault("ETIMS_BRANCH_ID", "00")
+	viper.SetDefault("ETIMS_CMC_KEY", "")
+	viper.SetDefault("ETIMS_SDC_ID", "")
+	viper.SetDefault("ETIMS_RECEIPT_URL", "https://etims-sbx.kra.go.ke/common/link/etims/receipt/indexEtimsReceiptData")
+	viper.SetDefault("ETIMS_RETRY_INTERVAL", time.Minute)
+	viper.SetDefault("FORECAST_METHOD", "EXPONENTIAL_SMOOTHING")
+	viper.SetDefault("FORECAST_HISTORY_DAYS", 90)
+	viper.SetDefault("FORECAST_HORIZON_DAYS", 14)
 }
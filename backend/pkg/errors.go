@@ -24,6 +24,9 @@ const (
 type Error struct {
 	Code    string
 	Message string
+
+	// Details is optional data returned to the client alongside the message.
+	Details any
 }
 
 func Errorf(code string, format string, args ...any) *Error {
@@ -33,6 +36,13 @@ func Errorf(code string, format string, args ...any) *Error {
 	}
 }
 
+// WithDetails attaches details to the error and returns it.
+func (e *Error) WithDetails(details any) *Error {
+	e.Details = details
+
+	return e
+}
+
 func ErrorCode(err error) string {
 	var e *Error
 
@@ -57,6 +67,16 @@ func ErrorMessage(err error) string {
 	return "Internal error."
 }
 
+func ErrorDetails(err error) any {
+	var e *Error
+
+	if errors.As(err, &e) {
+		return e.Details
+	}
+
+	return nil
+}
+
 func PgxErrorCode(err error) string {
 	var pgErr *pgconn.PgError
 